@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+)
+
+// gravitonFamilyRE matches the instance families with arm64 processors, like m6g or t4g
+var gravitonFamilyRE = regexp.MustCompile(`^(a1|[a-z]+[0-9]+g[a-z]*)\.`)
+
+// zoneSuffixes are the availability zones of the emulated region
+var zoneSuffixes = []string{"a", "b", "c"}
+
+// ec2Emulator adds the EC2 actions that the mock does not implement, but clients need to get started
+type ec2Emulator struct {
+	*mockec2.MockEC2
+	region string
+}
+
+func (e *ec2Emulator) DescribeRegions(ctx context.Context, request *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	return &ec2.DescribeRegionsOutput{
+		Regions: []ec2types.Region{
+			{
+				RegionName:  aws.String(e.region),
+				Endpoint:    aws.String("ec2." + e.region + ".amazonaws.com"),
+				OptInStatus: aws.String("opt-in-not-required"),
+			},
+		},
+	}, nil
+}
+
+func (e *ec2Emulator) DescribeAvailabilityZones(ctx context.Context, request *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	response := &ec2.DescribeAvailabilityZonesOutput{}
+	for i, suffix := range zoneSuffixes {
+		response.AvailabilityZones = append(response.AvailabilityZones, ec2types.AvailabilityZone{
+			RegionName: aws.String(e.region),
+			ZoneName:   aws.String(e.region + suffix),
+			ZoneId:     aws.String(fmt.Sprintf("%s-az%d", e.region, i+1)),
+			ZoneType:   aws.String("availability-zone"),
+			State:      ec2types.AvailabilityZoneStateAvailable,
+		})
+	}
+	return response, nil
+}
+
+// DescribeReservedInstancesOfferings offers every instance type in every zone, which is how clients check instance types are available
+func (e *ec2Emulator) DescribeReservedInstancesOfferings(ctx context.Context, request *ec2.DescribeReservedInstancesOfferingsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeReservedInstancesOfferingsOutput, error) {
+	response := &ec2.DescribeReservedInstancesOfferingsOutput{}
+	if request.InstanceType == "" {
+		return response, nil
+	}
+	for _, suffix := range zoneSuffixes {
+		response.ReservedInstancesOfferings = append(response.ReservedInstancesOfferings, ec2types.ReservedInstancesOffering{
+			AvailabilityZone:   aws.String(e.region + suffix),
+			InstanceType:       request.InstanceType,
+			InstanceTenancy:    ec2types.TenancyDefault,
+			OfferingClass:      ec2types.OfferingClassTypeStandard,
+			OfferingType:       ec2types.OfferingTypeValuesNoUpfront,
+			ProductDescription: ec2types.RIProductDescriptionLinuxUnixAmazonVpc,
+		})
+	}
+	return response, nil
+}
+
+// DescribeInstanceTypes describes any requested instance type as a small instance,
+// with the architecture of the instance family
+func (e *ec2Emulator) DescribeInstanceTypes(ctx context.Context, request *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	response := &ec2.DescribeInstanceTypesOutput{}
+	for _, instanceType := range request.InstanceTypes {
+		architecture := ec2types.ArchitectureTypeX8664
+		if gravitonFamilyRE.MatchString(string(instanceType)) {
+			architecture = ec2types.ArchitectureTypeArm64
+		}
+		response.InstanceTypes = append(response.InstanceTypes, ec2types.InstanceTypeInfo{
+			InstanceType:                  instanceType,
+			CurrentGeneration:             aws.Bool(true),
+			SupportedUsageClasses:         []ec2types.UsageClassType{ec2types.UsageClassTypeOnDemand, ec2types.UsageClassTypeSpot},
+			SupportedVirtualizationTypes:  []ec2types.VirtualizationType{ec2types.VirtualizationTypeHvm},
+			Hypervisor:                    ec2types.InstanceTypeHypervisorNitro,
+			ProcessorInfo:                 &ec2types.ProcessorInfo{SupportedArchitectures: []ec2types.ArchitectureType{architecture}},
+			VCpuInfo:                      &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(2)},
+			MemoryInfo:                    &ec2types.MemoryInfo{SizeInMiB: aws.Int64(4096)},
+			EbsInfo:                       &ec2types.EbsInfo{EbsOptimizedSupport: ec2types.EbsOptimizedSupportDefault, NvmeSupport: ec2types.EbsNvmeSupportRequired},
+			NetworkInfo:                   &ec2types.NetworkInfo{MaximumNetworkInterfaces: aws.Int32(3), Ipv4AddressesPerInterface: aws.Int32(10), Ipv6AddressesPerInterface: aws.Int32(10), Ipv6Supported: aws.Bool(true)},
+			BurstablePerformanceSupported: aws.Bool(strings.HasPrefix(string(instanceType), "t")),
+		})
+	}
+	return response, nil
+}
+
+// DescribeImages registers the images looked up by exact name before describing them,
+// so that the images of the channels resolve without seeding them.
+// The image IDs are derived from the names, so that they are the same across restarts.
+func (e *ec2Emulator) DescribeImages(ctx context.Context, request *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	owner := AccountID
+	if len(request.Owners) == 1 && request.Owners[0] != "self" {
+		owner = request.Owners[0]
+	}
+
+	for _, filter := range request.Filters {
+		if aws.ToString(filter.Name) != "name" {
+			continue
+		}
+		for _, name := range filter.Values {
+			if strings.ContainsAny(name, "*?") || e.hasImage(name) {
+				continue
+			}
+			hash := sha256.Sum256([]byte(name))
+			architecture := ec2types.ArchitectureValuesX8664
+			if strings.Contains(name, "arm64") || strings.Contains(name, "aarch64") {
+				architecture = ec2types.ArchitectureValuesArm64
+			}
+			e.Images = append(e.Images, &ec2types.Image{
+				CreationDate:   aws.String("2024-01-01T00:00:00.000Z"),
+				ImageId:        aws.String("ami-" + hex.EncodeToString(hash[:])[:17]),
+				Name:           aws.String(name),
+				OwnerId:        aws.String(owner),
+				RootDeviceName: aws.String("/dev/sda1"),
+				Architecture:   architecture,
+				State:          ec2types.ImageStateAvailable,
+			})
+		}
+	}
+
+	return e.MockEC2.DescribeImages(ctx, request, optFns...)
+}
+
+func (e *ec2Emulator) hasImage(name string) bool {
+	for _, image := range e.Images {
+		if aws.ToString(image.Name) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen extracts the EC2 query protocol names from the serializers and deserializers of the AWS SDK,
+// for the types used by the operations the EC2 mock implements.
+// Unlike the other query protocols, EC2 names its parameters and elements after the
+// ec2QueryName and xmlName traits, which are not available from the SDK types.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+const sdkModule = "github.com/aws/aws-sdk-go-v2/service/ec2"
+
+var (
+	mockMethodRE = regexp.MustCompile(`^func \(m \*MockEC2\) ([A-Z]\w*)\(ctx context\.Context`)
+
+	serializeFuncRE  = regexp.MustCompile(`^func awsEc2query_serialize(?:Op)?Document(\w+)\(v \*(?:types\.)?(\w+), value query\.Value\)`)
+	serializeFieldRE = regexp.MustCompile(`^\s+if (?:len\()?v\.(\w+)`)
+	serializeKeyRE   = regexp.MustCompile(`object\.(?:Flat)?Key\("(\w+)"\)`)
+
+	deserializeFuncRE  = regexp.MustCompile(`^func awsEc2query_deserialize(?:Op)?Document(\w+)\(v \*\*(?:types\.)?(\w+), decoder smithyxml\.NodeDecoder\)`)
+	deserializeCaseRE  = regexp.MustCompile(`case strings\.EqualFold\("(\w+)", t\.Name\.Local\):`)
+	deserializeFieldRE = regexp.MustCompile(`sv\.(\w+)`)
+
+	deserializeListFuncRE = regexp.MustCompile(`^func awsEc2query_deserializeDocument(\w+)\(v \*\[\]`)
+	deserializeListCallRE = regexp.MustCompile(`awsEc2query_deserializeDocument(\w+)\(&sv\.(\w+), `)
+)
+
+// nativeOperations are the EC2 operations the emulator serves itself, rather than the mock
+var nativeOperations = []string{"DescribeAvailabilityZones", "DescribeInstanceTypes", "DescribeRegions", "DescribeReservedInstancesOfferings"}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", sdkModule).Output()
+	if err != nil {
+		return fmt.Errorf("finding %s: %w", sdkModule, err)
+	}
+	sdkDir := strings.TrimSpace(string(out))
+
+	types, err := mockTypes("../mockec2")
+	if err != nil {
+		return err
+	}
+
+	requestNames, err := parseNames(filepath.Join(sdkDir, "serializers.go"), serializeFuncRE, serializeFieldRE, serializeKeyRE, true, types)
+	if err != nil {
+		return err
+	}
+	responseNames, err := parseNames(filepath.Join(sdkDir, "deserializers.go"), deserializeFuncRE, deserializeCaseRE, deserializeFieldRE, false, types)
+	if err != nil {
+		return err
+	}
+	itemNames, err := parseItemNames(filepath.Join(sdkDir, "deserializers.go"), types)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by gen. DO NOT EDIT.
+
+package emulator
+
+`)
+	writeMap(&b, "ec2RequestNames", "maps <type>.<field> to the EC2 query parameter name, where it is not the field name", requestNames)
+	writeMap(&b, "ec2ResponseNames", "maps <type>.<field> to the EC2 response element name, where it is not the field name", responseNames)
+	writeMap(&b, "ec2ResponseItemNames", "maps <type>.<field> to the EC2 response element name of the list items, where it is not item", itemNames)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting source: %w", err)
+	}
+	return os.WriteFile("zz_generated.ec2names.go", src, 0o644)
+}
+
+// mockTypes returns the names of the types used by the operations implemented by the EC2 mock,
+// and by the operations the emulator implements natively
+func mockTypes(mockDir string) (map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(mockDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	operations := append([]string{}, nativeOperations...)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			if m := mockMethodRE.FindStringSubmatch(line); m != nil {
+				operations = append(operations, m[1])
+			}
+		}
+	}
+
+	client := reflect.TypeOf(&ec2.Client{})
+	types := make(map[string]bool)
+	for _, operation := range operations {
+		method, found := client.MethodByName(operation)
+		if !found {
+			return nil, fmt.Errorf("operation %q not found in the EC2 client", operation)
+		}
+		// The method has the receiver, context and input as parameters
+		addTypes(types, method.Type.In(2))
+		addTypes(types, method.Type.Out(0))
+	}
+	return types, nil
+}
+
+func addTypes(types map[string]bool, t reflect.Type) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || types[t.Name()] {
+		return
+	}
+	types[t.Name()] = true
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			addTypes(types, t.Field(i).Type)
+		}
+	}
+}
+
+// parseNames extracts the wire names of the fields of each type from generated SDK code.
+// Within each function, the field and the name are on two lines, in either order.
+func parseNames(file string, funcRE, firstRE, secondRE *regexp.Regexp, fieldFirst bool, types map[string]bool) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	names := make(map[string]string)
+	typeName := ""
+	first := ""
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "func ") {
+			typeName = ""
+			if m := funcRE.FindStringSubmatch(line); m != nil && m[1] == m[2] && types[m[2]] {
+				typeName = m[2]
+			}
+			first = ""
+			continue
+		}
+		if typeName == "" {
+			continue
+		}
+		if m := firstRE.FindStringSubmatch(line); m != nil {
+			first = m[1]
+			continue
+		}
+		if first == "" {
+			continue
+		}
+		if m := secondRE.FindStringSubmatch(line); m != nil {
+			field, name := first, m[1]
+			if !fieldFirst {
+				field, name = m[1], first
+			}
+			if !strings.EqualFold(field, name) {
+				names[typeName+"."+field] = name
+			}
+			first = ""
+		}
+	}
+	return names, scanner.Err()
+}
+
+// parseItemNames extracts the names of the list items of the fields of each type from the deserializers,
+// in the two passes needed to resolve the list deserializers the fields are decoded with.
+func parseItemNames(file string, types map[string]bool) (map[string]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(b), "\n")
+
+	listItems := make(map[string]string)
+	list := ""
+	for _, line := range lines {
+		if strings.HasPrefix(line, "func ") {
+			list = ""
+			if m := deserializeListFuncRE.FindStringSubmatch(line); m != nil {
+				list = m[1]
+			}
+			continue
+		}
+		if list == "" {
+			continue
+		}
+		if m := deserializeCaseRE.FindStringSubmatch(line); m != nil {
+			listItems[list] = m[1]
+			list = ""
+		}
+	}
+
+	names := make(map[string]string)
+	typeName := ""
+	for _, line := range lines {
+		if strings.HasPrefix(line, "func ") {
+			typeName = ""
+			if m := deserializeFuncRE.FindStringSubmatch(line); m != nil && m[1] == m[2] && types[m[2]] {
+				typeName = m[2]
+			}
+			continue
+		}
+		if typeName == "" {
+			continue
+		}
+		if m := deserializeListCallRE.FindStringSubmatch(line); m != nil {
+			if item, found := listItems[m[1]]; found && item != "item" {
+				names[typeName+"."+m[2]] = item
+			}
+		}
+	}
+	return names, nil
+}
+
+func writeMap(b *bytes.Buffer, name string, doc string, values map[string]string) {
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(b, "// %s %s\n", name, doc)
+	fmt.Fprintf(b, "var %s = map[string]string{\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(b, "%q: %q,\n", k, values[k])
+	}
+	b.WriteString("}\n\n")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// journal records the requests that changed the state of the mocks, one JSON document per line.
+// The mocks allocate IDs sequentially, so replaying the requests in order rebuilds the same state.
+type journal struct {
+	file *os.File
+}
+
+func (j *journal) record(req *request) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err := j.file.Write(b); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// PersistTo replays the requests recorded in the state file, if it exists,
+// and records the requests that change the state to it from now on.
+func (s *Server) PersistTo(ctx context.Context, path string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journal != nil {
+		return fmt.Errorf("state is already persisted to %q", s.journal.file.Name())
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening state file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		req := &request{}
+		if err := json.Unmarshal(scanner.Bytes(), req); err != nil {
+			f.Close()
+			return fmt.Errorf("error parsing line %d of state file %q: %w", n, path, err)
+		}
+		if resp, _ := s.handle(ctx, req); resp.status >= 300 {
+			f.Close()
+			return fmt.Errorf("error replaying line %d of state file %q: %s", n, path, resp.body)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return fmt.Errorf("error reading state file %q: %w", path, err)
+	}
+
+	s.journal = &journal{file: f}
+	return nil
+}
+
+// Close closes the state file, if the state is persisted.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.file.Close()
+	s.journal = nil
+	return err
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// handleJSON serves an AWS JSON protocol request, for the service named by the X-Amz-Target header
+func (s *Server) handleJSON(ctx context.Context, req *request) *response {
+	var mock interface{}
+	contentType := "application/x-amz-json-1.1"
+	switch {
+	case strings.HasPrefix(req.Target, targetSQS):
+		mock = s.SQS
+		contentType = "application/x-amz-json-1.0"
+	case strings.HasPrefix(req.Target, targetEventBridge):
+		mock = s.EventBridge
+	default:
+		return jsonErrorResponse(&apiError{status: http.StatusBadRequest, code: "UnknownOperationException", message: fmt.Sprintf("unknown target %q", req.Target)}, contentType)
+	}
+	action := req.Target[strings.LastIndex(req.Target, ".")+1:]
+
+	output, err := invoke(ctx, mock, action, func(input reflect.Value) error {
+		if req.Body == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(req.Body), input.Addr().Interface())
+	})
+	if err != nil {
+		return jsonErrorResponse(toAPIError(err), contentType)
+	}
+
+	b, err := json.Marshal(toJSON(output))
+	if err != nil {
+		return jsonErrorResponse(&apiError{status: http.StatusInternalServerError, code: "InternalFailure", message: err.Error()}, contentType)
+	}
+	return &response{status: http.StatusOK, contentType: contentType, body: b}
+}
+
+func jsonErrorResponse(e *apiError, contentType string) *response {
+	b, _ := json.Marshal(map[string]string{
+		"__type":  e.code,
+		"message": e.message,
+	})
+	return &response{status: e.status, contentType: contentType, body: b}
+}
+
+// toJSON converts v to the values of the AWS JSON protocol, which omits nil values and encodes timestamps as epoch seconds
+func toJSON(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toJSON(v.Elem())
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return float64(v.Interface().(time.Time).UnixMilli()) / 1000
+		}
+		m := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || f.Name == "ResultMetadata" {
+				continue
+			}
+			if value := toJSON(v.Field(i)); value != nil {
+				m[f.Name] = value
+			}
+		}
+		return m
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = toJSON(v.Index(i))
+		}
+		return items
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{})
+		for _, k := range v.MapKeys() {
+			m[k.String()] = toJSON(v.MapIndex(k))
+		}
+		return m
+
+	default:
+		return v.Interface()
+	}
+}
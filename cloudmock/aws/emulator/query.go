@@ -0,0 +1,385 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// protocol is the flavour of the XML based AWS protocols
+type protocol int
+
+const (
+	// protocolEC2 flattens lists in requests, wraps list items in <item> in responses,
+	// and names parameters and elements after the ec2QueryName and xmlName traits
+	protocolEC2 protocol = iota
+	// protocolQuery wraps list items in <member>, in both requests and responses
+	protocolQuery
+	// protocolRESTXML names list items after the list, as used by Route 53
+	protocolRESTXML
+)
+
+const timestampFormat = "2006-01-02T15:04:05.000Z"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// handleEC2 serves an EC2 query protocol request
+func (s *Server) handleEC2(ctx context.Context, action string, params url.Values, requestID string) *response {
+	ec2 := &ec2Emulator{MockEC2: s.EC2, region: s.region}
+	output, err := invoke(ctx, ec2, action, func(input reflect.Value) error {
+		return decodeParams(params, input, protocolEC2)
+	})
+	if err != nil {
+		e := toAPIError(err)
+		var b bytes.Buffer
+		b.WriteString(xml.Header)
+		b.WriteString("<Response><Errors><Error>")
+		writeElement(&b, "Code", e.code)
+		writeElement(&b, "Message", e.message)
+		b.WriteString("</Error></Errors>")
+		writeElement(&b, "RequestID", requestID)
+		b.WriteString("</Response>")
+		return &response{status: e.status, contentType: "text/xml", body: b.Bytes()}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<%sResponse xmlns="http://ec2.amazonaws.com/doc/%s/">`, action, versionEC2)
+	writeElement(&b, "requestId", requestID)
+	encodeFields(&b, output.Elem(), protocolEC2)
+	fmt.Fprintf(&b, "</%sResponse>", action)
+	return &response{status: http.StatusOK, contentType: "text/xml", body: b.Bytes()}
+}
+
+// handleQuery serves a query protocol request with the mock
+func (s *Server) handleQuery(ctx context.Context, mock interface{}, action string, params url.Values, requestID string) *response {
+	output, err := invoke(ctx, mock, action, func(input reflect.Value) error {
+		return decodeParams(params, input, protocolQuery)
+	})
+	if err != nil {
+		return queryErrorResponse(toAPIError(err), requestID)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, "<%sResponse><%sResult>", action, action)
+	encodeFields(&b, output.Elem(), protocolQuery)
+	fmt.Fprintf(&b, "</%sResult><ResponseMetadata>", action)
+	writeElement(&b, "RequestId", requestID)
+	fmt.Fprintf(&b, "</ResponseMetadata></%sResponse>", action)
+	return &response{status: http.StatusOK, contentType: "text/xml", body: b.Bytes()}
+}
+
+// queryErrorResponse builds the error response of the query and REST-XML protocols
+func queryErrorResponse(e *apiError, requestID string) *response {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString("<ErrorResponse><Error><Type>Sender</Type>")
+	writeElement(&b, "Code", e.code)
+	writeElement(&b, "Message", e.message)
+	b.WriteString("</Error>")
+	writeElement(&b, "RequestId", requestID)
+	b.WriteString("</ErrorResponse>")
+	return &response{status: e.status, contentType: "text/xml", body: b.Bytes()}
+}
+
+// decodeParams decodes the query parameters of a request into the input struct v
+func decodeParams(params url.Values, v reflect.Value, p protocol) error {
+	// AWS matches the parameter names case-sensitively, but the SDK types do not always have the exact case
+	values := make(map[string]string, len(params))
+	for k, v := range params {
+		if len(v) > 0 {
+			values[strings.ToLower(k)] = v[0]
+		}
+	}
+	_, err := decodeParam(values, "", v, p)
+	return err
+}
+
+// decodeParam decodes the parameters under key into v, returning false if there were none
+func decodeParam(values map[string]string, key string, v reflect.Value, p protocol) (bool, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		e := reflect.New(v.Type().Elem())
+		found, err := decodeParam(values, key, e.Elem(), p)
+		if found {
+			v.Set(e)
+		}
+		return found, err
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			s, found := values[strings.ToLower(key)]
+			if !found {
+				return false, nil
+			}
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return false, fmt.Errorf("invalid timestamp for %s: %w", key, err)
+			}
+			v.Set(reflect.ValueOf(t))
+			return true, nil
+		}
+		if key != "" && !hasParamsUnder(values, key) {
+			return false, nil
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Type.Kind() == reflect.Interface {
+				continue
+			}
+			name := f.Name
+			if p == protocolEC2 {
+				if n, found := ec2RequestNames[t.Name()+"."+f.Name]; found {
+					name = n
+				}
+			}
+			if key != "" {
+				name = key + "." + name
+			}
+			if _, err := decodeParam(values, name, v.Field(i), p); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, found := values[strings.ToLower(key)]
+			if !found {
+				return false, nil
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return false, fmt.Errorf("invalid base64 value for %s: %w", key, err)
+			}
+			v.SetBytes(b)
+			return true, nil
+		}
+		prefix := key
+		if p != protocolEC2 {
+			prefix += ".member"
+		}
+		found := false
+		for n := 1; ; n++ {
+			item := reflect.New(v.Type().Elem()).Elem()
+			itemFound, err := decodeParam(values, fmt.Sprintf("%s.%d", prefix, n), item, p)
+			if err != nil {
+				return false, err
+			}
+			if !itemFound {
+				break
+			}
+			v.Set(reflect.Append(v, item))
+			found = true
+		}
+		return found, nil
+
+	case reflect.Map:
+		found := false
+		for n := 1; ; n++ {
+			entry := fmt.Sprintf("%s.entry.%d", key, n)
+			k := reflect.New(v.Type().Key()).Elem()
+			keyFound, err := decodeParam(values, entry+".key", k, p)
+			if err != nil {
+				return false, err
+			}
+			if !keyFound {
+				break
+			}
+			e := reflect.New(v.Type().Elem()).Elem()
+			if _, err := decodeParam(values, entry+".value", e, p); err != nil {
+				return false, err
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(k, e)
+			found = true
+		}
+		return found, nil
+
+	default:
+		s, found := values[strings.ToLower(key)]
+		if !found {
+			return false, nil
+		}
+		if err := setScalar(v, s); err != nil {
+			return false, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return true, nil
+	}
+}
+
+// hasParamsUnder returns true if there are parameters nested under key
+func hasParamsUnder(values map[string]string, key string) bool {
+	prefix := strings.ToLower(key) + "."
+	for k := range values {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setScalar parses s into the string, boolean or numeric value v
+func setScalar(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+// encodeFields writes the exported fields of the struct v as XML elements
+func encodeFields(b *bytes.Buffer, v reflect.Value, p protocol) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Name == "ResultMetadata" {
+			continue
+		}
+		name := f.Name
+		item := ""
+		if p == protocolEC2 {
+			name = lowerFirst(f.Name)
+			if n, found := ec2ResponseNames[t.Name()+"."+f.Name]; found {
+				name = n
+			}
+			item = ec2ResponseItemNames[t.Name()+"."+f.Name]
+		}
+		encodeValue(b, name, item, v.Field(i), p)
+	}
+}
+
+// encodeValue writes v as the XML element name, omitting nil values.
+// item overrides the name of the elements of the items of a list.
+func encodeValue(b *bytes.Buffer, name string, item string, v reflect.Value, p protocol) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		encodeValue(b, name, item, v.Elem(), p)
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			writeElement(b, name, v.Interface().(time.Time).UTC().Format(timestampFormat))
+			return
+		}
+		fmt.Fprintf(b, "<%s>", name)
+		encodeFields(b, v, p)
+		fmt.Fprintf(b, "</%s>", name)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			writeElement(b, name, base64.StdEncoding.EncodeToString(v.Bytes()))
+			return
+		}
+		if item == "" {
+			switch p {
+			case protocolEC2:
+				item = "item"
+			case protocolQuery:
+				item = "member"
+			case protocolRESTXML:
+				item = strings.TrimSuffix(name, "s")
+			}
+		}
+		fmt.Fprintf(b, "<%s>", name)
+		for i := 0; i < v.Len(); i++ {
+			encodeValue(b, item, "", v.Index(i), p)
+		}
+		fmt.Fprintf(b, "</%s>", name)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		fmt.Fprintf(b, "<%s>", name)
+		for _, k := range keys {
+			b.WriteString("<entry>")
+			writeElement(b, "key", k.String())
+			encodeValue(b, "value", "", v.MapIndex(k), p)
+			b.WriteString("</entry>")
+		}
+		fmt.Fprintf(b, "</%s>", name)
+
+	case reflect.String:
+		writeElement(b, name, v.String())
+	case reflect.Bool:
+		writeElement(b, name, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeElement(b, name, strconv.FormatInt(v.Int(), 10))
+	case reflect.Float32, reflect.Float64:
+		writeElement(b, name, strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()))
+	}
+}
+
+// writeElement writes an XML element with escaped text content
+func writeElement(b *bytes.Buffer, name string, text string) {
+	fmt.Fprintf(b, "<%s>", name)
+	_ = xml.EscapeText(b, []byte(text))
+	fmt.Fprintf(b, "</%s>", name)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
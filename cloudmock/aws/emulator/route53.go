@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+)
+
+// route53PathPrefix is the prefix of the paths of the Route 53 REST API
+const route53PathPrefix = "/2013-04-01/"
+
+// handleRoute53 serves a Route 53 REST-XML request.
+// Only the operations implemented by the mock are routed; the paging parameters are ignored, as the mock returns everything at once.
+func (s *Server) handleRoute53(ctx context.Context, req *request, requestID string) *response {
+	path := strings.Trim(strings.TrimPrefix(req.Path, route53PathPrefix), "/")
+	parts := strings.Split(path, "/")
+	query, err := url.ParseQuery(req.Query)
+	if err != nil {
+		return queryErrorResponse(&apiError{status: http.StatusBadRequest, code: "InvalidInput", message: err.Error()}, requestID)
+	}
+
+	var action string
+	var decode func(input reflect.Value) error
+	switch {
+	case req.Method == http.MethodGet && path == "hostedzone":
+		action = "ListHostedZones"
+		decode = func(input reflect.Value) error { return nil }
+
+	case req.Method == http.MethodGet && path == "hostedzonesbyname":
+		action = "ListHostedZonesByName"
+		decode = func(input reflect.Value) error {
+			setStringField(input, "DNSName", query.Get("dnsname"))
+			setStringField(input, "HostedZoneId", query.Get("hostedzoneid"))
+			return nil
+		}
+
+	case req.Method == http.MethodPost && path == "hostedzone":
+		action = "CreateHostedZone"
+		decode = func(input reflect.Value) error {
+			root, err := parseXML(req.Body)
+			if err != nil {
+				return err
+			}
+			return decodeXML(root, input)
+		}
+
+	case req.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "hostedzone":
+		action = "DeleteHostedZone"
+		decode = func(input reflect.Value) error {
+			setStringField(input, "Id", parts[1])
+			return nil
+		}
+
+	case req.Method == http.MethodGet && len(parts) == 2 && parts[0] == "hostedzone":
+		action = "GetHostedZone"
+		decode = func(input reflect.Value) error {
+			setStringField(input, "Id", parts[1])
+			return nil
+		}
+
+	case len(parts) == 3 && parts[0] == "hostedzone" && parts[2] == "rrset":
+		action = "ListResourceRecordSets"
+		if req.Method == http.MethodPost {
+			action = "ChangeResourceRecordSets"
+		}
+		decode = func(input reflect.Value) error {
+			if req.Body != "" {
+				root, err := parseXML(req.Body)
+				if err != nil {
+					return err
+				}
+				if err := decodeXML(root, input); err != nil {
+					return err
+				}
+			}
+			setStringField(input, "HostedZoneId", parts[1])
+			return nil
+		}
+
+	default:
+		return queryErrorResponse(&apiError{status: http.StatusNotImplemented, code: "NotImplemented", message: fmt.Sprintf("%s %s is not implemented by the emulator", req.Method, req.Path)}, requestID)
+	}
+
+	output, err := invoke(ctx, s.Route53, action, decode)
+	if err != nil {
+		var smithyErr smithy.APIError
+		if !errors.As(err, &smithyErr) && strings.HasSuffix(err.Error(), "NOT FOUND") {
+			// The mock does not return typed errors for missing zones
+			err = &apiError{status: http.StatusNotFound, code: "NoSuchHostedZone", message: fmt.Sprintf("no hosted zone found with ID: %s", parts[len(parts)-1])}
+		}
+		return queryErrorResponse(toAPIError(err), requestID)
+	}
+
+	if field := output.Elem().FieldByName("ChangeInfo"); field.IsValid() && !field.IsNil() {
+		changeInfo := field.Interface().(*route53types.ChangeInfo)
+		// Changes are applied immediately
+		changeInfo.Id = aws.String("/change/" + requestID)
+		changeInfo.Status = route53types.ChangeStatusInsync
+		changeInfo.SubmittedAt = aws.Time(time.Now())
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<%sResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">`, action)
+	encodeFields(&b, output.Elem(), protocolRESTXML)
+	fmt.Fprintf(&b, "</%sResponse>", action)
+	return &response{status: http.StatusOK, contentType: "text/xml", body: b.Bytes()}
+}
+
+// setStringField sets the *string field of the struct v, if value is not empty
+func setStringField(v reflect.Value, field string, value string) {
+	if value == "" {
+		return
+	}
+	v.FieldByName(field).Set(reflect.ValueOf(aws.String(value)))
+}
+
+// xmlNode is an element of an XML document
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+}
+
+// parseXML parses an XML document into its root element
+func parseXML(data string) (*xmlNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing XML: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("empty XML document")
+	}
+	return root, nil
+}
+
+// decodeXML decodes the element n into v, matching child elements to fields by name
+func decodeXML(n *xmlNode, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		e := reflect.New(v.Type().Elem())
+		if err := decodeXML(n, e.Elem()); err != nil {
+			return err
+		}
+		v.Set(e)
+		return nil
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			t, err := time.Parse(time.RFC3339, strings.TrimSpace(n.text))
+			if err != nil {
+				return fmt.Errorf("invalid timestamp for %s: %w", n.name, err)
+			}
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		for _, child := range n.children {
+			f, found := v.Type().FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, child.name) })
+			if !found || !f.IsExported() || f.Type.Kind() == reflect.Interface {
+				continue
+			}
+			if err := decodeXML(child, v.FieldByIndex(f.Index)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		for _, child := range n.children {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeXML(child, item); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, item))
+		}
+		return nil
+
+	default:
+		if err := setScalar(v, strings.TrimSpace(n.text)); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", n.name, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//go:generate go run ./gen
+
+// Package emulator serves the AWS mocks over the AWS HTTP protocols,
+// so that unmodified AWS clients can be pointed at them with AWS_ENDPOINT_URL.
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/smithy-go"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockelb"
+	"k8s.io/kops/cloudmock/aws/mockelbv2"
+	"k8s.io/kops/cloudmock/aws/mockeventbridge"
+	"k8s.io/kops/cloudmock/aws/mockiam"
+	"k8s.io/kops/cloudmock/aws/mockroute53"
+	"k8s.io/kops/cloudmock/aws/mocksqs"
+)
+
+// AccountID is the account of the emulated caller
+const AccountID = "123456789012"
+
+// The query protocol services are identified by the API version of the request
+const (
+	versionAutoscaling = "2011-01-01"
+	versionEC2         = "2016-11-15"
+	versionELB         = "2012-06-01"
+	versionELBV2       = "2015-12-01"
+	versionIAM         = "2010-05-08"
+	versionSTS         = "2011-06-15"
+)
+
+// The JSON protocol services are identified by the prefix of the X-Amz-Target header
+const (
+	targetEventBridge = "AWSEvents."
+	targetSQS         = "AmazonSQS."
+)
+
+// errorCodeRE matches the errors of the mocks that start with an AWS error code, like "LoadBalancerNotFound: ..."
+var errorCodeRE = regexp.MustCompile(`^([A-Z][A-Za-z]+(?:\.[A-Z][A-Za-z]+)?): (.*)$`)
+
+// Server emulates the AWS APIs used by kOps, backed by the AWS mocks.
+type Server struct {
+	// mutex serializes the requests, so the journal records them in the order they were applied
+	mutex sync.Mutex
+
+	region    string
+	requestID int
+	journal   *journal
+
+	EC2         *mockec2.MockEC2
+	Autoscaling *mockautoscaling.MockAutoscaling
+	ELB         *mockelb.MockELB
+	ELBV2       *mockelbv2.MockELBV2
+	IAM         *mockiam.MockIAM
+	Route53     *mockroute53.MockRoute53
+	SQS         *mocksqs.MockSQS
+	EventBridge *mockeventbridge.MockEventBridge
+}
+
+var _ http.Handler = &Server{}
+
+// NewServer builds an emulator for the region, with empty mocks.
+func NewServer(region string) *Server {
+	mockEC2 := &mockec2.MockEC2{}
+	return &Server{
+		region:      region,
+		EC2:         mockEC2,
+		Autoscaling: &mockautoscaling.MockAutoscaling{},
+		ELB:         &mockelb.MockELB{},
+		ELBV2:       &mockelbv2.MockELBV2{EC2: mockEC2},
+		IAM:         &mockiam.MockIAM{},
+		Route53:     &mockroute53.MockRoute53{},
+		SQS:         &mocksqs.MockSQS{},
+		EventBridge: &mockeventbridge.MockEventBridge{},
+	}
+}
+
+// AddHostedZone creates a Route 53 hosted zone for the domain, unless a zone with that name already exists.
+// The zone is created with CreateHostedZone, so that it is recorded in the state file like the zones created by the clients.
+func (s *Server) AddHostedZone(ctx context.Context, name string, private bool) error {
+	name = strings.TrimSuffix(name, ".") + "."
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	zones, err := s.Route53.ListHostedZones(ctx, &route53.ListHostedZonesInput{})
+	if err != nil {
+		return err
+	}
+	for _, zone := range zones.HostedZones {
+		if aws.ToString(zone.Name) == name {
+			return nil
+		}
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString(`<CreateHostedZoneRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">`)
+	// The caller reference is the name, so that the zone ID is the same whenever the zone is created for the domain
+	writeElement(&body, "Name", name)
+	writeElement(&body, "CallerReference", name)
+	fmt.Fprintf(&body, "<HostedZoneConfig><PrivateZone>%t</PrivateZone></HostedZoneConfig>", private)
+	body.WriteString("</CreateHostedZoneRequest>")
+
+	resp, _ := s.apply(ctx, &request{
+		Method: http.MethodPost,
+		Path:   route53PathPrefix + "hostedzone",
+		Body:   body.String(),
+	})
+	if resp.status >= 300 {
+		return fmt.Errorf("error creating hosted zone %q: %s", name, resp.body)
+	}
+	return nil
+}
+
+// request is an AWS API call, as received over HTTP and as recorded in the journal
+type request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Target string `json:"target,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// response is the HTTP response to an AWS API call
+type response struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+// apiError is an error to be returned to the client with an AWS error code
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.code + ": " + e.message
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Target: r.Header.Get("X-Amz-Target"),
+		Body:   string(body),
+	}
+
+	s.mutex.Lock()
+	resp, requestID := s.apply(r.Context(), req)
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", resp.contentType)
+	w.Header().Set("X-Amzn-Requestid", requestID)
+	w.WriteHeader(resp.status)
+	if _, err := w.Write(resp.body); err != nil {
+		klog.Warningf("error writing response: %v", err)
+	}
+}
+
+// apply handles the request and records it to the state file, if it changed the state of the mocks.
+// The caller must hold the mutex.
+func (s *Server) apply(ctx context.Context, req *request) (*response, string) {
+	resp, requestID := s.handle(ctx, req)
+	if resp.status < 300 && s.journal != nil && isMutating(req) {
+		if err := s.journal.record(req); err != nil {
+			klog.Warningf("error recording request to the state file: %v", err)
+		}
+	}
+	return resp, requestID
+}
+
+// handle dispatches the request to the protocol of the service it is for
+func (s *Server) handle(ctx context.Context, req *request) (*response, string) {
+	s.requestID++
+	requestID := fmt.Sprintf("00000000-0000-0000-0000-%012d", s.requestID)
+
+	if strings.HasPrefix(req.Path, route53PathPrefix) {
+		return s.handleRoute53(ctx, req, requestID), requestID
+	}
+	if req.Target != "" {
+		return s.handleJSON(ctx, req), requestID
+	}
+
+	params, err := url.ParseQuery(req.Body)
+	if err != nil {
+		return queryErrorResponse(&apiError{status: http.StatusBadRequest, code: "MalformedQueryString", message: err.Error()}, requestID), requestID
+	}
+	if req.Query != "" {
+		query, err := url.ParseQuery(req.Query)
+		if err != nil {
+			return queryErrorResponse(&apiError{status: http.StatusBadRequest, code: "MalformedQueryString", message: err.Error()}, requestID), requestID
+		}
+		for k, v := range query {
+			params[k] = append(params[k], v...)
+		}
+	}
+
+	action := params.Get("Action")
+	switch params.Get("Version") {
+	case versionEC2:
+		return s.handleEC2(ctx, action, params, requestID), requestID
+	case versionAutoscaling:
+		return s.handleQuery(ctx, s.Autoscaling, action, params, requestID), requestID
+	case versionELB:
+		return s.handleQuery(ctx, s.ELB, action, params, requestID), requestID
+	case versionELBV2:
+		return s.handleQuery(ctx, s.ELBV2, action, params, requestID), requestID
+	case versionIAM:
+		return s.handleQuery(ctx, s.IAM, action, params, requestID), requestID
+	case versionSTS:
+		return s.handleQuery(ctx, &stsEmulator{}, action, params, requestID), requestID
+	default:
+		err := &apiError{status: http.StatusBadRequest, code: "InvalidAction", message: fmt.Sprintf("unsupported API version %q for action %q", params.Get("Version"), action)}
+		return queryErrorResponse(err, requestID), requestID
+	}
+}
+
+// isMutating returns true if the request changes the state of the mocks, and needs to be recorded
+func isMutating(req *request) bool {
+	if strings.HasPrefix(req.Path, route53PathPrefix) {
+		return req.Method != http.MethodGet
+	}
+
+	action := req.Target
+	if action != "" {
+		action = action[strings.LastIndex(action, ".")+1:]
+	} else {
+		params, err := url.ParseQuery(req.Body)
+		if err != nil {
+			return false
+		}
+		action = params.Get("Action")
+	}
+	for _, prefix := range []string{"Describe", "Get", "List"} {
+		if strings.HasPrefix(action, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// invoke calls the method of the mock implementing the action, with the request decoded by decode.
+// Actions that are not implemented by the mock return a NotImplemented error.
+func invoke(ctx context.Context, mock interface{}, action string, decode func(input reflect.Value) error) (output reflect.Value, err error) {
+	method := reflect.ValueOf(mock).MethodByName(action)
+	if !method.IsValid() || method.Type().NumIn() < 2 || method.Type().NumOut() != 2 {
+		return reflect.Value{}, &apiError{status: http.StatusNotImplemented, code: "NotImplemented", message: fmt.Sprintf("action %q is not implemented by the emulator", action)}
+	}
+
+	input := reflect.New(method.Type().In(1).Elem())
+	if err := decode(input.Elem()); err != nil {
+		return reflect.Value{}, &apiError{status: http.StatusBadRequest, code: "ValidationError", message: err.Error()}
+	}
+
+	// The mocks embed the client interfaces, so calling an action they don't implement panics
+	defer func() {
+		if r := recover(); r != nil {
+			klog.V(2).Infof("action %q panicked: %v", action, r)
+			err = &apiError{status: http.StatusNotImplemented, code: "NotImplemented", message: fmt.Sprintf("action %q is not implemented by the emulator", action)}
+		}
+	}()
+
+	results := method.Call([]reflect.Value{reflect.ValueOf(ctx), input})
+	if !results[1].IsNil() {
+		return reflect.Value{}, toAPIError(results[1].Interface().(error))
+	}
+	return results[0], nil
+}
+
+// toAPIError maps the errors of the mocks to AWS errors
+func toAPIError(err error) *apiError {
+	var e *apiError
+	if errors.As(err, &e) {
+		return e
+	}
+	var smithyErr smithy.APIError
+	if errors.As(err, &smithyErr) {
+		message := smithyErr.ErrorMessage()
+		if message == "" {
+			message = smithyErr.ErrorCode()
+		}
+		return &apiError{status: http.StatusBadRequest, code: smithyErr.ErrorCode(), message: message}
+	}
+	if m := errorCodeRE.FindStringSubmatch(err.Error()); m != nil {
+		return &apiError{status: http.StatusBadRequest, code: m[1], message: m[2]}
+	}
+	return &apiError{status: http.StatusBadRequest, code: "InvalidParameterValue", message: err.Error()}
+}
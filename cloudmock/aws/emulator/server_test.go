@@ -0,0 +1,416 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+func startServer(t *testing.T, s *Server) aws.Config {
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return aws.Config{
+		Region:       "us-test-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEMULATOR", "secret", ""),
+		BaseEndpoint: aws.String(ts.URL),
+		Retryer:      func() aws.Retryer { return aws.NopRetryer{} },
+	}
+}
+
+func TestEC2(t *testing.T) {
+	ctx := context.TODO()
+	cfg := startServer(t, NewServer("us-test-1"))
+	client := ec2.NewFromConfig(cfg)
+
+	created, err := client.CreateVpc(ctx, &ec2.CreateVpcInput{
+		CidrBlock: aws.String("172.20.0.0/16"),
+		TagSpecifications: []ec2types.TagSpecification{
+			{
+				ResourceType: ec2types.ResourceTypeVpc,
+				Tags: []ec2types.Tag{
+					{Key: aws.String("Name"), Value: aws.String("minimal.example.com")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating VPC: %v", err)
+	}
+	vpcID := aws.ToString(created.Vpc.VpcId)
+	if vpcID == "" {
+		t.Fatalf("expected VPC ID to be returned")
+	}
+
+	if _, err := client.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String("10.0.0.0/16")}); err != nil {
+		t.Fatalf("error creating VPC: %v", err)
+	}
+
+	described, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("tag:Name"), Values: []string{"minimal.example.com"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error describing VPCs: %v", err)
+	}
+	if len(described.Vpcs) != 1 {
+		t.Fatalf("expected 1 VPC, got %d", len(described.Vpcs))
+	}
+	vpc := described.Vpcs[0]
+	if aws.ToString(vpc.VpcId) != vpcID || aws.ToString(vpc.CidrBlock) != "172.20.0.0/16" {
+		t.Errorf("unexpected VPC %s with CIDR %s", aws.ToString(vpc.VpcId), aws.ToString(vpc.CidrBlock))
+	}
+	if len(vpc.Tags) != 1 || aws.ToString(vpc.Tags[0].Value) != "minimal.example.com" {
+		t.Errorf("unexpected VPC tags %v", vpc.Tags)
+	}
+
+	zones, err := client.DescribeAvailabilityZones(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		t.Fatalf("error describing zones: %v", err)
+	}
+	if len(zones.AvailabilityZones) != 3 || aws.ToString(zones.AvailabilityZones[0].ZoneName) != "us-test-1a" {
+		t.Errorf("unexpected zones %v", zones.AvailabilityZones)
+	}
+
+	images, err := client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []string{"099720109477"},
+		Filters: []ec2types.Filter{
+			{Name: aws.String("name"), Values: []string{"ubuntu/images/hvm-ssd-gp3/ubuntu-noble-24.04-arm64-server-20240607"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error describing images: %v", err)
+	}
+	if len(images.Images) != 1 || images.Images[0].Architecture != ec2types.ArchitectureValuesArm64 {
+		t.Errorf("unexpected images %v", images.Images)
+	}
+
+	_, err = client.DeleteVpc(ctx, &ec2.DeleteVpcInput{VpcId: aws.String("vpc-notfound")})
+	if err == nil {
+		t.Errorf("expected error deleting missing VPC")
+	}
+
+	_, err = client.CreateTransitGateway(ctx, &ec2.CreateTransitGatewayInput{})
+	if code := errorCode(err); code != "NotImplemented" {
+		t.Errorf("expected NotImplemented error, got %v", err)
+	}
+}
+
+func TestIAM(t *testing.T) {
+	ctx := context.TODO()
+	cfg := startServer(t, NewServer("us-test-1"))
+	client := iam.NewFromConfig(cfg)
+
+	_, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("nodes.minimal.example.com")})
+	var noSuchEntity *iamtypes.NoSuchEntityException
+	if !errors.As(err, &noSuchEntity) {
+		t.Fatalf("expected NoSuchEntity error, got %v", err)
+	}
+
+	_, err = client.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String("nodes.minimal.example.com"),
+		AssumeRolePolicyDocument: aws.String(`{"Version":"2012-10-17"}`),
+		Tags: []iamtypes.Tag{
+			{Key: aws.String("KubernetesCluster"), Value: aws.String("minimal.example.com")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating role: %v", err)
+	}
+
+	role, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("nodes.minimal.example.com")})
+	if err != nil {
+		t.Fatalf("error getting role: %v", err)
+	}
+	if aws.ToString(role.Role.AssumeRolePolicyDocument) != `{"Version":"2012-10-17"}` {
+		t.Errorf("unexpected policy document %q", aws.ToString(role.Role.AssumeRolePolicyDocument))
+	}
+	if len(role.Role.Tags) != 1 || aws.ToString(role.Role.Tags[0].Key) != "KubernetesCluster" {
+		t.Errorf("unexpected role tags %v", role.Role.Tags)
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		t.Fatalf("error getting caller identity: %v", err)
+	}
+	if aws.ToString(identity.Account) != AccountID {
+		t.Errorf("unexpected account %q", aws.ToString(identity.Account))
+	}
+}
+
+func TestAutoscaling(t *testing.T) {
+	ctx := context.TODO()
+	cfg := startServer(t, NewServer("us-test-1"))
+	client := autoscaling.NewFromConfig(cfg)
+
+	_, err := client.CreateAutoScalingGroup(ctx, &autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("nodes.minimal.example.com"),
+		MinSize:              aws.Int32(1),
+		MaxSize:              aws.Int32(3),
+		VPCZoneIdentifier:    aws.String("subnet-1"),
+	})
+	if err != nil {
+		t.Fatalf("error creating autoscaling group: %v", err)
+	}
+
+	groups, err := client.DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{"nodes.minimal.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("error describing autoscaling groups: %v", err)
+	}
+	if len(groups.AutoScalingGroups) != 1 {
+		t.Fatalf("expected 1 autoscaling group, got %d", len(groups.AutoScalingGroups))
+	}
+	group := groups.AutoScalingGroups[0]
+	if aws.ToInt32(group.MaxSize) != 3 || group.CreatedTime == nil {
+		t.Errorf("unexpected autoscaling group %+v", group)
+	}
+}
+
+func TestRoute53(t *testing.T) {
+	ctx := context.TODO()
+	s := NewServer("us-test-1")
+	if err := s.AddHostedZone(ctx, "example.com", false); err != nil {
+		t.Fatalf("error adding zone: %v", err)
+	}
+	cfg := startServer(t, s)
+	client := route53.NewFromConfig(cfg)
+
+	zones, err := client.ListHostedZones(ctx, &route53.ListHostedZonesInput{})
+	if err != nil {
+		t.Fatalf("error listing zones: %v", err)
+	}
+	if len(zones.HostedZones) != 1 || aws.ToString(zones.HostedZones[0].Name) != "example.com." {
+		t.Fatalf("unexpected zones %v", zones.HostedZones)
+	}
+	zoneID := zones.HostedZones[0].Id
+
+	_, err = client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: zoneID,
+		ChangeBatch: &route53types.ChangeBatch{
+			Changes: []route53types.Change{
+				{
+					Action: route53types.ChangeActionUpsert,
+					ResourceRecordSet: &route53types.ResourceRecordSet{
+						Name:            aws.String("api.minimal.example.com."),
+						Type:            route53types.RRTypeA,
+						TTL:             aws.Int64(60),
+						ResourceRecords: []route53types.ResourceRecord{{Value: aws.String("203.0.113.1")}},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error changing records: %v", err)
+	}
+
+	records, err := client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{HostedZoneId: zoneID})
+	if err != nil {
+		t.Fatalf("error listing records: %v", err)
+	}
+	if len(records.ResourceRecordSets) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records.ResourceRecordSets))
+	}
+	record := records.ResourceRecordSets[0]
+	if aws.ToInt64(record.TTL) != 60 || len(record.ResourceRecords) != 1 || aws.ToString(record.ResourceRecords[0].Value) != "203.0.113.1" {
+		t.Errorf("unexpected record %+v", record)
+	}
+
+	_, err = client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String("ZNOTFOUND")})
+	var noSuchZone *route53types.NoSuchHostedZone
+	if !errors.As(err, &noSuchZone) {
+		t.Errorf("expected NoSuchHostedZone error, got %v", err)
+	}
+
+	_, err = client.DeleteHostedZone(ctx, &route53.DeleteHostedZoneInput{Id: zoneID})
+	var notEmpty *route53types.HostedZoneNotEmpty
+	if !errors.As(err, &notEmpty) {
+		t.Errorf("expected HostedZoneNotEmpty error, got %v", err)
+	}
+}
+
+func TestPersistHostedZones(t *testing.T) {
+	ctx := context.TODO()
+	stateFile := filepath.Join(t.TempDir(), "state.jsonl")
+
+	s := NewServer("us-test-1")
+	if err := s.PersistTo(ctx, stateFile); err != nil {
+		t.Fatalf("error persisting state: %v", err)
+	}
+	for _, name := range []string{"example.com", "example.org"} {
+		if err := s.AddHostedZone(ctx, name, false); err != nil {
+			t.Fatalf("error adding zone: %v", err)
+		}
+	}
+	client := route53.NewFromConfig(startServer(t, s))
+	created, err := client.CreateHostedZone(ctx, &route53.CreateHostedZoneInput{
+		Name:            aws.String("internal.example.com"),
+		CallerReference: aws.String("internal"),
+		HostedZoneConfig: &route53types.HostedZoneConfig{
+			PrivateZone: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating zone: %v", err)
+	}
+	if aws.ToString(created.HostedZone.Name) != "internal.example.com." || !created.HostedZone.Config.PrivateZone {
+		t.Errorf("unexpected zone %+v", created.HostedZone)
+	}
+	zones, err := client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{})
+	if err != nil {
+		t.Fatalf("error listing zones: %v", err)
+	}
+	for _, zone := range zones.HostedZones {
+		if aws.ToString(zone.Name) == "example.org." {
+			if _, err := client.DeleteHostedZone(ctx, &route53.DeleteHostedZoneInput{Id: zone.Id}); err != nil {
+				t.Fatalf("error deleting zone: %v", err)
+			}
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing state file: %v", err)
+	}
+
+	restored := NewServer("us-test-1")
+	if err := restored.PersistTo(ctx, stateFile); err != nil {
+		t.Fatalf("error restoring state: %v", err)
+	}
+	defer restored.Close()
+	// Adding a zone that was restored is a no-op, as on a restart with the same --dns-zone flags
+	if err := restored.AddHostedZone(ctx, "example.com", false); err != nil {
+		t.Fatalf("error adding zone: %v", err)
+	}
+
+	restoredZones, err := route53.NewFromConfig(startServer(t, restored)).ListHostedZones(ctx, &route53.ListHostedZonesInput{})
+	if err != nil {
+		t.Fatalf("error listing zones: %v", err)
+	}
+	var names []string
+	for _, zone := range restoredZones.HostedZones {
+		names = append(names, aws.ToString(zone.Name))
+	}
+	if len(names) != 2 || names[0] != "example.com." || names[1] != "internal.example.com." {
+		t.Errorf("expected zones example.com. and internal.example.com. to be restored, got %v", names)
+	}
+}
+
+func TestSQS(t *testing.T) {
+	ctx := context.TODO()
+	cfg := startServer(t, NewServer("us-test-1"))
+	client := sqs.NewFromConfig(cfg)
+
+	_, err := client.CreateQueue(ctx, &sqs.CreateQueueInput{
+		QueueName:  aws.String("minimal-example-com-nth"),
+		Attributes: map[string]string{"MessageRetentionPeriod": "300"},
+	})
+	if err != nil {
+		t.Fatalf("error creating queue: %v", err)
+	}
+
+	queues, err := client.ListQueues(ctx, &sqs.ListQueuesInput{QueueNamePrefix: aws.String("minimal-example-com-nth")})
+	if err != nil {
+		t.Fatalf("error listing queues: %v", err)
+	}
+	if len(queues.QueueUrls) != 1 {
+		t.Fatalf("expected 1 queue, got %v", queues.QueueUrls)
+	}
+
+	attributes, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{QueueUrl: aws.String(queues.QueueUrls[0])})
+	if err != nil {
+		t.Fatalf("error getting queue attributes: %v", err)
+	}
+	if attributes.Attributes["MessageRetentionPeriod"] != "300" {
+		t.Errorf("unexpected queue attributes %v", attributes.Attributes)
+	}
+}
+
+func TestPersistTo(t *testing.T) {
+	ctx := context.TODO()
+	stateFile := filepath.Join(t.TempDir(), "state.jsonl")
+
+	s := NewServer("us-test-1")
+	if err := s.PersistTo(ctx, stateFile); err != nil {
+		t.Fatalf("error persisting state: %v", err)
+	}
+	client := ec2.NewFromConfig(startServer(t, s))
+	var vpcIDs []string
+	for _, cidr := range []string{"10.0.0.0/16", "10.1.0.0/16"} {
+		created, err := client.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String(cidr)})
+		if err != nil {
+			t.Fatalf("error creating VPC: %v", err)
+		}
+		vpcIDs = append(vpcIDs, aws.ToString(created.Vpc.VpcId))
+	}
+	if _, err := client.DeleteVpc(ctx, &ec2.DeleteVpcInput{VpcId: aws.String(vpcIDs[0])}); err != nil {
+		t.Fatalf("error deleting VPC: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing state file: %v", err)
+	}
+
+	restored := NewServer("us-test-1")
+	if err := restored.PersistTo(ctx, stateFile); err != nil {
+		t.Fatalf("error restoring state: %v", err)
+	}
+	defer restored.Close()
+	client = ec2.NewFromConfig(startServer(t, restored))
+
+	described, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+	if err != nil {
+		t.Fatalf("error describing VPCs: %v", err)
+	}
+	if len(described.Vpcs) != 1 || aws.ToString(described.Vpcs[0].VpcId) != vpcIDs[1] {
+		t.Errorf("expected only VPC %s to be restored, got %v", vpcIDs[1], described.Vpcs)
+	}
+
+	// IDs continue from the restored state
+	created, err := client.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String("10.2.0.0/16")})
+	if err != nil {
+		t.Fatalf("error creating VPC: %v", err)
+	}
+	for _, id := range vpcIDs {
+		if aws.ToString(created.Vpc.VpcId) == id {
+			t.Errorf("VPC ID %s was reused", id)
+		}
+	}
+}
+
+func errorCode(err error) string {
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
@@ -48,6 +48,8 @@ type MockEC2 struct {
 
 	Volumes map[string]*ec2types.Volume
 
+	Snapshots map[string]*ec2types.Snapshot
+
 	KeyPairs map[string]*ec2types.KeyPairInfo
 
 	Tags []*ec2types.TagDescription
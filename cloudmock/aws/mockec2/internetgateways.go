@@ -170,7 +170,7 @@ func (m *MockEC2) DetachInternetGateway(ctx context.Context, request *ec2.Detach
 			found := false
 			var newAttachments []ec2types.InternetGatewayAttachment
 			for _, a := range igw.Attachments {
-				if a.VpcId == request.VpcId {
+				if aws.ToString(a.VpcId) == aws.ToString(request.VpcId) {
 					found = true
 					continue
 				}
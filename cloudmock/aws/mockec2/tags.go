@@ -67,6 +67,8 @@ func (m *MockEC2) addTags(resourceId string, tags ...ec2types.Tag) {
 		resourceType = ec2types.ResourceTypeLaunchTemplate
 	} else if strings.HasPrefix(resourceId, "key-") {
 		resourceType = ec2types.ResourceTypeKeyPair
+	} else if strings.HasPrefix(resourceId, "snap-") {
+		resourceType = ec2types.ResourceTypeSnapshot
 	} else {
 		klog.Fatalf("Unknown resource-type in create tags: %v", resourceId)
 	}
@@ -19,7 +19,9 @@ package mockec2
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -82,13 +84,13 @@ func (m *MockEC2) DescribeVolumes(ctx context.Context, request *ec2.DescribeVolu
 
 	klog.Infof("DescribeVolumes: %v", request)
 
-	if request.VolumeIds != nil {
-		klog.Fatalf("VolumeIds")
-	}
-
 	var volumes []ec2types.Volume
 
 	for _, volume := range m.Volumes {
+		if len(request.VolumeIds) != 0 && !slices.Contains(request.VolumeIds, aws.ToString(volume.VolumeId)) {
+			continue
+		}
+
 		allFiltersMatch := true
 		for _, filter := range request.Filters {
 			match := false
@@ -138,3 +140,95 @@ func (m *MockEC2) DeleteVolume(ctx context.Context, request *ec2.DeleteVolumeInp
 
 	return &ec2.DeleteVolumeOutput{}, nil
 }
+
+func (m *MockEC2) CreateSnapshot(ctx context.Context, request *ec2.CreateSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateSnapshot: %v", request)
+
+	if request.DryRun != nil {
+		klog.Fatalf("DryRun")
+	}
+
+	volume := m.Volumes[aws.ToString(request.VolumeId)]
+	if volume == nil {
+		return nil, fmt.Errorf("Volume %q not found", aws.ToString(request.VolumeId))
+	}
+
+	n := len(m.Snapshots) + 1
+	id := fmt.Sprintf("snap-%d", n)
+
+	snapshot := &ec2types.Snapshot{
+		SnapshotId:  s(id),
+		VolumeId:    volume.VolumeId,
+		VolumeSize:  volume.Size,
+		Description: request.Description,
+		Encrypted:   volume.Encrypted,
+		KmsKeyId:    volume.KmsKeyId,
+		State:       ec2types.SnapshotStateCompleted,
+		StartTime:   aws.Time(time.Now()),
+	}
+
+	if m.Snapshots == nil {
+		m.Snapshots = make(map[string]*ec2types.Snapshot)
+	}
+	m.Snapshots[id] = snapshot
+
+	m.addTags(id, tagSpecificationsToTags(request.TagSpecifications, ec2types.ResourceTypeSnapshot)...)
+
+	return &ec2.CreateSnapshotOutput{
+		SnapshotId:  snapshot.SnapshotId,
+		VolumeId:    snapshot.VolumeId,
+		VolumeSize:  snapshot.VolumeSize,
+		Description: snapshot.Description,
+		State:       snapshot.State,
+		StartTime:   snapshot.StartTime,
+		Tags:        m.getTags(ec2types.ResourceTypeSnapshot, id),
+	}, nil
+}
+
+func (m *MockEC2) DescribeSnapshots(ctx context.Context, request *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeSnapshots: %v", request)
+
+	var snapshots []ec2types.Snapshot
+
+	for _, snapshot := range m.Snapshots {
+		if len(request.SnapshotIds) != 0 && !slices.Contains(request.SnapshotIds, aws.ToString(snapshot.SnapshotId)) {
+			continue
+		}
+
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			match := false
+			switch *filter.Name {
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") {
+					match = m.hasTag(ec2types.ResourceTypeSnapshot, *snapshot.SnapshotId, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := *snapshot
+		copy.Tags = m.getTags(ec2types.ResourceTypeSnapshot, *snapshot.SnapshotId)
+		snapshots = append(snapshots, copy)
+	}
+
+	return &ec2.DescribeSnapshotsOutput{
+		Snapshots: snapshots,
+	}, nil
+}
@@ -78,6 +78,7 @@ func (m *MockELBV2) CreateLoadBalancer(ctx context.Context, request *elbv2.Creat
 		IpAddressType:         request.IpAddressType,
 		DNSName:               aws.String(fmt.Sprintf("%v.amazonaws.com", aws.ToString(request.Name))),
 		CanonicalHostedZoneId: aws.String("HZ123456"),
+		State:                 &elbv2types.LoadBalancerState{Code: elbv2types.LoadBalancerStateEnumActive},
 	}
 	zones := make([]elbv2types.AvailabilityZone, 0)
 	vpc := "vpc-1"
@@ -18,8 +18,12 @@ package mockroute53
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
 	"k8s.io/klog/v2"
@@ -83,3 +87,78 @@ func (m *MockRoute53) ListHostedZonesByName(ctx context.Context, request *route5
 		HostedZones: zones,
 	}, nil
 }
+
+func (m *MockRoute53) CreateHostedZone(ctx context.Context, request *route53.CreateHostedZoneInput, optFns ...func(*route53.Options)) (*route53.CreateHostedZoneOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateHostedZone %v", request)
+
+	if request.Name == nil || request.CallerReference == nil {
+		return nil, &route53types.InvalidInput{Message: aws.String("Name and CallerReference are required")}
+	}
+
+	// The ID is derived from the caller reference, so that recreating the zone from the same requests gives the same ID
+	hash := sha256.Sum256([]byte(*request.CallerReference))
+	id := "/hostedzone/Z" + strings.ToUpper(hex.EncodeToString(hash[:]))[:13]
+	if m.findZone(id) != nil {
+		return nil, &route53types.HostedZoneAlreadyExists{Message: aws.String(fmt.Sprintf("a hosted zone has already been created with caller reference %q", *request.CallerReference))}
+	}
+
+	zone := &route53types.HostedZone{
+		Id:              aws.String(id),
+		Name:            aws.String(strings.TrimSuffix(*request.Name, ".") + "."),
+		CallerReference: request.CallerReference,
+		Config:          request.HostedZoneConfig,
+	}
+	if zone.Config == nil {
+		zone.Config = &route53types.HostedZoneConfig{}
+	}
+	var vpcs []*route53types.VPC
+	if request.VPC != nil {
+		zone.Config.PrivateZone = true
+		vpcs = append(vpcs, request.VPC)
+	}
+	m.Zones = append(m.Zones, &zoneInfo{
+		ID:         id,
+		hostedZone: zone,
+		vpcs:       vpcs,
+	})
+
+	copy := *zone
+	return &route53.CreateHostedZoneOutput{
+		HostedZone: &copy,
+		ChangeInfo: &route53types.ChangeInfo{},
+		VPC:        request.VPC,
+	}, nil
+}
+
+func (m *MockRoute53) DeleteHostedZone(ctx context.Context, request *route53.DeleteHostedZoneInput, optFns ...func(*route53.Options)) (*route53.DeleteHostedZoneOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteHostedZone %v", request)
+
+	if request.Id == nil {
+		return nil, &route53types.InvalidInput{Message: aws.String("Id is required")}
+	}
+	zone := m.findZone(*request.Id)
+	if zone == nil {
+		return nil, &route53types.NoSuchHostedZone{Message: aws.String(fmt.Sprintf("no hosted zone found with ID: %s", *request.Id))}
+	}
+	for _, rr := range zone.records {
+		if rr.Type != route53types.RRTypeNs && rr.Type != route53types.RRTypeSoa {
+			return nil, &route53types.HostedZoneNotEmpty{Message: aws.String("the hosted zone contains resource record sets")}
+		}
+	}
+
+	for i, z := range m.Zones {
+		if z == zone {
+			m.Zones = append(m.Zones[:i], m.Zones[i+1:]...)
+			break
+		}
+	}
+	return &route53.DeleteHostedZoneOutput{
+		ChangeInfo: &route53types.ChangeInfo{},
+	}, nil
+}
@@ -42,13 +42,18 @@ type MockClient struct {
 	instanceGroupManagerClient *instanceGroupManagerClient
 	targetPoolClient           *targetPoolClient
 
-	diskClient *diskClient
+	diskClient     *diskClient
+	snapshotClient *snapshotClient
 }
 
 var _ gce.ComputeClient = &MockClient{}
 
 // NewMockClient creates a new mock client.
 func NewMockClient(project string) *MockClient {
+	snapshotClient := newSnapshotClient()
+	diskClient := newDiskClient()
+	diskClient.snapshots = snapshotClient
+
 	return &MockClient{
 		projectClient: newProjectClient(project),
 		zoneClient:    newZoneClient(project),
@@ -68,7 +73,8 @@ func NewMockClient(project string) *MockClient {
 		instanceGroupManagerClient: newInstanceGroupManagerClient(),
 		targetPoolClient:           newTargetPoolClient(),
 
-		diskClient: newDiskClient(),
+		diskClient:     diskClient,
+		snapshotClient: snapshotClient,
 	}
 }
 
@@ -178,6 +184,10 @@ func (c *MockClient) Disks() gce.DiskClient {
 	return c.diskClient
 }
 
+func (c *MockClient) Snapshots() gce.SnapshotClient {
+	return c.snapshotClient
+}
+
 func notFoundError() error {
 	return &googleapi.Error{
 		Code: 404,
@@ -28,6 +28,8 @@ import (
 type diskClient struct {
 	// disks are disks keyed by project, zone, and disk name.
 	disks map[string]map[string]map[string]*compute.Disk
+	// snapshots receives the snapshots created of disks.
+	snapshots *snapshotClient
 	sync.Mutex
 }
 
@@ -164,3 +166,24 @@ func (c *diskClient) SetLabels(project, zone, name string, req *compute.ZoneSetL
 	disk.Labels = req.Labels
 	return nil
 }
+
+func (c *diskClient) CreateSnapshot(project, zone, name string, snapshot *compute.Snapshot) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.disks[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	disks, ok := zones[zone]
+	if !ok {
+		return nil, notFoundError()
+	}
+	disk, ok := disks[name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	snapshot.SourceDisk = disk.SelfLink
+	snapshot.DiskSizeGb = disk.SizeGb
+	c.snapshots.insert(project, snapshot)
+	return doneOperation(), nil
+}
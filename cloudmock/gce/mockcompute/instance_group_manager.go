@@ -134,6 +134,10 @@ func (c *instanceGroupManagerClient) RecreateInstances(project, zone, name, id s
 	return doneOperation(), nil
 }
 
+func (c *instanceGroupManagerClient) DeleteInstances(project, zone, name, id string) (*compute.Operation, error) {
+	return doneOperation(), nil
+}
+
 func (c *instanceGroupManagerClient) SetTargetPools(project, zone, name string, targetPools []string) (*compute.Operation, error) {
 	return doneOperation(), nil
 }
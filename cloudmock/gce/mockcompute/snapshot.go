@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+type snapshotClient struct {
+	// snapshots are snapshots keyed by project and snapshot name.
+	snapshots map[string]map[string]*compute.Snapshot
+	sync.Mutex
+}
+
+var _ gce.SnapshotClient = &snapshotClient{}
+
+func newSnapshotClient() *snapshotClient {
+	return &snapshotClient{
+		snapshots: map[string]map[string]*compute.Snapshot{},
+	}
+}
+
+func (c *snapshotClient) insert(project string, snapshot *compute.Snapshot) {
+	c.Lock()
+	defer c.Unlock()
+	snapshots, ok := c.snapshots[project]
+	if !ok {
+		snapshots = map[string]*compute.Snapshot{}
+		c.snapshots[project] = snapshots
+	}
+	snapshot.SelfLink = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/global/snapshots/%s", project, snapshot.Name)
+	snapshot.Status = "READY"
+	snapshots[snapshot.Name] = snapshot
+}
+
+func (c *snapshotClient) List(ctx context.Context, project string) ([]*compute.Snapshot, error) {
+	c.Lock()
+	defer c.Unlock()
+	snapshots, ok := c.snapshots[project]
+	if !ok {
+		return nil, nil
+	}
+	var l []*compute.Snapshot
+	for _, s := range snapshots {
+		l = append(l, s)
+	}
+	return l, nil
+}
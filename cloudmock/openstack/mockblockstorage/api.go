@@ -20,6 +20,7 @@ import (
 	"net/http/httptest"
 	"sync"
 
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	cinderv3 "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"k8s.io/kops/cloudmock/openstack"
@@ -31,6 +32,7 @@ type MockClient struct {
 	mutex sync.Mutex
 
 	volumes           map[string]cinderv3.Volume
+	snapshots         map[string]snapshots.Snapshot
 	availabilityZones map[string]availabilityzones.AvailabilityZone
 }
 
@@ -40,6 +42,7 @@ func CreateClient() *MockClient {
 	m.Reset()
 	m.SetupMux()
 	m.mockVolumes()
+	m.mockSnapshots()
 	m.mockAvailabilityZones()
 	m.Server = httptest.NewServer(m.Mux)
 	return m
@@ -48,5 +51,6 @@ func CreateClient() *MockClient {
 // Reset will empty the state of the mock data
 func (m *MockClient) Reset() {
 	m.volumes = make(map[string]cinderv3.Volume)
+	m.snapshots = make(map[string]snapshots.Snapshot)
 	m.availabilityZones = make(map[string]availabilityzones.AvailabilityZone)
 }
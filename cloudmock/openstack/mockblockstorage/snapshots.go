@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockblockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+)
+
+// snapshot is the JSON form of a snapshot; snapshots.Snapshot does not marshal its timestamps
+type snapshot struct {
+	snapshots.Snapshot
+	CreatedAt string `json:"created_at"`
+}
+
+type snapshotListResponse struct {
+	Snapshots []snapshot `json:"snapshots"`
+}
+
+type snapshotGetResponse struct {
+	Snapshot snapshot `json:"snapshot"`
+}
+
+type snapshotCreateRequest struct {
+	Snapshot snapshots.CreateOpts `json:"snapshot"`
+}
+
+func (m *MockClient) mockSnapshots() {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			m.listSnapshots(w)
+		case http.MethodPost:
+			m.createSnapshot(w, r)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+	m.Mux.HandleFunc("/snapshots", handler)
+}
+
+func (m *MockClient) listSnapshots(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+
+	resp := snapshotListResponse{
+		Snapshots: make([]snapshot, 0),
+	}
+	for _, s := range m.snapshots {
+		resp.Snapshots = append(resp.Snapshots, toSnapshotJSON(s))
+	}
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
+
+func (m *MockClient) createSnapshot(w http.ResponseWriter, r *http.Request) {
+	var create snapshotCreateRequest
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		panic("error decoding create snapshot request")
+	}
+
+	vol, ok := m.volumes[create.Snapshot.VolumeID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+
+	s := snapshots.Snapshot{
+		ID:          uuid.New().String(),
+		CreatedAt:   time.Now().UTC(),
+		Name:        create.Snapshot.Name,
+		Description: create.Snapshot.Description,
+		VolumeID:    vol.ID,
+		Status:      "available",
+		Size:        vol.Size,
+		Metadata:    create.Snapshot.Metadata,
+	}
+	m.snapshots[s.ID] = s
+
+	resp := snapshotGetResponse{
+		Snapshot: toSnapshotJSON(s),
+	}
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
+
+func toSnapshotJSON(s snapshots.Snapshot) snapshot {
+	return snapshot{
+		Snapshot:  s,
+		CreatedAt: s.CreatedAt.Format("2006-01-02T15:04:05.000000"),
+	}
+}
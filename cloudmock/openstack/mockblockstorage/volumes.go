@@ -151,6 +151,7 @@ func (m *MockClient) createVolume(w http.ResponseWriter, r *http.Request) {
 		AvailabilityZone: create.Volume.AvailabilityZone,
 		Metadata:         create.Volume.Metadata,
 		VolumeType:       create.Volume.VolumeType,
+		SnapshotID:       create.Volume.SnapshotID,
 	}
 	m.volumes[v.ID] = v
 
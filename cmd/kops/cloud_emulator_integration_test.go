@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cloudmock/aws/emulator"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// TestCloudEmulatorLifecycle creates, updates and deletes a cluster through the AWS SDK clients pointed at the cloud emulator,
+// instead of the mocks installed in awsup, so that the emulated APIs are exercised end to end.
+func TestCloudEmulatorLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.MockKopsVersion("1.21.0-alpha.1")
+
+	// The AWS clouds are cached by region, and the machine types are cached as described by the cloud;
+	// make sure the mocks of the other tests are not used, and that they don't use the emulated machine types
+	awsup.ResetAWSCloudInstances()
+	defer awsup.ResetAWSCloudInstances()
+	awsup.ResetMachineTypeInfo()
+	defer awsup.ResetMachineTypeInfo()
+
+	server := emulator.NewServer("us-test-1")
+	if err := server.AddHostedZone(ctx, "example.com", false); err != nil {
+		t.Fatalf("error creating hosted zone: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	// The environment printed by kops toolbox cloud-emulator --print-env
+	var env bytes.Buffer
+	if err := printCloudEmulatorEnv(&env, httpServer.URL, "us-test-1"); err != nil {
+		t.Fatalf("error printing environment: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(env.String()), "\n") {
+		name, value, _ := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		t.Setenv(name, value)
+	}
+
+	beforeIDs := emulatedEC2Resources(server)
+
+	clusterName := "minimal-aws.example.com"
+	var stdout bytes.Buffer
+	factory := newIntegrationTest(clusterName, "../../tests/integration/update_cluster/minimal-aws").
+		setupCluster(t, ctx, "in-v1alpha2.yaml", stdout)
+
+	// Applies the cluster, then checks that a dry-run update has no changes
+	updateEnsureNoChanges(ctx, t, factory, clusterName, stdout)
+
+	if ids := emulatedEC2Resources(server); len(ids) == len(beforeIDs) {
+		t.Fatalf("no EC2 resources were created in the emulator")
+	}
+
+	options := &DeleteClusterOptions{}
+	options.Yes = true
+	options.ClusterName = clusterName
+	if err := RunDeleteCluster(ctx, factory, &stdout, options); err != nil {
+		t.Fatalf("error running delete cluster %q: %v", clusterName, err)
+	}
+
+	if afterIDs := emulatedEC2Resources(server); !reflect.DeepEqual(afterIDs, beforeIDs) {
+		t.Fatalf("resources changed by cluster create / destroy: %v -> %v", beforeIDs, afterIDs)
+	}
+}
+
+// emulatedEC2Resources returns the sorted IDs of the EC2 resources in the emulator,
+// except the images the emulator registers when they are looked up.
+func emulatedEC2Resources(server *emulator.Server) []string {
+	var ids []string
+	for id := range server.EC2.All() {
+		if strings.HasPrefix(id, "ami-") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
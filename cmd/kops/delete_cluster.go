@@ -20,13 +20,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
@@ -45,6 +49,7 @@ type DeleteClusterOptions struct {
 	External    bool
 	Unregister  bool
 	ClusterName string
+	Keep        []string
 	wait        time.Duration
 	count       int
 	interval    time.Duration
@@ -60,6 +65,14 @@ var (
 	deleteClusterLong = templates.LongDesc(i18n.T(`
 	Deletes a Kubernetes cluster and all associated resources.  Resources include instancegroups,
 	secrets, and the state store.  There is no "UNDO" for this command.
+
+	Clusters with spec.deletionProtection set cannot be deleted until it is cleared.
+
+	The --keep option preserves some of the resources: "vpc" keeps the network of the cluster,
+	"dns" keeps its DNS records, and "etcd-volumes" snapshots the etcd volumes before deleting them.
+
+	The progress of the deletion is recorded in the state store, so an interrupted deletion
+	can be resumed by running the command again.
 	`))
 
 	deleteClusterExample = templates.Examples(i18n.T(`
@@ -67,6 +80,9 @@ var (
 	# The --yes option runs the command immediately.
 	kops delete cluster --name=k8s.cluster.site --yes
 
+	# Delete a cluster, but keep its VPC and snapshot its etcd volumes.
+	kops delete cluster --name=k8s.cluster.site --keep=vpc,etcd-volumes --yes
+
 	`))
 
 	deleteClusterShort = i18n.T("Delete a cluster.")
@@ -91,6 +107,10 @@ func NewCmdDeleteCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to delete the cluster")
 	cmd.Flags().BoolVar(&options.Unregister, "unregister", options.Unregister, "Don't delete cloud resources, just unregister the cluster")
 	cmd.Flags().BoolVar(&options.External, "external", options.External, "Delete an external cluster")
+	cmd.Flags().StringSliceVar(&options.Keep, "keep", options.Keep, "Resources to keep: "+strings.Join(resourceops.KeepFilters, ", "))
+	cmd.RegisterFlagCompletionFunc("keep", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return resourceops.KeepFilters, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	cmd.Flags().StringVar(&options.Region, "region", options.Region, "External cluster's cloud region")
 	cmd.RegisterFlagCompletionFunc("region", completeRegion)
@@ -128,6 +148,10 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+
+		if cluster.Spec.DeletionProtection {
+			return fmt.Errorf("cluster %q has deletion protection enabled; clear spec.deletionProtection with \"kops edit cluster\" to delete it", clusterName)
+		}
 	}
 
 	wouldDeleteCloudResources := false
@@ -140,6 +164,14 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 			}
 		}
 
+		manifest, keep, err := loadDeletionManifest(ctx, f, out, clusterName, cluster, options.Keep)
+		if err != nil {
+			return err
+		}
+		if err := resourceops.ValidateKeepFilters(cloud, keep); err != nil {
+			return err
+		}
+
 		klog.Info("Looking for cloud resources to delete")
 		allResources, err := resourceops.ListResources(cloud, cluster)
 		if err != nil {
@@ -154,31 +186,28 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 			clusterResources[k] = resource
 		}
 
+		keptResources := resourceops.KeepResources(clusterResources, keep)
+
 		if len(clusterResources) == 0 {
 			fmt.Fprintf(out, "No cloud resources to delete\n")
 		} else {
 			wouldDeleteCloudResources = true
 
-			t := &tables.Table{}
-			t.AddColumn("TYPE", func(r *resources.Resource) string {
-				return r.Type
-			})
-			t.AddColumn("ID", func(r *resources.Resource) string {
-				return r.ID
-			})
-			t.AddColumn("NAME", func(r *resources.Resource) string {
-				return r.Name
-			})
-			var l []*resources.Resource
-			for _, v := range clusterResources {
-				l = append(l, v)
+			if err := renderResources(clusterResources, out); err != nil {
+				return err
 			}
-
-			err := t.Render(l, out, "TYPE", "NAME", "ID")
-			if err != nil {
+		}
+		if len(keptResources) != 0 {
+			fmt.Fprintf(out, "\nResources that will be kept:\n\n")
+			if err := renderResources(keptResources, out); err != nil {
 				return err
 			}
+		}
+		if slices.Contains(keep, resourceops.KeepEtcdVolumes) {
+			fmt.Fprintf(out, "\nThe etcd volumes will be snapshotted before they are deleted.\n")
+		}
 
+		if len(clusterResources) != 0 {
 			if !options.Yes {
 				fmt.Fprintf(out, "\nMust specify --yes to delete cluster\n")
 				return nil
@@ -186,10 +215,31 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 
 			fmt.Fprintf(out, "\n")
 
-			err = resourceops.DeleteResources(cloud, clusterResources, options.count, options.interval, options.wait)
+			if err := manifest.AddResources(keptResources, resourceops.DeletionKept); err != nil {
+				return err
+			}
+			if err := manifest.AddResources(clusterResources, resourceops.DeletionPending); err != nil {
+				return err
+			}
+			if slices.Contains(keep, resourceops.KeepEtcdVolumes) {
+				if err := resourceops.SnapshotEtcdVolumes(cloud, clusterResources, manifest); err != nil {
+					return err
+				}
+			}
+
+			err = resourceops.DeleteResourcesTracked(cloud, clusterResources, options.count, options.interval, options.wait, manifest)
 			if err != nil {
 				return err
 			}
+			if err := manifest.Complete(); err != nil {
+				return err
+			}
+
+			for _, r := range manifest.Resources {
+				if r.SnapshotID != "" {
+					fmt.Fprintf(out, "Snapshot of volume %s (%s): %s\n", r.ID, r.Name, r.SnapshotID)
+				}
+			}
 		}
 	}
 
@@ -223,6 +273,54 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 	return nil
 }
 
+// loadDeletionManifest loads the manifest of an interrupted deletion of the cluster, to resume it with the same keep filters,
+// or starts a new one. The manifest is only persisted for clusters in the state store.
+func loadDeletionManifest(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, cluster *kopsapi.Cluster, keep []string) (*resourceops.DeletionManifest, []string, error) {
+	if cluster == nil {
+		return resourceops.NewDeletionManifest(nil, clusterName, keep), keep, nil
+	}
+
+	configBase, err := registry.ConfigBase(f.VFSContext(), cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifestPath := configBase.Join(registry.PathDeletionManifest)
+	manifest, err := resourceops.ReadDeletionManifest(ctx, manifestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest == nil {
+		return resourceops.NewDeletionManifest(manifestPath, clusterName, keep), keep, nil
+	}
+
+	if len(keep) == 0 {
+		keep = manifest.Keep
+	} else if !slices.Equal(sets.List(sets.New(keep...)), sets.List(sets.New(manifest.Keep...))) {
+		return nil, nil, fmt.Errorf("the deletion of cluster %q was started with --keep=%s, and must be resumed with the same filters", clusterName, strings.Join(manifest.Keep, ","))
+	}
+	fmt.Fprintf(out, "Resuming the deletion of cluster %q started at %s\n\n", clusterName, manifest.StartedAt.Format(time.RFC3339))
+	return manifest, keep, nil
+}
+
+func renderResources(resourceMap map[string]*resources.Resource, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("TYPE", func(r *resources.Resource) string {
+		return r.Type
+	})
+	t.AddColumn("ID", func(r *resources.Resource) string {
+		return r.ID
+	})
+	t.AddColumn("NAME", func(r *resources.Resource) string {
+		return r.Name
+	})
+	var l []*resources.Resource
+	for _, v := range resourceMap {
+		l = append(l, v)
+	}
+
+	return t.Render(l, out, "TYPE", "NAME", "ID")
+}
+
 func completeRegion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// TODO call into cloud provider(s) to get list of valid regions
 	return nil, cobra.ShellCompDirectiveNoFileComp
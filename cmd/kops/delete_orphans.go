@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/resources"
+	resourceops "k8s.io/kops/pkg/resources/ops"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type DeleteOrphansOptions struct {
+	Yes         bool
+	ClusterName string
+	wait        time.Duration
+	count       int
+	interval    time.Duration
+}
+
+func (o *DeleteOrphansOptions) InitDefaults() {
+	o.count = 0
+	o.interval = 10 * time.Second
+	o.wait = 10 * time.Minute
+}
+
+var (
+	deleteOrphansLong = templates.LongDesc(i18n.T(`
+	Deletes cloud resources of a cluster that are no longer part of its configuration.
+
+	Resources left behind when instance groups or load balancers are removed are considered:
+	launch templates, detached etcd volumes, security groups, target groups, and IAM instance profiles and roles.
+	Resources that are still in use by other resources of the cluster are not deleted.
+
+	Run "kops update cluster --yes" first, so that the cloud resources match the current configuration.
+	Resources created for Services and Ingresses by cloud-controller-manager or the AWS Load Balancer Controller
+	are never considered orphaned. Only AWS is (yet) supported.
+	`))
+
+	deleteOrphansExample = templates.Examples(i18n.T(`
+	# List the orphaned resources of a cluster.
+	kops delete orphans --name=k8s.cluster.site
+
+	# Delete the orphaned resources of a cluster.
+	# The --yes option runs the command immediately.
+	kops delete orphans --name=k8s.cluster.site --yes
+	`))
+
+	deleteOrphansShort = i18n.T("Delete orphaned cloud resources of a cluster.")
+)
+
+func NewCmdDeleteOrphans(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DeleteOrphansOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:               "orphans [CLUSTER]",
+		Short:             deleteOrphansShort,
+		Long:              deleteOrphansLong,
+		Example:           deleteOrphansExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDeleteOrphans(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to delete the orphaned resources")
+
+	cmd.Flags().DurationVar(&options.wait, "wait", options.wait, "Amount of time to wait for the orphaned resources to be deleted")
+	cmd.Flags().IntVar(&options.count, "count", options.count, "Number of consecutive failures to make progress deleting the orphaned resources")
+	cmd.Flags().DurationVar(&options.interval, "interval", options.interval, "Time in duration to wait between deletion attempts")
+
+	return cmd
+}
+
+func RunDeleteOrphans(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteOrphansOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required (for safety)")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		return fmt.Errorf("deleting orphaned resources is not (yet) supported on %q", cluster.GetCloudProvider())
+	}
+
+	// Build the tasks for the current configuration as a dry-run of update cluster does,
+	// so that every phase keeps its normal lifecycle and all the expected resources are in the task map.
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.Target = cloudup.TargetDryRun
+	updateOptions.ClusterName = options.ClusterName
+	updateOptions.CreateKubecfg = false
+	updateOptions.DryRunOutput = io.Discard
+	updateClusterResults, err := RunUpdateCluster(ctx, f, io.Discard, updateOptions)
+	if err != nil {
+		return err
+	}
+	cluster = updateClusterResults.Cluster
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	klog.Info("Looking for orphaned cloud resources")
+	orphans, err := resourceops.ListOrphanedResources(cloud, cluster, updateClusterResults.TaskMap)
+	if err != nil {
+		return err
+	}
+
+	if len(orphans) == 0 {
+		fmt.Fprintf(out, "No orphaned cloud resources to delete\n")
+		return nil
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("TYPE", func(r *resources.Resource) string {
+		return r.Type
+	})
+	t.AddColumn("ID", func(r *resources.Resource) string {
+		return r.ID
+	})
+	t.AddColumn("NAME", func(r *resources.Resource) string {
+		return r.Name
+	})
+	var l []*resources.Resource
+	for _, v := range orphans {
+		l = append(l, v)
+	}
+
+	if err := t.Render(l, out, "TYPE", "NAME", "ID"); err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to delete orphaned resources\n")
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n")
+
+	if err := resourceops.DeleteResources(cloud, orphans, options.count, options.interval, options.wait); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nDeleted orphaned resources of cluster: %q\n", options.ClusterName)
+	return nil
+}
@@ -41,6 +41,9 @@ var (
 	Display image and file assets used by a cluster. Displays both their canonical
 	(original) and download (local repository) locations.
 
+	If asset verification is enabled in the cluster spec, also displays whether each
+	asset has a valid signature.
+
 	When invoked with the ` + pretty.Bash("--copy") + ` flag, will copy each asset from the
 	canonical to the download location.`))
 
@@ -61,14 +64,16 @@ type GetAssetsOptions struct {
 }
 
 type Image struct {
-	Canonical string `json:"canonical"`
-	Download  string `json:"download"`
+	Canonical    string `json:"canonical"`
+	Download     string `json:"download"`
+	Verification string `json:"verification,omitempty"`
 }
 
 type File struct {
-	Canonical string `json:"canonical"`
-	Download  string `json:"download"`
-	SHA       string `json:"sha"`
+	Canonical    string `json:"canonical"`
+	Download     string `json:"download"`
+	SHA          string `json:"sha"`
+	Verification string `json:"verification,omitempty"`
 }
 
 type AssetResult struct {
@@ -118,8 +123,9 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 	seen := map[string]bool{}
 	for _, imageAsset := range updateClusterResults.ImageAssets {
 		image := Image{
-			Canonical: imageAsset.CanonicalLocation,
-			Download:  imageAsset.DownloadLocation,
+			Canonical:    imageAsset.CanonicalLocation,
+			Download:     imageAsset.DownloadLocation,
+			Verification: string(imageAsset.Verification),
 		}
 		if !seen[image.Canonical] {
 			result.Images = append(result.Images, &image)
@@ -130,9 +136,10 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 	seen = map[string]bool{}
 	for _, fileAsset := range updateClusterResults.FileAssets {
 		file := File{
-			Canonical: fileAsset.CanonicalURL.String(),
-			Download:  fileAsset.DownloadURL.String(),
-			SHA:       fileAsset.SHAValue.Hex(),
+			Canonical:    fileAsset.CanonicalURL.String(),
+			Download:     fileAsset.DownloadURL.String(),
+			SHA:          fileAsset.SHAValue.Hex(),
+			Verification: string(fileAsset.Verification),
 		}
 		if !seen[file.Canonical] {
 			result.Files = append(result.Files, &file)
@@ -186,7 +193,17 @@ func imageOutputTable(images []*Image, out io.Writer) error {
 		return i.Download
 	})
 
+	t.AddColumn("VERIFICATION", func(i *Image) string {
+		return i.Verification
+	})
+
 	columns := []string{"CANONICAL", "DOWNLOAD"}
+	for _, i := range images {
+		if i.Verification != "" {
+			columns = append(columns, "VERIFICATION")
+			break
+		}
+	}
 	return t.Render(images, out, columns...)
 }
 
@@ -203,6 +220,16 @@ func fileOutputTable(files []*File, out io.Writer) error {
 		return f.SHA
 	})
 
+	t.AddColumn("VERIFICATION", func(f *File) string {
+		return f.Verification
+	})
+
 	columns := []string{"CANONICAL", "DOWNLOAD", "SHA"}
+	for _, f := range files {
+		if f.Verification != "" {
+			columns = append(columns, "VERIFICATION")
+			break
+		}
+	}
 	return t.Render(files, out, columns...)
 }
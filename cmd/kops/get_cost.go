@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/cost"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCostLong = templates.LongDesc(i18n.T(`
+	Display the estimated monthly cost of the cloud resources of a cluster.
+
+	The cost of the instance groups, volumes, load balancers and NAT gateways
+	that the cluster spec would create is estimated from a price catalog file.
+	Instances are priced at the minimum size of their instance group,
+	split between on-demand and spot instances as the instance group would be.
+	Cost estimation is only supported on AWS.`))
+
+	getCostExample = templates.Examples(i18n.T(`
+	# Display the estimated cost of a cluster.
+	kops get cost --name k8s-cluster.example.com --price-catalog prices.yaml
+	`))
+
+	getCostShort = i18n.T(`Display the estimated cost of a cluster.`)
+)
+
+type GetCostOptions struct {
+	*GetOptions
+	// PriceCatalog is the path to the price catalog file.
+	PriceCatalog string
+}
+
+func NewCmdGetCost(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetCostOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "cost [CLUSTER]",
+		Short:             getCostShort,
+		Long:              getCostLong,
+		Example:           getCostExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetCost(cmd.Context(), f, out, &options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.PriceCatalog, "price-catalog", options.PriceCatalog, "Path to the price catalog file")
+	cmd.MarkFlagFilename("price-catalog", "yaml", "json")
+	cmd.MarkFlagRequired("price-catalog")
+
+	return cmd
+}
+
+func RunGetCost(ctx context.Context, f *util.Factory, out io.Writer, options *GetCostOptions) error {
+	catalog, err := cost.LoadCatalog(options.PriceCatalog)
+	if err != nil {
+		return err
+	}
+
+	// Build the tasks for the cluster spec, without looking at the cloud
+	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
+		Target:      cloudup.TargetDryRun,
+		GetAssets:   true,
+		ClusterName: options.ClusterName,
+	})
+	if err != nil {
+		return err
+	}
+
+	estimate, err := estimateCost(catalog, updateClusterResults.Cluster, updateClusterResults.TaskMap, nil)
+	if err != nil {
+		return err
+	}
+
+	switch options.Output {
+	case OutputTable:
+		return costOutputTable(estimate, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(estimate)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(estimate)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+// estimateCost estimates the monthly cost of the cloud resources produced by the tasks of the cluster.
+func estimateCost(catalog *cost.Catalog, cluster *kops.Cluster, tasks map[string]fi.CloudupTask, state func(fi.CloudupTask) fi.CloudupTask) (*cost.Estimate, error) {
+	if cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		return nil, fmt.Errorf("cost estimation is not (yet) supported on %q", cluster.GetCloudProvider())
+	}
+	region, err := awsup.FindRegion(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return cost.EstimateTasks(catalog, region, tasks, state)
+}
+
+func costOutputTable(estimate *cost.Estimate, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("KIND", func(i *cost.Item) string {
+		return i.Kind
+	})
+	t.AddColumn("NAME", func(i *cost.Item) string {
+		return i.Name
+	})
+	t.AddColumn("DESCRIPTION", func(i *cost.Item) string {
+		return i.Description
+	})
+	t.AddColumn("COUNT", func(i *cost.Item) string {
+		return fmt.Sprintf("%d", i.Count)
+	})
+	t.AddColumn("MONTHLY", func(i *cost.Item) string {
+		if len(i.Missing) != 0 {
+			return "unknown"
+		}
+		return cost.FormatAmount(estimate.Currency, i.MonthlyCost)
+	})
+	if err := t.Render(estimate.Items, out, "KIND", "NAME", "DESCRIPTION", "COUNT", "MONTHLY"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nEstimated total: %s/month\n", cost.FormatAmount(estimate.Currency, estimate.Total()))
+	printMissingPrices(estimate, out)
+	return nil
+}
+
+// printDryRunCost prints the estimated monthly cost of the cluster after the changes found by a dry-run,
+// and the change from the current cost, including the resources that are going to be deleted.
+func printDryRunCost(priceCatalog string, cluster *kops.Cluster, tasks map[string]fi.CloudupTask, target *fi.CloudupDryRunTarget, out io.Writer) error {
+	if cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		fmt.Fprintf(out, "Estimated cost: not available, only AWS pricing is supported\n\n")
+		return nil
+	}
+
+	catalog, err := cost.LoadCatalog(priceCatalog)
+	if err != nil {
+		return err
+	}
+
+	planned, err := estimateCost(catalog, cluster, tasks, nil)
+	if err != nil {
+		return err
+	}
+	actuals := target.Actuals()
+	current, err := estimateCost(catalog, cluster, tasks, func(task fi.CloudupTask) fi.CloudupTask {
+		if a, found := actuals[task]; found {
+			return a
+		}
+		return task
+	})
+	if err != nil {
+		return err
+	}
+	region, err := awsup.FindRegion(cluster)
+	if err != nil {
+		return err
+	}
+	deleted, err := cost.EstimateDeletions(catalog, region, target.PendingDeletions())
+	if err != nil {
+		return err
+	}
+
+	change := planned.Total() - current.Total() - deleted.Total()
+	sign := "+"
+	if change < 0 {
+		sign = "-"
+		change = -change
+	}
+	fmt.Fprintf(out, "Estimated cost: %s/month (%s%s/month)\n", cost.FormatAmount(planned.Currency, planned.Total()), sign, cost.FormatAmount(planned.Currency, change))
+	printMissingPrices(planned, out)
+	if len(deleted.Items) != 0 {
+		fmt.Fprintf(out, "Including %s/month saved by deleting %d resources\n", cost.FormatAmount(deleted.Currency, deleted.Total()), len(deleted.Items))
+		printMissingPrices(deleted, out)
+	}
+	fmt.Fprintf(out, "\n")
+	return nil
+}
+
+func printMissingPrices(estimate *cost.Estimate, out io.Writer) {
+	if missing := estimate.Missing(); len(missing) != 0 {
+		fmt.Fprintf(out, "Prices not found in the price catalog, so not included: %s\n", strings.Join(missing, ", "))
+	}
+}
@@ -64,6 +64,8 @@ type renderableCloudInstance struct {
 	InstanceGroup string   `json:"instanceGroup"`
 	MachineType   string   `json:"machineType"`
 	State         string   `json:"state"`
+	Lifecycle     string   `json:"lifecycle,omitempty"`
+	Notice        string   `json:"notice,omitempty"`
 }
 
 func NewCmdGetInstances(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
@@ -195,8 +197,14 @@ func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Write
 	t.AddColumn("STATE", func(i *cloudinstances.CloudInstance) string {
 		return string(i.State)
 	})
+	t.AddColumn("LIFECYCLE", func(i *cloudinstances.CloudInstance) string {
+		return string(i.Lifecycle)
+	})
+	t.AddColumn("NOTICE", func(i *cloudinstances.CloudInstance) string {
+		return i.InterruptionNotice()
+	})
 
-	columns := []string{"ID", "NODE-NAME", "STATUS", "ROLES", "STATE", "INTERNAL-IP", "EXTERNAL-IP", "INSTANCE-GROUP", "MACHINE-TYPE"}
+	columns := []string{"ID", "NODE-NAME", "STATUS", "ROLES", "STATE", "INTERNAL-IP", "EXTERNAL-IP", "INSTANCE-GROUP", "MACHINE-TYPE", "LIFECYCLE", "NOTICE"}
 	return t.Render(instances, out, columns...)
 }
 
@@ -228,6 +236,8 @@ func asRenderable(instances []*cloudinstances.CloudInstance) []*renderableCloudI
 			InstanceGroup: ci.CloudInstanceGroup.HumanName,
 			MachineType:   ci.MachineType,
 			State:         string(ci.State),
+			Lifecycle:     string(ci.Lifecycle),
+			Notice:        ci.InterruptionNotice(),
 		}
 		if ci.Node != nil {
 			arr[i].NodeName = ci.Node.Name
@@ -74,6 +74,8 @@ type integrationTest struct {
 	nthRebalance bool
 	// enable GCE startup script
 	startupScript bool
+	// terraformModule writes a terraform module, compared against kubernetes-module.tf
+	terraformModule bool
 }
 
 func newIntegrationTest(clusterName, srcDir string) *integrationTest {
@@ -118,6 +120,11 @@ func (i *integrationTest) withLifecycleOverrides(lco []string) *integrationTest
 	return i
 }
 
+func (i *integrationTest) withTerraformModule() *integrationTest {
+	i.terraformModule = true
+	return i
+}
+
 func (i *integrationTest) withPrivate() *integrationTest {
 	i.private = true
 	return i
@@ -248,6 +255,18 @@ func TestMinimalAWS(t *testing.T) {
 		runTestTerraformAWS(t)
 }
 
+// TestMinimalAWSTerraformModule runs the minimal configuration, writing a terraform module
+func TestMinimalAWSTerraformModule(t *testing.T) {
+	newIntegrationTest("minimal-aws.example.com", "minimal-aws").
+		withAddons(
+			awsEBSCSIAddon,
+			dnsControllerAddon,
+			awsCCMAddon,
+		).
+		withTerraformModule().
+		runTestTerraformAWS(t)
+}
+
 // TestMinimal runs the test on a minimum configuration
 func TestMinimal_v1_25(t *testing.T) {
 	newIntegrationTest("minimal.example.com", "minimal-1.25").
@@ -511,6 +530,8 @@ func TestComplex(t *testing.T) {
 			awsAuthenticatorAddon,
 		).
 		runTestTerraformAWS(t)
+	newIntegrationTest("complex.example.com", "complex").withoutSSHKey().
+		runTestCloudformation(t)
 }
 
 // TestCompress runs a test on compressing structs in nodeus.sh user-data
@@ -540,6 +561,8 @@ func TestMinimalIPv6(t *testing.T) {
 	newIntegrationTest("minimal-ipv6.example.com", "minimal-ipv6").
 		withDefaultAddons24().
 		runTestTerraformAWS(t)
+	newIntegrationTest("minimal-ipv6.example.com", "minimal-ipv6").
+		runTestCloudformation(t)
 }
 
 // TestMinimalIPv6Calico runs the test on a minimum IPv6 configuration with Calico
@@ -575,6 +598,8 @@ func TestMinimalWarmPool(t *testing.T) {
 			awsCCMAddon,
 		).
 		runTestTerraformAWS(t)
+	newIntegrationTest("minimal-warmpool.example.com", "minimal-warmpool").
+		runTestCloudformation(t)
 }
 
 // TestMinimalEtcd runs the test on a minimum configuration using custom etcd config, similar to kops create cluster minimal.example.com --zones us-west-1a
@@ -661,6 +686,8 @@ func TestPrivateCilium(t *testing.T) {
 			awsCCMAddon,
 		).
 		runTestTerraformAWS(t)
+	newIntegrationTest("privatecilium.example.com", "privatecilium").
+		runTestCloudformation(t)
 }
 
 func TestPrivateCilium2(t *testing.T) {
@@ -1079,6 +1106,8 @@ func TestMixedInstancesSpotASG(t *testing.T) {
 			awsCCMAddon,
 		).
 		runTestTerraformAWS(t)
+	newIntegrationTest("mixedinstances.example.com", "mixed_instances_spot").withZones(3).
+		runTestCloudformation(t)
 }
 
 // TestAdditionalObjects runs the test on a configuration that includes additional objects
@@ -1174,6 +1203,8 @@ func TestCustomIRSA(t *testing.T) {
 		).
 		withAddons("eks-pod-identity-webhook.addons.k8s.io-k8s-1.16").
 		runTestTerraformAWS(t)
+	newIntegrationTest("minimal.example.com", "irsa").
+		runTestCloudformation(t)
 }
 
 // TestClusterNameDigit runs a configuration with a cluster name beginning with a digit
@@ -1258,6 +1289,7 @@ func (i *integrationTest) runTest(t *testing.T, ctx context.Context, h *testutil
 		options.CreateKubecfg = false
 		options.ClusterName = i.clusterName
 		options.LifecycleOverrides = i.lifecycleOverrides
+		options.TerraformModule = i.terraformModule
 
 		_, err := RunUpdateCluster(ctx, factory, &stdout, options)
 		if err != nil {
@@ -1611,7 +1643,11 @@ func (i *integrationTest) runTestTerraformAWS(t *testing.T) {
 	}
 	expectedFilenames = append(expectedFilenames, i.expectServiceAccountRolePolicies...)
 
-	i.runTest(t, ctx, h, expectedFilenames, "", "", nil)
+	tfFileName := ""
+	if i.terraformModule {
+		tfFileName = "kubernetes-module.tf"
+	}
+	i.runTest(t, ctx, h, expectedFilenames, tfFileName, "", nil)
 }
 
 func (i *integrationTest) runTestPhase(t *testing.T, phase cloudup.Phase) {
@@ -1659,6 +1695,66 @@ func (i *integrationTest) runTestPhase(t *testing.T, phase cloudup.Phase) {
 	i.runTest(t, ctx, h, expectedFilenames, tfFileName, "", &phase)
 }
 
+func (i *integrationTest) runTestCloudformation(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	var stdout bytes.Buffer
+	i.srcDir = updateClusterTestBase + i.srcDir
+	inputYAML := "in-" + i.version + ".yaml"
+
+	factory := i.setupCluster(t, ctx, inputYAML, stdout)
+
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Target = "cloudformation"
+		options.OutDir = path.Join(h.TempDir, "out")
+		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+
+		// We don't test it here, and it adds a dependency on kubectl
+		options.CreateKubecfg = false
+		options.ClusterName = i.clusterName
+		options.LifecycleOverrides = i.lifecycleOverrides
+
+		_, err := RunUpdateCluster(ctx, factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running update cluster %q: %v", i.clusterName, err)
+		}
+	}
+
+	// Compare main files
+	{
+		files, err := os.ReadDir(path.Join(h.TempDir, "out"))
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+
+		var fileNames []string
+		for _, f := range files {
+			fileNames = append(fileNames, f.Name())
+		}
+		sort.Strings(fileNames)
+
+		actualFilenames := strings.Join(fileNames, ",")
+		expectedFilenames := "kubernetes.json"
+		if actualFilenames != expectedFilenames {
+			t.Fatalf("unexpected files.  actual=%q, expected=%q", actualFilenames, expectedFilenames)
+		}
+
+		actualCF, err := os.ReadFile(path.Join(h.TempDir, "out", "kubernetes.json"))
+		if err != nil {
+			t.Fatalf("unexpected error reading actual cloudformation output: %v", err)
+		}
+
+		golden.AssertMatchesFile(t, string(actualCF), path.Join(i.srcDir, "cloudformation.json"))
+	}
+}
+
 func (i *integrationTest) runTestTerraformGCE(t *testing.T) {
 	ctx := testcontext.ForTest(t)
 	h := testutils.NewIntegrationTestHarness(t)
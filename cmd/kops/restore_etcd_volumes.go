@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdsnapshots"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	restoreEtcdVolumesLong = templates.LongDesc(i18n.T(`
+	Restore the etcd volumes of a cluster from cloud snapshots.
+
+	kOps takes snapshots of the etcd volumes when requested with
+	--snapshot-etcd on rolling-update cluster and upgrade cluster, or
+	with --keep=etcd-volumes on delete cluster. The snapshots are tagged
+	with the cluster, the etcd member and the version of kOps.
+
+	Without --from-snapshot, the snapshots of the cluster are listed.
+
+	The volume of an etcd member must be deleted before it can be restored,
+	so that etcd-manager does not find two volumes for the same member.
+	Restored volumes are tagged as the original volumes, so are attached
+	to the control plane nodes as usual.
+
+	Snapshots are a cloud-native fallback for the etcd-manager backups, which
+	do not depend on the state store; prefer restoring from the etcd-manager
+	backups where possible.`))
+
+	restoreEtcdVolumesExample = templates.Examples(i18n.T(`
+	# List the etcd volume snapshots of a cluster.
+	kops restore etcd-volumes --name k8s-cluster.example.com
+
+	# Restore the etcd volumes from snapshots.
+	kops restore etcd-volumes --name k8s-cluster.example.com \
+		--from-snapshot snap-0123456789abcdef0,snap-0123456789abcdef1,snap-0123456789abcdef2 --yes
+	`))
+
+	restoreEtcdVolumesShort = i18n.T(`Restore the etcd volumes of a cluster from snapshots.`)
+)
+
+type RestoreEtcdVolumesOptions struct {
+	ClusterName   string
+	FromSnapshots []string
+	Yes           bool
+}
+
+func NewCmdRestoreEtcdVolumes(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RestoreEtcdVolumesOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "etcd-volumes [CLUSTER]",
+		Short:             restoreEtcdVolumesShort,
+		Long:              restoreEtcdVolumesLong,
+		Example:           restoreEtcdVolumesExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRestoreEtcdVolumes(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.FromSnapshots, "from-snapshot", options.FromSnapshots, "IDs of the snapshots to restore the etcd volumes from")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to restore the etcd volumes")
+
+	return cmd
+}
+
+func RunRestoreEtcdVolumes(ctx context.Context, f *util.Factory, out io.Writer, options *RestoreEtcdVolumesOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	snapshotter, err := etcdsnapshots.NewSnapshotter(cloud)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := snapshotter.ListSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(options.FromSnapshots) == 0 {
+		if len(snapshots) == 0 {
+			fmt.Fprintf(out, "No etcd volume snapshots found\n")
+			return nil
+		}
+		return printEtcdSnapshots(out, snapshots)
+	}
+
+	var restoring []*etcdsnapshots.Snapshot
+	for _, id := range options.FromSnapshots {
+		i := slices.IndexFunc(snapshots, func(s *etcdsnapshots.Snapshot) bool {
+			return s.ID == id
+		})
+		if i < 0 {
+			return fmt.Errorf("etcd volume snapshot %q not found for cluster %q", id, cluster.Name)
+		}
+		restoring = append(restoring, snapshots[i])
+	}
+
+	if err := printEtcdSnapshots(out, restoring); err != nil {
+		return err
+	}
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to restore the etcd volumes\n")
+		return nil
+	}
+
+	volumeIDs, err := etcdsnapshots.RestoreVolumes(ctx, cloud, cluster, restoring)
+	for _, volumeID := range volumeIDs {
+		fmt.Fprintf(out, "Restored volume %s\n", volumeID)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nRestored the etcd volumes; the control plane nodes must be replaced to attach them.\n")
+	return nil
+}
+
+// printEtcdSnapshots prints a table of the etcd volume snapshots.
+func printEtcdSnapshots(out io.Writer, snapshots []*etcdsnapshots.Snapshot) error {
+	t := &tables.Table{}
+	t.AddColumn("ID", func(s *etcdsnapshots.Snapshot) string {
+		return s.ID
+	})
+	t.AddColumn("ETCD", func(s *etcdsnapshots.Snapshot) string {
+		return s.EtcdCluster
+	})
+	t.AddColumn("MEMBER", func(s *etcdsnapshots.Snapshot) string {
+		return s.Member
+	})
+	t.AddColumn("VOLUME", func(s *etcdsnapshots.Snapshot) string {
+		return s.VolumeID
+	})
+	t.AddColumn("ZONE", func(s *etcdsnapshots.Snapshot) string {
+		return s.Zone
+	})
+	t.AddColumn("REASON", func(s *etcdsnapshots.Snapshot) string {
+		return s.Reason
+	})
+	t.AddColumn("KOPS", func(s *etcdsnapshots.Snapshot) string {
+		return s.KopsVersion
+	})
+	t.AddColumn("CREATED", func(s *etcdsnapshots.Snapshot) string {
+		if s.CreatedAt.IsZero() {
+			return ""
+		}
+		return s.CreatedAt.UTC().Format(time.RFC3339)
+	})
+	return t.Render(snapshots, out, "ID", "ETCD", "MEMBER", "VOLUME", "ZONE", "REASON", "KOPS", "CREATED")
+}
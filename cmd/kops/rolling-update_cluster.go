@@ -37,6 +37,7 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdsnapshots"
 	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/pretty"
 	"k8s.io/kops/pkg/validation"
@@ -52,7 +53,10 @@ var (
 
 	To perform a rolling update, you need to update the cloud resources first with the command
 	` + pretty.Bash("kops update cluster --yes") + `. Nodes may be additionally marked for update by placing a
-	` + pretty.Bash("kops.k8s.io/needs-update") + ` annotation on them.
+	` + pretty.Bash("kops.k8s.io/needs-update") + ` annotation on them, and nodes older than the
+	` + pretty.Bash("maxInstanceLifetime") + ` of their instance group are always marked for update.
+	On AWS, spot instances whose node was tainted by the Node Termination Handler with an interruption notice
+	or rebalance recommendation are replaced too. Notices are not detected on other clouds, such as GCE preemption.
 
 	If rolling-update does not report that the cluster needs to be updated, you can force the cluster to be
 	updated with the --force flag.  Rolling update drains and validates the cluster by default.  A cluster is
@@ -89,6 +93,11 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Snapshot the etcd volumes of the k8s-cluster.example.com kOps cluster
+		# before updating its control plane nodes.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --snapshot-etcd
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -134,6 +143,9 @@ type RollingUpdateOptions struct {
 	// Interactive rolling-update prompts user to continue after each instances is updated.
 	Interactive bool
 
+	// SnapshotEtcd snapshots the etcd volumes before control plane nodes are updated.
+	SnapshotEtcd bool
+
 	ClusterName string
 
 	// InstanceGroups is the list of instance groups to rolling-update;
@@ -204,6 +216,7 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.BastionInterval, "bastion-interval", options.BastionInterval, "Time to wait between restarting bastions")
 	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
 	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
+	cmd.Flags().BoolVar(&options.SnapshotEtcd, "snapshot-etcd", options.SnapshotEtcd, "Snapshot the etcd volumes before updating control plane nodes")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to update (defaults to all if not specified)")
 	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(f, &options.InstanceGroups, &options.InstanceGroupRoles))
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
@@ -432,7 +445,7 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 
 	needUpdate := false
 	for _, group := range groups {
-		if len(group.NeedUpdate) != 0 {
+		if len(group.NeedUpdate) != 0 || len(group.NoticedSpotInstances()) != 0 {
 			needUpdate = true
 		}
 	}
@@ -456,6 +469,24 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 	}
 	d.ClusterValidator = clusterValidator
 
+	if options.SnapshotEtcd {
+		updatingControlPlane := false
+		for _, group := range groups {
+			if group.InstanceGroup.IsControlPlane() && (len(group.NeedUpdate) != 0 || options.Force) {
+				updatingControlPlane = true
+			}
+		}
+		if updatingControlPlane {
+			snapshots, err := etcdsnapshots.SnapshotCluster(ctx, cloud, cluster, etcdsnapshots.ReasonRollingUpdate)
+			if err != nil {
+				return fmt.Errorf("error snapshotting etcd volumes: %w", err)
+			}
+			if err := printEtcdSnapshots(out, snapshots); err != nil {
+				return err
+			}
+		}
+	}
+
 	return d.RollingUpdate(groups, list)
 }
 
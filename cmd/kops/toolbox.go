@@ -32,11 +32,13 @@ func NewCmdToolbox(f commandutils.Factory, out io.Writer) *cobra.Command {
 		Short: toolboxShort,
 	}
 
+	cmd.AddCommand(NewCmdToolboxAdopt(out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
 	cmd.AddCommand(NewCmdToolboxEnroll(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
 	cmd.AddCommand(NewCmdToolboxInstanceSelector(f, out))
 	cmd.AddCommand(NewCmdToolboxAddons(out))
+	cmd.AddCommand(NewCmdToolboxCloudEmulator(out))
 
 	return cmd
 }
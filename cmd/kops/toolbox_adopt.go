@@ -0,0 +1,331 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/pkg/adopt"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxAdoptLong = templates.LongDesc(i18n.T(`
+	Inspects an existing VPC or network, its subnets, NAT gateways, route tables and
+	security groups, and writes a cluster spec that creates a cluster in them as shared resources.
+
+	Subnets routed through an internet gateway become public (or utility) subnets; the others become
+	private subnets, with the NAT gateway, NAT instance or transit gateway of their default route as egress,
+	or External egress if kOps cannot reuse it.
+	On AWS, the security groups are added to all the instance groups, and the load balancers and
+	target groups are attached to the node instance groups.
+
+	Unless --validate=false, the spec is checked by running the network and security tasks in dry-run,
+	with the adopted resources set to the ExistsAndValidates lifecycle. Nothing is written to the state store:
+	review the spec and create the cluster with 'kops create -f'.`))
+
+	toolboxAdoptExample = templates.Examples(i18n.T(`
+	# Adopt an AWS VPC with private subnets behind NAT gateways and public utility subnets
+	kops toolbox adopt --name k8s-cluster.example.com --cloud aws --region us-east-1 \
+		--subnets subnet-1a2b3c4d,subnet-2b3c4d5e,subnet-3c4d5e6f,subnet-4d5e6f7a \
+		--security-groups sg-1a2b3c4d \
+		--target-groups arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/ingress/0123456789abcdef \
+		> cluster.yaml
+	kops create -f cluster.yaml
+
+	# Adopt a GCE network and subnet
+	kops toolbox adopt --name k8s-cluster.k8s.local --cloud gce --project my-project --zones us-central1-a \
+		--network shared --subnets shared-us-central1
+	`))
+
+	toolboxAdoptShort = i18n.T(`Build a cluster spec from existing network infrastructure`)
+)
+
+type ToolboxAdoptOptions struct {
+	ClusterName       string
+	CloudProvider     string
+	Region            string
+	Project           string
+	Zones             []string
+	KubernetesVersion string
+
+	adopt.Options
+
+	Output   string
+	Validate bool
+}
+
+func (o *ToolboxAdoptOptions) InitDefaults() {
+	o.Output = OutputYaml
+	o.Validate = true
+}
+
+func NewCmdToolboxAdopt(out io.Writer) *cobra.Command {
+	options := &ToolboxAdoptOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "adopt",
+		Short:   toolboxAdoptShort,
+		Long:    toolboxAdoptLong,
+		Example: toolboxAdoptExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			return RunToolboxAdopt(cmd.Context(), out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.CloudProvider, "cloud", options.CloudProvider, "Cloud provider of the infrastructure.  One of aws or gce")
+	cmd.RegisterFlagCompletionFunc("cloud", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(kops.CloudProviderAWS), string(kops.CloudProviderGCE)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.Region, "region", options.Region, "AWS region of the infrastructure; defaults to the region of --zones")
+	cmd.RegisterFlagCompletionFunc("region", cobra.NoFileCompletions)
+	cmd.Flags().StringVar(&options.Project, "project", options.Project, "GCE project of the infrastructure")
+	cmd.RegisterFlagCompletionFunc("project", completeProject)
+	cmd.Flags().StringSliceVar(&options.Zones, "zones", options.Zones, "Zones in which to run the cluster; required on GCE, as its subnets are regional")
+	cmd.RegisterFlagCompletionFunc("zones", cobra.NoFileCompletions)
+	cmd.Flags().StringVar(&options.NetworkID, "network", options.NetworkID, "ID of the VPC (AWS) or name of the network (GCE); on AWS it defaults to the VPC of the subnets")
+	cmd.RegisterFlagCompletionFunc("network", completeNetworkID)
+	cmd.Flags().StringSliceVar(&options.SubnetIDs, "subnets", options.SubnetIDs, "IDs (AWS) or names (GCE) of the subnets")
+	cmd.RegisterFlagCompletionFunc("subnets", cobra.NoFileCompletions)
+	cmd.Flags().StringSliceVar(&options.SecurityGroupIDs, "security-groups", options.SecurityGroupIDs, "IDs of security groups to add to all the instance groups (AWS only)")
+	cmd.RegisterFlagCompletionFunc("security-groups", completeSecurityGroup)
+	cmd.Flags().StringSliceVar(&options.LoadBalancerNames, "load-balancers", options.LoadBalancerNames, "Names of classic load balancers to attach to the node instance groups (AWS only)")
+	cmd.RegisterFlagCompletionFunc("load-balancers", cobra.NoFileCompletions)
+	cmd.Flags().StringSliceVar(&options.TargetGroupARNs, "target-groups", options.TargetGroupARNs, "ARNs of target groups to attach to the node instance groups (AWS only)")
+	cmd.RegisterFlagCompletionFunc("target-groups", cobra.NoFileCompletions)
+	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", options.KubernetesVersion, "Version of Kubernetes to run (defaults to version in channel)")
+	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+	cmd.Flags().BoolVar(&options.Validate, "validate", options.Validate, "Validate the spec against the infrastructure by running the network tasks in dry-run")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format.  One of json or yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func RunToolboxAdopt(ctx context.Context, out io.Writer, options *ToolboxAdoptOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.Output != OutputYaml && options.Output != OutputJSON {
+		return fmt.Errorf("unsupported output type %q", options.Output)
+	}
+
+	var network *adopt.Network
+	switch kops.CloudProviderID(options.CloudProvider) {
+	case kops.CloudProviderAWS:
+		region := options.Region
+		if region == "" && len(options.Zones) != 0 {
+			var err error
+			region, err = awsup.ZoneToRegion(options.Zones[0])
+			if err != nil {
+				return err
+			}
+		}
+		if region == "" {
+			return fmt.Errorf("--region or --zones is required for AWS")
+		}
+		cloud, err := awsup.NewAWSCloud(region, nil)
+		if err != nil {
+			return err
+		}
+		network, err = adopt.InspectAWS(ctx, cloud, &options.Options)
+		if err != nil {
+			return err
+		}
+	case kops.CloudProviderGCE:
+		if len(options.Zones) == 0 {
+			return fmt.Errorf("--zones is required for GCE")
+		}
+		region, err := gce.ZoneToRegion(options.Zones[0])
+		if err != nil {
+			return err
+		}
+		if options.Project == "" {
+			options.Project, err = gce.DefaultProject()
+			if err != nil {
+				return err
+			}
+		}
+		cloud, err := gce.NewGCECloud(region, options.Project, nil)
+		if err != nil {
+			return err
+		}
+		network, err = adopt.InspectGCE(ctx, cloud, &options.Options)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--cloud must be one of %q or %q", kops.CloudProviderAWS, kops.CloudProviderGCE)
+	}
+	for _, warning := range network.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	// The cluster is built in an in-memory state store, so that nothing is written before the spec has been reviewed
+	vfsContext := vfs.Context
+	vfsContext.ResetMemfsContext(true)
+	basePath, err := vfsContext.BuildVfsPath("memfs://adopt")
+	if err != nil {
+		return err
+	}
+	clientset := vfsclientset.NewVFSClientset(vfsContext, basePath)
+
+	clusterOptions := &cloudup.NewClusterOptions{}
+	clusterOptions.InitDefaults()
+	clusterOptions.ClusterName = options.ClusterName
+	clusterOptions.CloudProvider = options.CloudProvider
+	clusterOptions.Project = options.Project
+	clusterOptions.Zones = options.Zones
+	clusterOptions.KubernetesVersion = options.KubernetesVersion
+	network.BuildClusterOptions(clusterOptions)
+
+	clusterResult, err := cloudup.NewCluster(clusterOptions, clientset)
+	if err != nil {
+		return err
+	}
+	cluster := clusterResult.Cluster
+	instanceGroups := clusterResult.InstanceGroups
+	if err := network.Apply(cluster, instanceGroups); err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	if err := cloudup.PerformAssignments(cluster, clientset.VFSContext(), cloud); err != nil {
+		return fmt.Errorf("error populating configuration: %w", err)
+	}
+	if err := validation.DeepValidate(cluster, instanceGroups, false, clientset.VFSContext(), nil); err != nil {
+		return err
+	}
+
+	if options.Validate {
+		if err := validateAdoptedCluster(ctx, clientset, cloud, network, cluster, instanceGroups); err != nil {
+			return err
+		}
+	}
+
+	// The config base is that of the in-memory state store; kops create sets the real one
+	cluster.Spec.ConfigStore = kops.ConfigStoreSpec{}
+
+	var obj []runtime.Object
+	obj = append(obj, cluster)
+	for _, group := range instanceGroups {
+		// Cluster name is not populated, and we need it
+		group.ObjectMeta.Labels = map[string]string{
+			kops.LabelClusterName: cluster.ObjectMeta.Name,
+		}
+		obj = append(obj, group)
+	}
+	switch options.Output {
+	case OutputYaml:
+		if err := fullOutputYAML(out, obj...); err != nil {
+			return fmt.Errorf("error writing cluster yaml to stdout: %w", err)
+		}
+	case OutputJSON:
+		if err := fullOutputJSON(out, true, obj...); err != nil {
+			return fmt.Errorf("error writing cluster json to stdout: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateAdoptedCluster runs the network and security tasks for the cluster in dry-run, with the adopted resources
+// set to ExistsAndValidates, so that any mismatch between the spec and the infrastructure is reported.
+func validateAdoptedCluster(ctx context.Context, clientset simple.Clientset, cloud fi.Cloud, network *adopt.Network, cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) error {
+	lifecycleOverrides, err := adopt.LifecycleOverrides(cluster.GetCloudProvider())
+	if err != nil {
+		return err
+	}
+
+	// The overrides for the adopted NAT gateways and security groups are only set on the validated copy of the spec
+	cluster = cluster.DeepCopy()
+	cluster.Spec.LifecycleOverrides = append(cluster.Spec.LifecycleOverrides, network.LifecycleOverrideSpecs()...)
+
+	if err := registry.CreateClusterConfig(ctx, clientset, cluster, instanceGroups, nil); err != nil {
+		return fmt.Errorf("error writing configuration: %w", err)
+	}
+	cluster, err = clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	runTasksOptions := &fi.RunTasksOptions{}
+	runTasksOptions.InitDefaults()
+	// Validation failures are not retryable, so there is no point waiting long
+	runTasksOptions.MaxTaskDuration = time.Minute
+
+	names := make([]string, 0, len(lifecycleOverrides))
+	for name := range lifecycleOverrides {
+		names = append(names, name)
+	}
+	for _, override := range cluster.Spec.LifecycleOverrides {
+		if !slices.Contains(names, override.Task) {
+			names = append(names, override.Task)
+		}
+	}
+	sort.Strings(names)
+
+	// The security groups are only built in the security phase, which expects the network resources to exist
+	for _, phase := range []cloudup.Phase{cloudup.PhaseNetwork, cloudup.PhaseSecurity} {
+		var report bytes.Buffer
+		applyCmd := &cloudup.ApplyClusterCmd{
+			Cloud:              cloud,
+			Clientset:          clientset,
+			Cluster:            cluster,
+			DryRun:             true,
+			DryRunOutput:       &report,
+			RunTasksOptions:    runTasksOptions,
+			OutDir:             "out",
+			Phase:              phase,
+			TargetName:         cloudup.TargetDryRun,
+			LifecycleOverrides: lifecycleOverrides,
+			DeletionProcessing: fi.DeletionProcessingModeIgnore,
+		}
+		if _, err := applyCmd.Run(ctx); err != nil {
+			report.WriteTo(os.Stderr)
+			return fmt.Errorf("the cluster spec does not match the existing %v resources: %w", names, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cloudmock/aws/emulator"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxCloudEmulatorLong = templates.LongDesc(i18n.T(`
+	Serves emulated AWS APIs, backed by the in-memory mocks used by the kOps tests.
+
+	AWS clients are pointed at the emulator with AWS_ENDPOINT_URL, so that clusters can be
+	created, updated and deleted without an AWS account. The emulator does not launch any
+	instances, so the clusters never become ready; it is intended for testing cluster specs
+	and the cloud resources kOps creates for them.
+
+	The changes made through the emulator are recorded to the state file, if set, and
+	restored when the emulator is restarted with the same state file.`))
+
+	toolboxCloudEmulatorExample = templates.Examples(i18n.T(`
+	# Start the emulator, keeping its state across restarts
+	kops toolbox cloud-emulator --state-file emulator.jsonl
+
+	# In another shell, use the emulator with a local state store
+	eval $(kops toolbox cloud-emulator --print-env)
+	kops create cluster --cloud aws --state file:///tmp/kops --name minimal.k8s.local --zones us-east-1a
+	kops update cluster --state file:///tmp/kops --name minimal.k8s.local --yes
+	`))
+
+	toolboxCloudEmulatorShort = i18n.T(`Serve emulated AWS APIs for local testing`)
+)
+
+type ToolboxCloudEmulatorOptions struct {
+	Listen    string
+	Region    string
+	StateFile string
+	DNSZones  []string
+	PrintEnv  bool
+}
+
+func (o *ToolboxCloudEmulatorOptions) InitDefaults() {
+	o.Listen = "127.0.0.1:4566"
+	o.Region = "us-east-1"
+}
+
+func NewCmdToolboxCloudEmulator(out io.Writer) *cobra.Command {
+	options := &ToolboxCloudEmulatorOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "cloud-emulator",
+		Short:   toolboxCloudEmulatorShort,
+		Long:    toolboxCloudEmulatorLong,
+		Example: toolboxCloudEmulatorExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxCloudEmulator(cmd.Context(), out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Listen, "listen", options.Listen, "Address to serve the emulated AWS APIs on")
+	cmd.Flags().StringVar(&options.Region, "region", options.Region, "AWS region to emulate")
+	cmd.Flags().StringVar(&options.StateFile, "state-file", options.StateFile, "File to persist the emulated resources to")
+	cmd.MarkFlagFilename("state-file")
+	cmd.Flags().StringSliceVar(&options.DNSZones, "dns-zone", options.DNSZones, "Route 53 hosted zones to create")
+	cmd.Flags().BoolVar(&options.PrintEnv, "print-env", options.PrintEnv, "Print the environment variables to use the emulator, without starting it")
+
+	return cmd
+}
+
+func RunToolboxCloudEmulator(ctx context.Context, out io.Writer, options *ToolboxCloudEmulatorOptions) error {
+	endpoint := "http://" + options.Listen
+	if options.PrintEnv {
+		return printCloudEmulatorEnv(out, endpoint, options.Region)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := emulator.NewServer(options.Region)
+	if options.StateFile != "" {
+		if err := server.PersistTo(ctx, options.StateFile); err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+	for _, zone := range options.DNSZones {
+		if err := server.AddHostedZone(ctx, zone, false); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", options.Listen)
+	if err != nil {
+		return fmt.Errorf("error listening on %q: %w", options.Listen, err)
+	}
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			klog.Warningf("error shutting down emulator: %v", err)
+		}
+	}()
+
+	fmt.Fprintf(out, "Serving emulated AWS APIs on %s; to use them:\n\n", listener.Addr())
+	if err := printCloudEmulatorEnv(out, "http://"+listener.Addr().String(), options.Region); err != nil {
+		return err
+	}
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func printCloudEmulatorEnv(out io.Writer, endpoint string, region string) error {
+	// The emulator does not check the signatures of the requests, but the clients need credentials to sign them.
+	// The instance metadata service is disabled, as the clients would otherwise retry it on every run.
+	_, err := fmt.Fprintf(out, "export AWS_ENDPOINT_URL=%s\nexport AWS_REGION=%s\nexport AWS_ACCESS_KEY_ID=%s\nexport AWS_SECRET_ACCESS_KEY=%s\nexport AWS_EC2_METADATA_DISABLED=true\n",
+		endpoint, region, "AKIDEMULATOR", "emulator")
+	return err
+}
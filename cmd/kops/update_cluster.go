@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -86,6 +88,19 @@ type UpdateClusterOptions struct {
 	// The goal is that the cluster can keep running even during more disruptive
 	// infrastructure changes.
 	Prune bool
+
+	// TerraformImportExisting generates terraform import blocks for cloud resources that already exist,
+	// so that a cluster created with the direct target can be adopted by terraform.
+	TerraformImportExisting bool
+
+	// TerraformModule writes a reusable terraform module, with environment-specific values exposed as variables.
+	TerraformModule bool
+
+	// PriceCatalog is the path to a price catalog file, used to estimate the cost of the changes in a dry-run.
+	PriceCatalog string
+
+	// DryRunOutput is where the dry-run target reports the changes; it defaults to stdout.
+	DryRunOutput io.Writer
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -120,7 +135,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation")
 	cmd.RegisterFlagCompletionFunc("target", completeUpdateClusterTarget(f, options))
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
@@ -142,6 +157,10 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("lifecycle-overrides", completeLifecycleOverrides)
 
 	cmd.Flags().BoolVar(&options.Prune, "prune", options.Prune, "Delete old revisions of cloud resources that were needed during an upgrade")
+	cmd.Flags().BoolVar(&options.TerraformImportExisting, "terraform-import-existing", options.TerraformImportExisting, "Generate terraform import blocks for cloud resources that already exist (terraform target only)")
+	cmd.Flags().BoolVar(&options.TerraformModule, "terraform-module", options.TerraformModule, "Write a reusable terraform module with environment-specific values as variables (terraform target only)")
+	cmd.Flags().StringVar(&options.PriceCatalog, "price-catalog", options.PriceCatalog, "Path to a price catalog file, to estimate the cost of the changes in dry-run mode (AWS only)")
+	cmd.MarkFlagFilename("price-catalog", "yaml", "json")
 
 	return cmd
 }
@@ -201,6 +220,8 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
+		} else if c.Target == cloudup.TargetCloudformation {
+			c.OutDir = "out/cloudformation"
 		} else {
 			c.OutDir = "out"
 		}
@@ -291,18 +312,21 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	}
 
 	applyCmd := &cloudup.ApplyClusterCmd{
-		Cloud:              cloud,
-		Clientset:          clientset,
-		Cluster:            cluster,
-		DryRun:             isDryrun,
-		AllowKopsDowngrade: c.AllowKopsDowngrade,
-		RunTasksOptions:    &c.RunTasksOptions,
-		OutDir:             c.OutDir,
-		Phase:              phase,
-		TargetName:         targetName,
-		LifecycleOverrides: lifecycleOverrideMap,
-		GetAssets:          c.GetAssets,
-		DeletionProcessing: deletionProcessing,
+		Cloud:                   cloud,
+		Clientset:               clientset,
+		Cluster:                 cluster,
+		DryRun:                  isDryrun,
+		AllowKopsDowngrade:      c.AllowKopsDowngrade,
+		RunTasksOptions:         &c.RunTasksOptions,
+		OutDir:                  c.OutDir,
+		Phase:                   phase,
+		TargetName:              targetName,
+		LifecycleOverrides:      lifecycleOverrideMap,
+		GetAssets:               c.GetAssets,
+		DeletionProcessing:      deletionProcessing,
+		TerraformImportExisting: c.TerraformImportExisting,
+		TerraformModule:         c.TerraformModule,
+		DryRunOutput:            c.DryRunOutput,
 	}
 
 	applyResults, err := applyCmd.Run(ctx)
@@ -318,6 +342,11 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.CloudupDryRunTarget)
+		if c.PriceCatalog != "" {
+			if err := printDryRunCost(c.PriceCatalog, cluster, applyCmd.TaskMap, target, out); err != nil {
+				return results, err
+			}
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -379,6 +408,15 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 				fmt.Fprintf(sb, "   terraform apply\n")
 				fmt.Fprintf(sb, "\n")
 			}
+		} else if c.Target == cloudup.TargetCloudformation {
+			fmt.Fprintf(sb, "\n")
+			fmt.Fprintf(sb, "CloudFormation template has been placed into %s\n", filepath.Join(c.OutDir, cloudformation.TemplateFileName))
+
+			if firstRun {
+				fmt.Fprintf(sb, "Run this command to apply the configuration:\n")
+				fmt.Fprintf(sb, "   aws cloudformation deploy --template-file %s --stack-name <stack-name> --capabilities CAPABILITY_NAMED_IAM\n", filepath.Join(c.OutDir, cloudformation.TemplateFileName))
+				fmt.Fprintf(sb, "\n")
+			}
 		} else if firstRun {
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Cluster is starting.  It should be ready in a few minutes.\n")
@@ -485,6 +523,7 @@ func completeUpdateClusterTarget(f commandutils.Factory, options *UpdateClusterO
 				cloudup.TargetDirect,
 				cloudup.TargetDryRun,
 				cloudup.TargetTerraform,
+				cloudup.TargetCloudformation,
 			}, directive
 		}
 
@@ -497,6 +536,9 @@ func completeUpdateClusterTarget(f commandutils.Factory, options *UpdateClusterO
 				completions = append(completions, cloudup.TargetTerraform)
 			}
 		}
+		if cluster.GetCloudProvider() == kops.CloudProviderAWS {
+			completions = append(completions, cloudup.TargetCloudformation)
+		}
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
 }
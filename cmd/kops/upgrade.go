@@ -33,6 +33,7 @@ func NewCmdUpgrade(f *util.Factory, out io.Writer) *cobra.Command {
 
 	// create subcommands
 	cmd.AddCommand(NewCmdUpgradeCluster(f, out))
+	cmd.AddCommand(NewCmdUpgradePreflight(f, out))
 
 	return cmd
 }
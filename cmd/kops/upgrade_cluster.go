@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/spf13/cobra"
@@ -32,7 +34,10 @@ import (
 	kopsutil "k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdsnapshots"
+	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -49,6 +54,14 @@ var (
 	upgradeClusterExample = templates.Examples(i18n.T(`
 	# Upgrade a cluster's Kubernetes version.
 	kops upgrade cluster k8s-cluster.example.com --yes --state=s3://my-state-store
+
+	# Upgrade a cluster's Kubernetes version, snapshotting the etcd volumes
+	# first if the etcd version changes.
+	kops upgrade cluster k8s-cluster.example.com --yes --snapshot-etcd --state=s3://my-state-store
+
+	# Upgrade a cluster to Kubernetes 1.31 one minor version at a time, updating,
+	# rolling-updating and validating the cluster for each version.
+	kops upgrade cluster k8s-cluster.example.com --to 1.31 --orchestrate --yes --state=s3://my-state-store
 	`))
 
 	upgradeClusterShort = i18n.T("Upgrade a kubernetes cluster.")
@@ -60,10 +73,27 @@ type UpgradeClusterOptions struct {
 	Channel     string
 	// KubernetesVersion is the k8s version to use for upgrade.
 	KubernetesVersion string
+	// SnapshotEtcd snapshots the etcd volumes before an upgrade that changes the etcd version.
+	SnapshotEtcd bool
+
+	// Orchestrate upgrades the cluster through each minor version up to To,
+	// applying and rolling out each version.
+	Orchestrate bool
+	// To is the Kubernetes version, or minor version, to upgrade to when orchestrating the upgrade.
+	To string
+	// SkipPreflight skips the preflight checks of each version when orchestrating the upgrade.
+	SkipPreflight bool
+	// ValidationTimeout is the time to wait for the cluster to validate after each version is rolled out.
+	ValidationTimeout time.Duration
+
+	// orchestrated is set when the upgrade is a step of an orchestrated upgrade.
+	orchestrated bool
 }
 
 func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
-	options := &UpgradeClusterOptions{}
+	options := &UpgradeClusterOptions{
+		ValidationTimeout: 15 * time.Minute,
+	}
 
 	cmd := &cobra.Command{
 		Use:               "cluster [CLUSTER]",
@@ -75,6 +105,12 @@ func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			if options.Orchestrate {
+				return RunOrchestratedUpgrade(ctx, f, out, options)
+			}
+			if options.To != "" {
+				return fmt.Errorf("--to can only be used with --orchestrate; use --kubernetes-version")
+			}
 			return RunUpgradeCluster(ctx, f, out, options)
 		},
 	}
@@ -84,6 +120,12 @@ func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("channel", completeChannel)
 	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", "", "Kubernetes version to use for upgrade")
 	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+	cmd.Flags().BoolVar(&options.SnapshotEtcd, "snapshot-etcd", false, "Snapshot the etcd volumes if the upgrade changes the etcd version")
+	cmd.Flags().BoolVar(&options.Orchestrate, "orchestrate", false, "Upgrade one minor version at a time up to --to, updating, rolling-updating and validating the cluster for each version")
+	cmd.Flags().StringVar(&options.To, "to", "", "Kubernetes version, or minor version, to upgrade to with --orchestrate")
+	cmd.RegisterFlagCompletionFunc("to", completeKubernetesVersion)
+	cmd.Flags().BoolVar(&options.SkipPreflight, "skip-preflight", false, "Skip the preflight checks of each version with --orchestrate")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the cluster to validate after each version with --orchestrate")
 
 	return cmd
 }
@@ -257,6 +299,13 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		fmt.Printf("\nMust specify --yes to perform upgrade\n")
 		return nil
 	}
+
+	if options.SnapshotEtcd {
+		if err := snapshotEtcdForUpgrade(ctx, f, out, cloud, cluster); err != nil {
+			return err
+		}
+	}
+
 	for _, action := range actions {
 		action.apply()
 	}
@@ -275,11 +324,48 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 	fmt.Printf("\nUpdates applied to configuration.\n")
 
 	// TODO: automate this step
-	fmt.Printf("You can now apply these changes, using `kops update cluster %s`\n", cluster.ObjectMeta.Name)
+	if !options.orchestrated {
+		fmt.Printf("You can now apply these changes, using `kops update cluster %s`\n", cluster.ObjectMeta.Name)
+	}
 
 	return nil
 }
 
+// snapshotEtcdForUpgrade snapshots the etcd volumes if the etcd versions of the cluster differ from those last applied.
+func snapshotEtcdForUpgrade(ctx context.Context, f *util.Factory, out io.Writer, cloud fi.Cloud, cluster *kopsapi.Cluster) error {
+	fullSpecs, err := fullClusterSpecs(ctx, f.VFSContext(), []*kopsapi.Cluster{cluster})
+	if err != nil {
+		klog.Warningf("not snapshotting etcd volumes, as the last applied etcd versions are unknown: %v", err)
+		return nil
+	}
+
+	applied := make(map[string]string)
+	for _, etcdCluster := range fullSpecs[0].Spec.EtcdClusters {
+		applied[etcdCluster.Name] = etcdCluster.Version
+	}
+	var changes []string
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		version := etcdCluster.Version
+		if version == "" {
+			version = components.DefaultEtcd3Version_1_22
+		}
+		if applied[etcdCluster.Name] != version {
+			changes = append(changes, fmt.Sprintf("%s (%s -> %s)", etcdCluster.Name, applied[etcdCluster.Name], version))
+		}
+	}
+	if len(changes) == 0 {
+		klog.Infof("not snapshotting etcd volumes, as the etcd versions are unchanged")
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nSnapshotting etcd volumes, as the etcd version changes: %s\n", strings.Join(changes, ", "))
+	snapshots, err := etcdsnapshots.SnapshotCluster(ctx, cloud, cluster, etcdsnapshots.ReasonUpgradeCluster)
+	if err != nil {
+		return fmt.Errorf("error snapshotting etcd volumes: %w", err)
+	}
+	return printEtcdSnapshots(out, snapshots)
+}
+
 func completeChannel(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// TODO implement completion against VFS
 	return []string{"alpha", "stable"}, cobra.ShellCompDirectiveNoFileComp
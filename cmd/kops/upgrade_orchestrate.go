@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/kops"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/upgradecheck"
+	"k8s.io/kops/pkg/upgradeplan"
+	"k8s.io/kops/util/pkg/tables"
+)
+
+// RunOrchestratedUpgrade upgrades a cluster one minor version at a time, recording its progress
+// in the state store so that an interrupted upgrade resumes where it stopped.
+func RunOrchestratedUpgrade(ctx context.Context, f *util.Factory, out io.Writer, options *UpgradeClusterOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster.ObjectMeta.Annotations[kopsapi.AnnotationNameManagement] == kopsapi.AnnotationValueManagementImported {
+		return fmt.Errorf("upgrade is not for use with imported clusters")
+	}
+	if options.KubernetesVersion != "" {
+		return fmt.Errorf("--kubernetes-version cannot be used with --orchestrate; use --to")
+	}
+
+	configBase, err := registry.ConfigBase(f.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	progressPath := configBase.Join(registry.PathUpgradeProgress)
+	progress, err := upgradeplan.ReadProgress(ctx, progressPath)
+	if err != nil {
+		return err
+	}
+	if progress != nil && progress.CompletedAt != nil {
+		progress = nil
+	}
+
+	channelLocation := options.Channel
+	if channelLocation == "" {
+		channelLocation = cluster.Spec.Channel
+	}
+	if channelLocation == "" {
+		channelLocation = kopsapi.DefaultChannel
+	}
+	channel, err := kopsapi.LoadChannel(f.VFSContext(), channelLocation)
+	if err != nil {
+		return fmt.Errorf("error loading channel %q: %v", channelLocation, err)
+	}
+
+	if progress != nil {
+		if options.To != "" {
+			to, err := upgradeplan.ResolveVersion(channel, options.To)
+			if err != nil {
+				return err
+			}
+			if to.String() != progress.To {
+				return fmt.Errorf("the upgrade of cluster %q to Kubernetes %s is in progress, and must be resumed with --to %s", cluster.Name, progress.To, progress.To)
+			}
+		}
+		fmt.Fprintf(out, "Resuming the upgrade of cluster %s from Kubernetes %s to %s\n\n", cluster.Name, progress.From, progress.To)
+	} else {
+		current, err := kopsutil.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("parsing kubernetesVersion %q: %w", cluster.Spec.KubernetesVersion, err)
+		}
+		target := kopsapi.RecommendedKubernetesVersion(channel, kops.Version)
+		if options.To != "" {
+			target, err = upgradeplan.ResolveVersion(channel, options.To)
+			if err != nil {
+				return err
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("channel %q has no recommended Kubernetes version; specify --to", channelLocation)
+		}
+		plan, err := upgradeplan.Plan(channel, *current, *target)
+		if err != nil {
+			return err
+		}
+		if len(plan) == 0 {
+			fmt.Fprintf(out, "Cluster %s already runs Kubernetes %s\n", cluster.Name, current)
+			return nil
+		}
+		var versions []string
+		for _, version := range plan {
+			versions = append(versions, version.String())
+		}
+		progress = upgradeplan.NewProgress(progressPath, cluster.Name, current.String(), versions)
+		fmt.Fprintf(out, "Upgrading cluster %s from Kubernetes %s to %s\n\n", cluster.Name, progress.From, progress.To)
+	}
+
+	if err := upgradeProgressOutputTable(progress, out); err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to upgrade\n")
+		return nil
+	}
+
+	for _, v := range progress.Versions {
+		version := v.Version
+		for _, step := range upgradeplan.Steps {
+			if progress.IsDone(version, step) {
+				continue
+			}
+			if step == upgradeplan.StepPreflight && options.SkipPreflight {
+				continue
+			}
+			fmt.Fprintf(out, "\nUpgrading to Kubernetes %s: %s\n", version, step)
+			if err := runUpgradeStep(ctx, f, out, options, cluster.Name, version, step); err != nil {
+				return fmt.Errorf("upgrade to Kubernetes %s failed at step %s (rerun the command to resume): %w", version, step, err)
+			}
+			if err := progress.RecordDone(ctx, version, step); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := progress.Complete(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nCluster %s is upgraded to Kubernetes %s\n", cluster.Name, progress.To)
+	return nil
+}
+
+// runUpgradeStep runs a step of upgrading a cluster to a Kubernetes version.
+func runUpgradeStep(ctx context.Context, f *util.Factory, out io.Writer, options *UpgradeClusterOptions, clusterName string, version string, step string) error {
+	switch step {
+	case upgradeplan.StepPreflight:
+		return runUpgradePreflightStep(ctx, f, out, clusterName, version)
+
+	case upgradeplan.StepUpgrade:
+		return RunUpgradeCluster(ctx, f, out, &UpgradeClusterOptions{
+			ClusterName:       clusterName,
+			Yes:               true,
+			Channel:           options.Channel,
+			KubernetesVersion: version,
+			SnapshotEtcd:      options.SnapshotEtcd,
+			orchestrated:      true,
+		})
+
+	case upgradeplan.StepUpdate, upgradeplan.StepPrune:
+		updateOptions := &UpdateClusterOptions{}
+		updateOptions.InitDefaults()
+		updateOptions.ClusterName = clusterName
+		updateOptions.Yes = true
+		updateOptions.Prune = step == upgradeplan.StepPrune
+		_, err := RunUpdateCluster(ctx, f, out, updateOptions)
+		return err
+
+	case upgradeplan.StepRollingUpdateControlPlane, upgradeplan.StepRollingUpdateNodes:
+		rollingUpdateOptions := &RollingUpdateOptions{}
+		rollingUpdateOptions.InitDefaults()
+		rollingUpdateOptions.ClusterName = clusterName
+		rollingUpdateOptions.Yes = true
+		rollingUpdateOptions.ValidationTimeout = options.ValidationTimeout
+		if step == upgradeplan.StepRollingUpdateControlPlane {
+			rollingUpdateOptions.InstanceGroupRoles = []string{kopsapi.InstanceGroupRoleControlPlane.ToLowerString(), kopsapi.InstanceGroupRoleAPIServer.ToLowerString()}
+		} else {
+			rollingUpdateOptions.InstanceGroupRoles = []string{kopsapi.InstanceGroupRoleNode.ToLowerString(), kopsapi.InstanceGroupRoleBastion.ToLowerString()}
+		}
+		return RunRollingUpdateCluster(ctx, f, out, rollingUpdateOptions)
+
+	case upgradeplan.StepValidate:
+		validateOptions := &ValidateClusterOptions{}
+		validateOptions.InitDefaults()
+		validateOptions.ClusterName = clusterName
+		validateOptions.wait = options.ValidationTimeout
+		result, err := RunValidateCluster(ctx, f, out, validateOptions)
+		if err != nil {
+			return err
+		}
+		if len(result.Failures) != 0 {
+			return fmt.Errorf("cluster %q did not validate", clusterName)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown upgrade step %q", step)
+}
+
+// runUpgradePreflightStep checks that the cluster can be upgraded to a Kubernetes version.
+func runUpgradePreflightStep(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, version string) error {
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return err
+	}
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+	targetVersion, err := kopsutil.ParseKubernetesVersion(version)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := createK8sClient(cluster)
+	if err != nil {
+		return err
+	}
+
+	report, err := upgradecheck.Run(ctx, &upgradecheck.Options{
+		Cluster:        cluster,
+		InstanceGroups: instanceGroups,
+		TargetVersion:  *targetVersion,
+		K8sClient:      k8sClient,
+		VFSContext:     f.VFSContext(),
+	})
+	if err != nil {
+		return err
+	}
+	if report.Passed() {
+		return nil
+	}
+	if err := upgradeReportOutputTable(report, out); err != nil {
+		return err
+	}
+	return fmt.Errorf("preflight checks failed; fix the failures, or rerun with --skip-preflight")
+}
+
+func upgradeProgressOutputTable(progress *upgradeplan.Progress, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("VERSION", func(v *upgradeplan.VersionProgress) string {
+		return v.Version
+	})
+	t.AddColumn("COMPLETED", func(v *upgradeplan.VersionProgress) string {
+		if len(v.Completed) == 0 {
+			return "-"
+		}
+		return strings.Join(v.Completed, ",")
+	})
+	return t.Render(progress.Versions, out, "VERSION", "COMPLETED")
+}
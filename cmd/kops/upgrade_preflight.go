@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"k8s.io/kops"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/upgradecheck"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	upgradePreflightLong = pretty.LongDesc(i18n.T(`
+	Checks that a cluster can be upgraded to a Kubernetes version, before changing its KubernetesVersion.
+
+	The checks look for clients and objects using APIs removed by the upgrade, component flags in the
+	cluster spec that are deprecated or removed, the version skew between the control plane, the nodes
+	and the addons, and the support of the networking and the rest of the cluster spec for the new version.
+	The command fails if any check fails.
+	`))
+
+	upgradePreflightExample = templates.Examples(i18n.T(`
+	# Check the upgrade of a cluster to the recommended Kubernetes version.
+	kops upgrade preflight k8s-cluster.example.com --state=s3://my-state-store
+
+	# Check the upgrade of a cluster to a Kubernetes version.
+	kops upgrade preflight k8s-cluster.example.com --kubernetes-version 1.30.2 --state=s3://my-state-store
+	`))
+
+	upgradePreflightShort = i18n.T("Check that a cluster can be upgraded to a Kubernetes version.")
+)
+
+type UpgradePreflightOptions struct {
+	ClusterName string
+	Channel     string
+	// KubernetesVersion is the k8s version to check the upgrade to.
+	KubernetesVersion string
+	Output            string
+}
+
+func NewCmdUpgradePreflight(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &UpgradePreflightOptions{
+		Output: OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "preflight [CLUSTER]",
+		Short:             upgradePreflightShort,
+		Long:              upgradePreflightLong,
+		Example:           upgradePreflightExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := RunUpgradePreflight(cmd.Context(), f, out, options)
+			if err != nil {
+				return err
+			}
+			if !report.Passed() {
+				return fmt.Errorf("upgrade to Kubernetes %s failed the preflight checks", report.TargetVersion)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Channel, "channel", "", "Channel to use for the recommended Kubernetes version")
+	cmd.RegisterFlagCompletionFunc("channel", completeChannel)
+	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", "", "Kubernetes version to check the upgrade to")
+	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of json|yaml|table.")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputTable, OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func RunUpgradePreflight(ctx context.Context, f *util.Factory, out io.Writer, options *UpgradePreflightOptions) (*upgradecheck.Report, error) {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetVersion *semver.Version
+	if options.KubernetesVersion != "" {
+		targetVersion, err = kopsutil.ParseKubernetesVersion(options.KubernetesVersion)
+		if err != nil {
+			return nil, fmt.Errorf("parsing --kubernetes-version %q: %w", options.KubernetesVersion, err)
+		}
+	} else {
+		channelLocation := options.Channel
+		if channelLocation == "" {
+			channelLocation = cluster.Spec.Channel
+		}
+		if channelLocation == "" {
+			channelLocation = kopsapi.DefaultChannel
+		}
+		channel, err := kopsapi.LoadChannel(f.VFSContext(), channelLocation)
+		if err != nil {
+			return nil, fmt.Errorf("error loading channel %q: %v", channelLocation, err)
+		}
+		targetVersion = kopsapi.RecommendedKubernetesVersion(channel, kops.Version)
+		if targetVersion == nil {
+			return nil, fmt.Errorf("channel %q has no recommended Kubernetes version; specify --kubernetes-version", channelLocation)
+		}
+	}
+
+	k8sClient, err := createK8sClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := upgradecheck.Run(ctx, &upgradecheck.Options{
+		Cluster:        cluster,
+		InstanceGroups: instanceGroups,
+		TargetVersion:  *targetVersion,
+		K8sClient:      k8sClient,
+		VFSContext:     f.VFSContext(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch options.Output {
+	case OutputTable:
+		fmt.Fprintf(out, "Checking the upgrade of cluster %s from Kubernetes %s to %s\n\n", cluster.Name, report.CurrentVersion, report.TargetVersion)
+		if err := upgradeReportOutputTable(report, out); err != nil {
+			return nil, err
+		}
+		if report.Passed() {
+			fmt.Fprintf(out, "\nCluster %s can be upgraded to Kubernetes %s\n", cluster.Name, report.TargetVersion)
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return report, nil
+}
+
+func upgradeReportOutputTable(report *upgradecheck.Report, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("CHECK", func(r *upgradecheck.Result) string {
+		return r.Check
+	})
+	t.AddColumn("STATUS", func(r *upgradecheck.Result) string {
+		return string(r.Status)
+	})
+	t.AddColumn("OBJECT", func(r *upgradecheck.Result) string {
+		return r.Object
+	})
+	t.AddColumn("MESSAGE", func(r *upgradecheck.Result) string {
+		return r.Message
+	})
+	return t.Render(report.Results, out, "CHECK", "STATUS", "OBJECT", "MESSAGE")
+}
@@ -56,7 +56,10 @@ var (
 	validateClusterExample = templates.Examples(i18n.T(`
 	# Validate the cluster set as the current context of the kube config.
 	# Kops will try for 10 minutes to validate the cluster 3 times.
-	kops validate cluster --wait 10m --count 3`))
+	kops validate cluster --wait 10m --count 3
+
+	# Validate the cluster every 5 minutes, serving the results as Prometheus metrics on port 9090.
+	kops validate cluster --serve :9090 --interval 5m`))
 
 	validateClusterShort = i18n.T(`Validate a kOps cluster.`)
 )
@@ -68,6 +71,7 @@ type ValidateClusterOptions struct {
 	count       int
 	interval    time.Duration
 	kubeconfig  string
+	serve       string
 }
 
 func (o *ValidateClusterOptions) InitDefaults() {
@@ -87,6 +91,13 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
 		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.serve != "" {
+				if !cmd.Flags().Changed("interval") {
+					options.interval = time.Minute
+				}
+				return RunValidateClusterServe(cmd.Context(), f, out, options)
+			}
+
 			result, err := RunValidateCluster(cmd.Context(), f, out, options)
 			if err != nil {
 				return fmt.Errorf("validation failed: %v", err)
@@ -109,33 +120,31 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().IntVar(&options.count, "count", options.count, "Number of consecutive successful validations required")
 	cmd.Flags().DurationVar(&options.interval, "interval", options.interval, "Time in duration to wait between validation attempts")
 	cmd.Flags().StringVar(&options.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringVar(&options.serve, "serve", options.serve, "Address to serve Prometheus metrics on, validating the cluster continuously every interval (1m unless --interval is set)")
 
 	return cmd
 }
 
-func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ValidateClusterOptions) (*validation.ValidationCluster, error) {
+// buildClusterValidator builds the validator of the cluster, using its kubeconfig context.
+func buildClusterValidator(ctx context.Context, f *util.Factory, options *ValidateClusterOptions) (*kopsapi.Cluster, []kopsapi.InstanceGroup, validation.ClusterValidator, error) {
 	clientSet, err := f.KopsClient()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	cluster, err := GetCluster(ctx, f, options.ClusterName)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	list, err := clientSet.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("cannot get InstanceGroups for %q: %v", cluster.ObjectMeta.Name, err)
-	}
-
-	if options.output == OutputTable {
-		fmt.Fprintf(out, "Validating cluster %v\n\n", cluster.ObjectMeta.Name)
+		return nil, nil, nil, fmt.Errorf("cannot get InstanceGroups for %q: %v", cluster.ObjectMeta.Name, err)
 	}
 
 	var instanceGroups []kopsapi.InstanceGroup
@@ -145,7 +154,7 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 	}
 
 	if len(instanceGroups) == 0 {
-		return nil, fmt.Errorf("no InstanceGroup objects found")
+		return nil, nil, nil, fmt.Errorf("no InstanceGroup objects found")
 	}
 
 	// TODO: Refactor into util.Factory
@@ -158,21 +167,34 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 		configLoadingRules,
 		&clientcmd.ConfigOverrides{CurrentContext: contextName}).ClientConfig()
 	if err != nil {
-		return nil, fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+		return nil, nil, nil, fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
 	}
 
 	k8sClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot build kubernetes api client for %q: %v", contextName, err)
+		return nil, nil, nil, fmt.Errorf("cannot build kubernetes api client for %q: %v", contextName, err)
 	}
 
-	timeout := time.Now().Add(options.wait)
-
 	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error creating validatior: %v", err)
+		return nil, nil, nil, fmt.Errorf("unexpected error creating validatior: %v", err)
 	}
 
+	return cluster, instanceGroups, validator, nil
+}
+
+func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ValidateClusterOptions) (*validation.ValidationCluster, error) {
+	cluster, instanceGroups, validator, err := buildClusterValidator(ctx, f, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.output == OutputTable {
+		fmt.Fprintf(out, "Validating cluster %v\n\n", cluster.ObjectMeta.Name)
+	}
+
+	timeout := time.Now().Add(options.wait)
+
 	consecutive := 0
 	for {
 		if options.wait > 0 && time.Now().After(timeout) && consecutive == 0 {
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/validation"
+)
+
+// RunValidateClusterServe validates the cluster every interval until interrupted,
+// serving the results of the last validation as Prometheus metrics.
+// The instance groups and the validator are rebuilt for each validation, so that changes to the cluster are picked up.
+func RunValidateClusterServe(ctx context.Context, f *util.Factory, out io.Writer, options *ValidateClusterOptions) error {
+	if options.wait != 0 || options.count != 0 {
+		return fmt.Errorf("--serve cannot be used with --wait or --count")
+	}
+	if options.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Fail early if the cluster cannot be validated at all
+	cluster, _, _, err := buildClusterValidator(ctx, f, options)
+	if err != nil {
+		return err
+	}
+
+	registry := prometheus.NewRegistry()
+	metrics, err := validation.NewMetrics(registry, cluster.ObjectMeta.Name)
+	if err != nil {
+		return fmt.Errorf("registering metrics: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", options.serve)
+	if err != nil {
+		return fmt.Errorf("error listening on %q: %w", options.serve, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	httpServer := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			klog.Warningf("error shutting down metrics server: %v", err)
+		}
+	}()
+
+	go func() {
+		for {
+			var result *validation.ValidationCluster
+			_, _, validator, err := buildClusterValidator(ctx, f, options)
+			if err == nil {
+				result, err = validator.Validate()
+			}
+			if err != nil {
+				klog.Warningf("unexpected error during validation: %v", err)
+			} else if len(result.Failures) != 0 {
+				klog.Warningf("cluster %s is not healthy: %d validation failures", cluster.ObjectMeta.Name, len(result.Failures))
+				for _, failure := range result.Failures {
+					klog.V(2).Infof("validation failure %s %q: %s", failure.Kind, failure.Name, failure.Message)
+				}
+			} else {
+				klog.Infof("cluster %s is healthy", cluster.ObjectMeta.Name)
+			}
+			metrics.Record(result, err, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(options.interval):
+			}
+		}
+	}()
+
+	fmt.Fprintf(out, "Validating cluster %s every %v, serving metrics on http://%s/metrics\n", cluster.ObjectMeta.Name, options.interval, listener.Addr())
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
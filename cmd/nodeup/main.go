@@ -38,7 +38,7 @@ func main() {
 
 	var flagConf, flagCacheDir, gitVersion string
 	var flagRetries int
-	var dryrun, installSystemdUnit bool
+	var dryrun, installSystemdUnit, containerdRegistriesOnly bool
 	target := "direct"
 
 	if kops.GitVersion != "" {
@@ -51,6 +51,7 @@ func main() {
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
 	flag.StringVar(&target, "target", target, "Target - direct, dryrun")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
+	flag.BoolVar(&containerdRegistriesOnly, "containerd-registries-only", containerdRegistriesOnly, "If true, will only apply the containerd registry hosts configuration")
 
 	if dryrun {
 		target = "dryrun"
@@ -113,6 +114,8 @@ func main() {
 				ConfigLocation: flagConf,
 				Target:         target,
 				CacheDir:       flagCacheDir,
+
+				ContainerdRegistriesOnly: containerdRegistriesOnly,
 			}
 			err = cmd.Run(os.Stdout)
 			if err == nil {
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// acceleratorModulesService loads the kernel modules of the accelerators on boot
+const acceleratorModulesService = "kops-accelerator-modules.service"
+
+// AcceleratorBuilder installs the drivers of the accelerator devices and loads their kernel modules.
+// The container runtimes are configured by the ContainerdBuilder, and the device plugins run as an addon.
+type AcceleratorBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &AcceleratorBuilder{}
+
+// Build is responsible for installing the accelerator drivers.
+func (b *AcceleratorBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	var modules []string
+	for _, accelerator := range b.NodeupConfig.Accelerators {
+		for _, name := range accelerator.Packages {
+			c.EnsureTask(&nodetasks.Package{Name: name})
+		}
+		modules = append(modules, accelerator.KernelModules...)
+	}
+
+	if len(modules) == 0 {
+		return nil
+	}
+
+	// Services are started after the packages are installed, so the modules built by DKMS are available
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Load the kernel modules of the accelerator devices")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Before", "containerd.service kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", "/sbin/modprobe -a "+strings.Join(modules, " "))
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", acceleratorModulesService, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       acceleratorModulesService,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
+	return nil
+}
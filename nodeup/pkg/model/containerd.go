@@ -499,6 +499,9 @@ func (b *ContainerdBuilder) buildContainerdConfig() (string, error) {
 	for name, endpoints := range containerd.RegistryMirrors {
 		config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "registry", "mirrors", name, "endpoint"}, endpoints)
 	}
+	if b.NodeupConfig.ContainerdRegistriesConfigPath != "" {
+		config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "registry", "config_path"}, b.NodeupConfig.ContainerdRegistriesConfigPath)
+	}
 	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "runtime_type"}, "io.containerd.runc.v2")
 	// only enable systemd cgroups for kubernetes >= 1.20
 	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "options", "SystemdCgroup"}, true)
@@ -515,6 +518,12 @@ func (b *ContainerdBuilder) buildContainerdConfig() (string, error) {
 		}
 	}
 
+	for _, accelerator := range b.NodeupConfig.Accelerators {
+		if accelerator.Runtime != nil {
+			appendAcceleratorRuntimeConfig(config, accelerator.Runtime)
+		}
+	}
+
 	for k, v := range containerd.ConfigAdditions {
 		r := csv.NewReader(strings.NewReader(k))
 		r.Comma = '.'
@@ -539,6 +548,14 @@ func (b *ContainerdBuilder) buildContainerdConfig() (string, error) {
 	return config.String(), nil
 }
 
+// appendAcceleratorRuntimeConfig adds a runtime handler that runs containers using the OCI runtime of the accelerator
+func appendAcceleratorRuntimeConfig(config *toml.Tree, runtime *kops.AcceleratorRuntimeSpec) {
+	path := []string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", runtime.Name}
+	config.SetPath(append(path, "runtime_type"), "io.containerd.runc.v2")
+	config.SetPath(append(path, "options", "BinaryName"), runtime.BinaryName)
+	config.SetPath(append(path, "options", "SystemdCgroup"), true)
+}
+
 func appendNvidiaGPURuntimeConfig(config *toml.Tree) error {
 	gpuConfig, err := toml.TreeFromMap(
 		map[string]interface{}{
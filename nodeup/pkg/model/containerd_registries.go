@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// containerdRegistriesRefreshService re-applies the registry hosts configuration on running nodes
+	containerdRegistriesRefreshService = "kops-containerd-registries.service"
+	// containerdRegistriesRefreshInterval is how often running nodes pick up registry changes
+	containerdRegistriesRefreshInterval = "1h"
+)
+
+// ContainerdRegistriesBuilder writes the containerd registry hosts configuration.
+// containerd reads it on every pull, so changes are applied without restarting containerd,
+// and nodeup periodically re-renders only these files to pick up changes without replacing the node.
+type ContainerdRegistriesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &ContainerdRegistriesBuilder{}
+
+// Build is responsible for configuring the containerd registry hosts
+func (b *ContainerdRegistriesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	configPath := b.NodeupConfig.ContainerdRegistriesConfigPath
+	if configPath == "" {
+		return nil
+	}
+
+	credentials, err := b.registryCredentials()
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]string)
+	for name, registry := range b.NodeupConfig.ContainerdRegistries {
+		if err := buildContainerdRegistryHosts(files, configPath, name, registry, credentials); err != nil {
+			return err
+		}
+	}
+
+	c.AddTask(&nodetasks.RegistryHosts{
+		Path:  configPath,
+		Files: files,
+	})
+
+	if len(b.NodeupCommand) != 0 {
+		b.buildRefreshTimer(c)
+	}
+
+	return nil
+}
+
+// buildContainerdRegistryHosts adds the hosts.toml file of a registry, and the CA bundles it refers to
+func buildContainerdRegistryHosts(files map[string]string, configPath string, name string, registry kops.ContainerdRegistryConfig, credentials map[string]string) error {
+	var sb strings.Builder
+	sb.WriteString("# Built by kOps - do not edit\n")
+
+	var top strings.Builder
+	server := registry.Server
+	if server != "" {
+		fmt.Fprintf(&top, "server = %q\n", server)
+	} else if name != "_default" {
+		server = "https://" + name
+	}
+	if registry.CACertificate != "" {
+		files[path.Join(name, "ca.crt")] = registry.CACertificate
+		fmt.Fprintf(&top, "ca = %q\n", path.Join(configPath, name, "ca.crt"))
+	}
+	if registry.SkipVerify {
+		top.WriteString("skip_verify = true\n")
+	}
+	if top.Len() != 0 {
+		sb.WriteString("\n" + top.String())
+	}
+	if server != "" {
+		u, err := url.Parse(server)
+		if err != nil {
+			return fmt.Errorf("parsing server %q of registry %q: %w", server, name, err)
+		}
+		if auth := credentials[u.Host]; auth != "" {
+			sb.WriteString("\n[header]\n")
+			fmt.Fprintf(&sb, "  Authorization = [%q]\n", "Basic "+auth)
+		}
+	}
+
+	for _, mirror := range registry.Mirrors {
+		u, err := url.Parse(mirror.URL)
+		if err != nil {
+			return fmt.Errorf("parsing mirror %q of registry %q: %w", mirror.URL, name, err)
+		}
+
+		capabilities := mirror.Capabilities
+		if len(capabilities) == 0 {
+			capabilities = []string{"pull", "resolve"}
+		}
+		var quoted []string
+		for _, capability := range capabilities {
+			quoted = append(quoted, fmt.Sprintf("%q", capability))
+		}
+
+		fmt.Fprintf(&sb, "\n[host.%q]\n", mirror.URL)
+		fmt.Fprintf(&sb, "  capabilities = [%s]\n", strings.Join(quoted, ", "))
+		if mirror.CACertificate != "" {
+			caFile := path.Join(name, strings.ReplaceAll(u.Host, ":", "_")+".crt")
+			files[caFile] = mirror.CACertificate
+			fmt.Fprintf(&sb, "  ca = %q\n", path.Join(configPath, caFile))
+		}
+		if mirror.SkipVerify {
+			sb.WriteString("  skip_verify = true\n")
+		}
+		if mirror.OverridePath {
+			sb.WriteString("  override_path = true\n")
+		}
+		if auth := credentials[u.Host]; auth != "" {
+			fmt.Fprintf(&sb, "  [host.%q.header]\n", mirror.URL)
+			fmt.Fprintf(&sb, "    Authorization = [%q]\n", "Basic "+auth)
+		}
+	}
+
+	files[path.Join(name, "hosts.toml")] = sb.String()
+
+	return nil
+}
+
+// registryCredentials returns the basic auth credentials from the dockerconfig secret, keyed by registry host
+func (b *ContainerdRegistriesBuilder) registryCredentials() (map[string]string, error) {
+	credentials := make(map[string]string)
+	if b.SecretStore == nil {
+		return credentials, nil
+	}
+
+	secret, _ := b.SecretStore.Secret("dockerconfig")
+	if secret == nil {
+		return credentials, nil
+	}
+
+	var dockerConfig struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(secret.Data, &dockerConfig); err != nil {
+		return nil, fmt.Errorf("error parsing dockerconfig secret: %w", err)
+	}
+
+	for key, auth := range dockerConfig.Auths {
+		// Keys are either a host, or a URL like "https://index.docker.io/v1/"
+		host := key
+		if u, err := url.Parse(key); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if auth.Auth != "" {
+			credentials[host] = auth.Auth
+		} else if auth.Username != "" {
+			credentials[host] = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		}
+	}
+
+	return credentials, nil
+}
+
+// buildRefreshTimer re-renders the registry hosts files periodically, so that running nodes pick up registry changes in place
+func (b *ContainerdRegistriesBuilder) buildRefreshTimer(c *fi.NodeupModelBuilderContext) {
+	command := append([]string{}, b.NodeupCommand...)
+	command = append(command, "--containerd-registries-only", "--retries=0")
+
+	{
+		manifest := &systemd.Manifest{}
+		manifest.Set("Unit", "Description", "Apply the containerd registry hosts configuration")
+		manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+		manifest.Set("Unit", "After", "kops-configuration.service")
+		manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+		manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+		manifest.Set("Service", "ExecStart", strings.Join(command, " "))
+		manifest.Set("Service", "Type", "oneshot")
+
+		manifestString := manifest.Render()
+		klog.V(8).Infof("Built service manifest %q\n%s", containerdRegistriesRefreshService, manifestString)
+
+		// The service is started by the timer only, as it must not run alongside this nodeup run
+		service := &nodetasks.Service{
+			Name:       containerdRegistriesRefreshService,
+			Definition: s(manifestString),
+			Running:    fi.PtrTo(false),
+		}
+		service.InitDefaults()
+		c.AddTask(service)
+	}
+
+	{
+		manifest := &systemd.Manifest{}
+		manifest.Set("Unit", "Description", "Periodically apply the containerd registry hosts configuration")
+		manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+		manifest.Set("Timer", "OnActiveSec", containerdRegistriesRefreshInterval)
+		manifest.Set("Timer", "OnUnitActiveSec", containerdRegistriesRefreshInterval)
+		manifest.Set("Timer", "Unit", containerdRegistriesRefreshService)
+		manifest.Set("Install", "WantedBy", "timers.target")
+
+		name := strings.TrimSuffix(containerdRegistriesRefreshService, ".service") + ".timer"
+		manifestString := manifest.Render()
+		klog.V(8).Infof("Built timer manifest %q\n%s", name, manifestString)
+
+		timer := &nodetasks.Service{
+			Name:       name,
+			Definition: s(manifestString),
+		}
+		timer.InitDefaults()
+		c.AddTask(timer)
+	}
+}
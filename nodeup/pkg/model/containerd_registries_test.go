@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestContainerdRegistriesBuilder(t *testing.T) {
+	RunGoldenTest(t, "tests/golden/containerd-registries", "containerd-registries", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		nodeupModelContext.NodeupCommand = []string{"/opt/kops/bin/nodeup", "--conf=/opt/kops/conf/kube_env.yaml", "--cache=/var/cache/nodeup"}
+		builder := ContainerdRegistriesBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
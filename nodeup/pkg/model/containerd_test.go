@@ -254,3 +254,59 @@ func TestAppendGPURuntimeContainerdConfig(t *testing.T) {
 		t.Error("new config did not match expected new config")
 	}
 }
+
+func TestAppendAcceleratorRuntimeContainerdConfig(t *testing.T) {
+	originalConfig := `version = 2
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+	[plugins."io.containerd.grpc.v1.cri".containerd]
+	  [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+		[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+		  runtime_type = "io.containerd.runc.v2"
+		  [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+			SystemdCgroup = true
+`
+
+	expectedNewConfig := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.neuron]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.neuron.options]
+            BinaryName = "/opt/aws/neuron/bin/oci_neuron_hook_wrapper"
+            SystemdCgroup = true
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = true
+`
+	config, err := toml.Load(originalConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	appendAcceleratorRuntimeConfig(config, &kops.AcceleratorRuntimeSpec{
+		Name:       "neuron",
+		BinaryName: "/opt/aws/neuron/bin/oci_neuron_hook_wrapper",
+	})
+
+	newConfig, err := config.ToTomlString()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if newConfig != expectedNewConfig {
+		fmt.Println(diff.FormatDiff(expectedNewConfig, newConfig))
+		t.Error("new config did not match expected new config")
+	}
+}
@@ -82,6 +82,9 @@ type NodeupModelContext struct {
 	ConfigurationMode string
 	InstanceID        string
 	MachineType       string
+
+	// NodeupCommand is the command line used to run nodeup again, for the tasks that refresh configuration in place
+	NodeupCommand []string
 }
 
 // Init completes initialization of the object, for example pre-parsing the kubernetes version
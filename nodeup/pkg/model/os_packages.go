@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// packageVersionReportPath is where nodeup reports the installed versions of pinned packages
+const packageVersionReportPath = "/var/lib/kops/package-versions.yaml"
+
+// OSPackagesBuilder pins the versions of OS packages and the kernel.
+// It must run after all the other builders that install packages.
+type OSPackagesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &OSPackagesBuilder{}
+
+// Build is responsible for configuring package repositories and pinning package versions
+func (b *OSPackagesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	spec := b.NodeupConfig.OSPackages
+	if spec == nil {
+		return nil
+	}
+
+	if !b.Distribution.IsDebianFamily() && !b.Distribution.IsRHELFamily() {
+		klog.Warningf("unknown distribution, skipping pinned packages install: %v", b.Distribution)
+		return nil
+	}
+
+	for _, repository := range spec.Repositories {
+		if b.Distribution.IsDebianFamily() {
+			if len(repository.Sources) == 0 {
+				klog.Warningf("package repository %q has no apt sources; skipping", repository.Name)
+				continue
+			}
+			c.AddTask(&nodetasks.AptSource{
+				Name:    repository.Name,
+				Keyring: repository.Keyring,
+				Sources: repository.Sources,
+			})
+		} else {
+			if repository.BaseURL == "" {
+				klog.Warningf("package repository %q has no yum base URL; skipping", repository.Name)
+				continue
+			}
+			c.AddTask(&nodetasks.YumRepository{
+				Name:    repository.Name,
+				Keyring: repository.Keyring,
+				BaseURL: repository.BaseURL,
+			})
+		}
+	}
+
+	report := &nodetasks.PackageVersionReport{
+		Path:          packageVersionReportPath,
+		Packages:      make(map[string]string),
+		KernelVersion: spec.KernelVersion,
+	}
+
+	for _, pinned := range spec.Pinned {
+		// Packages that are already installed by other builders are pinned in place
+		if task, found := c.Tasks["Package/"+pinned.Name]; found {
+			pkg, ok := task.(*nodetasks.Package)
+			if !ok {
+				return fmt.Errorf("unexpected task type %T for package %q", task, pinned.Name)
+			}
+			if pkg.Source != nil {
+				return fmt.Errorf("package %q is installed from %q and cannot be pinned", pinned.Name, fi.ValueOf(pkg.Source))
+			}
+			pkg.Version = fi.PtrTo(pinned.Version)
+		} else {
+			c.AddTask(&nodetasks.Package{
+				Name:    pinned.Name,
+				Version: fi.PtrTo(pinned.Version),
+			})
+		}
+		report.Packages[pinned.Name] = pinned.Version
+	}
+
+	// On RHEL based distributions, pinned packages are locked with the versionlock plugin
+	if len(spec.Pinned) != 0 && b.Distribution.IsRHELFamily() {
+		c.EnsureTask(&nodetasks.Package{Name: nodetasks.VersionlockPluginPackage(b.Distribution)})
+	}
+
+	c.AddTask(report)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestOSPackagesBuilder(t *testing.T) {
+	RunGoldenTest(t, "tests/golden/os-packages", "os-packages", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		packagesBuilder := PackagesBuilder{NodeupModelContext: nodeupModelContext}
+		if err := packagesBuilder.Build(target); err != nil {
+			return err
+		}
+		builder := OSPackagesBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// systemdDropInPath is the directory under which drop-in directories are written
+const systemdDropInPath = "/etc/systemd/system"
+
+// SystemdUnitBuilder installs the systemd units and drop-ins from the cluster and instance group specs
+type SystemdUnitBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &SystemdUnitBuilder{}
+
+// Build is responsible for rendering the systemd units and their drop-ins
+func (b *SystemdUnitBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	// instance group units come first, so they can override the cluster wide ones with the same name
+	unitNames := make(map[string]bool)
+	for _, unit := range b.NodeupConfig.SystemdUnits {
+		if unitNames[unit.Name] {
+			klog.V(2).Infof("Skipping the systemd unit: %v as we've already processed a unit with the same name", unit.Name)
+			continue
+		}
+		unitNames[unit.Name] = true
+
+		for _, dropIn := range unit.DropIns {
+			c.AddTask(&nodetasks.File{
+				Path:           filepath.Join(systemdDropInPath, unit.Name+".d", dropIn.Name),
+				Contents:       fi.NewStringResource(dropIn.Content),
+				Type:           nodetasks.FileType_File,
+				Mode:           s("0644"),
+				BeforeServices: []string{unit.Name},
+				OnChangeExecute: [][]string{
+					{"systemctl", "daemon-reload"},
+					// try-restart only restarts the unit if it is already running,
+					// so this is a no-op on first boot, before nodeup starts the unit.
+					{"systemctl", "try-restart", unit.Name},
+				},
+			})
+		}
+
+		// Units without content only carry drop-ins for a unit that already exists on the node
+		if unit.Content == "" {
+			continue
+		}
+
+		service := &nodetasks.Service{
+			Name:           unit.Name,
+			Definition:     s(unit.Content),
+			BeforeServices: unit.Before,
+		}
+		if unit.Enabled != nil && !*unit.Enabled {
+			service.Running = fi.PtrTo(false)
+			service.Enabled = fi.PtrTo(false)
+		}
+		service.InitDefaults()
+
+		c.AddTask(service)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt inspects existing, hand-built network infrastructure,
+// so that a kOps cluster can be created in it as shared resources.
+package adopt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+)
+
+// Options are the IDs of the existing resources to adopt.
+type Options struct {
+	// NetworkID is the ID of the VPC (AWS) or the name of the network (GCE).
+	// On AWS it defaults to the VPC of the subnets.
+	NetworkID string
+	// SubnetIDs are the IDs (AWS) or names (GCE) of the subnets.
+	SubnetIDs []string
+	// SecurityGroupIDs are the IDs of security groups to add to all the instance groups (AWS only).
+	SecurityGroupIDs []string
+	// LoadBalancerNames are the names of classic load balancers to attach to the node instance groups (AWS only).
+	LoadBalancerNames []string
+	// TargetGroupARNs are the ARNs of target groups to attach to the node instance groups (AWS only).
+	TargetGroupARNs []string
+}
+
+// Network is the existing network infrastructure, as inspected.
+type Network struct {
+	// ID is the ID of the VPC (AWS) or the name of the network (GCE).
+	ID string
+	// CIDR is the primary CIDR of the network, if any.
+	CIDR string
+	// AdditionalCIDRs are the other CIDRs associated with the network.
+	AdditionalCIDRs []string
+	// Subnets are the subnets to create the cluster in.
+	Subnets []*Subnet
+	// NatGateways are the IDs of the NAT gateways (AWS) or routers (GCE) providing egress for the private subnets.
+	NatGateways []string
+	// SecurityGroups are the IDs of the security groups to add to all the instance groups.
+	SecurityGroups []string
+	// ExternalLoadBalancers are the load balancers to attach to the node instance groups.
+	ExternalLoadBalancers []kops.LoadBalancerSpec
+	// Warnings are problems found with the infrastructure that do not prevent its adoption.
+	Warnings []string
+}
+
+// Subnet is an existing subnet, as inspected.
+type Subnet struct {
+	// ID is the ID (AWS) or name (GCE) of the subnet.
+	ID string
+	// Zone is the zone of the subnet; GCE subnets are regional and have no zone.
+	Zone string
+	// CIDR is the primary IPv4 CIDR of the subnet.
+	CIDR string
+	// Type is Private if the subnet has no route to an internet gateway, otherwise Public.
+	Type kops.SubnetType
+	// RouteTableID is the ID of the route table of the subnet (AWS only).
+	RouteTableID string
+	// Egress is the ID of the NAT gateway, instance or transit gateway the default route of a private subnet goes through.
+	Egress string
+}
+
+// Topology returns the topology of the cluster: private if any subnet is private.
+func (n *Network) Topology() string {
+	for _, subnet := range n.Subnets {
+		if subnet.Type == kops.SubnetTypePrivate {
+			return kops.TopologyPrivate
+		}
+	}
+	return kops.TopologyPublic
+}
+
+// BuildClusterOptions sets the network options for creating a cluster in the network.
+// With a private topology the public subnets become the utility subnets.
+func (n *Network) BuildClusterOptions(opt *cloudup.NewClusterOptions) {
+	opt.Topology = n.Topology()
+	opt.NetworkID = n.ID
+	opt.SubnetIDs = nil
+	opt.UtilitySubnetIDs = nil
+
+	zones := make(map[string]bool)
+	for _, subnet := range n.Subnets {
+		if opt.Topology == kops.TopologyPrivate && subnet.Type == kops.SubnetTypePublic {
+			opt.UtilitySubnetIDs = append(opt.UtilitySubnetIDs, subnet.ID)
+			continue
+		}
+		opt.SubnetIDs = append(opt.SubnetIDs, subnet.ID)
+		if subnet.Zone != "" {
+			zones[subnet.Zone] = true
+		}
+	}
+	if len(zones) != 0 {
+		opt.Zones = nil
+		for zone := range zones {
+			opt.Zones = append(opt.Zones, zone)
+		}
+		sort.Strings(opt.Zones)
+	}
+}
+
+// Apply completes the cluster and instance groups created with the options from BuildClusterOptions.
+// Private subnets keep their NAT gateway, NAT instance or transit gateway as egress;
+// any other egress is not something kOps can reuse, so it is External.
+func (n *Network) Apply(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) error {
+	if n.CIDR != "" {
+		cluster.Spec.Networking.NetworkCIDR = n.CIDR
+		cluster.Spec.Networking.AdditionalNetworkCIDRs = n.AdditionalCIDRs
+	}
+
+	subnets := make(map[string]*Subnet)
+	for _, subnet := range n.Subnets {
+		subnets[subnet.ID] = subnet
+	}
+	for i := range cluster.Spec.Networking.Subnets {
+		spec := &cluster.Spec.Networking.Subnets[i]
+		subnet := subnets[spec.ID]
+		if subnet == nil {
+			return fmt.Errorf("subnet %q of the cluster was not adopted", spec.Name)
+		}
+		spec.CIDR = subnet.CIDR
+		if spec.Type == kops.SubnetTypePrivate {
+			spec.Egress = kops.EgressExternal
+			for _, prefix := range []string{kops.EgressNatGateway, kops.EgressNatInstance, kops.EgressTransitGateway} {
+				if strings.HasPrefix(subnet.Egress, prefix+"-") {
+					spec.Egress = subnet.Egress
+				}
+			}
+		}
+	}
+
+	for _, ig := range instanceGroups {
+		ig.Spec.AdditionalSecurityGroups = append(ig.Spec.AdditionalSecurityGroups, n.SecurityGroups...)
+		if ig.Spec.Role == kops.InstanceGroupRoleNode {
+			ig.Spec.ExternalLoadBalancers = append(ig.Spec.ExternalLoadBalancers, n.ExternalLoadBalancers...)
+		}
+	}
+	return nil
+}
+
+// LifecycleOverrides returns the lifecycles that make the cloudup tasks for the adopted resources
+// check that the resources exist and match the cluster spec, rather than creating or changing them.
+func LifecycleOverrides(cloudProvider kops.CloudProviderID) (map[string]fi.Lifecycle, error) {
+	var taskNames []string
+	switch cloudProvider {
+	case kops.CloudProviderAWS:
+		taskNames = []string{"VPC", "VPCCIDRBlock", "Subnet", "InternetGateway"}
+	case kops.CloudProviderGCE:
+		taskNames = []string{"Network", "Subnet"}
+	default:
+		return nil, fmt.Errorf("adopting infrastructure on %q is not supported", cloudProvider)
+	}
+
+	overrides := make(map[string]fi.Lifecycle)
+	for _, taskName := range taskNames {
+		overrides[taskName] = fi.LifecycleExistsAndValidates
+	}
+	return overrides, nil
+}
+
+// LifecycleOverrideSpecs returns the lifecycle overrides that make the cloudup tasks for the adopted
+// NAT gateways and security groups check that they exist and match the cluster spec.
+// They are restricted to the adopted IDs, as kOps creates resources of the same types for the cluster.
+func (n *Network) LifecycleOverrideSpecs() []kops.LifecycleOverrideSpec {
+	var overrides []kops.LifecycleOverrideSpec
+	for _, id := range n.NatGateways {
+		if strings.HasPrefix(id, kops.EgressNatGateway+"-") {
+			overrides = append(overrides, kops.LifecycleOverrideSpec{Task: "NatGateway", ID: id, Lifecycle: string(fi.LifecycleExistsAndValidates)})
+		}
+	}
+	for _, id := range n.SecurityGroups {
+		overrides = append(overrides, kops.LifecycleOverrideSpec{Task: "SecurityGroup", ID: id, Lifecycle: string(fi.LifecycleExistsAndValidates)})
+	}
+	return overrides
+}
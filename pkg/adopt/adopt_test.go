@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockelbv2"
+	gcemock "k8s.io/kops/cloudmock/gce"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestInspectAWS(t *testing.T) {
+	ctx := context.TODO()
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "ab")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+	cloud.MockELBV2 = &mockelbv2.MockELBV2{}
+
+	vpc, err := c.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: fi.PtrTo("10.0.0.0/16")})
+	if err != nil {
+		t.Fatalf("error creating VPC: %v", err)
+	}
+	vpcID := vpc.Vpc.VpcId
+
+	createSubnet := func(zone, cidr string) string {
+		subnet, err := c.CreateSubnet(ctx, &ec2.CreateSubnetInput{VpcId: vpcID, AvailabilityZone: fi.PtrTo(zone), CidrBlock: fi.PtrTo(cidr)})
+		if err != nil {
+			t.Fatalf("error creating subnet: %v", err)
+		}
+		return fi.ValueOf(subnet.Subnet.SubnetId)
+	}
+	publicA := createSubnet("us-east-1a", "10.0.0.0/24")
+	publicB := createSubnet("us-east-1b", "10.0.1.0/24")
+	privateA := createSubnet("us-east-1a", "10.0.10.0/24")
+	privateB := createSubnet("us-east-1b", "10.0.11.0/24")
+
+	ngw, err := c.CreateNatGateway(ctx, &ec2.CreateNatGatewayInput{SubnetId: fi.PtrTo(publicA)})
+	if err != nil {
+		t.Fatalf("error creating NAT gateway: %v", err)
+	}
+	ngwID := ngw.NatGateway.NatGatewayId
+
+	// The public subnets use the main route table, the private subnets are explicitly associated
+	c.AddRouteTable(&ec2types.RouteTable{
+		RouteTableId: fi.PtrTo("rtb-public"),
+		VpcId:        vpcID,
+		Associations: []ec2types.RouteTableAssociation{{Main: fi.PtrTo(true)}},
+		Routes:       []ec2types.Route{{DestinationCidrBlock: fi.PtrTo("0.0.0.0/0"), GatewayId: fi.PtrTo("igw-1")}},
+	})
+	c.AddRouteTable(&ec2types.RouteTable{
+		RouteTableId: fi.PtrTo("rtb-private"),
+		VpcId:        vpcID,
+		Associations: []ec2types.RouteTableAssociation{{SubnetId: fi.PtrTo(privateA)}, {SubnetId: fi.PtrTo(privateB)}},
+		Routes:       []ec2types.Route{{DestinationCidrBlock: fi.PtrTo("0.0.0.0/0"), NatGatewayId: ngwID}},
+	})
+	c.AddRouteTable(&ec2types.RouteTable{
+		RouteTableId: fi.PtrTo("rtb-other"),
+		VpcId:        fi.PtrTo("vpc-other"),
+		Associations: []ec2types.RouteTableAssociation{{Main: fi.PtrTo(true)}},
+	})
+
+	sg, err := c.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{GroupName: fi.PtrTo("extra"), VpcId: vpcID})
+	if err != nil {
+		t.Fatalf("error creating security group: %v", err)
+	}
+	tg, err := cloud.MockELBV2.CreateTargetGroup(ctx, &elbv2.CreateTargetGroupInput{Name: fi.PtrTo("ingress"), VpcId: vpcID})
+	if err != nil {
+		t.Fatalf("error creating target group: %v", err)
+	}
+	tgARN := fi.ValueOf(tg.TargetGroups[0].TargetGroupArn)
+
+	network, err := InspectAWS(ctx, cloud, &Options{
+		SubnetIDs:        []string{privateA, privateB, publicA, publicB},
+		SecurityGroupIDs: []string{fi.ValueOf(sg.GroupId)},
+		TargetGroupARNs:  []string{tgARN},
+	})
+	if err != nil {
+		t.Fatalf("error inspecting network: %v", err)
+	}
+
+	if network.ID != fi.ValueOf(vpcID) || network.CIDR != "10.0.0.0/16" {
+		t.Errorf("unexpected network %+v", network)
+	}
+	expectedSubnets := []*Subnet{
+		{ID: privateA, Zone: "us-east-1a", CIDR: "10.0.10.0/24", Type: kops.SubnetTypePrivate, RouteTableID: "rtb-private", Egress: fi.ValueOf(ngwID)},
+		{ID: privateB, Zone: "us-east-1b", CIDR: "10.0.11.0/24", Type: kops.SubnetTypePrivate, RouteTableID: "rtb-private", Egress: fi.ValueOf(ngwID)},
+		{ID: publicA, Zone: "us-east-1a", CIDR: "10.0.0.0/24", Type: kops.SubnetTypePublic, RouteTableID: "rtb-public"},
+		{ID: publicB, Zone: "us-east-1b", CIDR: "10.0.1.0/24", Type: kops.SubnetTypePublic, RouteTableID: "rtb-public"},
+	}
+	if !reflect.DeepEqual(network.Subnets, expectedSubnets) {
+		for _, subnet := range network.Subnets {
+			t.Logf("subnet %+v", subnet)
+		}
+		t.Errorf("unexpected subnets")
+	}
+	if !reflect.DeepEqual(network.NatGateways, []string{fi.ValueOf(ngwID)}) {
+		t.Errorf("unexpected NAT gateways %v", network.NatGateways)
+	}
+	if !reflect.DeepEqual(network.SecurityGroups, []string{fi.ValueOf(sg.GroupId)}) {
+		t.Errorf("unexpected security groups %v", network.SecurityGroups)
+	}
+	if len(network.ExternalLoadBalancers) != 1 || fi.ValueOf(network.ExternalLoadBalancers[0].TargetGroupARN) != tgARN {
+		t.Errorf("unexpected external load balancers %v", network.ExternalLoadBalancers)
+	}
+
+	opt := &cloudup.NewClusterOptions{}
+	network.BuildClusterOptions(opt)
+	if opt.Topology != kops.TopologyPrivate || opt.NetworkID != fi.ValueOf(vpcID) {
+		t.Errorf("unexpected cluster options %+v", opt)
+	}
+	if !reflect.DeepEqual(opt.SubnetIDs, []string{privateA, privateB}) || !reflect.DeepEqual(opt.UtilitySubnetIDs, []string{publicA, publicB}) {
+		t.Errorf("unexpected subnet options %v, %v", opt.SubnetIDs, opt.UtilitySubnetIDs)
+	}
+	if !reflect.DeepEqual(opt.Zones, []string{"us-east-1a", "us-east-1b"}) {
+		t.Errorf("unexpected zones %v", opt.Zones)
+	}
+
+	// Two private subnets in the same zone cannot be mapped to a cluster
+	privateA2 := createSubnet("us-east-1a", "10.0.12.0/24")
+	c.RouteTables["rtb-private"].Associations = append(c.RouteTables["rtb-private"].Associations, ec2types.RouteTableAssociation{SubnetId: fi.PtrTo(privateA2)})
+	if _, err := InspectAWS(ctx, cloud, &Options{SubnetIDs: []string{privateA, privateA2}}); err == nil {
+		t.Errorf("expected error inspecting two private subnets in the same zone")
+	}
+
+	// A default route through a deleted NAT gateway is not usable
+	c.RouteTables["rtb-private"].Routes[0].State = ec2types.RouteStateBlackhole
+	if _, err := InspectAWS(ctx, cloud, &Options{SubnetIDs: []string{privateA}}); err == nil {
+		t.Errorf("expected error inspecting a subnet whose default route is a blackhole")
+	}
+}
+
+func TestInspectGCE(t *testing.T) {
+	ctx := context.TODO()
+	cloud := gcemock.InstallMockGCECloud("us-central1", "testproject")
+
+	if _, err := cloud.Compute().Networks().Insert("testproject", &compute.Network{Name: "shared"}); err != nil {
+		t.Fatalf("error creating network: %v", err)
+	}
+	for _, name := range []string{"private", "public"} {
+		if _, err := cloud.Compute().Subnetworks().Insert("testproject", "us-central1", &compute.Subnetwork{
+			Name:        name,
+			Network:     "https://www.googleapis.com/compute/v1/projects/testproject/global/networks/shared",
+			IpCidrRange: "10.0.0.0/20",
+		}); err != nil {
+			t.Fatalf("error creating subnet: %v", err)
+		}
+	}
+	if _, err := cloud.Compute().Routers().Insert("testproject", "us-central1", &compute.Router{
+		Name:    "nat",
+		Network: "https://www.googleapis.com/compute/v1/projects/testproject/global/networks/shared",
+		Nats: []*compute.RouterNat{
+			{
+				Name:                          "nat",
+				SourceSubnetworkIpRangesToNat: "LIST_OF_SUBNETWORKS",
+				Subnetworks: []*compute.RouterNatSubnetworkToNat{
+					{Name: "https://www.googleapis.com/compute/v1/projects/testproject/regions/us-central1/subnetworks/private"},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("error creating router: %v", err)
+	}
+
+	grid := []struct {
+		Subnet      string
+		Type        kops.SubnetType
+		NatGateways []string
+	}{
+		{Subnet: "private", Type: kops.SubnetTypePrivate, NatGateways: []string{"nat"}},
+		{Subnet: "public", Type: kops.SubnetTypePublic},
+	}
+	for _, g := range grid {
+		t.Run(g.Subnet, func(t *testing.T) {
+			network, err := InspectGCE(ctx, cloud, &Options{NetworkID: "shared", SubnetIDs: []string{g.Subnet}})
+			if err != nil {
+				t.Fatalf("error inspecting network: %v", err)
+			}
+			expected := []*Subnet{{ID: g.Subnet, CIDR: "10.0.0.0/20", Type: g.Type}}
+			if network.ID != "shared" || !reflect.DeepEqual(network.Subnets, expected) || !reflect.DeepEqual(network.NatGateways, g.NatGateways) {
+				t.Errorf("unexpected network %+v", network)
+			}
+		})
+	}
+
+	if _, err := InspectGCE(ctx, cloud, &Options{NetworkID: "other", SubnetIDs: []string{"private"}}); err == nil {
+		t.Errorf("expected error inspecting missing network")
+	}
+}
+
+func TestApply(t *testing.T) {
+	network := &Network{
+		ID:   "vpc-1",
+		CIDR: "10.0.0.0/16",
+		Subnets: []*Subnet{
+			{ID: "subnet-private", Zone: "us-east-1a", CIDR: "10.0.10.0/24", Type: kops.SubnetTypePrivate, Egress: "nat-1"},
+			{ID: "subnet-private-b", Zone: "us-east-1b", CIDR: "10.0.11.0/24", Type: kops.SubnetTypePrivate, Egress: "vgw-1"},
+			{ID: "subnet-public", Zone: "us-east-1a", CIDR: "10.0.0.0/24", Type: kops.SubnetTypePublic},
+		},
+		NatGateways:           []string{"nat-1"},
+		SecurityGroups:        []string{"sg-1"},
+		ExternalLoadBalancers: []kops.LoadBalancerSpec{{LoadBalancerName: fi.PtrTo("ingress")}},
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.Networking.Subnets = []kops.ClusterSubnetSpec{
+		{Name: "us-east-1a", ID: "subnet-private", Type: kops.SubnetTypePrivate},
+		{Name: "us-east-1b", ID: "subnet-private-b", Type: kops.SubnetTypePrivate},
+		{Name: "utility-us-east-1a", ID: "subnet-public", Type: kops.SubnetTypeUtility},
+	}
+	controlPlane := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleControlPlane}}
+	nodes := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}}
+
+	if err := network.Apply(cluster, []*kops.InstanceGroup{controlPlane, nodes}); err != nil {
+		t.Fatalf("error applying network: %v", err)
+	}
+
+	if cluster.Spec.Networking.NetworkCIDR != "10.0.0.0/16" {
+		t.Errorf("unexpected network CIDR %q", cluster.Spec.Networking.NetworkCIDR)
+	}
+	expectedSubnets := []kops.ClusterSubnetSpec{
+		{Name: "us-east-1a", ID: "subnet-private", CIDR: "10.0.10.0/24", Type: kops.SubnetTypePrivate, Egress: "nat-1"},
+		{Name: "us-east-1b", ID: "subnet-private-b", CIDR: "10.0.11.0/24", Type: kops.SubnetTypePrivate, Egress: kops.EgressExternal},
+		{Name: "utility-us-east-1a", ID: "subnet-public", CIDR: "10.0.0.0/24", Type: kops.SubnetTypeUtility},
+	}
+	if !reflect.DeepEqual(cluster.Spec.Networking.Subnets, expectedSubnets) {
+		t.Errorf("unexpected subnets %+v", cluster.Spec.Networking.Subnets)
+	}
+	if !reflect.DeepEqual(controlPlane.Spec.AdditionalSecurityGroups, []string{"sg-1"}) || len(controlPlane.Spec.ExternalLoadBalancers) != 0 {
+		t.Errorf("unexpected control plane instance group %+v", controlPlane.Spec)
+	}
+	if !reflect.DeepEqual(nodes.Spec.AdditionalSecurityGroups, []string{"sg-1"}) || !reflect.DeepEqual(nodes.Spec.ExternalLoadBalancers, network.ExternalLoadBalancers) {
+		t.Errorf("unexpected node instance group %+v", nodes.Spec)
+	}
+
+	expectedOverrides := []kops.LifecycleOverrideSpec{
+		{Task: "NatGateway", ID: "nat-1", Lifecycle: "ExistsAndValidates"},
+		{Task: "SecurityGroup", ID: "sg-1", Lifecycle: "ExistsAndValidates"},
+	}
+	if overrides := network.LifecycleOverrideSpecs(); !reflect.DeepEqual(overrides, expectedOverrides) {
+		t.Errorf("unexpected lifecycle overrides %+v", overrides)
+	}
+
+	cluster.Spec.Networking.Subnets = append(cluster.Spec.Networking.Subnets, kops.ClusterSubnetSpec{Name: "us-east-1c", ID: "subnet-other"})
+	if err := network.Apply(cluster, nil); err == nil {
+		t.Errorf("expected error applying network to a cluster with a subnet that was not adopted")
+	}
+}
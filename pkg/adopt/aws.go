@@ -0,0 +1,294 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// InspectAWS inspects the VPC, subnets, route tables, NAT gateways, security groups and load balancers to adopt.
+func InspectAWS(ctx context.Context, cloud awsup.AWSCloud, opt *Options) (*Network, error) {
+	if len(opt.SubnetIDs) == 0 {
+		return nil, fmt.Errorf("at least one subnet must be specified")
+	}
+
+	subnets, err := cloud.EC2().DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: opt.SubnetIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing subnets: %w", err)
+	}
+
+	network := &Network{ID: opt.NetworkID}
+	for _, id := range opt.SubnetIDs {
+		var found *ec2types.Subnet
+		for i := range subnets.Subnets {
+			if aws.ToString(subnets.Subnets[i].SubnetId) == id {
+				found = &subnets.Subnets[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("subnet %q not found", id)
+		}
+		vpcID := aws.ToString(found.VpcId)
+		if network.ID == "" {
+			network.ID = vpcID
+		} else if network.ID != vpcID {
+			return nil, fmt.Errorf("subnet %q is in VPC %q, not %q", id, vpcID, network.ID)
+		}
+		network.Subnets = append(network.Subnets, &Subnet{
+			ID:   id,
+			Zone: aws.ToString(found.AvailabilityZone),
+			CIDR: aws.ToString(found.CidrBlock),
+		})
+	}
+
+	vpcs, err := cloud.EC2().DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []string{network.ID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing VPC %q: %w", network.ID, err)
+	}
+	if len(vpcs.Vpcs) != 1 {
+		return nil, fmt.Errorf("VPC %q not found", network.ID)
+	}
+	vpc := vpcs.Vpcs[0]
+	network.CIDR = aws.ToString(vpc.CidrBlock)
+	for _, association := range vpc.CidrBlockAssociationSet {
+		cidr := aws.ToString(association.CidrBlock)
+		if cidr == network.CIDR || association.CidrBlockState == nil || association.CidrBlockState.State != ec2types.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		network.AdditionalCIDRs = append(network.AdditionalCIDRs, cidr)
+	}
+
+	if err := inspectAWSRouteTables(ctx, cloud, network); err != nil {
+		return nil, err
+	}
+	if err := inspectAWSNatGateways(ctx, cloud, network); err != nil {
+		return nil, err
+	}
+	if err := checkAWSSubnetZones(network); err != nil {
+		return nil, err
+	}
+
+	if len(opt.SecurityGroupIDs) != 0 {
+		response, err := cloud.EC2().DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+			GroupIds: opt.SecurityGroupIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing security groups: %w", err)
+		}
+		for _, id := range opt.SecurityGroupIDs {
+			found := false
+			for _, sg := range response.SecurityGroups {
+				if aws.ToString(sg.GroupId) != id {
+					continue
+				}
+				if aws.ToString(sg.VpcId) != network.ID {
+					return nil, fmt.Errorf("security group %q is in VPC %q, not %q", id, aws.ToString(sg.VpcId), network.ID)
+				}
+				found = true
+			}
+			if !found {
+				return nil, fmt.Errorf("security group %q not found", id)
+			}
+			network.SecurityGroups = append(network.SecurityGroups, id)
+		}
+	}
+
+	if len(opt.LoadBalancerNames) != 0 {
+		response, err := cloud.ELB().DescribeLoadBalancers(ctx, &elb.DescribeLoadBalancersInput{
+			LoadBalancerNames: opt.LoadBalancerNames,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing load balancers: %w", err)
+		}
+		for _, name := range opt.LoadBalancerNames {
+			found := false
+			for _, lb := range response.LoadBalancerDescriptions {
+				if aws.ToString(lb.LoadBalancerName) != name {
+					continue
+				}
+				if aws.ToString(lb.VPCId) != network.ID {
+					return nil, fmt.Errorf("load balancer %q is in VPC %q, not %q", name, aws.ToString(lb.VPCId), network.ID)
+				}
+				found = true
+			}
+			if !found {
+				return nil, fmt.Errorf("load balancer %q not found", name)
+			}
+			network.ExternalLoadBalancers = append(network.ExternalLoadBalancers, kops.LoadBalancerSpec{
+				LoadBalancerName: aws.String(name),
+			})
+		}
+	}
+
+	if len(opt.TargetGroupARNs) != 0 {
+		response, err := cloud.ELBV2().DescribeTargetGroups(ctx, &elbv2.DescribeTargetGroupsInput{
+			TargetGroupArns: opt.TargetGroupARNs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing target groups: %w", err)
+		}
+		for _, arn := range opt.TargetGroupARNs {
+			found := false
+			for _, tg := range response.TargetGroups {
+				if aws.ToString(tg.TargetGroupArn) != arn {
+					continue
+				}
+				if aws.ToString(tg.VpcId) != network.ID {
+					return nil, fmt.Errorf("target group %q is in VPC %q, not %q", arn, aws.ToString(tg.VpcId), network.ID)
+				}
+				found = true
+			}
+			if !found {
+				return nil, fmt.Errorf("target group %q not found", arn)
+			}
+			network.ExternalLoadBalancers = append(network.ExternalLoadBalancers, kops.LoadBalancerSpec{
+				TargetGroupARN: aws.String(arn),
+			})
+		}
+	}
+
+	return network, nil
+}
+
+// inspectAWSRouteTables classifies the subnets as public or private from the default route of their route table.
+func inspectAWSRouteTables(ctx context.Context, cloud awsup.AWSCloud, network *Network) error {
+	response, err := cloud.EC2().DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []ec2types.Filter{awsup.NewEC2Filter("vpc-id", network.ID)},
+	})
+	if err != nil {
+		return fmt.Errorf("error describing route tables of VPC %q: %w", network.ID, err)
+	}
+
+	var mainRouteTable *ec2types.RouteTable
+	routeTables := make(map[string]*ec2types.RouteTable)
+	for i := range response.RouteTables {
+		rt := &response.RouteTables[i]
+		for _, association := range rt.Associations {
+			if aws.ToBool(association.Main) {
+				mainRouteTable = rt
+			}
+			if association.SubnetId != nil {
+				routeTables[*association.SubnetId] = rt
+			}
+		}
+	}
+
+	for _, subnet := range network.Subnets {
+		rt := routeTables[subnet.ID]
+		if rt == nil {
+			// Subnets without an explicit association use the main route table
+			rt = mainRouteTable
+		}
+		if rt == nil {
+			return fmt.Errorf("route table of subnet %q not found", subnet.ID)
+		}
+		subnet.RouteTableID = aws.ToString(rt.RouteTableId)
+		subnet.Type = kops.SubnetTypePrivate
+
+		var defaultRoute *ec2types.Route
+		for i := range rt.Routes {
+			if aws.ToString(rt.Routes[i].DestinationCidrBlock) == "0.0.0.0/0" {
+				defaultRoute = &rt.Routes[i]
+			}
+		}
+		if defaultRoute != nil && defaultRoute.State == ec2types.RouteStateBlackhole {
+			return fmt.Errorf("default route of subnet %q in route table %q is a blackhole", subnet.ID, subnet.RouteTableID)
+		}
+		switch {
+		case defaultRoute == nil:
+			network.Warnings = append(network.Warnings, fmt.Sprintf("route table %q of subnet %q has no default route", subnet.RouteTableID, subnet.ID))
+		case strings.HasPrefix(aws.ToString(defaultRoute.GatewayId), "igw-"):
+			subnet.Type = kops.SubnetTypePublic
+		case defaultRoute.NatGatewayId != nil:
+			subnet.Egress = aws.ToString(defaultRoute.NatGatewayId)
+		case defaultRoute.TransitGatewayId != nil:
+			subnet.Egress = aws.ToString(defaultRoute.TransitGatewayId)
+		case defaultRoute.InstanceId != nil:
+			subnet.Egress = aws.ToString(defaultRoute.InstanceId)
+		default:
+			network.Warnings = append(network.Warnings, fmt.Sprintf("default route of subnet %q is neither through an internet gateway nor a NAT", subnet.ID))
+		}
+	}
+	return nil
+}
+
+// inspectAWSNatGateways checks that the NAT gateways used by the private subnets are available.
+func inspectAWSNatGateways(ctx context.Context, cloud awsup.AWSCloud, network *Network) error {
+	var ids []string
+	for _, subnet := range network.Subnets {
+		if strings.HasPrefix(subnet.Egress, "nat-") && !slices.Contains(ids, subnet.Egress) {
+			ids = append(ids, subnet.Egress)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Strings(ids)
+
+	response, err := cloud.EC2().DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{
+		NatGatewayIds: ids,
+	})
+	if err != nil {
+		return fmt.Errorf("error describing NAT gateways: %w", err)
+	}
+	for _, id := range ids {
+		var found *ec2types.NatGateway
+		for i := range response.NatGateways {
+			if aws.ToString(response.NatGateways[i].NatGatewayId) == id {
+				found = &response.NatGateways[i]
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("NAT gateway %q not found", id)
+		}
+		if found.State != "" && found.State != ec2types.NatGatewayStateAvailable {
+			network.Warnings = append(network.Warnings, fmt.Sprintf("NAT gateway %q is %s", id, found.State))
+		}
+		network.NatGateways = append(network.NatGateways, id)
+	}
+	return nil
+}
+
+// checkAWSSubnetZones checks there is at most one public and one private subnet in each zone,
+// as kOps maps each zone to a single subnet of each type.
+func checkAWSSubnetZones(network *Network) error {
+	seen := make(map[string]string)
+	for _, subnet := range network.Subnets {
+		key := string(subnet.Type) + "/" + subnet.Zone
+		if other, found := seen[key]; found {
+			return fmt.Errorf("subnets %q and %q are both %s subnets in zone %q", other, subnet.ID, strings.ToLower(string(subnet.Type)), subnet.Zone)
+		}
+		seen[key] = subnet.ID
+	}
+	return nil
+}
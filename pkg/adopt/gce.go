@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+// InspectGCE inspects the network, subnet and Cloud NAT routers to adopt.
+// kOps creates GCE clusters in a single, regional subnet.
+func InspectGCE(ctx context.Context, cloud gce.GCECloud, opt *Options) (*Network, error) {
+	if opt.NetworkID == "" {
+		return nil, fmt.Errorf("the network must be specified")
+	}
+	if len(opt.SubnetIDs) != 1 {
+		return nil, fmt.Errorf("exactly one subnet must be specified for GCE, got %d", len(opt.SubnetIDs))
+	}
+	if len(opt.SecurityGroupIDs) != 0 || len(opt.LoadBalancerNames) != 0 || len(opt.TargetGroupARNs) != 0 {
+		return nil, fmt.Errorf("adopting security groups and load balancers is only supported on AWS")
+	}
+
+	project := cloud.Project()
+	region := cloud.Region()
+
+	network, err := cloud.Compute().Networks().Get(project, opt.NetworkID)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return nil, fmt.Errorf("network %q not found", opt.NetworkID)
+		}
+		return nil, fmt.Errorf("error getting network %q: %w", opt.NetworkID, err)
+	}
+
+	result := &Network{
+		ID:   network.Name,
+		CIDR: network.IPv4Range,
+	}
+
+	name := opt.SubnetIDs[0]
+	subnet, err := cloud.Compute().Subnetworks().Get(project, region, name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return nil, fmt.Errorf("subnet %q not found in region %q", name, region)
+		}
+		return nil, fmt.Errorf("error getting subnet %q: %w", name, err)
+	}
+	if gce.LastComponent(subnet.Network) != network.Name {
+		return nil, fmt.Errorf("subnet %q is in network %q, not %q", name, gce.LastComponent(subnet.Network), network.Name)
+	}
+
+	// A subnet is private if a Cloud NAT provides it with egress
+	routers, err := cloud.Compute().Routers().List(ctx, project, region)
+	if err != nil {
+		return nil, fmt.Errorf("error listing routers: %w", err)
+	}
+	subnetType := kops.SubnetTypePublic
+	for _, router := range routers {
+		if gce.LastComponent(router.Network) != network.Name {
+			continue
+		}
+		for _, nat := range router.Nats {
+			covered := false
+			switch nat.SourceSubnetworkIpRangesToNat {
+			case "ALL_SUBNETWORKS_ALL_IP_RANGES", "ALL_SUBNETWORKS_ALL_PRIMARY_IP_RANGES":
+				covered = true
+			default:
+				covered = slices.ContainsFunc(nat.Subnetworks, func(s *compute.RouterNatSubnetworkToNat) bool {
+					return gce.LastComponent(s.Name) == subnet.Name
+				})
+			}
+			if covered {
+				subnetType = kops.SubnetTypePrivate
+				if !slices.Contains(result.NatGateways, router.Name) {
+					result.NatGateways = append(result.NatGateways, router.Name)
+				}
+			}
+		}
+	}
+
+	result.Subnets = append(result.Subnets, &Subnet{
+		ID:   subnet.Name,
+		CIDR: subnet.IpCidrRange,
+		Type: subnetType,
+	})
+	return result, nil
+}
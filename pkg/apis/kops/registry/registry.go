@@ -31,6 +31,12 @@ const (
 	PathClusterCompleted = "cluster-completed.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathDeletionManifest is the path for the manifest recording the progress of deleting the cluster.
+	PathDeletionManifest = "deletion.yaml"
+	// PathDeletionHistory is the directory the deletion manifest is kept in once the cluster has been deleted.
+	PathDeletionHistory = "deletions"
+	// PathUpgradeProgress is the path for the progress of an orchestrated upgrade of the cluster.
+	PathUpgradeProgress = "upgrade.yaml"
 )
 
 func ConfigBase(vfsContext *vfs.VFSContext, c *api.Cluster) (vfs.Path, error) {
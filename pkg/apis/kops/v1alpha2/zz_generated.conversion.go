@@ -84,6 +84,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AcceleratorRuntimeSpec)(nil), (*kops.AcceleratorRuntimeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec(a.(*AcceleratorRuntimeSpec), b.(*kops.AcceleratorRuntimeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AcceleratorRuntimeSpec)(nil), (*AcceleratorRuntimeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec(a.(*kops.AcceleratorRuntimeSpec), b.(*AcceleratorRuntimeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AcceleratorSpec)(nil), (*kops.AcceleratorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec(a.(*AcceleratorSpec), b.(*kops.AcceleratorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AcceleratorSpec)(nil), (*AcceleratorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec(a.(*kops.AcceleratorSpec), b.(*AcceleratorSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AccessLogSpec)(nil), (*kops.AccessLogSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(a.(*AccessLogSpec), b.(*kops.AccessLogSpec), scope)
 	}); err != nil {
@@ -124,6 +144,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AssetVerificationSpec)(nil), (*kops.AssetVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec(a.(*AssetVerificationSpec), b.(*kops.AssetVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AssetVerificationSpec)(nil), (*AssetVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec(a.(*kops.AssetVerificationSpec), b.(*AssetVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AssetsSpec)(nil), (*kops.AssetsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(a.(*AssetsSpec), b.(*kops.AssetsSpec), scope)
 	}); err != nil {
@@ -304,6 +334,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRegistryConfig)(nil), (*kops.ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(a.(*ContainerdRegistryConfig), b.(*kops.ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRegistryConfig)(nil), (*ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(a.(*kops.ContainerdRegistryConfig), b.(*ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRegistryHostConfig)(nil), (*kops.ContainerdRegistryHostConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig(a.(*ContainerdRegistryHostConfig), b.(*kops.ContainerdRegistryHostConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRegistryHostConfig)(nil), (*ContainerdRegistryHostConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig(a.(*kops.ContainerdRegistryHostConfig), b.(*ContainerdRegistryHostConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CustomResourcesValidationCheck)(nil), (*kops.CustomResourcesValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck(a.(*CustomResourcesValidationCheck), b.(*kops.CustomResourcesValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CustomResourcesValidationCheck)(nil), (*CustomResourcesValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck(a.(*kops.CustomResourcesValidationCheck), b.(*CustomResourcesValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DCGMExporterConfig)(nil), (*kops.DCGMExporterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(a.(*DCGMExporterConfig), b.(*kops.DCGMExporterConfig), scope)
 	}); err != nil {
@@ -344,6 +404,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeploymentsValidationCheck)(nil), (*kops.DeploymentsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(a.(*DeploymentsValidationCheck), b.(*kops.DeploymentsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DeploymentsValidationCheck)(nil), (*DeploymentsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(a.(*kops.DeploymentsValidationCheck), b.(*DeploymentsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DevicePluginSpec)(nil), (*kops.DevicePluginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec(a.(*DevicePluginSpec), b.(*kops.DevicePluginSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DevicePluginSpec)(nil), (*DevicePluginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec(a.(*kops.DevicePluginSpec), b.(*DevicePluginSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DockerConfig)(nil), (*kops.DockerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DockerConfig_To_kops_DockerConfig(a.(*DockerConfig), b.(*kops.DockerConfig), scope)
 	}); err != nil {
@@ -494,6 +574,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HTTPGetValidationCheck)(nil), (*kops.HTTPGetValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck(a.(*HTTPGetValidationCheck), b.(*kops.HTTPGetValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HTTPGetValidationCheck)(nil), (*HTTPGetValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck(a.(*kops.HTTPGetValidationCheck), b.(*HTTPGetValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HTTPProxy)(nil), (*kops.HTTPProxy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(a.(*HTTPProxy), b.(*kops.HTTPProxy), scope)
 	}); err != nil {
@@ -734,6 +824,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LifecycleOverrideSpec)(nil), (*kops.LifecycleOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(a.(*LifecycleOverrideSpec), b.(*kops.LifecycleOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LifecycleOverrideSpec)(nil), (*LifecycleOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(a.(*kops.LifecycleOverrideSpec), b.(*LifecycleOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadBalancerAccessSpec)(nil), (*kops.LoadBalancerAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LoadBalancerAccessSpec_To_kops_LoadBalancerAccessSpec(a.(*LoadBalancerAccessSpec), b.(*kops.LoadBalancerAccessSpec), scope)
 	}); err != nil {
@@ -864,6 +964,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeConditionsValidationCheck)(nil), (*kops.NodeConditionsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(a.(*NodeConditionsValidationCheck), b.(*kops.NodeConditionsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeConditionsValidationCheck)(nil), (*NodeConditionsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(a.(*kops.NodeConditionsValidationCheck), b.(*NodeConditionsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -904,6 +1014,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OSPackagesSpec)(nil), (*kops.OSPackagesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(a.(*OSPackagesSpec), b.(*kops.OSPackagesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OSPackagesSpec)(nil), (*OSPackagesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(a.(*kops.OSPackagesSpec), b.(*OSPackagesSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -984,6 +1104,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageRepositorySpec)(nil), (*kops.PackageRepositorySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec(a.(*PackageRepositorySpec), b.(*kops.PackageRepositorySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PackageRepositorySpec)(nil), (*PackageRepositorySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec(a.(*kops.PackageRepositorySpec), b.(*PackageRepositorySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackagesConfig)(nil), (*kops.PackagesConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(a.(*PackagesConfig), b.(*kops.PackagesConfig), scope)
 	}); err != nil {
@@ -994,6 +1124,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PinnedPackageSpec)(nil), (*kops.PinnedPackageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec(a.(*PinnedPackageSpec), b.(*kops.PinnedPackageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PinnedPackageSpec)(nil), (*PinnedPackageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec(a.(*kops.PinnedPackageSpec), b.(*PinnedPackageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PluginValidationCheck)(nil), (*kops.PluginValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck(a.(*PluginValidationCheck), b.(*kops.PluginValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PluginValidationCheck)(nil), (*PluginValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck(a.(*kops.PluginValidationCheck), b.(*PluginValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodDisruptionBudgetsValidationCheck)(nil), (*kops.PodDisruptionBudgetsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck(a.(*PodDisruptionBudgetsValidationCheck), b.(*kops.PodDisruptionBudgetsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PodDisruptionBudgetsValidationCheck)(nil), (*PodDisruptionBudgetsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck(a.(*kops.PodDisruptionBudgetsValidationCheck), b.(*PodDisruptionBudgetsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PodIdentityWebhookSpec)(nil), (*kops.PodIdentityWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_PodIdentityWebhookSpec_To_kops_PodIdentityWebhookSpec(a.(*PodIdentityWebhookSpec), b.(*kops.PodIdentityWebhookSpec), scope)
 	}); err != nil {
@@ -1114,6 +1274,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SystemdDropInSpec)(nil), (*kops.SystemdDropInSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec(a.(*SystemdDropInSpec), b.(*kops.SystemdDropInSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SystemdDropInSpec)(nil), (*SystemdDropInSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec(a.(*kops.SystemdDropInSpec), b.(*SystemdDropInSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SystemdUnitSpec)(nil), (*kops.SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(a.(*SystemdUnitSpec), b.(*kops.SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SystemdUnitSpec)(nil), (*SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(a.(*kops.SystemdUnitSpec), b.(*SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1144,6 +1324,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ValidationCheckSpec)(nil), (*kops.ValidationCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec(a.(*ValidationCheckSpec), b.(*kops.ValidationCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ValidationCheckSpec)(nil), (*ValidationCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec(a.(*kops.ValidationCheckSpec), b.(*ValidationCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1439,6 +1629,90 @@ func Convert_kops_AcceleratorConfig_To_v1alpha2_AcceleratorConfig(in *kops.Accel
 	return autoConvert_kops_AcceleratorConfig_To_v1alpha2_AcceleratorConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec(in *AcceleratorRuntimeSpec, out *kops.AcceleratorRuntimeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryName = in.BinaryName
+	return nil
+}
+
+// Convert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec(in *AcceleratorRuntimeSpec, out *kops.AcceleratorRuntimeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec(in, out, s)
+}
+
+func autoConvert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec(in *kops.AcceleratorRuntimeSpec, out *AcceleratorRuntimeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryName = in.BinaryName
+	return nil
+}
+
+// Convert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec is an autogenerated conversion function.
+func Convert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec(in *kops.AcceleratorRuntimeSpec, out *AcceleratorRuntimeSpec, s conversion.Scope) error {
+	return autoConvert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec(in *AcceleratorSpec, out *kops.AcceleratorSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Vendor = kops.AcceleratorVendor(in.Vendor)
+	out.Packages = in.Packages
+	out.KernelModules = in.KernelModules
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(kops.AcceleratorRuntimeSpec)
+		if err := Convert_v1alpha2_AcceleratorRuntimeSpec_To_kops_AcceleratorRuntimeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Runtime = nil
+	}
+	if in.DevicePlugin != nil {
+		in, out := &in.DevicePlugin, &out.DevicePlugin
+		*out = new(kops.DevicePluginSpec)
+		if err := Convert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.DevicePlugin = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec(in *AcceleratorSpec, out *kops.AcceleratorSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec(in, out, s)
+}
+
+func autoConvert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec(in *kops.AcceleratorSpec, out *AcceleratorSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Vendor = AcceleratorVendor(in.Vendor)
+	out.Packages = in.Packages
+	out.KernelModules = in.KernelModules
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(AcceleratorRuntimeSpec)
+		if err := Convert_kops_AcceleratorRuntimeSpec_To_v1alpha2_AcceleratorRuntimeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Runtime = nil
+	}
+	if in.DevicePlugin != nil {
+		in, out := &in.DevicePlugin, &out.DevicePlugin
+		*out = new(DevicePluginSpec)
+		if err := Convert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.DevicePlugin = nil
+	}
+	return nil
+}
+
+// Convert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec is an autogenerated conversion function.
+func Convert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec(in *kops.AcceleratorSpec, out *AcceleratorSpec, s conversion.Scope) error {
+	return autoConvert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(in *AccessLogSpec, out *kops.AccessLogSpec, s conversion.Scope) error {
 	out.Interval = in.Interval
 	out.Bucket = in.Bucket
@@ -1547,10 +1821,45 @@ func Convert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in
 	return autoConvert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec(in *AssetVerificationSpec, out *kops.AssetVerificationSpec, s conversion.Scope) error {
+	out.PublicKey = in.PublicKey
+	out.Bundle = in.Bundle
+	out.Files = in.Files
+	out.Images = in.Images
+	return nil
+}
+
+// Convert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec(in *AssetVerificationSpec, out *kops.AssetVerificationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec(in, out, s)
+}
+
+func autoConvert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec(in *kops.AssetVerificationSpec, out *AssetVerificationSpec, s conversion.Scope) error {
+	out.PublicKey = in.PublicKey
+	out.Bundle = in.Bundle
+	out.Files = in.Files
+	out.Images = in.Images
+	return nil
+}
+
+// Convert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec is an autogenerated conversion function.
+func Convert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec(in *kops.AssetVerificationSpec, out *AssetVerificationSpec, s conversion.Scope) error {
+	return autoConvert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kops.AssetsSpec, s conversion.Scope) error {
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(kops.AssetVerificationSpec)
+		if err := Convert_v1alpha2_AssetVerificationSpec_To_kops_AssetVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Verification = nil
+	}
 	return nil
 }
 
@@ -1563,6 +1872,15 @@ func autoConvert_kops_AssetsSpec_To_v1alpha2_AssetsSpec(in *kops.AssetsSpec, out
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(AssetVerificationSpec)
+		if err := Convert_kops_AssetVerificationSpec_To_v1alpha2_AssetVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Verification = nil
+	}
 	return nil
 }
 
@@ -2617,6 +2935,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.NTP = nil
 	}
 	out.Packages = in.Packages
+	if in.OSPackages != nil {
+		in, out := &in.OSPackages, &out.OSPackages
+		*out = new(kops.OSPackagesSpec)
+		if err := Convert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPackages = nil
+	}
 	// INFO: in.NodeTerminationHandler opted out of conversion generation
 	if in.NodeProblemDetector != nil {
 		in, out := &in.NodeProblemDetector, &out.NodeProblemDetector
@@ -2693,6 +3020,17 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Hooks = nil
 	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(kops.AssetsSpec)
@@ -2770,6 +3108,29 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Karpenter = nil
 	}
+	out.DeletionProtection = in.DeletionProtection
+	if in.LifecycleOverrides != nil {
+		in, out := &in.LifecycleOverrides, &out.LifecycleOverrides
+		*out = make([]kops.LifecycleOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.LifecycleOverrides = nil
+	}
+	if in.ValidationChecks != nil {
+		in, out := &in.ValidationChecks, &out.ValidationChecks
+		*out = make([]kops.ValidationCheckSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ValidationChecks = nil
+	}
 	// INFO: in.PodIdentityWebhook opted out of conversion generation
 	return nil
 }
@@ -2957,6 +3318,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.NTP = nil
 	}
 	out.Packages = in.Packages
+	if in.OSPackages != nil {
+		in, out := &in.OSPackages, &out.OSPackages
+		*out = new(OSPackagesSpec)
+		if err := Convert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPackages = nil
+	}
 	if in.NodeProblemDetector != nil {
 		in, out := &in.NodeProblemDetector, &out.NodeProblemDetector
 		*out = new(NodeProblemDetectorConfig)
@@ -3029,6 +3399,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Hooks = nil
 	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(AssetsSpec)
@@ -3104,6 +3485,29 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Karpenter = nil
 	}
+	out.DeletionProtection = in.DeletionProtection
+	if in.LifecycleOverrides != nil {
+		in, out := &in.LifecycleOverrides, &out.LifecycleOverrides
+		*out = make([]LifecycleOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.LifecycleOverrides = nil
+	}
+	if in.ValidationChecks != nil {
+		in, out := &in.ValidationChecks, &out.ValidationChecks
+		*out = make([]ValidationCheckSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ValidationChecks = nil
+	}
 	return nil
 }
 
@@ -3180,6 +3584,19 @@ func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *Containe
 		out.Packages = nil
 	}
 	out.RegistryMirrors = in.RegistryMirrors
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]kops.ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(kops.ContainerdRegistryConfig)
+			if err := Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.Root = in.Root
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
@@ -3235,6 +3652,19 @@ func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Con
 		out.Packages = nil
 	}
 	out.RegistryMirrors = in.RegistryMirrors
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerdRegistryConfig)
+			if err := Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.Root = in.Root
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
@@ -3275,33 +3705,135 @@ func Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Contain
 	return autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in, out, s)
 }
 
-func autoConvert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in *DCGMExporterConfig, out *kops.DCGMExporterConfig, s conversion.Scope) error {
-	out.Enabled = in.Enabled
+func autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Server = in.Server
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]kops.ContainerdRegistryHostConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mirrors = nil
+	}
+	out.CACertificate = in.CACertificate
+	out.SkipVerify = in.SkipVerify
 	return nil
 }
 
-// Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig is an autogenerated conversion function.
-func Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in *DCGMExporterConfig, out *kops.DCGMExporterConfig, s conversion.Scope) error {
-	return autoConvert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in, out, s)
+// Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in, out, s)
 }
 
-func autoConvert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in *kops.DCGMExporterConfig, out *DCGMExporterConfig, s conversion.Scope) error {
-	out.Enabled = in.Enabled
+func autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Server = in.Server
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]ContainerdRegistryHostConfig, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mirrors = nil
+	}
+	out.CACertificate = in.CACertificate
+	out.SkipVerify = in.SkipVerify
 	return nil
 }
 
-// Convert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig is an autogenerated conversion function.
-func Convert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in *kops.DCGMExporterConfig, out *DCGMExporterConfig, s conversion.Scope) error {
-	return autoConvert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in, out, s)
+// Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in, out, s)
 }
 
-func autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
+func autoConvert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig(in *ContainerdRegistryHostConfig, out *kops.ContainerdRegistryHostConfig, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = in.Capabilities
+	out.CACertificate = in.CACertificate
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
 	return nil
 }
 
-// Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec is an autogenerated conversion function.
-func Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in, out, s)
+// Convert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig(in *ContainerdRegistryHostConfig, out *kops.ContainerdRegistryHostConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRegistryHostConfig_To_kops_ContainerdRegistryHostConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig(in *kops.ContainerdRegistryHostConfig, out *ContainerdRegistryHostConfig, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = in.Capabilities
+	out.CACertificate = in.CACertificate
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
+	return nil
+}
+
+// Convert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig(in *kops.ContainerdRegistryHostConfig, out *ContainerdRegistryHostConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRegistryHostConfig_To_v1alpha2_ContainerdRegistryHostConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck(in *CustomResourcesValidationCheck, out *kops.CustomResourcesValidationCheck, s conversion.Scope) error {
+	out.APIVersion = in.APIVersion
+	out.Resource = in.Resource
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	out.Condition = in.Condition
+	return nil
+}
+
+// Convert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck(in *CustomResourcesValidationCheck, out *kops.CustomResourcesValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck(in *kops.CustomResourcesValidationCheck, out *CustomResourcesValidationCheck, s conversion.Scope) error {
+	out.APIVersion = in.APIVersion
+	out.Resource = in.Resource
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	out.Condition = in.Condition
+	return nil
+}
+
+// Convert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck is an autogenerated conversion function.
+func Convert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck(in *kops.CustomResourcesValidationCheck, out *CustomResourcesValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in *DCGMExporterConfig, out *kops.DCGMExporterConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig is an autogenerated conversion function.
+func Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in *DCGMExporterConfig, out *kops.DCGMExporterConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in, out, s)
+}
+
+func autoConvert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in *kops.DCGMExporterConfig, out *DCGMExporterConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig is an autogenerated conversion function.
+func Convert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in *kops.DCGMExporterConfig, out *DCGMExporterConfig, s conversion.Scope) error {
+	return autoConvert_kops_DCGMExporterConfig_To_v1alpha2_DCGMExporterConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec is an autogenerated conversion function.
+func Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in, out, s)
 }
 
 func autoConvert_kops_DNSAccessSpec_To_v1alpha2_DNSAccessSpec(in *kops.DNSAccessSpec, out *DNSAccessSpec, s conversion.Scope) error {
@@ -3383,6 +3915,52 @@ func Convert_kops_DNSControllerGossipConfigSecondary_To_v1alpha2_DNSControllerGo
 	return autoConvert_kops_DNSControllerGossipConfigSecondary_To_v1alpha2_DNSControllerGossipConfigSecondary(in, out, s)
 }
 
+func autoConvert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in *DeploymentsValidationCheck, out *kops.DeploymentsValidationCheck, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in *DeploymentsValidationCheck, out *kops.DeploymentsValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in *kops.DeploymentsValidationCheck, out *DeploymentsValidationCheck, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck is an autogenerated conversion function.
+func Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in *kops.DeploymentsValidationCheck, out *DeploymentsValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec(in *DevicePluginSpec, out *kops.DevicePluginSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	out.ResourceName = in.ResourceName
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec is an autogenerated conversion function.
+func Convert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec(in *DevicePluginSpec, out *kops.DevicePluginSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DevicePluginSpec_To_kops_DevicePluginSpec(in, out, s)
+}
+
+func autoConvert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec(in *kops.DevicePluginSpec, out *DevicePluginSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	out.ResourceName = in.ResourceName
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec is an autogenerated conversion function.
+func Convert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec(in *kops.DevicePluginSpec, out *DevicePluginSpec, s conversion.Scope) error {
+	return autoConvert_kops_DevicePluginSpec_To_v1alpha2_DevicePluginSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_DockerConfig_To_kops_DockerConfig(in *DockerConfig, out *kops.DockerConfig, s conversion.Scope) error {
 	out.AuthorizationPlugins = in.AuthorizationPlugins
 	out.Bridge = in.Bridge
@@ -3979,6 +4557,32 @@ func Convert_kops_GossipConfigSecondary_To_v1alpha2_GossipConfigSecondary(in *ko
 	return autoConvert_kops_GossipConfigSecondary_To_v1alpha2_GossipConfigSecondary(in, out, s)
 }
 
+func autoConvert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck(in *HTTPGetValidationCheck, out *kops.HTTPGetValidationCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	out.ExpectedStatus = in.ExpectedStatus
+	out.Timeout = in.Timeout
+	out.InsecureSkipVerify = in.InsecureSkipVerify
+	return nil
+}
+
+// Convert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck(in *HTTPGetValidationCheck, out *kops.HTTPGetValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck(in *kops.HTTPGetValidationCheck, out *HTTPGetValidationCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	out.ExpectedStatus = in.ExpectedStatus
+	out.Timeout = in.Timeout
+	out.InsecureSkipVerify = in.InsecureSkipVerify
+	return nil
+}
+
+// Convert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck is an autogenerated conversion function.
+func Convert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck(in *kops.HTTPGetValidationCheck, out *HTTPGetValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(in *HTTPProxy, out *kops.HTTPProxy, s conversion.Scope) error {
 	out.Host = in.Host
 	out.Port = in.Port
@@ -4268,6 +4872,17 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Hooks = nil
 	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.SpotDurationInMinutes = in.SpotDurationInMinutes
 	out.CPUCredits = in.CPUCredits
@@ -4383,6 +4998,15 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 		out.Containerd = nil
 	}
 	out.Packages = in.Packages
+	if in.OSPackages != nil {
+		in, out := &in.OSPackages, &out.OSPackages
+		*out = new(kops.OSPackagesSpec)
+		if err := Convert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPackages = nil
+	}
 	if in.GuestAccelerators != nil {
 		in, out := &in.GuestAccelerators, &out.GuestAccelerators
 		*out = make([]kops.AcceleratorConfig, len(*in))
@@ -4394,8 +5018,30 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.GuestAccelerators = nil
 	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = make([]kops.AcceleratorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_AcceleratorSpec_To_kops_AcceleratorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Accelerators = nil
+	}
 	out.MaxInstanceLifetime = in.MaxInstanceLifetime
 	out.GCPProvisioningModel = in.GCPProvisioningModel
+	if in.LifecycleOverrides != nil {
+		in, out := &in.LifecycleOverrides, &out.LifecycleOverrides
+		*out = make([]kops.LifecycleOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.LifecycleOverrides = nil
+	}
 	return nil
 }
 
@@ -4444,6 +5090,17 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Hooks = nil
 	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.SpotDurationInMinutes = in.SpotDurationInMinutes
 	out.CPUCredits = in.CPUCredits
@@ -4559,6 +5216,15 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 		out.Containerd = nil
 	}
 	out.Packages = in.Packages
+	if in.OSPackages != nil {
+		in, out := &in.OSPackages, &out.OSPackages
+		*out = new(OSPackagesSpec)
+		if err := Convert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPackages = nil
+	}
 	if in.GuestAccelerators != nil {
 		in, out := &in.GuestAccelerators, &out.GuestAccelerators
 		*out = make([]AcceleratorConfig, len(*in))
@@ -4570,8 +5236,30 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.GuestAccelerators = nil
 	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = make([]AcceleratorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_AcceleratorSpec_To_v1alpha2_AcceleratorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Accelerators = nil
+	}
 	out.MaxInstanceLifetime = in.MaxInstanceLifetime
 	out.GCPProvisioningModel = in.GCPProvisioningModel
+	if in.LifecycleOverrides != nil {
+		in, out := &in.LifecycleOverrides, &out.LifecycleOverrides
+		*out = make([]LifecycleOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.LifecycleOverrides = nil
+	}
 	return nil
 }
 
@@ -5737,6 +6425,32 @@ func Convert_kops_LeaderElectionConfiguration_To_v1alpha2_LeaderElectionConfigur
 	return autoConvert_kops_LeaderElectionConfiguration_To_v1alpha2_LeaderElectionConfiguration(in, out, s)
 }
 
+func autoConvert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(in *LifecycleOverrideSpec, out *kops.LifecycleOverrideSpec, s conversion.Scope) error {
+	out.Task = in.Task
+	out.Name = in.Name
+	out.ID = in.ID
+	out.Lifecycle = in.Lifecycle
+	return nil
+}
+
+// Convert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(in *LifecycleOverrideSpec, out *kops.LifecycleOverrideSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LifecycleOverrideSpec_To_kops_LifecycleOverrideSpec(in, out, s)
+}
+
+func autoConvert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(in *kops.LifecycleOverrideSpec, out *LifecycleOverrideSpec, s conversion.Scope) error {
+	out.Task = in.Task
+	out.Name = in.Name
+	out.ID = in.ID
+	out.Lifecycle = in.Lifecycle
+	return nil
+}
+
+// Convert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec is an autogenerated conversion function.
+func Convert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(in *kops.LifecycleOverrideSpec, out *LifecycleOverrideSpec, s conversion.Scope) error {
+	return autoConvert_kops_LifecycleOverrideSpec_To_v1alpha2_LifecycleOverrideSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_LoadBalancerAccessSpec_To_kops_LoadBalancerAccessSpec(in *LoadBalancerAccessSpec, out *kops.LoadBalancerAccessSpec, s conversion.Scope) error {
 	out.Class = kops.LoadBalancerClass(in.Class)
 	out.Type = kops.LoadBalancerType(in.Type)
@@ -6477,6 +7191,26 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in *NodeConditionsValidationCheck, out *kops.NodeConditionsValidationCheck, s conversion.Scope) error {
+	out.Types = in.Types
+	return nil
+}
+
+// Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in *NodeConditionsValidationCheck, out *kops.NodeConditionsValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in *kops.NodeConditionsValidationCheck, out *NodeConditionsValidationCheck, s conversion.Scope) error {
+	out.Types = in.Types
+	return nil
+}
+
+// Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck is an autogenerated conversion function.
+func Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in *kops.NodeConditionsValidationCheck, out *NodeConditionsValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.ExternalCoreFile = in.ExternalCoreFile
@@ -6637,6 +7371,70 @@ func Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(in *kops.NvidiaGPU
 	return autoConvert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(in *OSPackagesSpec, out *kops.OSPackagesSpec, s conversion.Scope) error {
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]kops.PackageRepositorySpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Repositories = nil
+	}
+	if in.Pinned != nil {
+		in, out := &in.Pinned, &out.Pinned
+		*out = make([]kops.PinnedPackageSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Pinned = nil
+	}
+	out.KernelVersion = in.KernelVersion
+	return nil
+}
+
+// Convert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec is an autogenerated conversion function.
+func Convert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(in *OSPackagesSpec, out *kops.OSPackagesSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OSPackagesSpec_To_kops_OSPackagesSpec(in, out, s)
+}
+
+func autoConvert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(in *kops.OSPackagesSpec, out *OSPackagesSpec, s conversion.Scope) error {
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]PackageRepositorySpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Repositories = nil
+	}
+	if in.Pinned != nil {
+		in, out := &in.Pinned, &out.Pinned
+		*out = make([]PinnedPackageSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Pinned = nil
+	}
+	out.KernelVersion = in.KernelVersion
+	return nil
+}
+
+// Convert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec is an autogenerated conversion function.
+func Convert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(in *kops.OSPackagesSpec, out *OSPackagesSpec, s conversion.Scope) error {
+	return autoConvert_kops_OSPackagesSpec_To_v1alpha2_OSPackagesSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
 	out.Version = in.Version
 	out.IgnoreAZ = in.IgnoreAZ
@@ -6959,6 +7757,32 @@ func Convert_kops_PDCSIDriver_To_v1alpha2_PDCSIDriver(in *kops.PDCSIDriver, out
 	return autoConvert_kops_PDCSIDriver_To_v1alpha2_PDCSIDriver(in, out, s)
 }
 
+func autoConvert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec(in *PackageRepositorySpec, out *kops.PackageRepositorySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Keyring = in.Keyring
+	out.Sources = in.Sources
+	out.BaseURL = in.BaseURL
+	return nil
+}
+
+// Convert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec is an autogenerated conversion function.
+func Convert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec(in *PackageRepositorySpec, out *kops.PackageRepositorySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PackageRepositorySpec_To_kops_PackageRepositorySpec(in, out, s)
+}
+
+func autoConvert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec(in *kops.PackageRepositorySpec, out *PackageRepositorySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Keyring = in.Keyring
+	out.Sources = in.Sources
+	out.BaseURL = in.BaseURL
+	return nil
+}
+
+// Convert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec is an autogenerated conversion function.
+func Convert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec(in *kops.PackageRepositorySpec, out *PackageRepositorySpec, s conversion.Scope) error {
+	return autoConvert_kops_PackageRepositorySpec_To_v1alpha2_PackageRepositorySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(in *PackagesConfig, out *kops.PackagesConfig, s conversion.Scope) error {
 	out.HashAmd64 = in.HashAmd64
 	out.HashArm64 = in.HashArm64
@@ -6985,6 +7809,72 @@ func Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in *kops.PackagesCon
 	return autoConvert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec(in *PinnedPackageSpec, out *kops.PinnedPackageSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec is an autogenerated conversion function.
+func Convert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec(in *PinnedPackageSpec, out *kops.PinnedPackageSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PinnedPackageSpec_To_kops_PinnedPackageSpec(in, out, s)
+}
+
+func autoConvert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec(in *kops.PinnedPackageSpec, out *PinnedPackageSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec is an autogenerated conversion function.
+func Convert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec(in *kops.PinnedPackageSpec, out *PinnedPackageSpec, s conversion.Scope) error {
+	return autoConvert_kops_PinnedPackageSpec_To_v1alpha2_PinnedPackageSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck(in *PluginValidationCheck, out *kops.PluginValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck(in *PluginValidationCheck, out *kops.PluginValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck(in *kops.PluginValidationCheck, out *PluginValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck is an autogenerated conversion function.
+func Convert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck(in *kops.PluginValidationCheck, out *PluginValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck(in *PodDisruptionBudgetsValidationCheck, out *kops.PodDisruptionBudgetsValidationCheck, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck(in *PodDisruptionBudgetsValidationCheck, out *kops.PodDisruptionBudgetsValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck(in *kops.PodDisruptionBudgetsValidationCheck, out *PodDisruptionBudgetsValidationCheck, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck is an autogenerated conversion function.
+func Convert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck(in *kops.PodDisruptionBudgetsValidationCheck, out *PodDisruptionBudgetsValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_PodIdentityWebhookSpec_To_kops_PodIdentityWebhookSpec(in *PodIdentityWebhookSpec, out *kops.PodIdentityWebhookSpec, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Replicas = in.Replicas
@@ -7029,6 +7919,7 @@ func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.Strategy = kops.RollingUpdateStrategy(in.Strategy)
 	return nil
 }
 
@@ -7041,6 +7932,7 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.Strategy = RollingUpdateStrategy(in.Strategy)
 	return nil
 }
 
@@ -7305,6 +8197,94 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec(in *SystemdDropInSpec, out *kops.SystemdDropInSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Content = in.Content
+	return nil
+}
+
+// Convert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec(in *SystemdDropInSpec, out *kops.SystemdDropInSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec(in, out, s)
+}
+
+func autoConvert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec(in *kops.SystemdDropInSpec, out *SystemdDropInSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Content = in.Content
+	return nil
+}
+
+// Convert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec is an autogenerated conversion function.
+func Convert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec(in *kops.SystemdDropInSpec, out *SystemdDropInSpec, s conversion.Scope) error {
+	return autoConvert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Enabled = in.Enabled
+	out.Before = in.Before
+	out.Content = in.Content
+	if in.DropIns != nil {
+		in, out := &in.DropIns, &out.DropIns
+		*out = make([]kops.SystemdDropInSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SystemdDropInSpec_To_kops_SystemdDropInSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.DropIns = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in, out, s)
+}
+
+func autoConvert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Enabled = in.Enabled
+	out.Before = in.Before
+	out.Content = in.Content
+	if in.DropIns != nil {
+		in, out := &in.DropIns, &out.DropIns
+		*out = make([]SystemdDropInSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdDropInSpec_To_v1alpha2_SystemdDropInSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.DropIns = nil
+	}
+	return nil
+}
+
+// Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -7418,6 +8398,134 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec(in *ValidationCheckSpec, out *kops.ValidationCheckSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = new(kops.DeploymentsValidationCheck)
+		if err := Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Deployments = nil
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(kops.HTTPGetValidationCheck)
+		if err := Convert_v1alpha2_HTTPGetValidationCheck_To_kops_HTTPGetValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTPGet = nil
+	}
+	if in.PodDisruptionBudgets != nil {
+		in, out := &in.PodDisruptionBudgets, &out.PodDisruptionBudgets
+		*out = new(kops.PodDisruptionBudgetsValidationCheck)
+		if err := Convert_v1alpha2_PodDisruptionBudgetsValidationCheck_To_kops_PodDisruptionBudgetsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodDisruptionBudgets = nil
+	}
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = new(kops.CustomResourcesValidationCheck)
+		if err := Convert_v1alpha2_CustomResourcesValidationCheck_To_kops_CustomResourcesValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CustomResources = nil
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = new(kops.NodeConditionsValidationCheck)
+		if err := Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConditions = nil
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(kops.PluginValidationCheck)
+		if err := Convert_v1alpha2_PluginValidationCheck_To_kops_PluginValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugin = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec(in *ValidationCheckSpec, out *kops.ValidationCheckSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ValidationCheckSpec_To_kops_ValidationCheckSpec(in, out, s)
+}
+
+func autoConvert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec(in *kops.ValidationCheckSpec, out *ValidationCheckSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = new(DeploymentsValidationCheck)
+		if err := Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Deployments = nil
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPGetValidationCheck)
+		if err := Convert_kops_HTTPGetValidationCheck_To_v1alpha2_HTTPGetValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTPGet = nil
+	}
+	if in.PodDisruptionBudgets != nil {
+		in, out := &in.PodDisruptionBudgets, &out.PodDisruptionBudgets
+		*out = new(PodDisruptionBudgetsValidationCheck)
+		if err := Convert_kops_PodDisruptionBudgetsValidationCheck_To_v1alpha2_PodDisruptionBudgetsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodDisruptionBudgets = nil
+	}
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = new(CustomResourcesValidationCheck)
+		if err := Convert_kops_CustomResourcesValidationCheck_To_v1alpha2_CustomResourcesValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CustomResources = nil
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = new(NodeConditionsValidationCheck)
+		if err := Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConditions = nil
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginValidationCheck)
+		if err := Convert_kops_PluginValidationCheck_To_v1alpha2_PluginValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugin = nil
+	}
+	return nil
+}
+
+// Convert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec is an autogenerated conversion function.
+func Convert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec(in *kops.ValidationCheckSpec, out *ValidationCheckSpec, s conversion.Scope) error {
+	return autoConvert_kops_ValidationCheckSpec_To_v1alpha2_ValidationCheckSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
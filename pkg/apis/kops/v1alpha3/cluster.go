@@ -113,6 +113,8 @@ type ClusterSpec struct {
 	NTP                 *NTPConfig          `json:"ntp,omitempty"`
 	// Packages specifies additional packages to be installed.
 	Packages []string `json:"packages,omitempty"`
+	// OSPackages pins the versions of OS packages and the kernel.
+	OSPackages *OSPackagesSpec `json:"osPackages,omitempty"`
 
 	// NodeProblemDetector determines the node problem detector configuration.
 	NodeProblemDetector *NodeProblemDetectorConfig `json:"nodeProblemDetector,omitempty"`
@@ -133,6 +135,8 @@ type ClusterSpec struct {
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// SystemdUnits are systemd units and drop-ins to install on the nodes
+	SystemdUnits []SystemdUnitSpec `json:"systemdUnits,omitempty"`
 	// Alternative locations for files and containers
 	Assets *AssetsSpec `json:"assets,omitempty"`
 	// IAM field adds control over the IAM security policies applied to resources
@@ -158,6 +162,102 @@ type ClusterSpec struct {
 	SnapshotController *SnapshotControllerConfig `json:"snapshotController,omitempty"`
 	// Karpenter defines the Karpenter configuration.
 	Karpenter *KarpenterConfig `json:"karpenter,omitempty"`
+	// DeletionProtection prevents the cluster from being deleted until it is cleared.
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+	// LifecycleOverrides override the lifecycle of the cloud resources of the cluster when they are stricter
+	// than the lifecycle of the task for the phase, yielding to --lifecycle-overrides.
+	LifecycleOverrides []LifecycleOverrideSpec `json:"lifecycleOverrides,omitempty"`
+	// ValidationChecks are additional checks run when validating the cluster, by kops validate cluster
+	// and between the instance groups of a rolling update.
+	ValidationChecks []ValidationCheckSpec `json:"validationChecks,omitempty"`
+}
+
+// LifecycleOverrideSpec overrides the lifecycle of the cloud resources managed by a task.
+type LifecycleOverrideSpec struct {
+	// Task is the type of the task managing the resources, for example SecurityGroup or NatGateway.
+	Task string `json:"task"`
+	// Name restricts the override to the task with this name, as shown by kops update cluster.
+	Name string `json:"name,omitempty"`
+	// ID restricts the override to the task for the cloud resource with this ID, for example sg-12345678.
+	// It only matches resources whose ID is in the spec, such as shared resources.
+	ID string `json:"id,omitempty"`
+	// Lifecycle is one of Sync, Ignore, WarnIfInsufficientAccess, ExistsAndValidates or ExistsAndWarnIfChanges.
+	Lifecycle string `json:"lifecycle"`
+}
+
+// ValidationCheckSpec configures an additional check run when validating the cluster.
+// Exactly one type of check must be set.
+type ValidationCheckSpec struct {
+	// Name identifies the check in the validation failures.
+	Name string `json:"name"`
+	// Deployments checks that the Deployments matching a label selector have all their replicas available.
+	Deployments *DeploymentsValidationCheck `json:"deployments,omitempty"`
+	// HTTPGet checks that an HTTP endpoint, such as an ingress, responds successfully.
+	HTTPGet *HTTPGetValidationCheck `json:"httpGet,omitempty"`
+	// PodDisruptionBudgets checks that no PodDisruptionBudget currently blocks evictions.
+	PodDisruptionBudgets *PodDisruptionBudgetsValidationCheck `json:"podDisruptionBudgets,omitempty"`
+	// CustomResources checks that a status condition of custom resources is True.
+	CustomResources *CustomResourcesValidationCheck `json:"customResources,omitempty"`
+	// NodeConditions checks that no node has a problem condition, such as those set by node-problem-detector.
+	NodeConditions *NodeConditionsValidationCheck `json:"nodeConditions,omitempty"`
+	// Plugin runs a check registered with kOps under a name.
+	Plugin *PluginValidationCheck `json:"plugin,omitempty"`
+}
+
+// DeploymentsValidationCheck checks that the Deployments matching a label selector have all their replicas available.
+type DeploymentsValidationCheck struct {
+	// Namespace restricts the check to a namespace; by default all namespaces are checked.
+	Namespace string `json:"namespace,omitempty"`
+	// Selector is the label selector of the Deployments, for example app.kubernetes.io/part-of=ingress.
+	Selector string `json:"selector"`
+}
+
+// HTTPGetValidationCheck checks that an HTTP endpoint responds successfully.
+type HTTPGetValidationCheck struct {
+	// URL is the http or https URL to get.
+	URL string `json:"url"`
+	// ExpectedStatus is the expected status code; by default any 2xx status code is expected.
+	ExpectedStatus *int32 `json:"expectedStatus,omitempty"`
+	// Timeout is the timeout of the request; defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// InsecureSkipVerify skips the verification of the server certificate.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PodDisruptionBudgetsValidationCheck checks that no PodDisruptionBudget currently blocks evictions.
+type PodDisruptionBudgetsValidationCheck struct {
+	// Namespace restricts the check to a namespace; by default all namespaces are checked.
+	Namespace string `json:"namespace,omitempty"`
+	// Selector is the label selector of the PodDisruptionBudgets; by default all are checked.
+	Selector string `json:"selector,omitempty"`
+}
+
+// CustomResourcesValidationCheck checks that a status condition of custom resources is True.
+type CustomResourcesValidationCheck struct {
+	// APIVersion is the group and version of the resources, for example cert-manager.io/v1.
+	APIVersion string `json:"apiVersion"`
+	// Resource is the plural name of the resources, for example certificates.
+	Resource string `json:"resource"`
+	// Namespace restricts the check to a namespace; by default all namespaces are checked.
+	Namespace string `json:"namespace,omitempty"`
+	// Selector is the label selector of the resources; by default all are checked.
+	Selector string `json:"selector,omitempty"`
+	// Condition is the type of the status condition that must be True; defaults to Ready.
+	Condition string `json:"condition,omitempty"`
+}
+
+// NodeConditionsValidationCheck checks that no node has a problem condition, such as those set by node-problem-detector.
+type NodeConditionsValidationCheck struct {
+	// Types are the types of the node conditions that are problems when True, for example KernelDeadlock or ReadonlyFilesystem.
+	Types []string `json:"types"`
+}
+
+// PluginValidationCheck runs a check registered with kOps under a name.
+type PluginValidationCheck struct {
+	// Name is the name the check is registered under.
+	Name string `json:"name"`
+	// Config is the configuration of the check.
+	Config map[string]string `json:"config,omitempty"`
 }
 
 // ConfigStoreSpec configures the stores that nodes use to get their configuration.
@@ -324,6 +424,21 @@ type AssetsSpec struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// Verification configures the verification of asset signatures.
+	Verification *AssetVerificationSpec `json:"verification,omitempty"`
+}
+
+// AssetVerificationSpec configures the verification of cosign style signatures of the file and container image assets.
+type AssetVerificationSpec struct {
+	// PublicKey is the PEM encoded ECDSA or RSA public key the signatures are verified with, as generated by `cosign generate-key-pair`.
+	PublicKey string `json:"publicKey,omitempty"`
+	// Bundle is the location of an offline signature bundle.
+	// When set, signatures are read from the bundle instead of being fetched next to each asset.
+	Bundle string `json:"bundle,omitempty"`
+	// Files requires the file assets, like kubelet and containerd, to be signed.
+	Files bool `json:"files,omitempty"`
+	// Images requires the container images to be signed, and pins them by digest.
+	Images bool `json:"images,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -370,6 +485,30 @@ type ExecContainerAction struct {
 	Environment map[string]string `json:"environment,omitempty"`
 }
 
+// SystemdUnitSpec defines a systemd unit, or a set of drop-ins for an existing unit, managed by kOps on the node
+type SystemdUnitSpec struct {
+	// Name is the name of the unit including its type suffix, e.g. "foo.service", "foo.timer" or "mnt-data.mount"
+	Name string `json:"name,omitempty"`
+	// Roles is an optional list of roles the unit should be rolled out to, defaults to all
+	Roles []InstanceGroupRole `json:"roles,omitempty"`
+	// Enabled indicates if you want the unit switched on. Default: true
+	Enabled *bool `json:"enabled,omitempty"`
+	// Before is a series of systemd units, such as kubelet.service or containerd.service, which nodeup must start after this unit
+	Before []string `json:"before,omitempty"`
+	// Content is the raw contents of the unit file. If empty, only the drop-ins are written and the unit must already exist on the node
+	Content string `json:"content,omitempty"`
+	// DropIns are drop-in configuration files written to /etc/systemd/system/<name>.d/
+	DropIns []SystemdDropInSpec `json:"dropIns,omitempty"`
+}
+
+// SystemdDropInSpec defines a systemd drop-in configuration file
+type SystemdDropInSpec struct {
+	// Name is the file name of the drop-in, e.g. "10-limits.conf"
+	Name string `json:"name,omitempty"`
+	// Content is the contents of the drop-in
+	Content string `json:"content,omitempty"`
+}
+
 type AuthenticationSpec struct {
 	Kopeio *KopeioAuthenticationSpec `json:"kopeio,omitempty"`
 	AWS    *AWSAuthenticationSpec    `json:"aws,omitempty"`
@@ -826,7 +965,8 @@ type RollingUpdate struct {
 	// The value can be an absolute number (for example 5) or a percentage of
 	// desired machines (for example 10%).
 	// The absolute number is calculated from a percentage by rounding up.
-	// Has no effect on instance groups with role "Master".
+	// Instance groups with role "ControlPlane" only surge when this is set to 1 on the instance group;
+	// neither the default nor the cluster-wide setting applies to them.
 	// Defaults to 1 on AWS, 0 otherwise.
 	// Example: when this is set to 30%, the InstanceGroup can be scaled
 	// up immediately when the rolling update starts, such that the total
@@ -834,8 +974,17 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Strategy is how the instances of the instance group are replaced, either "Rolling" or "BlueGreen".
+	// "BlueGreen" creates replacements for all the instances needing update and waits for them to validate
+	// before draining and terminating the old instances, MaxUnavailable at a time. MaxSurge is ignored.
+	// Defaults to "Rolling".
+	// +optional
+	Strategy RollingUpdateStrategy `json:"strategy,omitempty"`
 }
 
+// RollingUpdateStrategy is how the instances of an instance group are replaced during a rolling update.
+type RollingUpdateStrategy string
+
 type PackagesConfig struct {
 	// HashAmd64 overrides the hash for the AMD64 package.
 	HashAmd64 *string `json:"hashAmd64,omitempty"`
@@ -847,6 +996,37 @@ type PackagesConfig struct {
 	UrlArm64 *string `json:"urlArm64,omitempty"`
 }
 
+// OSPackagesSpec pins the versions of OS packages and the kernel, and configures the repositories they are installed from
+type OSPackagesSpec struct {
+	// Repositories are additional apt or yum repositories to configure before installing packages
+	Repositories []PackageRepositorySpec `json:"repositories,omitempty"`
+	// Pinned is a list of OS packages to install at an exact version and hold at that version
+	Pinned []PinnedPackageSpec `json:"pinned,omitempty"`
+	// KernelVersion is the kernel release, as reported by `uname -r`, that nodes are expected to run.
+	// It is only reported: nodeup logs a warning if the running kernel differs, but does not change it
+	KernelVersion string `json:"kernelVersion,omitempty"`
+}
+
+// PackageRepositorySpec defines an additional OS package repository
+type PackageRepositorySpec struct {
+	// Name is the name of the repository
+	Name string `json:"name,omitempty"`
+	// Keyring is the URL of the GPG key the repository is signed with
+	Keyring string `json:"keyring,omitempty"`
+	// Sources are the apt source lines for the repository, used on Debian based distributions
+	Sources []string `json:"sources,omitempty"`
+	// BaseURL is the base URL of the yum repository, used on RHEL based distributions
+	BaseURL string `json:"baseURL,omitempty"`
+}
+
+// PinnedPackageSpec defines an OS package pinned to an exact version
+type PinnedPackageSpec struct {
+	// Name is the name of the package
+	Name string `json:"name,omitempty"`
+	// Version is the exact version of the package, as reported by dpkg-query or rpm
+	Version string `json:"version,omitempty"`
+}
+
 type WarmPoolSpec struct {
 	// MinSize is the minimum size of the pool
 	MinSize int64 `json:"minSize,omitempty"`
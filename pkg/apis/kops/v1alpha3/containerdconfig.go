@@ -35,6 +35,9 @@ type ContainerdConfig struct {
 	Packages *PackagesConfig `json:"packages,omitempty"`
 	// RegistryMirrors is list of image registries
 	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
+	// Registries configures the hosts containerd uses to pull from each registry, keyed by registry
+	// name (e.g. "docker.io", or "_default" for all registries). Changes are applied in place by nodeup.
+	Registries map[string]ContainerdRegistryConfig `json:"registries,omitempty"`
 	// Root directory for persistent data (default "/var/lib/containerd").
 	Root *string `json:"root,omitempty" flag:"root"`
 	// SkipInstall prevents kOps from installing and modifying containerd in any way (default "false").
@@ -53,6 +56,33 @@ type ContainerdConfig struct {
 	NRI *NRIConfig `json:"nri,omitempty"`
 }
 
+// ContainerdRegistryConfig configures how containerd reaches an image registry.
+// It is rendered as the hosts.toml file of the registry, which containerd reads on every pull.
+type ContainerdRegistryConfig struct {
+	// Server is the URL of the upstream registry (default "https://<registry>").
+	Server string `json:"server,omitempty"`
+	// Mirrors is the ordered list of hosts that are tried before the upstream registry.
+	Mirrors []ContainerdRegistryHostConfig `json:"mirrors,omitempty"`
+	// CACertificate is the PEM encoded CA bundle used to verify the upstream registry.
+	CACertificate string `json:"caCertificate,omitempty"`
+	// SkipVerify disables TLS verification of the upstream registry.
+	SkipVerify bool `json:"skipVerify,omitempty"`
+}
+
+// ContainerdRegistryHostConfig configures a mirror of an image registry.
+type ContainerdRegistryHostConfig struct {
+	// URL of the mirror (e.g. "https://mirror.example.com").
+	URL string `json:"url"`
+	// Capabilities are the operations the mirror is trusted with [pull, resolve, push] (default "pull", "resolve").
+	Capabilities []string `json:"capabilities,omitempty"`
+	// CACertificate is the PEM encoded CA bundle used to verify the mirror.
+	CACertificate string `json:"caCertificate,omitempty"`
+	// SkipVerify disables TLS verification of the mirror.
+	SkipVerify bool `json:"skipVerify,omitempty"`
+	// OverridePath indicates that the URL already includes the API root path (e.g. "/v2").
+	OverridePath bool `json:"overridePath,omitempty"`
+}
+
 type NRIConfig struct {
 	// Enable NRI support in containerd
 	Enabled *bool `json:"enabled,omitempty"`
@@ -83,6 +83,8 @@ type InstanceGroupSpec struct {
 	Zones []string `json:"zones,omitempty"`
 	// Hooks is a list of hooks for this instanceGroup, note: these can override the cluster wide ones if required
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// SystemdUnits is a list of systemd units and drop-ins for this instance group, note: these can override the cluster wide ones if required
+	SystemdUnits []SystemdUnitSpec `json:"systemdUnits,omitempty"`
 	// MaxPrice indicates this is a spot-pricing group, with the specified value as our max-price bid
 	MaxPrice *string `json:"maxPrice,omitempty"`
 	// SpotDurationInMinutes indicates this is a spot-block group, with the specified value as the spot reservation time
@@ -149,16 +151,25 @@ type InstanceGroupSpec struct {
 	Containerd *ContainerdConfig `json:"containerd,omitempty"`
 	// Packages specifies additional packages to be installed.
 	Packages []string `json:"packages,omitempty"`
+	// OSPackages pins the versions of OS packages and the kernel, overriding the cluster wide ones.
+	OSPackages *OSPackagesSpec `json:"osPackages,omitempty"`
 	// GuestAccelerators configures additional accelerators
 	GuestAccelerators []AcceleratorConfig `json:"guestAccelerators,omitempty"`
+	// Accelerators installs the drivers, container runtime and device plugin for accelerator devices, like AWS Neuron or AMD GPUs.
+	Accelerators []AcceleratorSpec `json:"accelerators,omitempty"`
 	// MaxInstanceLifetime to the maximum amount of time, in seconds, that an instance can be in service.
 	// Value expected must be in form of duration ("ms", "s", "m", "h")
+	// On all cloud providers, rolling updates replace the instances whose node is older than this.
 	MaxInstanceLifetime *metav1.Duration `json:"maxInstanceLifetime,omitempty"`
 	// GCPProvisioningModel: Specifies the provisioning model of the GCP instance.
 	// Valid values:
 	//   'STANDARD': (default) standard provisioning with user controlled run time, no discounts
 	//   'SPOT': heavily discounted, no guaranteed run time.
 	GCPProvisioningModel *string `json:"gcpProvisioningModel,omitempty"`
+	// LifecycleOverrides override the lifecycle of the cloud resources built only for the instance group,
+	// such as its autoscaling group and launch template, taking precedence over those of the cluster.
+	// Resources shared with other instance groups, such as IAM roles and security groups, are not affected.
+	LifecycleOverrides []LifecycleOverrideSpec `json:"lifecycleOverrides,omitempty"`
 }
 
 // InstanceRootVolumeSpec specifies options for an instance's root volume.
@@ -291,3 +302,43 @@ type AcceleratorConfig struct {
 	AcceleratorCount int64  `json:"acceleratorCount,omitempty"`
 	AcceleratorType  string `json:"acceleratorType,omitempty"`
 }
+
+// AcceleratorVendor is the vendor of an accelerator device
+type AcceleratorVendor string
+
+// AcceleratorSpec configures a class of accelerator devices.
+// Fields that are not set are defaulted based on the vendor.
+type AcceleratorSpec struct {
+	// Name identifies the accelerator in the node label and device plugin names (default depends on the vendor).
+	Name string `json:"name,omitempty"`
+	// Vendor of the accelerator [AWSNeuron, AMD, Custom].
+	Vendor AcceleratorVendor `json:"vendor"`
+	// Packages are the OS packages that install the drivers and tools.
+	// They can come from the repositories configured in osPackages.
+	Packages []string `json:"packages,omitempty"`
+	// KernelModules are loaded on boot, before the container runtime starts.
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// Runtime registers an additional container runtime, for accelerators that need a runtime hook.
+	Runtime *AcceleratorRuntimeSpec `json:"runtime,omitempty"`
+	// DevicePlugin configures the device plugin that advertises the devices to the kubelet.
+	DevicePlugin *DevicePluginSpec `json:"devicePlugin,omitempty"`
+}
+
+// AcceleratorRuntimeSpec configures a containerd runtime handler and the matching RuntimeClass.
+type AcceleratorRuntimeSpec struct {
+	// Name of the runtime handler and RuntimeClass.
+	Name string `json:"name"`
+	// BinaryName is the path of the OCI runtime that wraps runc and injects the devices.
+	BinaryName string `json:"binaryName"`
+}
+
+// DevicePluginSpec configures a device plugin DaemonSet.
+type DevicePluginSpec struct {
+	// Image of the device plugin.
+	Image string `json:"image,omitempty"`
+	// ResourceName is the extended resource advertised by the device plugin (e.g. "aws.amazon.com/neuron").
+	// Nodes are tainted with it, so that only pods that request the resource are scheduled on them.
+	ResourceName string `json:"resourceName,omitempty"`
+	// Args are the arguments passed to the device plugin.
+	Args []string `json:"args,omitempty"`
+}
@@ -108,7 +108,7 @@ func awsValidateMaximumInstanceLifetime(fieldPath *field.Path, maxInstanceLifeti
 	allErrs := field.ErrorList{}
 	const minMaxInstanceLifetime = 86400
 	lifetimeSec := int64(maxInstanceLifetime.Seconds())
-	if lifetimeSec != 0 && lifetimeSec < minMaxInstanceLifetime {
+	if lifetimeSec > 0 && lifetimeSec < minMaxInstanceLifetime {
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxInstanceLifetime"), maxInstanceLifetime, fmt.Sprintf("max instance lifetime must be greater than %d or equal to 0", int64(minMaxInstanceLifetime))))
 	}
 
@@ -18,13 +18,16 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -34,6 +37,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 )
 
+var kernelModuleRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // ValidateInstanceGroup is responsible for validating the configuration of a instancegroup
 func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -105,6 +110,15 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 		allErrs = append(allErrs, validateHookSpec(&g.Spec.Hooks[i], field.NewPath("spec", "hooks").Index(i))...)
 	}
 
+	// @check all the systemd units are valid in this instancegroup
+	allErrs = append(allErrs, validateSystemdUnits(g.Spec.SystemdUnits, field.NewPath("spec", "systemdUnits"))...)
+
+	allErrs = append(allErrs, validateLifecycleOverrides(g.Spec.LifecycleOverrides, field.NewPath("spec", "lifecycleOverrides"))...)
+
+	if g.Spec.OSPackages != nil {
+		allErrs = append(allErrs, validateOSPackages(g.Spec.OSPackages, field.NewPath("spec", "osPackages"))...)
+	}
+
 	// @check the fileAssets for this instancegroup are valid
 	for i := range g.Spec.FileAssets {
 		allErrs = append(allErrs, validateFileAssetSpec(&g.Spec.FileAssets[i], field.NewPath("spec", "fileAssets").Index(i))...)
@@ -155,6 +169,12 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 		allErrs = append(allErrs, validateRollingUpdate(g.Spec.RollingUpdate, field.NewPath("spec", "rollingUpdate"), g.Spec.Role == kops.InstanceGroupRoleControlPlane)...)
 	}
 
+	if g.Spec.MaxInstanceLifetime != nil && g.Spec.MaxInstanceLifetime.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "maxInstanceLifetime"), g.Spec.MaxInstanceLifetime, "Cannot be negative"))
+	}
+
+	allErrs = append(allErrs, validateAccelerators(g.Spec.Accelerators, field.NewPath("spec", "accelerators"))...)
+
 	if g.Spec.NodeLabels != nil {
 		allErrs = append(allErrs, validateNodeLabels(g.Spec.NodeLabels, field.NewPath("spec", "nodeLabels"))...)
 	}
@@ -238,6 +258,10 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		allErrs = append(allErrs, ValidateControlPlaneInstanceGroup(g, cluster)...)
 	}
 
+	if g.Spec.RollingUpdate != nil {
+		allErrs = append(allErrs, validateRollingUpdateCloud(g.Spec.RollingUpdate, field.NewPath("spec", "rollingUpdate"), cluster)...)
+	}
+
 	if g.Spec.Role == kops.InstanceGroupRoleAPIServer {
 		if cluster.GetCloudProvider() != kops.CloudProviderAWS {
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "APIServer role only supported on AWS"))
@@ -312,6 +336,13 @@ func ValidateControlPlaneInstanceGroup(g *kops.InstanceGroup, cluster *kops.Clus
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "metadata", "name"), fmt.Sprintf("InstanceGroup \"%s\" with role ControlPlane must have a member in etcd cluster \"%s\"", g.ObjectMeta.Name, etcd.Name)))
 		}
 	}
+
+	if g.Spec.RollingUpdate != nil && g.Spec.RollingUpdate.MaxSurge != nil {
+		surge, _ := intstr.GetScaledValueFromIntOrPercent(g.Spec.RollingUpdate.MaxSurge, 1, true)
+		if surge > 0 && cluster.GetCloudProvider() != kops.CloudProviderAWS && cluster.GetCloudProvider() != kops.CloudProviderGCE {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "rollingUpdate", "maxSurge"), "Surging instance groups with role \"ControlPlane\" is only supported on AWS and GCE"))
+		}
+	}
 	return allErrs
 }
 
@@ -358,6 +389,76 @@ func validateInstanceProfile(v *kops.IAMProfileSpec, fldPath *field.Path) field.
 	return allErrs
 }
 
+func validateAccelerators(accelerators []kops.AcceleratorSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, accelerator := range accelerators {
+		acceleratorPath := fldPath.Index(i)
+		allErrs = append(allErrs, IsValidValue(acceleratorPath.Child("vendor"), &accelerator.Vendor, kops.SupportedAcceleratorVendors)...)
+
+		if accelerator.Name == "" {
+			// The other vendors have a default name
+			if accelerator.Vendor == kops.AcceleratorVendorCustom {
+				allErrs = append(allErrs, field.Required(acceleratorPath.Child("name"), "you must set a name for a custom accelerator"))
+			}
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(accelerator.Name) {
+				allErrs = append(allErrs, field.Invalid(acceleratorPath.Child("name"), accelerator.Name, msg))
+			}
+			if names.Has(accelerator.Name) {
+				allErrs = append(allErrs, field.Duplicate(acceleratorPath.Child("name"), accelerator.Name))
+			}
+			names.Insert(accelerator.Name)
+		}
+
+		for j, pkg := range accelerator.Packages {
+			if pkg == "" || strings.ContainsAny(pkg, " \t\n") {
+				allErrs = append(allErrs, field.Invalid(acceleratorPath.Child("packages").Index(j), pkg, "package name may not be empty or contain whitespace"))
+			}
+		}
+		for j, module := range accelerator.KernelModules {
+			if !kernelModuleRegex.MatchString(module) {
+				allErrs = append(allErrs, field.Invalid(acceleratorPath.Child("kernelModules").Index(j), module, "kernel module name may only contain letters, digits, '_' and '-'"))
+			}
+		}
+
+		if runtime := accelerator.Runtime; runtime != nil {
+			runtimePath := acceleratorPath.Child("runtime")
+			if runtime.Name == "" {
+				allErrs = append(allErrs, field.Required(runtimePath.Child("name"), "you must set a name for the runtime"))
+			} else {
+				for _, msg := range utilvalidation.IsDNS1123Label(runtime.Name) {
+					allErrs = append(allErrs, field.Invalid(runtimePath.Child("name"), runtime.Name, msg))
+				}
+				if runtime.Name == "runc" || runtime.Name == "nvidia" {
+					allErrs = append(allErrs, field.Forbidden(runtimePath.Child("name"), fmt.Sprintf("runtime %q is reserved", runtime.Name)))
+				}
+			}
+			if !strings.HasPrefix(runtime.BinaryName, "/") {
+				allErrs = append(allErrs, field.Invalid(runtimePath.Child("binaryName"), runtime.BinaryName, "binaryName must be an absolute path"))
+			}
+		}
+
+		if accelerator.Vendor == kops.AcceleratorVendorCustom {
+			devicePluginPath := acceleratorPath.Child("devicePlugin")
+			if accelerator.DevicePlugin == nil || accelerator.DevicePlugin.Image == "" {
+				allErrs = append(allErrs, field.Required(devicePluginPath.Child("image"), "you must set a device plugin image for a custom accelerator"))
+			}
+			if accelerator.DevicePlugin == nil || accelerator.DevicePlugin.ResourceName == "" {
+				allErrs = append(allErrs, field.Required(devicePluginPath.Child("resourceName"), "you must set the resource name advertised by the device plugin of a custom accelerator"))
+			}
+		}
+		if accelerator.DevicePlugin != nil && accelerator.DevicePlugin.ResourceName != "" {
+			for _, msg := range utilvalidation.IsQualifiedName(accelerator.DevicePlugin.ResourceName) {
+				allErrs = append(allErrs, field.Invalid(acceleratorPath.Child("devicePlugin", "resourceName"), accelerator.DevicePlugin.ResourceName, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
 func validateNodeLabels(labels map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
 	for key := range labels {
 		if strings.Count(key, "/") > 1 {
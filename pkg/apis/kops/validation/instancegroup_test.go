@@ -18,10 +18,12 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
@@ -172,6 +174,72 @@ func TestValidMasterInstanceGroup(t *testing.T) {
 			ExpectedErrors: 1,
 			Description:    "Master IG without etcd member validated",
 		},
+		{
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						AWS: &kops.AWSSpec{},
+					},
+					EtcdClusters: []kops.EtcdClusterSpec{
+						{
+							Name: "main",
+							Members: []kops.EtcdMemberSpec{
+								{
+									Name:          "a",
+									InstanceGroup: fi.PtrTo("eu-central-1a"),
+								},
+							},
+						},
+					},
+				},
+			},
+			IG: &kops.InstanceGroup{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "eu-central-1a",
+				},
+				Spec: kops.InstanceGroupSpec{
+					Role: kops.InstanceGroupRoleControlPlane,
+					RollingUpdate: &kops.RollingUpdate{
+						MaxSurge: intStr(intstr.FromInt(1)),
+					},
+				},
+			},
+			ExpectedErrors: 0,
+			Description:    "Surging master IG on AWS failed to validate",
+		},
+		{
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						Hetzner: &kops.HetznerSpec{},
+					},
+					EtcdClusters: []kops.EtcdClusterSpec{
+						{
+							Name: "main",
+							Members: []kops.EtcdMemberSpec{
+								{
+									Name:          "a",
+									InstanceGroup: fi.PtrTo("eu-central-1a"),
+								},
+							},
+						},
+					},
+				},
+			},
+			IG: &kops.InstanceGroup{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "eu-central-1a",
+				},
+				Spec: kops.InstanceGroupSpec{
+					Role: kops.InstanceGroupRoleControlPlane,
+					RollingUpdate: &kops.RollingUpdate{
+						MaxSurge: intStr(intstr.FromInt(1)),
+					},
+				},
+			},
+			ExpectedErrors: 1,
+			Description:    "Surging master IG on Hetzner validated",
+		},
 	}
 
 	for _, g := range grid {
@@ -229,6 +297,31 @@ func TestValidBootDevice(t *testing.T) {
 	}
 }
 
+func TestValidMaxInstanceLifetime(t *testing.T) {
+	grid := []struct {
+		lifetime time.Duration
+		expected []string
+	}{
+		{
+			lifetime: 0,
+		},
+		{
+			lifetime: 720 * time.Hour,
+		},
+		{
+			lifetime: -time.Hour,
+			expected: []string{"Invalid value::spec.maxInstanceLifetime"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+		ig.Spec.MaxInstanceLifetime = &v1.Duration{Duration: g.lifetime}
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.lifetime.String(), errs, g.expected)
+	}
+}
+
 func TestValidNodeLabels(t *testing.T) {
 	grid := []struct {
 		label    string
@@ -259,6 +352,87 @@ func TestValidNodeLabels(t *testing.T) {
 	}
 }
 
+func TestValidAccelerators(t *testing.T) {
+	grid := []struct {
+		name         string
+		accelerators []kops.AcceleratorSpec
+		expected     []string
+	}{
+		{
+			name: "vendor defaults",
+			accelerators: []kops.AcceleratorSpec{
+				{Vendor: kops.AcceleratorVendorAWSNeuron},
+				{Vendor: kops.AcceleratorVendorAMD},
+			},
+		},
+		{
+			name: "unsupported vendor",
+			accelerators: []kops.AcceleratorSpec{
+				{Vendor: "Unknown"},
+			},
+			expected: []string{"Unsupported value::spec.accelerators[0].vendor"},
+		},
+		{
+			name: "custom",
+			accelerators: []kops.AcceleratorSpec{
+				{
+					Name:          "example",
+					Vendor:        kops.AcceleratorVendorCustom,
+					Packages:      []string{"example-dkms"},
+					KernelModules: []string{"example_drv"},
+					Runtime:       &kops.AcceleratorRuntimeSpec{Name: "example", BinaryName: "/usr/bin/example-container-runtime"},
+					DevicePlugin:  &kops.DevicePluginSpec{Image: "registry.example.com/device-plugin:1.0", ResourceName: "example.com/accelerator"},
+				},
+			},
+		},
+		{
+			name: "custom without device plugin",
+			accelerators: []kops.AcceleratorSpec{
+				{Vendor: kops.AcceleratorVendorCustom},
+			},
+			expected: []string{
+				"Required value::spec.accelerators[0].name",
+				"Required value::spec.accelerators[0].devicePlugin.image",
+				"Required value::spec.accelerators[0].devicePlugin.resourceName",
+			},
+		},
+		{
+			name: "duplicate name",
+			accelerators: []kops.AcceleratorSpec{
+				{Name: "gpu", Vendor: kops.AcceleratorVendorAMD},
+				{Name: "gpu", Vendor: kops.AcceleratorVendorAWSNeuron},
+			},
+			expected: []string{"Duplicate value::spec.accelerators[1].name"},
+		},
+		{
+			name: "invalid kernel module",
+			accelerators: []kops.AcceleratorSpec{
+				{Vendor: kops.AcceleratorVendorAMD, KernelModules: []string{"amdgpu; reboot"}},
+			},
+			expected: []string{"Invalid value::spec.accelerators[0].kernelModules[0]"},
+		},
+		{
+			name: "invalid runtime",
+			accelerators: []kops.AcceleratorSpec{
+				{Vendor: kops.AcceleratorVendorAWSNeuron, Runtime: &kops.AcceleratorRuntimeSpec{Name: "runc", BinaryName: "neuron-runtime"}},
+			},
+			expected: []string{
+				"Forbidden::spec.accelerators[0].runtime.name",
+				"Invalid value::spec.accelerators[0].runtime.binaryName",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			ig := createMinimalInstanceGroup()
+			ig.Spec.Accelerators = g.accelerators
+			errs := ValidateInstanceGroup(ig, nil, true)
+			testErrors(t, g.name, errs, g.expected)
+		})
+	}
+}
+
 func TestValidateIGCloudLabels(t *testing.T) {
 	grid := []struct {
 		label    string
@@ -17,12 +17,15 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
@@ -30,6 +33,7 @@ import (
 	"golang.org/x/net/ipv4"
 	"golang.org/x/net/ipv6"
 	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -40,6 +44,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
 )
@@ -120,6 +125,17 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
 	}
 
+	// SystemdUnits
+	allErrs = append(allErrs, validateSystemdUnits(spec.SystemdUnits, fieldPath.Child("systemdUnits"))...)
+
+	// LifecycleOverrides
+	allErrs = append(allErrs, validateLifecycleOverrides(spec.LifecycleOverrides, fieldPath.Child("lifecycleOverrides"))...)
+	allErrs = append(allErrs, validateValidationChecks(spec.ValidationChecks, fieldPath.Child("validationChecks"))...)
+
+	if spec.OSPackages != nil {
+		allErrs = append(allErrs, validateOSPackages(spec.OSPackages, fieldPath.Child("osPackages"))...)
+	}
+
 	if spec.FileAssets != nil {
 		for i, x := range spec.FileAssets {
 			allErrs = append(allErrs, validateFileAssetSpec(&x, fieldPath.Child("fileAssets").Index(i))...)
@@ -212,6 +228,9 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
 		}
+		if spec.Assets.Verification != nil {
+			allErrs = append(allErrs, validateAssetVerification(spec.Assets.Verification, fieldPath.Child("assets", "verification"))...)
+		}
 	}
 
 	for i, sysctlParameter := range spec.SysctlParameters {
@@ -222,6 +241,7 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 
 	if spec.RollingUpdate != nil {
 		allErrs = append(allErrs, validateRollingUpdate(spec.RollingUpdate, fieldPath.Child("rollingUpdate"), false)...)
+		allErrs = append(allErrs, validateRollingUpdateCloud(spec.RollingUpdate, fieldPath.Child("rollingUpdate"), c)...)
 	}
 
 	if spec.API.LoadBalancer != nil {
@@ -692,6 +712,108 @@ func validateFileAssetSpec(v *kops.FileAssetSpec, fieldPath *field.Path) field.E
 	return allErrs
 }
 
+func validateLifecycleOverrides(v []kops.LifecycleOverrideSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, override := range v {
+		if override.Task == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Index(i).Child("task"), ""))
+		}
+		if _, found := fi.LifecycleNameMap[override.Lifecycle]; !found {
+			allErrs = append(allErrs, field.NotSupported(fieldPath.Index(i).Child("lifecycle"), override.Lifecycle, fi.Lifecycles.List()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateValidationChecks(v []kops.ValidationCheckSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, check := range v {
+		fldPath := fieldPath.Index(i)
+		if check.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+		} else if names.Has(check.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), check.Name))
+		}
+		names.Insert(check.Name)
+
+		count := 0
+		if check.Deployments != nil {
+			count++
+			if check.Deployments.Selector == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("deployments", "selector"), ""))
+			}
+			allErrs = append(allErrs, validateLabelSelector(check.Deployments.Selector, fldPath.Child("deployments", "selector"))...)
+		}
+		if check.HTTPGet != nil {
+			count++
+			u, err := url.Parse(check.HTTPGet.URL)
+			if check.HTTPGet.URL == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("httpGet", "url"), ""))
+			} else if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("httpGet", "url"), check.HTTPGet.URL, "must be an http or https URL"))
+			}
+			if check.HTTPGet.ExpectedStatus != nil && (*check.HTTPGet.ExpectedStatus < 100 || *check.HTTPGet.ExpectedStatus > 599) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("httpGet", "expectedStatus"), *check.HTTPGet.ExpectedStatus, "must be an HTTP status code"))
+			}
+			if check.HTTPGet.Timeout != nil && check.HTTPGet.Timeout.Duration <= 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("httpGet", "timeout"), check.HTTPGet.Timeout.Duration.String(), "must be positive"))
+			}
+		}
+		if check.PodDisruptionBudgets != nil {
+			count++
+			allErrs = append(allErrs, validateLabelSelector(check.PodDisruptionBudgets.Selector, fldPath.Child("podDisruptionBudgets", "selector"))...)
+		}
+		if check.CustomResources != nil {
+			count++
+			if check.CustomResources.APIVersion == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("customResources", "apiVersion"), ""))
+			}
+			if check.CustomResources.Resource == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("customResources", "resource"), ""))
+			}
+			allErrs = append(allErrs, validateLabelSelector(check.CustomResources.Selector, fldPath.Child("customResources", "selector"))...)
+		}
+		if check.NodeConditions != nil {
+			count++
+			if len(check.NodeConditions.Types) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("nodeConditions", "types"), ""))
+			}
+			for j, conditionType := range check.NodeConditions.Types {
+				if conditionType == "" {
+					allErrs = append(allErrs, field.Required(fldPath.Child("nodeConditions", "types").Index(j), ""))
+				}
+			}
+		}
+		if check.Plugin != nil {
+			count++
+			if check.Plugin.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("plugin", "name"), ""))
+			}
+		}
+		if count != 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath, check.Name, "exactly one of deployments, httpGet, podDisruptionBudgets, customResources, nodeConditions or plugin must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateLabelSelector(selector string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, selector, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
 func validateHookSpec(v *kops.HookSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -727,6 +849,133 @@ func validateHookSpec(v *kops.HookSpec, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateSystemdUnits(units []kops.SystemdUnitSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i := range units {
+		unitPath := fieldPath.Index(i)
+		if names.Has(units[i].Name) {
+			allErrs = append(allErrs, field.Duplicate(unitPath.Child("name"), units[i].Name))
+		}
+		names.Insert(units[i].Name)
+		allErrs = append(allErrs, validateSystemdUnitSpec(&units[i], unitPath)...)
+	}
+
+	return allErrs
+}
+
+func validateSystemdUnitSpec(v *kops.SystemdUnitSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), "you must set a name for a systemd unit"))
+	} else {
+		validSuffix := false
+		for _, suffix := range []string{".service", ".socket", ".timer", ".mount", ".automount", ".path", ".target", ".slice"} {
+			if strings.HasSuffix(v.Name, suffix) && len(v.Name) > len(suffix) {
+				validSuffix = true
+				break
+			}
+		}
+		if !validSuffix || strings.Contains(v.Name, "/") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), v.Name, "name must be a systemd unit name with a type suffix, e.g. foo.service"))
+		}
+	}
+
+	if v.Content == "" && len(v.DropIns) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath, "you must set either content or dropIns for a systemd unit"))
+	}
+
+	if v.Content == "" && v.Enabled != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enabled"), "enabled may only be used together with content"))
+	}
+
+	if v.Content == "" && len(v.Before) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("before"), "before may only be used together with content"))
+	}
+
+	dropInNames := sets.NewString()
+	for i, dropIn := range v.DropIns {
+		dropInPath := fieldPath.Child("dropIns").Index(i)
+		if !strings.HasSuffix(dropIn.Name, ".conf") || len(dropIn.Name) == len(".conf") || strings.Contains(dropIn.Name, "/") {
+			allErrs = append(allErrs, field.Invalid(dropInPath.Child("name"), dropIn.Name, "drop-in name must be a file name ending in .conf"))
+		}
+		if dropInNames.Has(dropIn.Name) {
+			allErrs = append(allErrs, field.Duplicate(dropInPath.Child("name"), dropIn.Name))
+		}
+		dropInNames.Insert(dropIn.Name)
+	}
+
+	return allErrs
+}
+
+func validateAssetVerification(v *kops.AssetVerificationSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.PublicKey == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("publicKey"), "a public key is required to verify the asset signatures"))
+	} else if publicKey, err := pki.ParsePEMPublicKey([]byte(v.PublicKey)); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKey"), v.PublicKey, fmt.Sprintf("error parsing public key: %v", err)))
+	} else {
+		switch publicKey.Key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKey"), v.PublicKey, "only ECDSA and RSA public keys are supported"))
+		}
+	}
+
+	if !v.Files && !v.Images {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of files or images must be verified"))
+	}
+
+	return allErrs
+}
+
+func validateOSPackages(v *kops.OSPackagesSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	repositoryNames := sets.NewString()
+	for i, repository := range v.Repositories {
+		repositoryPath := fieldPath.Child("repositories").Index(i)
+		if repository.Name == "" {
+			allErrs = append(allErrs, field.Required(repositoryPath.Child("name"), "you must set a name for a package repository"))
+		} else if strings.ContainsAny(repository.Name, "/ ") {
+			allErrs = append(allErrs, field.Invalid(repositoryPath.Child("name"), repository.Name, "name may not contain slashes or spaces"))
+		}
+		if repositoryNames.Has(repository.Name) {
+			allErrs = append(allErrs, field.Duplicate(repositoryPath.Child("name"), repository.Name))
+		}
+		repositoryNames.Insert(repository.Name)
+		if len(repository.Sources) == 0 && repository.BaseURL == "" {
+			allErrs = append(allErrs, field.Required(repositoryPath, "you must set either sources or baseURL for a package repository"))
+		}
+	}
+
+	packageNames := sets.NewString()
+	for i, pinned := range v.Pinned {
+		pinnedPath := fieldPath.Child("pinned").Index(i)
+		if pinned.Name == "" {
+			allErrs = append(allErrs, field.Required(pinnedPath.Child("name"), "you must set a name for a pinned package"))
+		}
+		if packageNames.Has(pinned.Name) {
+			allErrs = append(allErrs, field.Duplicate(pinnedPath.Child("name"), pinned.Name))
+		}
+		packageNames.Insert(pinned.Name)
+		if pinned.Version == "" {
+			allErrs = append(allErrs, field.Required(pinnedPath.Child("version"), "you must set a version for a pinned package"))
+		} else if strings.ContainsAny(pinned.Version, " =") {
+			allErrs = append(allErrs, field.Invalid(pinnedPath.Child("version"), pinned.Version, "version may not contain spaces or '='"))
+		}
+	}
+
+	if strings.ContainsAny(v.KernelVersion, " \t\n") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("kernelVersion"), v.KernelVersion, "kernelVersion may not contain whitespace"))
+	}
+
+	return allErrs
+}
+
 func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -1778,9 +2027,87 @@ func validateContainerdConfig(cluster *kops.Cluster, config *kops.ContainerdConf
 		allErrs = append(allErrs, validateNvidiaConfig(cluster, config.NvidiaGPU, fldPath.Child("nvidia"), inClusterConfig)...)
 	}
 
+	if len(config.Registries) > 0 {
+		allErrs = append(allErrs, validateContainerdRegistries(config.Registries, fldPath.Child("registries"))...)
+
+		// containerd refuses to start when both the legacy mirrors and the hosts directory are configured
+		registryMirrors := len(config.RegistryMirrors) > 0
+		if !inClusterConfig && cluster.Spec.Containerd != nil && len(cluster.Spec.Containerd.RegistryMirrors) > 0 {
+			registryMirrors = true
+		}
+		if registryMirrors {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("registries"), "registries cannot be used together with registryMirrors"))
+		}
+		if config.ConfigOverride != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("registries"), "registries cannot be used together with configOverride"))
+		}
+	}
+
 	return allErrs
 }
 
+func validateContainerdRegistries(registries map[string]kops.ContainerdRegistryConfig, fldPath *field.Path) (allErrs field.ErrorList) {
+	for name, registry := range registries {
+		fieldPath := fldPath.Key(name)
+
+		if name != "_default" {
+			host, port, err := net.SplitHostPort(name)
+			if err != nil {
+				host, port = name, ""
+			}
+			if errs := utilvalidation.IsDNS1123Subdomain(host); len(errs) > 0 && net.ParseIP(host) == nil {
+				allErrs = append(allErrs, field.Invalid(fieldPath, name, "must be a registry host, optionally with a port, or \"_default\""))
+			} else if n, err := strconv.Atoi(port); port != "" && (err != nil || n < 1 || n > 65535) {
+				allErrs = append(allErrs, field.Invalid(fieldPath, name, "registry port must be between 1 and 65535"))
+			}
+		}
+
+		if registry.Server != "" {
+			allErrs = append(allErrs, validateContainerdRegistryURL(registry.Server, fieldPath.Child("server"))...)
+		}
+		if registry.CACertificate != "" {
+			allErrs = append(allErrs, validateContainerdRegistryCA(registry.CACertificate, fieldPath.Child("caCertificate"))...)
+		}
+
+		mirrors := sets.NewString()
+		for i, mirror := range registry.Mirrors {
+			mirrorPath := fieldPath.Child("mirrors").Index(i)
+			if mirror.URL == "" {
+				allErrs = append(allErrs, field.Required(mirrorPath.Child("url"), ""))
+			} else {
+				allErrs = append(allErrs, validateContainerdRegistryURL(mirror.URL, mirrorPath.Child("url"))...)
+				if mirrors.Has(mirror.URL) {
+					allErrs = append(allErrs, field.Duplicate(mirrorPath.Child("url"), mirror.URL))
+				}
+				mirrors.Insert(mirror.URL)
+			}
+			for j, capability := range mirror.Capabilities {
+				allErrs = append(allErrs, IsValidValue(mirrorPath.Child("capabilities").Index(j), &capability, []string{"pull", "resolve", "push"})...)
+			}
+			if mirror.CACertificate != "" {
+				allErrs = append(allErrs, validateContainerdRegistryCA(mirror.CACertificate, mirrorPath.Child("caCertificate"))...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateContainerdRegistryURL(s string, fldPath *field.Path) field.ErrorList {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return field.ErrorList{field.Invalid(fldPath, s, "must be an http or https URL")}
+	}
+	return nil
+}
+
+func validateContainerdRegistryCA(s string, fldPath *field.Path) field.ErrorList {
+	if _, err := pki.ParsePEMCertificate([]byte(s)); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, "...", fmt.Sprintf("must be a PEM encoded certificate: %v", err))}
+	}
+	return nil
+}
+
 func validateNriConfig(containerd *kops.ContainerdConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if containerd.NRI.Enabled == nil || !fi.ValueOf(containerd.NRI.Enabled) {
 		return allErrs
@@ -1834,15 +2161,30 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Invalid(fldpath.Child("maxSurge"), rollingUpdate.MaxSurge,
 				fmt.Sprintf("Unable to parse: %v", err)))
 		}
-		if onControlPlaneInstanceGroup && surge != 0 {
-			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot surge instance groups with role \"ControlPlane\""))
-		} else if surge < 0 {
+		if surge < 0 {
 			allErrs = append(allErrs, field.Invalid(fldpath.Child("maxSurge"), rollingUpdate.MaxSurge, "Cannot be negative"))
+		} else if onControlPlaneInstanceGroup && surge > 1 {
+			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot surge instance groups with role \"ControlPlane\" by more than 1"))
 		}
-		if unavailable == 0 && surge == 0 {
+		if unavailable == 0 && surge == 0 && rollingUpdate.Strategy != kops.RollingUpdateStrategyBlueGreen {
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	if rollingUpdate.Strategy != "" {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("strategy"), &rollingUpdate.Strategy, kops.SupportedRollingUpdateStrategies)...)
+		if onControlPlaneInstanceGroup && rollingUpdate.Strategy == kops.RollingUpdateStrategyBlueGreen {
+			allErrs = append(allErrs, field.Forbidden(fldpath.Child("strategy"), "Instance groups with role \"ControlPlane\" cannot use the \"BlueGreen\" strategy"))
+		}
+	}
+	return allErrs
+}
+
+// validateRollingUpdateCloud checks that the rolling update settings are supported by the cloud provider of the cluster.
+func validateRollingUpdateCloud(rollingUpdate *kops.RollingUpdate, fldpath *field.Path, cluster *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if rollingUpdate.Strategy == kops.RollingUpdateStrategyBlueGreen && cluster.GetCloudProvider() != kops.CloudProviderAWS && cluster.GetCloudProvider() != kops.CloudProviderGCE {
+		allErrs = append(allErrs, field.Forbidden(fldpath.Child("strategy"), "The \"BlueGreen\" strategy is only supported on AWS and GCE"))
+	}
 	return allErrs
 }
 
@@ -1274,6 +1274,12 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			Input: kops.RollingUpdate{
 				MaxSurge: intStr(intstr.FromInt(1)),
 			},
+			OnMasterIG: true,
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromInt(2)),
+			},
 			OnMasterIG:     true,
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
@@ -1296,14 +1302,14 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 				MaxSurge: intStr(intstr.FromInt(-1)),
 			},
 			OnMasterIG:     true,
-			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
+			ExpectedErrors: []string{"Invalid value::testField.maxSurge"},
 		},
 		{
 			Input: kops.RollingUpdate{
 				MaxSurge: intStr(intstr.FromString("-1%")),
 			},
 			OnMasterIG:     true,
-			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
+			ExpectedErrors: []string{"Invalid value::testField.maxSurge"},
 		},
 		{
 			Input: kops.RollingUpdate{
@@ -1312,6 +1318,37 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyRolling,
+			},
+			OnMasterIG: true,
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyBlueGreen,
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromInt(0)),
+				MaxSurge:       intStr(intstr.FromInt(0)),
+				Strategy:       kops.RollingUpdateStrategyBlueGreen,
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyBlueGreen,
+			},
+			OnMasterIG:     true,
+			ExpectedErrors: []string{"Forbidden::testField.strategy"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: "Recreate",
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.strategy"},
+		},
 	}
 	for _, g := range grid {
 		errs := validateRollingUpdate(&g.Input, field.NewPath("testField"), g.OnMasterIG)
@@ -1319,6 +1356,37 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 	}
 }
 
+func Test_Validate_RollingUpdateCloud(t *testing.T) {
+	grid := []struct {
+		Cloud          kops.CloudProviderSpec
+		Input          kops.RollingUpdate
+		ExpectedErrors []string
+	}{
+		{
+			Cloud: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			Input: kops.RollingUpdate{Strategy: kops.RollingUpdateStrategyBlueGreen},
+		},
+		{
+			Cloud: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			Input: kops.RollingUpdate{Strategy: kops.RollingUpdateStrategyBlueGreen},
+		},
+		{
+			Cloud: kops.CloudProviderSpec{Hetzner: &kops.HetznerSpec{}},
+			Input: kops.RollingUpdate{Strategy: kops.RollingUpdateStrategyRolling},
+		},
+		{
+			Cloud:          kops.CloudProviderSpec{Hetzner: &kops.HetznerSpec{}},
+			Input:          kops.RollingUpdate{Strategy: kops.RollingUpdateStrategyBlueGreen},
+			ExpectedErrors: []string{"Forbidden::testField.strategy"},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: kops.ClusterSpec{CloudProvider: g.Cloud}}
+		errs := validateRollingUpdateCloud(&g.Input, field.NewPath("testField"), cluster)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func intStr(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
@@ -1777,3 +1845,334 @@ func Test_Validate_NriConfig(t *testing.T) {
 		testErrors(t, g.Input.Containerd, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_SystemdUnits(t *testing.T) {
+	grid := []struct {
+		Input          []kops.SystemdUnitSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.SystemdUnitSpec{
+				{
+					Name:    "foo.service",
+					Content: "[Service]\nExecStart=/bin/true\n",
+					Before:  []string{"kubelet.service"},
+				},
+				{
+					Name: "kubelet.service",
+					DropIns: []kops.SystemdDropInSpec{
+						{Name: "10-limits.conf", Content: "[Service]\nLimitNOFILE=1048576\n"},
+					},
+				},
+			},
+		},
+		{
+			Input: []kops.SystemdUnitSpec{
+				{
+					Name:    "foo",
+					Content: "[Service]\nExecStart=/bin/true\n",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::systemdUnits[0].name"},
+		},
+		{
+			Input: []kops.SystemdUnitSpec{
+				{
+					Name: "foo.service",
+				},
+			},
+			ExpectedErrors: []string{"Required value::systemdUnits[0]"},
+		},
+		{
+			Input: []kops.SystemdUnitSpec{
+				{
+					Name:    "foo.timer",
+					Content: "[Timer]\nOnCalendar=daily\n",
+				},
+				{
+					Name:    "foo.timer",
+					Content: "[Timer]\nOnCalendar=weekly\n",
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::systemdUnits[1].name"},
+		},
+		{
+			Input: []kops.SystemdUnitSpec{
+				{
+					Name:    "containerd.service",
+					Enabled: fi.PtrTo(false),
+					Before:  []string{"kubelet.service"},
+					DropIns: []kops.SystemdDropInSpec{
+						{Name: "10-limits", Content: "[Service]\nLimitNOFILE=1048576\n"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::systemdUnits[0].enabled",
+				"Forbidden::systemdUnits[0].before",
+				"Invalid value::systemdUnits[0].dropIns[0].name",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSystemdUnits(g.Input, field.NewPath("systemdUnits"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_OSPackages(t *testing.T) {
+	grid := []struct {
+		Input          kops.OSPackagesSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.OSPackagesSpec{
+				Repositories: []kops.PackageRepositorySpec{
+					{
+						Name:    "internal",
+						Keyring: "https://packages.example.com/gpgkey",
+						Sources: []string{"deb https://packages.example.com/ubuntu jammy main"},
+					},
+				},
+				Pinned: []kops.PinnedPackageSpec{
+					{Name: "linux-image-aws", Version: "6.5.0.1020.20"},
+				},
+				KernelVersion: "6.5.0-1020-aws",
+			},
+		},
+		{
+			Input: kops.OSPackagesSpec{
+				Repositories: []kops.PackageRepositorySpec{
+					{Name: "internal"},
+				},
+			},
+			ExpectedErrors: []string{"Required value::osPackages.repositories[0]"},
+		},
+		{
+			Input: kops.OSPackagesSpec{
+				Pinned: []kops.PinnedPackageSpec{
+					{Name: "socat", Version: "1.7.4.1-3ubuntu4"},
+					{Name: "socat", Version: "1.7.4.1-3ubuntu5"},
+					{Name: "ethtool"},
+					{Name: "conntrack", Version: "conntrack=1.4.6"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::osPackages.pinned[1].name",
+				"Required value::osPackages.pinned[2].version",
+				"Invalid value::osPackages.pinned[3].version",
+			},
+		},
+		{
+			Input: kops.OSPackagesSpec{
+				KernelVersion: "6.5.0 aws",
+			},
+			ExpectedErrors: []string{"Invalid value::osPackages.kernelVersion"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateOSPackages(&g.Input, field.NewPath("osPackages"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_LifecycleOverrides(t *testing.T) {
+	grid := []struct {
+		Input          []kops.LifecycleOverrideSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.LifecycleOverrideSpec{
+				{Task: "SecurityGroup", ID: "sg-12345678", Lifecycle: "Ignore"},
+				{Task: "NatGateway", Name: "us-east-1a.example.com", Lifecycle: "ExistsAndValidates"},
+			},
+		},
+		{
+			Input: []kops.LifecycleOverrideSpec{
+				{Lifecycle: "Ignore"},
+				{Task: "SecurityGroup", Lifecycle: "Never"},
+			},
+			ExpectedErrors: []string{
+				"Required value::lifecycleOverrides[0].task",
+				"Unsupported value::lifecycleOverrides[1].lifecycle",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateLifecycleOverrides(g.Input, field.NewPath("lifecycleOverrides"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ValidationChecks(t *testing.T) {
+	grid := []struct {
+		Input          []kops.ValidationCheckSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.ValidationCheckSpec{
+				{Name: "ingress-replicas", Deployments: &kops.DeploymentsValidationCheck{Namespace: "ingress", Selector: "app=ingress"}},
+				{Name: "ingress", HTTPGet: &kops.HTTPGetValidationCheck{URL: "https://ingress.example.com/healthz", ExpectedStatus: fi.PtrTo(int32(204))}},
+				{Name: "pdbs", PodDisruptionBudgets: &kops.PodDisruptionBudgetsValidationCheck{}},
+				{Name: "certificates", CustomResources: &kops.CustomResourcesValidationCheck{APIVersion: "cert-manager.io/v1", Resource: "certificates"}},
+				{Name: "node-problems", NodeConditions: &kops.NodeConditionsValidationCheck{Types: []string{"KernelDeadlock", "ReadonlyFilesystem"}}},
+				{Name: "custom", Plugin: &kops.PluginValidationCheck{Name: "example", Config: map[string]string{"key": "value"}}},
+			},
+		},
+		{
+			Input: []kops.ValidationCheckSpec{
+				{Deployments: &kops.DeploymentsValidationCheck{}},
+				{Name: "ingress", HTTPGet: &kops.HTTPGetValidationCheck{URL: "ftp://ingress.example.com", ExpectedStatus: fi.PtrTo(int32(42))}},
+				{Name: "ingress", PodDisruptionBudgets: &kops.PodDisruptionBudgetsValidationCheck{Selector: "app in ("}},
+				{Name: "certificates", CustomResources: &kops.CustomResourcesValidationCheck{}},
+				{Name: "custom", Plugin: &kops.PluginValidationCheck{}, Deployments: &kops.DeploymentsValidationCheck{Selector: "app=ingress"}},
+				{Name: "empty"},
+				{Name: "node-problems", NodeConditions: &kops.NodeConditionsValidationCheck{}},
+				{Name: "node-problems-empty-type", NodeConditions: &kops.NodeConditionsValidationCheck{Types: []string{"KernelDeadlock", ""}}},
+			},
+			ExpectedErrors: []string{
+				"Required value::validationChecks[0].name",
+				"Required value::validationChecks[0].deployments.selector",
+				"Invalid value::validationChecks[1].httpGet.url",
+				"Invalid value::validationChecks[1].httpGet.expectedStatus",
+				"Duplicate value::validationChecks[2].name",
+				"Invalid value::validationChecks[2].podDisruptionBudgets.selector",
+				"Required value::validationChecks[3].customResources.apiVersion",
+				"Required value::validationChecks[3].customResources.resource",
+				"Required value::validationChecks[4].plugin.name",
+				"Invalid value::validationChecks[4]",
+				"Invalid value::validationChecks[5]",
+				"Required value::validationChecks[6].nodeConditions.types",
+				"Required value::validationChecks[7].nodeConditions.types[1]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateValidationChecks(g.Input, field.NewPath("validationChecks"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ContainerdRegistries(t *testing.T) {
+	caCertificate := "-----BEGIN CERTIFICATE-----\nMIIBTDCB96ADAgECAhBjHcUz56MCdYqSYy7TYNe3MA0GCSqGSIb3DQEBCwUAMBUx\nEzARBgNVBAMTCnNlbGZzaWduZWQwHhcNMjAwNDI0MjMzNDM5WhcNMzAwNDI0MjMz\nNDM5WjAVMRMwEQYDVQQDEwpzZWxmc2lnbmVkMFwwDQYJKoZIhvcNAQEBBQADSwAw\nSAJBAL5zWUObMH5dBestQgDIa4B/rT7Cc21AK+B7gPvMcEfIWow5u6QE+EyhRTPv\n727oY+2MU9e4vq5RXBG7hneuBoECAwEAAaMjMCEwDgYDVR0PAQH/BAQDAgEGMA8G\nA1UdEwEB/wQFMAMBAf8wDQYJKoZIhvcNAQELBQADQQBLUFz7gDKRRyjEwgRZnZzP\nOma9WIgOjX36OFllyGkspu1ZcW/EtGEGNXqtMsm1QmG38Lh7Nkehb5xoAmm6hkFA\n-----END CERTIFICATE-----"
+
+	grid := []struct {
+		Input          kops.ContainerdConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {
+						Mirrors: []kops.ContainerdRegistryHostConfig{
+							{URL: "https://mirror.example.com"},
+							{URL: "https://registry.example.com:5000/v2/dockerhub", Capabilities: []string{"pull"}, OverridePath: true, CACertificate: caCertificate},
+						},
+					},
+					"registry.example.com:5000": {
+						Server:        "https://registry.example.com:5000",
+						CACertificate: caCertificate,
+					},
+					"_default": {
+						Mirrors: []kops.ContainerdRegistryHostConfig{
+							{URL: "http://10.0.0.1:5000"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"https://docker.io": {},
+					"registry.example.com:99999": {
+						Server:        "registry.example.com",
+						CACertificate: "not a certificate",
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::containerd.registries[https://docker.io]",
+				"Invalid value::containerd.registries[registry.example.com:99999]",
+				"Invalid value::containerd.registries[registry.example.com:99999].server",
+				"Invalid value::containerd.registries[registry.example.com:99999].caCertificate",
+			},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {
+						Mirrors: []kops.ContainerdRegistryHostConfig{
+							{URL: "https://mirror.example.com"},
+							{URL: "https://mirror.example.com"},
+							{},
+							{URL: "https://mirror2.example.com", Capabilities: []string{"pull", "delete"}},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::containerd.registries[docker.io].mirrors[1].url",
+				"Required value::containerd.registries[docker.io].mirrors[2].url",
+				"Unsupported value::containerd.registries[docker.io].mirrors[3].capabilities[1]",
+			},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				RegistryMirrors: map[string][]string{
+					"docker.io": {"https://mirror.example.com"},
+				},
+				ConfigOverride: fi.PtrTo("version = 2"),
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::containerd.registries",
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{}
+		errs := validateContainerdConfig(cluster, &g.Input, field.NewPath("containerd"), true)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AssetVerification(t *testing.T) {
+	publicKey := "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEuiMEobvLgOAxVcu+XMeMgfovhbpn\ng1KWFQ4i6eHZ59VWpA5G4g5u0o63kyHc357RsmWgPx6+SWokyd/nMlKTLw==\n-----END PUBLIC KEY-----"
+
+	grid := []struct {
+		Input          kops.AssetVerificationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AssetVerificationSpec{
+				PublicKey: publicKey,
+				Files:     true,
+				Images:    true,
+			},
+		},
+		{
+			Input: kops.AssetVerificationSpec{
+				Files: true,
+			},
+			ExpectedErrors: []string{
+				"Required value::assets.verification.publicKey",
+			},
+		},
+		{
+			Input: kops.AssetVerificationSpec{
+				PublicKey: "not a public key",
+			},
+			ExpectedErrors: []string{
+				"Invalid value::assets.verification.publicKey",
+				"Required value::assets.verification",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAssetVerification(&g.Input, field.NewPath("assets", "verification"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
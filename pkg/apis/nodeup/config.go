@@ -17,20 +17,32 @@ limitations under the License.
 package nodeup
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/reflectutils"
 )
 
+// TrustedAssetVerificationKeyPath is where images can provide the public key the signatures of the assets are verified with.
+// When present, it is used instead of the key in the BootConfig.
+const TrustedAssetVerificationKeyPath = "/etc/kops/asset-verification.pub"
+
+// ContainerdRegistriesConfigPath is the directory holding the hosts.toml file of each registry
+const ContainerdRegistriesConfigPath = "/etc/containerd/certs.d"
+
 // Config is the configuration for the nodeup binary
 type Config struct {
 	// Assets are locations where we can find files to be installed
 	// TODO: Remove once everything is in containers?
 	Assets map[architectures.Architecture][]string `json:",omitempty"`
+	// AssetVerification holds the signatures of the assets, when they must be signed to be installed.
+	AssetVerification *AssetVerificationConfig `json:"assetVerification,omitempty"`
 	// Images are a list of images we should preload
 	Images map[architectures.Architecture][]*Image `json:"images,omitempty"`
 	// ClusterName is the name of the cluster
@@ -43,6 +55,8 @@ type Config struct {
 	KubernetesVersion string
 	// Packages specifies additional packages to be installed.
 	Packages []string `json:"packages,omitempty"`
+	// OSPackages pins the versions of OS packages and the kernel.
+	OSPackages *kops.OSPackagesSpec `json:"osPackages,omitempty"`
 
 	// ConfigStore configures the stores that nodes use to get their configuration when they don't use kops-controller.
 	ConfigStore *kops.ConfigStoreSpec `json:"configStore,omitempty"`
@@ -88,8 +102,17 @@ type Config struct {
 	FileAssets []kops.FileAssetSpec `json:",omitempty"`
 	// Hooks are for custom actions, for example on first installation.
 	Hooks [][]kops.HookSpec
+	// SystemdUnits are systemd units and drop-ins for this instance group, followed by the cluster wide ones.
+	SystemdUnits []kops.SystemdUnitSpec `json:",omitempty"`
+	// Accelerators are the accelerator devices to install drivers and container runtimes for.
+	Accelerators []kops.AcceleratorSpec `json:"accelerators,omitempty"`
 	// ContainerdConfig holds the configuration for containerd.
 	ContainerdConfig *kops.ContainerdConfig `json:"containerdConfig,omitempty"`
+	// ContainerdRegistriesConfigPath is the directory containerd reads the registry hosts configuration from.
+	ContainerdRegistriesConfigPath string `json:"containerdRegistriesConfigPath,omitempty"`
+	// ContainerdRegistries holds the registry hosts configuration for containerd.
+	// It is not part of the NodeupConfigHash, so that nodeup can apply changes on running nodes.
+	ContainerdRegistries map[string]kops.ContainerdRegistryConfig `json:"containerdRegistries,omitempty"`
 
 	// APIServerConfig is additional configuration for nodes running an APIServer.
 	APIServerConfig *APIServerConfig `json:",omitempty"`
@@ -159,6 +182,10 @@ type BootConfig struct {
 	InstanceGroupRole kops.InstanceGroupRole
 	// NodeupConfigHash holds a secure hash of the nodeup.Config.
 	NodeupConfigHash string
+	// AssetVerificationPublicKey is the PEM encoded public key the signatures of the assets are verified with.
+	// It is kept out of the nodeup.Config, which also holds the assets and their signatures, so that changing
+	// the nodeup.Config is not enough to have other assets installed.
+	AssetVerificationPublicKey string `json:",omitempty"`
 }
 
 type ConfigServerOptions struct {
@@ -178,6 +205,13 @@ type Image struct {
 	Hash string `json:"hash,omitempty"`
 }
 
+// AssetVerificationConfig holds the signatures the assets are verified with before being installed.
+// The public key they are verified with is in the BootConfig, or on the image at TrustedAssetVerificationKeyPath.
+type AssetVerificationConfig struct {
+	// Signatures maps the hex SHA-256 hash of each asset to its base64 encoded signature.
+	Signatures map[string]string `json:"signatures,omitempty"`
+}
+
 // StaticManifest is a generic static manifest
 type StaticManifest struct {
 	// Key identifies the static manifest
@@ -232,6 +266,8 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		VolumeMounts:         instanceGroup.Spec.VolumeMounts,
 		FileAssets:           append(filterFileAssets(instanceGroup.Spec.FileAssets, role), filterFileAssets(cluster.Spec.FileAssets, role)...),
 		Hooks:                [][]kops.HookSpec{igHooks, clusterHooks},
+		SystemdUnits:         append(filterSystemdUnits(instanceGroup.Spec.SystemdUnits, role), filterSystemdUnits(cluster.Spec.SystemdUnits, role)...),
+		Accelerators:         instanceGroup.Spec.Accelerators,
 		UsesLegacyGossip:     cluster.UsesLegacyGossip(),
 		UsesNoneDNS:          cluster.UsesNoneDNS(),
 	}
@@ -245,6 +281,13 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 
 	if cluster.Spec.Containerd != nil || instanceGroup.Spec.Containerd != nil {
 		config.ContainerdConfig = buildContainerdConfig(cluster, instanceGroup)
+
+		// The registries are moved out of the containerd config, as they are applied in place
+		if len(config.ContainerdConfig.Registries) != 0 {
+			config.ContainerdRegistriesConfigPath = ContainerdRegistriesConfigPath
+			config.ContainerdRegistries = config.ContainerdConfig.Registries
+			config.ContainerdConfig.Registries = nil
+		}
 	}
 
 	if (cluster.Spec.Containerd != nil && cluster.Spec.Containerd.NvidiaGPU != nil) || (instanceGroup.Spec.Containerd != nil && instanceGroup.Spec.Containerd.NvidiaGPU != nil) {
@@ -253,6 +296,10 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 
 	config.KubeProxy = buildKubeProxy(cluster, instanceGroup)
 
+	if cluster.Spec.OSPackages != nil || instanceGroup.Spec.OSPackages != nil {
+		config.OSPackages = buildOSPackages(cluster, instanceGroup)
+	}
+
 	if cluster.Spec.NTP != nil && cluster.Spec.NTP.Managed != nil && !*cluster.Spec.NTP.Managed {
 		config.NTPUnmanaged = true
 	}
@@ -399,6 +446,19 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 	return &config, &bootConfig
 }
 
+// HashConfig returns the hash of the nodeup configuration that is recorded in the BootConfig.
+// kOps and nodeup both hash the config struct, rather than the file nodeup reads, so that the hash doesn't depend on how the file was written.
+// ContainerdRegistries is excluded, as nodeup applies it in place on running nodes.
+func HashConfig(config *Config) ([32]byte, error) {
+	c := *config
+	c.ContainerdRegistries = nil
+	b, err := utils.YamlMarshal(&c)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("error converting nodeup config to yaml: %w", err)
+	}
+	return sha256.Sum256(b), nil
+}
+
 // buildContainerdConfig builds containerd configuration for instance. Instance group configuration will override cluster configuration
 func buildContainerdConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) *kops.ContainerdConfig {
 	config := cluster.Spec.Containerd.DeepCopy()
@@ -426,6 +486,44 @@ func buildNvidiaConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup)
 	return config
 }
 
+// buildOSPackages builds the pinned OS packages for an instance group. Instance group configuration will override cluster configuration
+func buildOSPackages(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) *kops.OSPackagesSpec {
+	config := &kops.OSPackagesSpec{}
+	for _, spec := range []*kops.OSPackagesSpec{cluster.Spec.OSPackages, instanceGroup.Spec.OSPackages} {
+		if spec == nil {
+			continue
+		}
+		for _, repository := range spec.Repositories {
+			found := false
+			for i := range config.Repositories {
+				if config.Repositories[i].Name == repository.Name {
+					config.Repositories[i] = repository
+					found = true
+				}
+			}
+			if !found {
+				config.Repositories = append(config.Repositories, repository)
+			}
+		}
+		for _, pinned := range spec.Pinned {
+			found := false
+			for i := range config.Pinned {
+				if config.Pinned[i].Name == pinned.Name {
+					config.Pinned[i] = pinned
+					found = true
+				}
+			}
+			if !found {
+				config.Pinned = append(config.Pinned, pinned)
+			}
+		}
+		if spec.KernelVersion != "" {
+			config.KernelVersion = spec.KernelVersion
+		}
+	}
+	return config
+}
+
 // buildkubeProxy builds the kube-proxy configuration for an instance group.
 func buildKubeProxy(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) *kops.KubeProxyConfig {
 	config := &kops.KubeProxyConfig{}
@@ -466,6 +564,18 @@ func filterHooks(h []kops.HookSpec, role kops.InstanceGroupRole) []kops.HookSpec
 	return hooks
 }
 
+func filterSystemdUnits(u []kops.SystemdUnitSpec, role kops.InstanceGroupRole) []kops.SystemdUnitSpec {
+	var units []kops.SystemdUnitSpec
+	for _, unit := range u {
+		if len(unit.Roles) > 0 && !containsRole(role, unit.Roles) {
+			continue
+		}
+		unit.Roles = nil
+		units = append(units, unit)
+	}
+	return units
+}
+
 func containsRole(v kops.InstanceGroupRole, list []kops.InstanceGroupRole) bool {
 	for _, x := range list {
 		if v == x {
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+func TestHashConfig_ContainerdRegistries(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.KubernetesVersion = "1.30.0"
+	cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+	cluster.Spec.Containerd = &kops.ContainerdConfig{
+		Registries: map[string]kops.ContainerdRegistryConfig{
+			"docker.io": {
+				Mirrors: []kops.ContainerdRegistryHostConfig{{URL: "https://mirror.example.com"}},
+			},
+		},
+	}
+	ig := &kops.InstanceGroup{}
+	ig.Spec.Role = kops.InstanceGroupRoleNode
+
+	config, _ := NewConfig(cluster, ig)
+	if config.ContainerdRegistriesConfigPath != ContainerdRegistriesConfigPath {
+		t.Errorf("unexpected registries config path %q", config.ContainerdRegistriesConfigPath)
+	}
+	if len(config.ContainerdConfig.Registries) != 0 {
+		t.Errorf("expected registries to be moved out of the containerd config")
+	}
+
+	expected, err := HashConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The hash must not change when only the registries change
+	config.ContainerdRegistries["quay.io"] = kops.ContainerdRegistryConfig{Server: "https://quay.example.com"}
+	actual, err := HashConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("hash changed when only the registries changed")
+	}
+
+	// nodeup hashes the config it read back from the state store
+	b, err := utils.YamlMarshal(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed Config
+	if err := utils.YamlUnmarshal(b, &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err = HashConfig(&parsed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("hash changed after a round trip through yaml")
+	}
+
+	// Removing the registries changes the config path, which needs a new node
+	config.ContainerdRegistriesConfigPath = ""
+	actual, err = HashConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual == expected {
+		t.Errorf("hash did not change when the registries config path changed")
+	}
+}
@@ -58,6 +58,9 @@ type AssetBuilder struct {
 	// StaticFiles records static files:
 	// * Configuration files supporting static pods
 	StaticFiles []*StaticFile
+
+	// verifier checks the asset signatures, it is built on first use
+	verifier *signatureVerifier
 }
 
 type StaticFile struct {
@@ -89,6 +92,8 @@ type ImageAsset struct {
 	DownloadLocation string
 	// CanonicalLocation will be the source location of the image.
 	CanonicalLocation string
+	// Verification is the result of the signature verification, if enabled.
+	Verification VerificationStatus
 }
 
 // FileAsset models a file's location.
@@ -99,6 +104,10 @@ type FileAsset struct {
 	CanonicalURL *url.URL
 	// SHAValue is the SHA hash of the FileAsset.
 	SHAValue *hashing.Hash
+	// Signature is the base64 encoded signature of the FileAsset, if verification is enabled.
+	Signature string
+	// Verification is the result of the signature verification, if enabled.
+	Verification VerificationStatus
 }
 
 // NewAssetBuilder creates a new AssetBuilder.
@@ -219,6 +228,10 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 
 	a.ImageAssets = append(a.ImageAssets, asset)
 
+	if verification := a.verification(); verification != nil && verification.Images {
+		return a.verifyImage(asset, image)
+	}
+
 	if !featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "" {
 		return image, nil
 	}
@@ -272,6 +285,12 @@ func (a *AssetBuilder) RemapFile(canonicalURL *url.URL, knownHash *hashing.Hash)
 
 	fileAsset.SHAValue = knownHash
 
+	if verification := a.verification(); verification != nil && verification.Files {
+		if err := a.verifyFile(fileAsset); err != nil {
+			return nil, err
+		}
+	}
+
 	klog.V(8).Infof("adding file: %+v", fileAsset)
 	a.FileAssets = append(a.FileAssets, fileAsset)
 
@@ -355,6 +374,93 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 	return nil, fmt.Errorf("cannot determine hash for %q (have you specified a valid file location?)", u)
 }
 
+// verification returns the asset verification spec, or nil if verification is not configured.
+func (a *AssetBuilder) verification() *kops.AssetVerificationSpec {
+	if a.AssetsLocation == nil {
+		return nil
+	}
+	return a.AssetsLocation.Verification
+}
+
+// signatureVerifier returns the verifier for the asset signatures.
+func (a *AssetBuilder) signatureVerifier() (*signatureVerifier, error) {
+	if a.verifier == nil {
+		v, err := newSignatureVerifier(a.vfsContext, a.verification())
+		if err != nil {
+			return nil, err
+		}
+		a.verifier = v
+	}
+	return a.verifier, nil
+}
+
+// verifyFile checks the signature of a file asset.
+// When only getting the assets, failures are recorded in the asset instead, so that they can be reported.
+func (a *AssetBuilder) verifyFile(file *FileAsset) error {
+	verifier, err := a.signatureVerifier()
+	if err != nil {
+		return err
+	}
+
+	// The signature is stored next to the hash file, so we use the same location as findHash
+	u := file.DownloadURL
+	if a.GetAssets {
+		u = file.CanonicalURL
+	}
+
+	signature, status, err := verifier.verifyFile(a.vfsContext, u.String(), file.SHAValue)
+	file.Signature = signature
+	file.Verification = status
+	if err != nil {
+		if a.GetAssets {
+			klog.Warningf("%v", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyImage checks the signatures of an image asset, and returns the image pinned to the verified digest.
+// When only getting the assets, failures are recorded in the asset instead, so that they can be reported.
+func (a *AssetBuilder) verifyImage(asset *ImageAsset, image string) (string, error) {
+	verifier, err := a.signatureVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	// Images can be remapped more than once, in which case they are already pinned
+	var digest string
+	if i := strings.Index(image, "@"); i != -1 {
+		digest = image[i+1:]
+		image = image[:i]
+	} else if verifier.bundle == nil {
+		d, err := crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
+		if err != nil {
+			asset.Verification = VerificationStatusUnsigned
+			if a.GetAssets {
+				klog.Warningf("failed to digest image %q: %v", image, err)
+				return image, nil
+			}
+			return "", fmt.Errorf("failed to digest image %q: %w", image, err)
+		}
+		digest = d
+	}
+
+	// Signatures are bound to the digest, so we look them up for the canonical image, wherever the image is pulled from
+	verified, status, err := verifier.verifyImage(strings.Split(asset.CanonicalLocation, "@")[0], digest)
+	asset.Verification = status
+	if err != nil {
+		if a.GetAssets {
+			klog.Warningf("%v", err)
+			return image, nil
+		}
+		return "", err
+	}
+
+	return image + "@" + verified, nil
+}
+
 func (a *AssetBuilder) remapURL(canonicalURL *url.URL) (*url.URL, error) {
 	f := ""
 	if a.AssetsLocation != nil {
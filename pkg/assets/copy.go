@@ -57,6 +57,7 @@ func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VF
 				TargetFile: fileAsset.DownloadURL.String(),
 				SourceFile: fileAsset.CanonicalURL.String(),
 				SHA:        fileAsset.SHAValue.Hex(),
+				Signature:  fileAsset.Signature,
 				VFSContext: vfsContext,
 				Cluster:    cluster,
 			}
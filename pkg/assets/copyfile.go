@@ -38,6 +38,8 @@ type CopyFile struct {
 	SourceFile string
 	TargetFile string
 	SHA        string
+	// Signature is the signature of the file, which is copied alongside it when set.
+	Signature  string
 	VFSContext *vfs.VFSContext
 	Cluster    *kops.Cluster
 }
@@ -80,7 +82,7 @@ func (e *CopyFile) Run() error {
 
 		if strings.TrimSpace(targetSHA) == expectedSHA {
 			klog.V(8).Infof("found matching target sha for file: %q", e.TargetFile)
-			return nil
+			return e.copySignature(ctx)
 		}
 
 		klog.V(8).Infof("did not find same file, found mismatching target sha1 for file: %q", e.TargetFile)
@@ -96,7 +98,25 @@ func (e *CopyFile) Run() error {
 		return fmt.Errorf("unable to transfer %q to %q: %v", source, target, err)
 	}
 
-	return nil
+	return e.copySignature(ctx)
+}
+
+// copySignature uploads the signature of the file next to the target file, so that it can be verified from there.
+func (e *CopyFile) copySignature(ctx context.Context) error {
+	if e.Signature == "" {
+		return nil
+	}
+
+	objectStore, err := buildVFSPath(e.TargetFile + ".sig")
+	if err != nil {
+		return err
+	}
+	signatureVFS, err := e.VFSContext.BuildVfsPath(objectStore)
+	if err != nil {
+		return fmt.Errorf("error building path %q: %v", objectStore, err)
+	}
+
+	return writeFile(ctx, e.Cluster, signatureVFS, []byte(e.Signature))
 }
 
 // transferFile downloads a file from the source location, validates the file matches the SHA,
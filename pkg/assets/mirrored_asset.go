@@ -26,6 +26,8 @@ import (
 type MirroredAsset struct {
 	Locations []string
 	Hash      *hashing.Hash
+	// Signature is the signature of the asset, if asset verification is enabled.
+	Signature string
 }
 
 // BuildMirroredAsset checks to see if this is a file under the standard base location, and if so constructs some mirror locations
@@ -33,7 +35,8 @@ func BuildMirroredAsset(asset *FileAsset) *MirroredAsset {
 	u := asset.DownloadURL
 
 	a := &MirroredAsset{
-		Hash: asset.SHAValue,
+		Hash:      asset.SHAValue,
+		Signature: asset.Signature,
 	}
 
 	if asset.SHAValue == nil {
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// VerificationStatus is the result of the signature verification of an asset.
+type VerificationStatus string
+
+const (
+	// VerificationStatusVerified means the asset has a valid signature
+	VerificationStatusVerified VerificationStatus = "Verified"
+	// VerificationStatusUnsigned means no signature was found for the asset
+	VerificationStatusUnsigned VerificationStatus = "Unsigned"
+	// VerificationStatusInvalid means the signatures of the asset do not match the public key
+	VerificationStatusInvalid VerificationStatus = "Invalid"
+)
+
+const (
+	// cosignSignatureAnnotation is the layer annotation holding the signature of a cosign image signature
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// cosignSignatureType is the type of the payload of a cosign image signature
+	cosignSignatureType = "cosign container image signature"
+)
+
+// SignatureBundle holds the signatures of the assets, for verification without access to the signature locations.
+type SignatureBundle struct {
+	// Files maps the hex SHA-256 hash of each file to its base64 encoded signature, as output by `cosign sign-blob`.
+	Files map[string]string `json:"files,omitempty"`
+	// Images maps each image, as referenced in the manifests, to its signatures.
+	Images map[string][]ImageSignature `json:"images,omitempty"`
+}
+
+// ImageSignature is a cosign container image signature.
+type ImageSignature struct {
+	// Payload is the base64 encoded simple signing payload.
+	Payload string `json:"payload"`
+	// Signature is the base64 encoded signature of the payload.
+	Signature string `json:"signature"`
+}
+
+// simpleSigningPayload is the part of the simple signing payload of a cosign image signature we verify
+type simpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// signatureVerifier verifies the signatures of file and image assets
+type signatureVerifier struct {
+	publicKey crypto.PublicKey
+	// bundle is set when the signatures are read from an offline bundle
+	bundle *SignatureBundle
+}
+
+// newSignatureVerifier builds the verifier for the verification spec, loading the offline bundle if set
+func newSignatureVerifier(vfsContext *vfs.VFSContext, spec *kops.AssetVerificationSpec) (*signatureVerifier, error) {
+	publicKey, err := pki.ParsePEMPublicKey([]byte(spec.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing asset verification public key: %w", err)
+	}
+
+	v := &signatureVerifier{
+		publicKey: publicKey.Key,
+	}
+
+	if spec.Bundle != "" {
+		b, err := vfsContext.ReadFile(spec.Bundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading signature bundle %q: %w", spec.Bundle, err)
+		}
+		bundle := &SignatureBundle{}
+		if err := yaml.Unmarshal(b, bundle); err != nil {
+			return nil, fmt.Errorf("error parsing signature bundle %q: %w", spec.Bundle, err)
+		}
+		v.bundle = bundle
+	}
+
+	return v, nil
+}
+
+// verifyFile checks the signature of a file, which is made over its SHA-256 hash.
+// The signature is read from the bundle, or from the .sig file next to fileURL.
+func (v *signatureVerifier) verifyFile(vfsContext *vfs.VFSContext, fileURL string, hash *hashing.Hash) (string, VerificationStatus, error) {
+	if hash == nil || hash.Algorithm != hashing.HashAlgorithmSHA256 {
+		return "", VerificationStatusInvalid, fmt.Errorf("file %q does not have a SHA-256 hash to verify the signature against", fileURL)
+	}
+
+	var signature string
+	if v.bundle != nil {
+		signature = v.bundle.Files[hash.Hex()]
+	} else {
+		for _, mirror := range FindURLMirrors(fileURL) {
+			signatureURL := mirror + ".sig"
+			klog.V(3).Infof("Trying to read signature file: %q", signatureURL)
+			b, err := vfsContext.ReadFile(signatureURL)
+			if err != nil {
+				klog.V(2).Infof("Unable to read signature file %q: %v", signatureURL, err)
+				continue
+			}
+			signature = strings.TrimSpace(string(b))
+			break
+		}
+	}
+	if signature == "" {
+		return "", VerificationStatusUnsigned, fmt.Errorf("no signature found for file %q", fileURL)
+	}
+
+	if err := VerifySignature(v.publicKey, hash.HashValue, signature); err != nil {
+		return "", VerificationStatusInvalid, fmt.Errorf("invalid signature for file %q: %w", fileURL, err)
+	}
+
+	return signature, VerificationStatusVerified, nil
+}
+
+// verifyImage checks the cosign signatures of an image, and returns the verified digest.
+// The signatures are read from the bundle, or from the signature tag in the repository of the canonical image,
+// and must be made for that repository.
+// digest is the digest the image is expected to have, if already known.
+func (v *signatureVerifier) verifyImage(image string, digest string) (string, VerificationStatus, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", VerificationStatusInvalid, fmt.Errorf("error parsing image %q: %w", image, err)
+	}
+	repository := ref.Context().Name()
+
+	var signatures []ImageSignature
+	if v.bundle != nil {
+		signatures = v.bundle.Images[image]
+	} else {
+		if digest == "" {
+			return "", VerificationStatusUnsigned, fmt.Errorf("cannot find signatures of image %q without its digest", image)
+		}
+		s, err := fetchImageSignatures(image, digest)
+		if err != nil {
+			klog.V(2).Infof("Unable to read signatures of image %q: %v", image, err)
+		}
+		signatures = s
+	}
+	if len(signatures) == 0 {
+		return "", VerificationStatusUnsigned, fmt.Errorf("no signature found for image %q", image)
+	}
+
+	var errs []string
+	for _, signature := range signatures {
+		payload, err := base64.StdEncoding.DecodeString(signature.Payload)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("error decoding payload: %v", err))
+			continue
+		}
+		signedDigest, err := verifyImageSignature(v.publicKey, payload, signature.Signature, repository)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if digest != "" && signedDigest != digest {
+			errs = append(errs, fmt.Sprintf("signature is for digest %q, not %q", signedDigest, digest))
+			continue
+		}
+		return signedDigest, VerificationStatusVerified, nil
+	}
+
+	return "", VerificationStatusInvalid, fmt.Errorf("no valid signature found for image %q: %s", image, strings.Join(errs, "; "))
+}
+
+// verifyImageSignature checks the signature of a simple signing payload made for the image repository,
+// and returns the image digest it signs
+func verifyImageSignature(publicKey crypto.PublicKey, payload []byte, signature string, repository string) (string, error) {
+	hash := sha256.Sum256(payload)
+	if err := VerifySignature(publicKey, hash[:], signature); err != nil {
+		return "", err
+	}
+
+	p := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return "", fmt.Errorf("error parsing payload: %w", err)
+	}
+	if p.Critical.Type != cosignSignatureType {
+		return "", fmt.Errorf("unexpected payload type %q", p.Critical.Type)
+	}
+	// A signature of the same digest in another repository must not vouch for this one
+	signedRef, err := name.ParseReference(p.Critical.Identity.DockerReference)
+	if err != nil {
+		return "", fmt.Errorf("unexpected docker reference %q", p.Critical.Identity.DockerReference)
+	}
+	if signedRef.Context().Name() != repository {
+		return "", fmt.Errorf("signature is for image %q, not %q", p.Critical.Identity.DockerReference, repository)
+	}
+	if !strings.HasPrefix(p.Critical.Image.DockerManifestDigest, "sha256:") {
+		return "", fmt.Errorf("unexpected image digest %q", p.Critical.Image.DockerManifestDigest)
+	}
+
+	return p.Critical.Image.DockerManifestDigest, nil
+}
+
+// fetchImageSignatures reads the cosign signatures stored in the sha256-<digest>.sig tag next to the image
+func fetchImageSignatures(image string, digest string) ([]ImageSignature, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %q: %w", image, err)
+	}
+	tag := ref.Context().Tag(strings.Replace(digest, ":", "-", 1) + ".sig")
+
+	img, err := remote.Image(tag, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("error reading signature image %q: %w", tag, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest of signature image %q: %w", tag, err)
+	}
+
+	var signatures []ImageSignature
+	for _, layer := range manifest.Layers {
+		signature := layer.Annotations[cosignSignatureAnnotation]
+		if signature == "" {
+			continue
+		}
+		l, err := img.LayerByDigest(layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("error reading signature layer %q: %w", layer.Digest, err)
+		}
+		rc, err := l.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("error reading signature layer %q: %w", layer.Digest, err)
+		}
+		payload, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading signature layer %q: %w", layer.Digest, err)
+		}
+		signatures = append(signatures, ImageSignature{
+			Payload:   base64.StdEncoding.EncodeToString(payload),
+			Signature: signature,
+		})
+	}
+
+	return signatures, nil
+}
+
+// VerifySignature checks a base64 encoded signature of a SHA-256 digest, as made by cosign with an ECDSA or RSA key.
+func VerifySignature(publicKey crypto.PublicKey, digest []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %w", err)
+	}
+
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest, sig) {
+			return fmt.Errorf("signature does not match the public key")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, sig); err != nil {
+			return fmt.Errorf("signature does not match the public key")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// VerifyFileSignature checks the signature of a file asset with the PEM encoded public key,
+// given the hex encoded SHA-256 hash of the file.
+func VerifyFileSignature(publicKeyPEM string, sha256Hex string, signature string) error {
+	publicKey, err := pki.ParsePEMPublicKey([]byte(publicKeyPEM))
+	if err != nil {
+		return fmt.Errorf("error parsing public key: %w", err)
+	}
+	digest, err := hex.DecodeString(sha256Hex)
+	if err != nil || len(digest) != sha256.Size {
+		return fmt.Errorf("invalid SHA-256 hash %q", sha256Hex)
+	}
+	return VerifySignature(publicKey.Key, digest, signature)
+}
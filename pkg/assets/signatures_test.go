@@ -0,0 +1,326 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+func publicKeyPEM(t *testing.T, publicKey crypto.PublicKey) string {
+	b, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("error marshaling public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func signDigest(t *testing.T, signer crypto.Signer, digest []byte) string {
+	sig, err := signer.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		t.Fatalf("error signing: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func writeMemfsFile(t *testing.T, vfsContext *vfs.VFSContext, location string, data []byte) {
+	p, err := vfsContext.BuildVfsPath(location)
+	if err != nil {
+		t.Fatalf("error building path %q: %v", location, err)
+	}
+	if err := p.WriteFile(context.TODO(), bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing %q: %v", location, err)
+	}
+}
+
+func TestVerifyFileSignature(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte("kubelet"))
+	otherDigest := sha256.Sum256([]byte("not kubelet"))
+
+	grid := []struct {
+		name      string
+		signer    crypto.Signer
+		publicKey crypto.PublicKey
+		digest    []byte
+		expectErr bool
+	}{
+		{
+			name:      "ecdsa",
+			signer:    ecdsaKey,
+			publicKey: ecdsaKey.Public(),
+			digest:    digest[:],
+		},
+		{
+			name:      "rsa",
+			signer:    rsaKey,
+			publicKey: rsaKey.Public(),
+			digest:    digest[:],
+		},
+		{
+			name:      "other file",
+			signer:    ecdsaKey,
+			publicKey: ecdsaKey.Public(),
+			digest:    otherDigest[:],
+			expectErr: true,
+		},
+		{
+			name:      "other key",
+			signer:    ecdsaKey,
+			publicKey: rsaKey.Public(),
+			digest:    digest[:],
+			expectErr: true,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			signature := signDigest(t, g.signer, g.digest)
+			err := VerifyFileSignature(publicKeyPEM(t, g.publicKey), hex.EncodeToString(digest[:]), signature)
+			if g.expectErr && err == nil {
+				t.Errorf("expected error verifying signature")
+			}
+			if !g.expectErr && err != nil {
+				t.Errorf("unexpected error verifying signature: %v", err)
+			}
+		})
+	}
+}
+
+func TestRemapFile_Verification(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+
+	signed := sha256.Sum256([]byte("kubelet"))
+	unsigned := sha256.Sum256([]byte("containerd"))
+	writeMemfsFile(t, vfsContext, "memfs://assets/kubelet.sig", []byte(signDigest(t, key, signed[:])))
+	writeMemfsFile(t, vfsContext, "memfs://assets/containerd.sig", []byte(signDigest(t, key, signed[:])))
+
+	grid := []struct {
+		name         string
+		file         string
+		hash         []byte
+		getAssets    bool
+		expectErr    bool
+		verification VerificationStatus
+	}{
+		{
+			name:         "signed",
+			file:         "memfs://assets/kubelet",
+			hash:         signed[:],
+			verification: VerificationStatusVerified,
+		},
+		{
+			name:         "invalid signature",
+			file:         "memfs://assets/containerd",
+			hash:         unsigned[:],
+			expectErr:    true,
+			verification: VerificationStatusInvalid,
+		},
+		{
+			name:         "unsigned",
+			file:         "memfs://assets/runc",
+			hash:         unsigned[:],
+			expectErr:    true,
+			verification: VerificationStatusUnsigned,
+		},
+		{
+			name:         "unsigned when getting assets",
+			file:         "memfs://assets/runc",
+			hash:         unsigned[:],
+			getAssets:    true,
+			verification: VerificationStatusUnsigned,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			builder := &AssetBuilder{
+				vfsContext: vfsContext,
+				AssetsLocation: &kops.AssetsSpec{
+					Verification: &kops.AssetVerificationSpec{
+						PublicKey: publicKeyPEM(t, key.Public()),
+						Files:     true,
+					},
+				},
+				GetAssets: g.getAssets,
+			}
+
+			u, err := url.Parse(g.file)
+			if err != nil {
+				t.Fatalf("error parsing url: %v", err)
+			}
+			hash := &hashing.Hash{Algorithm: hashing.HashAlgorithmSHA256, HashValue: g.hash}
+
+			_, err = builder.RemapFile(u, hash)
+			if g.expectErr && err == nil {
+				t.Errorf("expected error remapping file")
+			}
+			if !g.expectErr && err != nil {
+				t.Errorf("unexpected error remapping file: %v", err)
+			}
+			if !g.expectErr {
+				asset := builder.FileAssets[0]
+				if asset.Verification != g.verification {
+					t.Errorf("expected verification %q, got %q", g.verification, asset.Verification)
+				}
+				if g.verification == VerificationStatusVerified && asset.Signature == "" {
+					t.Errorf("expected signature to be recorded")
+				}
+			}
+		})
+	}
+}
+
+func TestRemapImage_VerificationBundle(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	digest := "sha256:" + hex.EncodeToString(bytes.Repeat([]byte{1}, sha256.Size))
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.k8s.io/pause"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+	payloadHash := sha256.Sum256(payload)
+
+	bundle := &SignatureBundle{
+		Images: map[string][]ImageSignature{
+			"registry.k8s.io/pause:3.9": {
+				{
+					Payload:   base64.StdEncoding.EncodeToString(payload),
+					Signature: signDigest(t, key, payloadHash[:]),
+				},
+			},
+			// A valid signature, made for another repository
+			"registry.k8s.io/etcd:3.5.13": {
+				{
+					Payload:   base64.StdEncoding.EncodeToString(payload),
+					Signature: signDigest(t, key, payloadHash[:]),
+				},
+			},
+			"registry.k8s.io/kube-proxy:v1.30.0": {
+				{
+					Payload:   base64.StdEncoding.EncodeToString(payload),
+					Signature: signDigest(t, key, []byte("not the payload hash, but 32 bytes")[:sha256.Size]),
+				},
+			},
+		},
+	}
+	bundleYAML, err := yaml.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("error marshaling bundle: %v", err)
+	}
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	writeMemfsFile(t, vfsContext, "memfs://assets/bundle.yaml", bundleYAML)
+
+	mirror := "mirror.example.com"
+
+	grid := []struct {
+		image        string
+		expected     string
+		expectErr    bool
+		verification VerificationStatus
+	}{
+		{
+			image:        "registry.k8s.io/pause:3.9",
+			expected:     "mirror.example.com/pause:3.9@" + digest,
+			verification: VerificationStatusVerified,
+		},
+		{
+			image:        "registry.k8s.io/pause:3.9@" + digest,
+			expected:     "mirror.example.com/pause:3.9@" + digest,
+			verification: VerificationStatusVerified,
+		},
+		{
+			image:        "registry.k8s.io/etcd:3.5.13",
+			expectErr:    true,
+			verification: VerificationStatusInvalid,
+		},
+		{
+			image:        "registry.k8s.io/kube-proxy:v1.30.0",
+			expectErr:    true,
+			verification: VerificationStatusInvalid,
+		},
+		{
+			image:        "registry.k8s.io/coredns/coredns:v1.11.1",
+			expectErr:    true,
+			verification: VerificationStatusUnsigned,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.image, func(t *testing.T) {
+			builder := &AssetBuilder{
+				vfsContext: vfsContext,
+				AssetsLocation: &kops.AssetsSpec{
+					ContainerRegistry: &mirror,
+					Verification: &kops.AssetVerificationSpec{
+						PublicKey: publicKeyPEM(t, key.Public()),
+						Bundle:    "memfs://assets/bundle.yaml",
+						Images:    true,
+					},
+				},
+			}
+
+			remapped, err := builder.RemapImage(g.image)
+			if g.expectErr && err == nil {
+				t.Errorf("expected error remapping image")
+			}
+			if !g.expectErr && err != nil {
+				t.Errorf("unexpected error remapping image: %v", err)
+			}
+			if remapped != g.expected {
+				t.Errorf("expected %q, got %q", g.expected, remapped)
+			}
+			if builder.ImageAssets[0].Verification != g.verification {
+				t.Errorf("expected verification %q, got %q", g.verification, builder.ImageAssets[0].Verification)
+			}
+		})
+	}
+}
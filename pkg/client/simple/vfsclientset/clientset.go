@@ -17,9 +17,12 @@ limitations under the License.
 package vfsclientset
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -154,7 +157,7 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		}
 
 		// "cluster.spec" was written by kOps 1.21 and earlier.
-		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated {
+		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated || relativePath == registry.PathDeletionManifest || relativePath == registry.PathUpgradeProgress {
 			continue
 		}
 		if strings.HasPrefix(relativePath, "addons/") {
@@ -178,6 +181,9 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
+		if strings.HasPrefix(relativePath, registry.PathDeletionHistory+"/") {
+			continue
+		}
 		// TODO: offer an option _not_ to delete backups?
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
@@ -186,11 +192,26 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		return fmt.Errorf("refusing to delete: unknown file found: %s", path)
 	}
 
+	// The deletion manifest is the record of what happened to the cloud resources, so it outlives the cluster
+	manifestPath := basePath.Join(registry.PathDeletionManifest)
+	manifest, err := manifestPath.ReadFile(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading deletion manifest %s: %w", manifestPath, err)
+	}
+
 	err = basePath.RemoveAll(ctx)
 	if err != nil {
 		return fmt.Errorf("error deleting cluster files in %s: %w", basePath, err)
 	}
 
+	if manifest != nil {
+		historyPath := basePath.Join(registry.PathDeletionHistory, "deletion-"+time.Now().UTC().Format("20060102T150405Z")+".yaml")
+		if err := historyPath.WriteFile(ctx, bytes.NewReader(manifest), nil); err != nil {
+			return fmt.Errorf("error keeping deletion manifest in %s: %w", historyPath, err)
+		}
+		klog.Infof("Kept the deletion manifest in %s", historyPath)
+	}
+
 	return nil
 }
 
@@ -17,9 +17,14 @@ limitations under the License.
 package vfsclientset
 
 import (
+	"bytes"
+	"context"
+	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -78,3 +83,73 @@ func TestSSHCredentialStoreOnOwnCFS(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
+
+func TestDeleteAllClusterStateKeepsDeletionManifest(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://state")
+	if err != nil {
+		t.Fatalf("error building state store path: %v", err)
+	}
+	configBase := basePath.Join("example.com")
+	manifest := []byte("clusterName: example.com\n")
+	for path, contents := range map[string][]byte{
+		registry.PathCluster:          []byte("apiVersion: kops.k8s.io/v1alpha2\n"),
+		registry.PathDeletionManifest: manifest,
+		"pki/ca.crt":                  []byte("cert"),
+	} {
+		if err := configBase.Join(path).WriteFile(ctx, bytes.NewReader(contents), nil); err != nil {
+			t.Fatalf("error writing %s: %v", path, err)
+		}
+	}
+
+	if err := DeleteAllClusterState(ctx, configBase); err != nil {
+		t.Fatalf("DeleteAllClusterState failed: %v", err)
+	}
+
+	tree, err := configBase.ReadTree(ctx)
+	if err != nil {
+		t.Fatalf("error listing state store: %v", err)
+	}
+	// memfs lists removed files, so only keep the ones with contents
+	var paths []vfs.Path
+	for _, p := range tree {
+		if _, err := p.ReadFile(ctx); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) != 1 {
+		t.Fatalf("expected only the deletion manifest to be left, got %v", paths)
+	}
+	relativePath, err := vfs.RelativePath(configBase, paths[0])
+	if err != nil {
+		t.Fatalf("error getting relative path: %v", err)
+	}
+	if !strings.HasPrefix(relativePath, registry.PathDeletionHistory+"/deletion-") || !strings.HasSuffix(relativePath, ".yaml") {
+		t.Errorf("unexpected deletion manifest path %q", relativePath)
+	}
+	b, err := paths[0].ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading deletion manifest: %v", err)
+	}
+	if !bytes.Equal(b, manifest) {
+		t.Errorf("expected deletion manifest %q, got %q", manifest, b)
+	}
+
+	clusters, err := NewVFSClientset(vfs.Context, basePath).ListClusters(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("error listing clusters: %v", err)
+	}
+	if len(clusters.Items) != 0 {
+		t.Errorf("expected the deleted cluster not to be listed, got %d clusters", len(clusters.Items))
+	}
+
+	// A cluster recreated with the same name can be deleted again
+	if err := configBase.Join(registry.PathCluster).WriteFile(ctx, bytes.NewReader([]byte("apiVersion: kops.k8s.io/v1alpha2\n")), nil); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+	if err := DeleteAllClusterState(ctx, configBase); err != nil {
+		t.Errorf("DeleteAllClusterState failed for a recreated cluster: %v", err)
+	}
+}
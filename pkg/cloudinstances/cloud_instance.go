@@ -16,7 +16,11 @@ limitations under the License.
 
 package cloudinstances
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
 
 // CloudInstanceStatusDetached means the instance needs update and has been detached.
 const CloudInstanceStatusDetached = "Detached"
@@ -27,6 +31,24 @@ const CloudInstanceStatusNeedsUpdate = "NeedsUpdate"
 // CloudInstanceStatusReady means the instance has joined the cluster, is not detached, and is up to date.
 const CloudInstanceStatusUpToDate = "UpToDate"
 
+// Lifecycle is the purchasing option of an instance.
+type Lifecycle string
+
+// LifecycleOnDemand means the instance is an on-demand instance.
+const LifecycleOnDemand Lifecycle = "OnDemand"
+
+// LifecycleSpot means the instance is a spot (or preemptible) instance, which the cloud provider may interrupt.
+const LifecycleSpot Lifecycle = "Spot"
+
+// interruptionTaints are the taints the AWS Node Termination Handler places on nodes that have received a notice,
+// keyed by the taint and valued by the notice.
+var interruptionTaints = map[string]string{
+	"aws-node-termination-handler/spot-itn":                  "SpotInterruption",
+	"aws-node-termination-handler/rebalance-recommendation":  "RebalanceRecommendation",
+	"aws-node-termination-handler/scheduled-maintenance":     "ScheduledMaintenance",
+	"aws-node-termination-handler/asg-lifecycle-termination": "ASGTermination",
+}
+
 type State string
 
 // WarmPool means the instance is in the warm pool
@@ -52,4 +74,22 @@ type CloudInstance struct {
 	ExternalIP string
 	// State indicates if the instance has joined the cluster and if it needs any updates.
 	State State
+	// Lifecycle is the purchasing option of the instance, if known.
+	Lifecycle Lifecycle
+	// LaunchTime is when the cloud provider launched the instance, if known.
+	LaunchTime time.Time
+}
+
+// InterruptionNotice returns the notice of interruption or rebalance recommendation the node of the instance
+// has received, or the empty string if it has received none.
+func (c *CloudInstance) InterruptionNotice() string {
+	if c.Node == nil {
+		return ""
+	}
+	for _, taint := range c.Node.Spec.Taints {
+		if notice, found := interruptionTaints[taint.Key]; found {
+			return notice
+		}
+	}
+	return ""
 }
@@ -19,6 +19,7 @@ package cloudinstances
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -74,7 +75,25 @@ func (c *CloudInstanceGroup) Status() string {
 	return "NeedsUpdate"
 }
 
+// NoticedSpotInstances returns the up to date spot instances whose node has received an interruption notice
+// or rebalance recommendation, which a rolling update replaces too.
+func (c *CloudInstanceGroup) NoticedSpotInstances() []*CloudInstance {
+	var noticed []*CloudInstance
+	for _, instance := range c.Ready {
+		if instance.Lifecycle == LifecycleSpot && instance.InterruptionNotice() != "" {
+			noticed = append(noticed, instance)
+		}
+	}
+	return noticed
+}
+
+// AdjustNeedUpdate moves the instances that need updating for reasons unknown to the cloud implementation,
+// such as having been annotated or having exceeded the maximum instance lifetime, from Ready to NeedUpdate.
 func (group *CloudInstanceGroup) AdjustNeedUpdate() {
+	group.adjustNeedUpdate(time.Now())
+}
+
+func (group *CloudInstanceGroup) adjustNeedUpdate(now time.Time) {
 	if group.Ready != nil {
 		var newReady []*CloudInstance
 		for _, member := range group.Ready {
@@ -84,6 +103,10 @@ func (group *CloudInstanceGroup) AdjustNeedUpdate() {
 					makeNotReady = true
 				}
 			}
+			if !makeNotReady && group.exceedsMaxInstanceLifetime(member, now) {
+				klog.V(2).Infof("instance %q is older than the maximum instance lifetime of group %q", member.ID, group.HumanName)
+				makeNotReady = true
+			}
 
 			if makeNotReady {
 				group.NeedUpdate = append(group.NeedUpdate, member)
@@ -96,6 +119,27 @@ func (group *CloudInstanceGroup) AdjustNeedUpdate() {
 	}
 }
 
+// exceedsMaxInstanceLifetime returns true if the instance was launched longer ago than
+// the maximum instance lifetime of the instance group. The launch time reported by the cloud provider is used,
+// falling back to the registration of the node of the instance when it is unknown.
+func (group *CloudInstanceGroup) exceedsMaxInstanceLifetime(member *CloudInstance, now time.Time) bool {
+	if group.InstanceGroup == nil || group.InstanceGroup.Spec.MaxInstanceLifetime == nil {
+		return false
+	}
+	maxLifetime := group.InstanceGroup.Spec.MaxInstanceLifetime.Duration
+	if maxLifetime <= 0 {
+		return false
+	}
+	launched := member.LaunchTime
+	if launched.IsZero() && member.Node != nil {
+		launched = member.Node.CreationTimestamp.Time
+	}
+	if launched.IsZero() {
+		return false
+	}
+	return now.Sub(launched) > maxLifetime
+}
+
 // GetNodeMap returns a list of nodes keyed by their external id
 func GetNodeMap(nodes []v1.Node, cluster *kopsapi.Cluster) map[string]*v1.Node {
 	nodeMap := make(map[string]*v1.Node)
@@ -19,6 +19,11 @@ package cloudinstances
 import (
 	"fmt"
 	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
 )
 
 func TestToAzureVMName(t *testing.T) {
@@ -55,3 +60,90 @@ func TestToAzureVMName(t *testing.T) {
 		})
 	}
 }
+
+func TestAdjustNeedUpdateMaxInstanceLifetime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	node := func(age time.Duration) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+
+	testCases := []struct {
+		name           string
+		lifetime       *metav1.Duration
+		nodes          map[string]*v1.Node
+		launched       map[string]time.Duration
+		expectedUpdate []string
+	}{
+		{
+			name:  "unset",
+			nodes: map[string]*v1.Node{"a": node(1000 * time.Hour)},
+		},
+		{
+			name:     "zero",
+			lifetime: &metav1.Duration{},
+			nodes:    map[string]*v1.Node{"a": node(1000 * time.Hour)},
+		},
+		{
+			name:           "older",
+			lifetime:       &metav1.Duration{Duration: 720 * time.Hour},
+			nodes:          map[string]*v1.Node{"a": node(721 * time.Hour), "b": node(719 * time.Hour)},
+			expectedUpdate: []string{"a"},
+		},
+		{
+			name:           "launch time",
+			lifetime:       &metav1.Duration{Duration: 720 * time.Hour},
+			nodes:          map[string]*v1.Node{"a": node(1 * time.Hour), "b": node(721 * time.Hour)},
+			launched:       map[string]time.Duration{"a": 721 * time.Hour, "b": 719 * time.Hour},
+			expectedUpdate: []string{"a"},
+		},
+		{
+			name:           "launch time without node",
+			lifetime:       &metav1.Duration{Duration: time.Hour},
+			nodes:          map[string]*v1.Node{"a": nil},
+			launched:       map[string]time.Duration{"a": 2 * time.Hour},
+			expectedUpdate: []string{"a"},
+		},
+		{
+			name:     "no node",
+			lifetime: &metav1.Duration{Duration: time.Hour},
+			nodes:    map[string]*v1.Node{"a": nil},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group := &CloudInstanceGroup{
+				HumanName: "nodes",
+				InstanceGroup: &kopsapi.InstanceGroup{
+					Spec: kopsapi.InstanceGroupSpec{MaxInstanceLifetime: tc.lifetime},
+				},
+			}
+			for _, id := range []string{"a", "b"} {
+				if n, ok := tc.nodes[id]; ok {
+					instance, err := group.NewCloudInstance(id, CloudInstanceStatusUpToDate, n)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if age, ok := tc.launched[id]; ok {
+						instance.LaunchTime = now.Add(-age)
+					}
+				}
+			}
+
+			group.adjustNeedUpdate(now)
+
+			var actual []string
+			for _, member := range group.NeedUpdate {
+				if member.Status != CloudInstanceStatusNeedsUpdate {
+					t.Errorf("instance %q has status %q", member.ID, member.Status)
+				}
+				actual = append(actual, member.ID)
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(tc.expectedUpdate) {
+				t.Errorf("expected %v to need update, but got %v", tc.expectedUpdate, actual)
+			}
+			if len(group.Ready)+len(group.NeedUpdate) != len(tc.nodes) {
+				t.Errorf("expected %d instances, but got %d", len(tc.nodes), len(group.Ready)+len(group.NeedUpdate))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Catalog holds the prices used to estimate the cost of a cluster.
+// It is read from a file, so that it can be refreshed offline from the cloud provider's price list.
+type Catalog struct {
+	// Currency is the currency of all the prices, defaulting to USD.
+	Currency string `json:"currency,omitempty"`
+	// Regions holds the prices for each region.
+	Regions map[string]*RegionPrices `json:"regions"`
+}
+
+// RegionPrices holds the prices for a single region.
+type RegionPrices struct {
+	// Instances holds the hourly on-demand price of each machine type.
+	Instances map[string]float64 `json:"instances,omitempty"`
+	// SpotInstances holds the expected hourly spot price of each machine type.
+	SpotInstances map[string]float64 `json:"spotInstances,omitempty"`
+	// Volumes holds the monthly price per GB of each volume type.
+	Volumes map[string]float64 `json:"volumes,omitempty"`
+	// LoadBalancers holds the hourly price of each type of load balancer, i.e. "network" or "classic".
+	LoadBalancers map[string]float64 `json:"loadBalancers,omitempty"`
+	// NATGateway is the hourly price of a NAT gateway.
+	NATGateway *float64 `json:"natGateway,omitempty"`
+}
+
+// LoadCatalog reads the price catalog from the file at path.
+func LoadCatalog(path string) (*Catalog, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading price catalog %q: %w", path, err)
+	}
+	return ParseCatalog(b)
+}
+
+// ParseCatalog parses a price catalog in YAML or JSON format.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	catalog := &Catalog{}
+	if err := yaml.UnmarshalStrict(data, catalog); err != nil {
+		return nil, fmt.Errorf("parsing price catalog: %w", err)
+	}
+	if catalog.Currency == "" {
+		catalog.Currency = "USD"
+	}
+	return catalog, nil
+}
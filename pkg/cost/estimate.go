@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+// HoursPerMonth is the number of hours in an average month, as used in cloud provider pricing.
+const HoursPerMonth = 730
+
+const (
+	KindInstanceGroup = "instance-group"
+	KindVolume        = "volume"
+	KindLoadBalancer  = "load-balancer"
+	KindNATGateway    = "nat-gateway"
+)
+
+// Item is the estimated cost of a single cloud resource, or group of instances.
+type Item struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Count       int32  `json:"count"`
+	// MonthlyCost is the estimated monthly cost, excluding any prices missing from the catalog.
+	MonthlyCost float64 `json:"monthlyCost"`
+	// Missing lists the prices that were not found in the catalog.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Estimate is the estimated cost of a cluster.
+type Estimate struct {
+	Currency string  `json:"currency"`
+	Items    []*Item `json:"items"`
+}
+
+// Total returns the estimated monthly cost of all the items.
+func (e *Estimate) Total() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.MonthlyCost
+	}
+	return total
+}
+
+// Missing returns the prices that were not found in the catalog, so that the total is an underestimate.
+func (e *Estimate) Missing() []string {
+	seen := make(map[string]bool)
+	var missing []string
+	for _, item := range e.Items {
+		for _, m := range item.Missing {
+			if !seen[m] {
+				seen[m] = true
+				missing = append(missing, m)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// EstimateTasks estimates the monthly cost of the cloud resources produced by the tasks.
+// If state is not nil, it maps each task to the state to estimate instead, returning nil for resources that do not exist;
+// this is used to estimate the current cost of the cluster, from the actual state found by a dry-run.
+// Instances are priced at the minimum size of their group, split between on-demand and spot instances as the group would be.
+func EstimateTasks(catalog *Catalog, region string, tasks map[string]fi.CloudupTask, state func(fi.CloudupTask) fi.CloudupTask) (*Estimate, error) {
+	prices := catalog.Regions[region]
+	if prices == nil {
+		return nil, fmt.Errorf("price catalog has no prices for region %q", region)
+	}
+	if state == nil {
+		state = func(task fi.CloudupTask) fi.CloudupTask { return task }
+	}
+
+	estimate := &Estimate{Currency: catalog.Currency}
+	for _, task := range tasks {
+		var item *Item
+		switch e := task.(type) {
+		case *awstasks.AutoscalingGroup:
+			if a, ok := state(e).(*awstasks.AutoscalingGroup); ok && a != nil {
+				lt := e.LaunchTemplate
+				if e.LaunchTemplate != nil {
+					if a, ok := state(e.LaunchTemplate).(*awstasks.LaunchTemplate); ok && a != nil {
+						lt = a
+					}
+				}
+				item = prices.instanceGroup(a, lt)
+			}
+		case *awstasks.EBSVolume:
+			if a, ok := state(e).(*awstasks.EBSVolume); ok && a != nil {
+				item = prices.volume(a)
+			}
+		case *awstasks.NetworkLoadBalancer:
+			if a, ok := state(e).(*awstasks.NetworkLoadBalancer); ok && a != nil {
+				item = prices.loadBalancer(fi.ValueOf(e.Name), "network")
+			}
+		case *awstasks.ClassicLoadBalancer:
+			if a, ok := state(e).(*awstasks.ClassicLoadBalancer); ok && a != nil {
+				item = prices.loadBalancer(fi.ValueOf(e.Name), "classic")
+			}
+		case *awstasks.NatGateway:
+			if fi.ValueOf(e.Shared) {
+				continue
+			}
+			if a, ok := state(e).(*awstasks.NatGateway); ok && a != nil {
+				item = prices.natGateway(fi.ValueOf(e.Name))
+			}
+		}
+		if item != nil {
+			estimate.Items = append(estimate.Items, item)
+		}
+	}
+
+	estimate.sortItems()
+	return estimate, nil
+}
+
+// EstimateDeletions estimates the monthly cost of the cloud resources removed by the deletions found by a dry-run.
+// Only the load balancers are priced; the other resources kOps deletes, such as launch templates, have no price.
+func EstimateDeletions(catalog *Catalog, region string, deletions []fi.CloudupDeletion) (*Estimate, error) {
+	prices := catalog.Regions[region]
+	if prices == nil {
+		return nil, fmt.Errorf("price catalog has no prices for region %q", region)
+	}
+
+	estimate := &Estimate{Currency: catalog.Currency}
+	for _, deletion := range deletions {
+		switch deletion.TaskName() {
+		case "ClassicLoadBalancer":
+			estimate.Items = append(estimate.Items, prices.loadBalancer(deletion.Item(), "classic"))
+		case "network-load-balancer":
+			estimate.Items = append(estimate.Items, prices.loadBalancer(deletion.Item(), "network"))
+		}
+	}
+
+	estimate.sortItems()
+	return estimate, nil
+}
+
+func (e *Estimate) sortItems() {
+	sort.Slice(e.Items, func(i, j int) bool {
+		if e.Items[i].Kind != e.Items[j].Kind {
+			return e.Items[i].Kind < e.Items[j].Kind
+		}
+		return e.Items[i].Name < e.Items[j].Name
+	})
+}
+
+func (p *RegionPrices) instanceGroup(asg *awstasks.AutoscalingGroup, lt *awstasks.LaunchTemplate) *Item {
+	item := &Item{
+		Kind:  KindInstanceGroup,
+		Name:  fi.ValueOf(asg.Name),
+		Count: fi.ValueOf(asg.MinSize),
+	}
+
+	var machineType string
+	if lt != nil && lt.InstanceType != nil {
+		machineType = string(*lt.InstanceType)
+	} else if len(asg.MixedInstanceOverrides) != 0 {
+		machineType = asg.MixedInstanceOverrides[0]
+	}
+	onDemand, spot := splitInstances(asg, lt, item.Count)
+
+	var descriptions []string
+	if machineType != "" {
+		descriptions = append(descriptions, machineType)
+		if onDemand != 0 {
+			if hourly, found := p.Instances[machineType]; found {
+				item.MonthlyCost += hourly * HoursPerMonth * float64(onDemand)
+			} else {
+				item.Missing = append(item.Missing, "instance "+machineType)
+			}
+		}
+		if spot != 0 {
+			descriptions = append(descriptions, fmt.Sprintf("%d on-demand, %d spot", onDemand, spot))
+			if hourly, found := p.SpotInstances[machineType]; found {
+				item.MonthlyCost += hourly * HoursPerMonth * float64(spot)
+			} else {
+				item.Missing = append(item.Missing, "spot instance "+machineType)
+			}
+		}
+	}
+
+	if lt != nil && lt.RootVolumeSize != nil {
+		volumeType := string(lt.RootVolumeType)
+		descriptions = append(descriptions, fmt.Sprintf("%dGB %s root volume", *lt.RootVolumeSize, volumeType))
+		if perGB, found := p.Volumes[volumeType]; found {
+			item.MonthlyCost += perGB * float64(*lt.RootVolumeSize) * float64(item.Count)
+		} else {
+			item.Missing = append(item.Missing, "volume "+volumeType)
+		}
+	}
+
+	item.Description = strings.Join(descriptions, ", ")
+	return item
+}
+
+// splitInstances returns how many of the count instances of the group are on-demand and how many are spot instances.
+// Groups with a mixed instances policy launch the on-demand base first, then the on-demand percentage of the rest,
+// rounded up as AWS does; other groups are all spot instances if their launch template sets a spot price.
+func splitInstances(asg *awstasks.AutoscalingGroup, lt *awstasks.LaunchTemplate, count int32) (onDemand int32, spot int32) {
+	if asg.MixedOnDemandBase == nil && asg.MixedOnDemandAboveBase == nil && len(asg.MixedInstanceOverrides) == 0 {
+		if lt != nil && fi.ValueOf(lt.SpotPrice) != "" {
+			return 0, count
+		}
+		return count, 0
+	}
+
+	base := min(fi.ValueOf(asg.MixedOnDemandBase), count)
+	aboveBase := int32(100)
+	if asg.MixedOnDemandAboveBase != nil {
+		aboveBase = *asg.MixedOnDemandAboveBase
+	}
+	onDemand = base + (int32(math.Ceil(float64(count-base) * float64(aboveBase) / 100)))
+	return onDemand, count - onDemand
+}
+
+func (p *RegionPrices) volume(v *awstasks.EBSVolume) *Item {
+	volumeType := string(v.VolumeType)
+	item := &Item{
+		Kind:        KindVolume,
+		Name:        fi.ValueOf(v.Name),
+		Description: fmt.Sprintf("%dGB %s", fi.ValueOf(v.SizeGB), volumeType),
+		Count:       1,
+	}
+	if perGB, found := p.Volumes[volumeType]; found {
+		item.MonthlyCost = perGB * float64(fi.ValueOf(v.SizeGB))
+	} else {
+		item.Missing = append(item.Missing, "volume "+volumeType)
+	}
+	return item
+}
+
+func (p *RegionPrices) loadBalancer(name string, lbType string) *Item {
+	item := &Item{
+		Kind:        KindLoadBalancer,
+		Name:        name,
+		Description: lbType,
+		Count:       1,
+	}
+	if hourly, found := p.LoadBalancers[lbType]; found {
+		item.MonthlyCost = hourly * HoursPerMonth
+	} else {
+		item.Missing = append(item.Missing, "load balancer "+lbType)
+	}
+	return item
+}
+
+func (p *RegionPrices) natGateway(name string) *Item {
+	item := &Item{
+		Kind:  KindNATGateway,
+		Name:  name,
+		Count: 1,
+	}
+	if p.NATGateway != nil {
+		item.MonthlyCost = *p.NATGateway * HoursPerMonth
+	} else {
+		item.Missing = append(item.Missing, "NAT gateway")
+	}
+	return item
+}
+
+// FormatAmount formats an amount of the currency for display, e.g. "$4,200.00".
+func FormatAmount(currency string, amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	s := fmt.Sprintf("%.2f", amount)
+	whole, fraction := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, r := range whole {
+		if i != 0 && (len(whole)-i)%3 == 0 {
+			b.WriteRune(',')
+		}
+		b.WriteRune(r)
+	}
+	if currency == "USD" {
+		return sign + "$" + b.String() + fraction
+	}
+	return sign + b.String() + fraction + " " + currency
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+const testCatalog = `
+regions:
+  us-test-1:
+    instances:
+      m5.large: 0.1
+    spotInstances:
+      m5.large: 0.04
+    volumes:
+      gp3: 0.08
+    loadBalancers:
+      network: 0.02
+    natGateway: 0.05
+`
+
+func TestEstimateTasks(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+	if catalog.Currency != "USD" {
+		t.Errorf("expected default currency USD, got %q", catalog.Currency)
+	}
+
+	lt := &awstasks.LaunchTemplate{
+		Name:           fi.PtrTo("nodes.example.com"),
+		InstanceType:   fi.PtrTo(ec2types.InstanceType("m5.large")),
+		RootVolumeSize: fi.PtrTo(int32(100)),
+		RootVolumeType: ec2types.VolumeTypeGp3,
+	}
+	asg := &awstasks.AutoscalingGroup{
+		Name:           fi.PtrTo("nodes.example.com"),
+		MinSize:        fi.PtrTo(int32(3)),
+		LaunchTemplate: lt,
+	}
+	volume := &awstasks.EBSVolume{
+		Name:       fi.PtrTo("a.etcd-main.example.com"),
+		SizeGB:     fi.PtrTo(int32(20)),
+		VolumeType: ec2types.VolumeTypeGp3,
+	}
+	nlb := &awstasks.NetworkLoadBalancer{Name: fi.PtrTo("api.example.com")}
+	nat := &awstasks.NatGateway{Name: fi.PtrTo("us-test-1a.example.com")}
+	tasks := map[string]fi.CloudupTask{
+		"LaunchTemplate/nodes.example.com":   lt,
+		"AutoscalingGroup/nodes.example.com": asg,
+		"EBSVolume/a.etcd-main.example.com":  volume,
+		"NetworkLoadBalancer/api":            nlb,
+		"NatGateway/us-test-1a":              nat,
+	}
+
+	estimate, err := EstimateTasks(catalog, "us-test-1", tasks, nil)
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+	costs := make(map[string]float64)
+	for _, item := range estimate.Items {
+		costs[item.Kind+"/"+item.Name] = item.MonthlyCost
+	}
+	expected := map[string]float64{
+		"instance-group/nodes.example.com":   3 * (0.1*HoursPerMonth + 0.08*100),
+		"volume/a.etcd-main.example.com":     0.08 * 20,
+		"load-balancer/api.example.com":      0.02 * HoursPerMonth,
+		"nat-gateway/us-test-1a.example.com": 0.05 * HoursPerMonth,
+	}
+	if len(costs) != len(expected) {
+		t.Fatalf("expected items %v, got %v", expected, costs)
+	}
+	for k, v := range expected {
+		if math.Abs(costs[k]-v) > 0.001 {
+			t.Errorf("expected %s to cost %v, got %v", k, v, costs[k])
+		}
+	}
+	if missing := estimate.Missing(); len(missing) != 0 {
+		t.Errorf("expected no missing prices, got %v", missing)
+	}
+
+	// The current state has a smaller group, and no load balancer yet
+	current, err := EstimateTasks(catalog, "us-test-1", tasks, func(task fi.CloudupTask) fi.CloudupTask {
+		switch task {
+		case asg:
+			return &awstasks.AutoscalingGroup{Name: asg.Name, MinSize: fi.PtrTo(int32(1)), LaunchTemplate: lt}
+		case nlb:
+			return nil
+		}
+		return task
+	})
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+	change := estimate.Total() - current.Total()
+	expectedChange := 2*(0.1*HoursPerMonth+0.08*100) + 0.02*HoursPerMonth
+	if math.Abs(change-expectedChange) > 0.001 {
+		t.Errorf("expected change of %v, got %v", expectedChange, change)
+	}
+}
+
+func TestEstimateTasksSpotInstances(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+
+	grid := []struct {
+		Name             string
+		ASG              *awstasks.AutoscalingGroup
+		SpotPrice        string
+		NoLaunchTemplate bool
+		Expected         float64
+		ExpectedDesc     string
+		ExpectedMissing  []string
+	}{
+		{
+			Name: "mixed, on-demand base and half above it",
+			ASG: &awstasks.AutoscalingGroup{
+				MinSize:                fi.PtrTo(int32(5)),
+				MixedInstanceOverrides: []string{"m5.large", "m5a.large"},
+				MixedOnDemandBase:      fi.PtrTo(int32(1)),
+				MixedOnDemandAboveBase: fi.PtrTo(int32(50)),
+			},
+			Expected:     3*0.1*HoursPerMonth + 2*0.04*HoursPerMonth,
+			ExpectedDesc: "m5.large, 3 on-demand, 2 spot",
+		},
+		{
+			Name: "mixed, on-demand percentage rounded up",
+			ASG: &awstasks.AutoscalingGroup{
+				MinSize:                fi.PtrTo(int32(3)),
+				MixedInstanceOverrides: []string{"m5.large"},
+				MixedOnDemandAboveBase: fi.PtrTo(int32(10)),
+			},
+			Expected:     1*0.1*HoursPerMonth + 2*0.04*HoursPerMonth,
+			ExpectedDesc: "m5.large, 1 on-demand, 2 spot",
+		},
+		{
+			Name: "mixed, all spot",
+			ASG: &awstasks.AutoscalingGroup{
+				MinSize:                fi.PtrTo(int32(2)),
+				MixedInstanceOverrides: []string{"m5.large"},
+				MixedOnDemandAboveBase: fi.PtrTo(int32(0)),
+			},
+			Expected:     2 * 0.04 * HoursPerMonth,
+			ExpectedDesc: "m5.large, 0 on-demand, 2 spot",
+		},
+		{
+			Name: "mixed, on-demand by default",
+			ASG: &awstasks.AutoscalingGroup{
+				MinSize:                fi.PtrTo(int32(2)),
+				MixedInstanceOverrides: []string{"m5.large"},
+			},
+			Expected:     2 * 0.1 * HoursPerMonth,
+			ExpectedDesc: "m5.large",
+		},
+		{
+			Name:         "spot price in launch template",
+			ASG:          &awstasks.AutoscalingGroup{MinSize: fi.PtrTo(int32(2))},
+			SpotPrice:    "0.05",
+			Expected:     2 * 0.04 * HoursPerMonth,
+			ExpectedDesc: "m5.large, 0 on-demand, 2 spot",
+		},
+		{
+			Name: "missing prices",
+			ASG: &awstasks.AutoscalingGroup{
+				MinSize:                fi.PtrTo(int32(4)),
+				MixedInstanceOverrides: []string{"c5.large"},
+				MixedOnDemandBase:      fi.PtrTo(int32(2)),
+				MixedOnDemandAboveBase: fi.PtrTo(int32(0)),
+			},
+			NoLaunchTemplate: true,
+			Expected:         0,
+			ExpectedDesc:     "c5.large, 2 on-demand, 2 spot",
+			ExpectedMissing:  []string{"instance c5.large", "spot instance c5.large"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			asg := g.ASG
+			asg.Name = fi.PtrTo("nodes.example.com")
+			if !g.NoLaunchTemplate {
+				asg.LaunchTemplate = &awstasks.LaunchTemplate{
+					Name:         fi.PtrTo("nodes.example.com"),
+					InstanceType: fi.PtrTo(ec2types.InstanceType("m5.large")),
+				}
+				if g.SpotPrice != "" {
+					asg.LaunchTemplate.SpotPrice = fi.PtrTo(g.SpotPrice)
+				}
+			}
+			tasks := map[string]fi.CloudupTask{"AutoscalingGroup/nodes.example.com": asg}
+
+			estimate, err := EstimateTasks(catalog, "us-test-1", tasks, nil)
+			if err != nil {
+				t.Fatalf("error estimating cost: %v", err)
+			}
+			if len(estimate.Items) != 1 {
+				t.Fatalf("expected a single item, got %d", len(estimate.Items))
+			}
+			item := estimate.Items[0]
+			if math.Abs(item.MonthlyCost-g.Expected) > 0.001 {
+				t.Errorf("expected cost %v, got %v", g.Expected, item.MonthlyCost)
+			}
+			if item.Description != g.ExpectedDesc {
+				t.Errorf("expected description %q, got %q", g.ExpectedDesc, item.Description)
+			}
+			if !reflect.DeepEqual(item.Missing, g.ExpectedMissing) {
+				t.Errorf("expected missing prices %v, got %v", g.ExpectedMissing, item.Missing)
+			}
+		})
+	}
+}
+
+func TestEstimateTasksMissingPrices(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+
+	tasks := map[string]fi.CloudupTask{
+		"EBSVolume/a.etcd-main.example.com": &awstasks.EBSVolume{
+			Name:       fi.PtrTo("a.etcd-main.example.com"),
+			SizeGB:     fi.PtrTo(int32(20)),
+			VolumeType: ec2types.VolumeTypeIo2,
+		},
+	}
+	estimate, err := EstimateTasks(catalog, "us-test-1", tasks, nil)
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+	if missing := estimate.Missing(); !reflect.DeepEqual(missing, []string{"volume io2"}) {
+		t.Errorf("unexpected missing prices %v", missing)
+	}
+
+	if _, err := EstimateTasks(catalog, "us-other-1", tasks, nil); err == nil {
+		t.Errorf("expected error for region not in catalog")
+	}
+}
+
+type testDeletion struct {
+	taskName string
+	item     string
+}
+
+var _ fi.CloudupDeletion = &testDeletion{}
+
+func (d *testDeletion) Delete(target fi.CloudupTarget) error { return nil }
+func (d *testDeletion) TaskName() string                     { return d.taskName }
+func (d *testDeletion) Item() string                         { return d.item }
+func (d *testDeletion) DeferDeletion() bool                  { return true }
+
+func TestEstimateDeletions(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+
+	deletions := []fi.CloudupDeletion{
+		&testDeletion{taskName: "network-load-balancer", item: "arn:aws:elasticloadbalancing:us-test-1:123456789012:loadbalancer/net/api/1"},
+		&testDeletion{taskName: "ClassicLoadBalancer", item: "api-example-com"},
+		&testDeletion{taskName: "LaunchTemplate", item: "nodes.example.com-1"},
+	}
+	estimate, err := EstimateDeletions(catalog, "us-test-1", deletions)
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+
+	if len(estimate.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(estimate.Items))
+	}
+	if total := estimate.Total(); math.Abs(total-0.02*HoursPerMonth) > 0.001 {
+		t.Errorf("unexpected total %v", total)
+	}
+	if missing := estimate.Missing(); !reflect.DeepEqual(missing, []string{"load balancer classic"}) {
+		t.Errorf("unexpected missing prices %v", missing)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	grid := []struct {
+		Currency string
+		Amount   float64
+		Expected string
+	}{
+		{Currency: "USD", Amount: 0, Expected: "$0.00"},
+		{Currency: "USD", Amount: 4200, Expected: "$4,200.00"},
+		{Currency: "USD", Amount: 1234567.891, Expected: "$1,234,567.89"},
+		{Currency: "USD", Amount: -999.5, Expected: "-$999.50"},
+		{Currency: "EUR", Amount: 100000, Expected: "100,000.00 EUR"},
+	}
+	for _, g := range grid {
+		actual := FormatAmount(g.Currency, g.Amount)
+		if actual != g.Expected {
+			t.Errorf("FormatAmount(%q, %v): expected %q, got %q", g.Currency, g.Amount, g.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshots
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	awsTagKopsVersion      = "kops.k8s.io/kops-version"
+	awsTagReason           = "kops.k8s.io/snapshot-reason"
+	awsTagVolumeZone       = "kops.k8s.io/volume-zone"
+	awsTagVolumeType       = "kops.k8s.io/volume-type"
+	awsTagVolumeIOPS       = "kops.k8s.io/volume-iops"
+	awsTagVolumeThroughput = "kops.k8s.io/volume-throughput"
+)
+
+// awsSnapshotter snapshots EBS volumes.
+// The snapshots copy the tags of the volume, so that restored volumes are found by etcd-manager like the original.
+type awsSnapshotter struct {
+	cloud awsup.AWSCloud
+}
+
+var _ Snapshotter = &awsSnapshotter{}
+
+func (s *awsSnapshotter) SnapshotVolume(ctx context.Context, volumeID string, reason string, requireDetached bool) (*Snapshot, error) {
+	response, err := s.cloud.EC2().DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing volume %q: %w", volumeID, err)
+	}
+	if len(response.Volumes) != 1 {
+		return nil, fmt.Errorf("found %d volumes with ID %q", len(response.Volumes), volumeID)
+	}
+	volume := response.Volumes[0]
+	if requireDetached && (volume.State == ec2types.VolumeStateInUse || len(volume.Attachments) != 0) {
+		return nil, fmt.Errorf("volume %q is still attached", volumeID)
+	}
+
+	var tags []ec2types.Tag
+	for _, tag := range volume.Tags {
+		// Tags with the aws: prefix are reserved
+		if !strings.HasPrefix(aws.ToString(tag.Key), "aws:") {
+			tags = append(tags, tag)
+		}
+	}
+	tags = append(tags,
+		ec2types.Tag{Key: aws.String(awsTagKopsVersion), Value: aws.String(kops.Version)},
+		ec2types.Tag{Key: aws.String(awsTagReason), Value: aws.String(reason)},
+		ec2types.Tag{Key: aws.String(awsTagVolumeZone), Value: volume.AvailabilityZone},
+		ec2types.Tag{Key: aws.String(awsTagVolumeType), Value: aws.String(string(volume.VolumeType))},
+	)
+	if volume.Iops != nil {
+		tags = append(tags, ec2types.Tag{Key: aws.String(awsTagVolumeIOPS), Value: aws.String(strconv.Itoa(int(*volume.Iops)))})
+	}
+	if volume.Throughput != nil {
+		tags = append(tags, ec2types.Tag{Key: aws.String(awsTagVolumeThroughput), Value: aws.String(strconv.Itoa(int(*volume.Throughput)))})
+	}
+
+	snapshot, err := s.cloud.EC2().CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(volumeID),
+		Description: aws.String(fmt.Sprintf("Snapshot of etcd volume %s, taken by kops %s before %s", volumeID, kops.Version, reason)),
+		TagSpecifications: []ec2types.TagSpecification{
+			{
+				ResourceType: ec2types.ResourceTypeSnapshot,
+				Tags:         tags,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot of volume %q: %w", volumeID, err)
+	}
+
+	return s.toSnapshot(ec2types.Snapshot{
+		SnapshotId: snapshot.SnapshotId,
+		VolumeId:   snapshot.VolumeId,
+		StartTime:  snapshot.StartTime,
+		Tags:       tags,
+	})
+}
+
+func (s *awsSnapshotter) ListSnapshots(ctx context.Context) ([]*Snapshot, error) {
+	request := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	}
+	for k, v := range s.cloud.Tags() {
+		request.Filters = append(request.Filters, awsup.NewEC2Filter("tag:"+k, v))
+	}
+
+	var snapshots []*Snapshot
+	paginator := ec2.NewDescribeSnapshotsPaginator(s.cloud.EC2(), request)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error describing snapshots: %w", err)
+		}
+		for _, o := range page.Snapshots {
+			snapshot, err := s.toSnapshot(o)
+			if err != nil {
+				return nil, err
+			}
+			if snapshot != nil {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	SortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+func (s *awsSnapshotter) RestoreVolume(ctx context.Context, snapshot *Snapshot) (string, error) {
+	o := snapshot.obj.(ec2types.Snapshot)
+	if snapshot.Zone == "" {
+		return "", fmt.Errorf("snapshot %s does not record the zone of its volume", snapshot.ID)
+	}
+
+	request := &ec2.CreateVolumeInput{
+		AvailabilityZone: aws.String(snapshot.Zone),
+		SnapshotId:       o.SnapshotId,
+	}
+	var tags []ec2types.Tag
+	for _, tag := range o.Tags {
+		v := aws.ToString(tag.Value)
+		switch aws.ToString(tag.Key) {
+		case awsTagKopsVersion, awsTagReason, awsTagVolumeZone:
+		case awsTagVolumeType:
+			request.VolumeType = ec2types.VolumeType(v)
+		case awsTagVolumeIOPS:
+			iops, err := strconv.Atoi(v)
+			if err != nil {
+				return "", fmt.Errorf("unexpected tag %s=%s on snapshot %s", awsTagVolumeIOPS, v, snapshot.ID)
+			}
+			request.Iops = aws.Int32(int32(iops))
+		case awsTagVolumeThroughput:
+			throughput, err := strconv.Atoi(v)
+			if err != nil {
+				return "", fmt.Errorf("unexpected tag %s=%s on snapshot %s", awsTagVolumeThroughput, v, snapshot.ID)
+			}
+			request.Throughput = aws.Int32(int32(throughput))
+		default:
+			tags = append(tags, tag)
+		}
+	}
+	request.TagSpecifications = []ec2types.TagSpecification{
+		{
+			ResourceType: ec2types.ResourceTypeVolume,
+			Tags:         tags,
+		},
+	}
+
+	volume, err := s.cloud.EC2().CreateVolume(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("error creating volume from snapshot %s: %w", snapshot.ID, err)
+	}
+	return aws.ToString(volume.VolumeId), nil
+}
+
+// toSnapshot converts the EC2 snapshot, returning nil if it is not of an etcd volume.
+func (s *awsSnapshotter) toSnapshot(o ec2types.Snapshot) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ID:        aws.ToString(o.SnapshotId),
+		VolumeID:  aws.ToString(o.VolumeId),
+		CreatedAt: aws.ToTime(o.StartTime),
+		obj:       o,
+	}
+	for _, tag := range o.Tags {
+		k := aws.ToString(tag.Key)
+		v := aws.ToString(tag.Value)
+		switch k {
+		case awsTagKopsVersion:
+			snapshot.KopsVersion = v
+		case awsTagReason:
+			snapshot.Reason = v
+		case awsTagVolumeZone:
+			snapshot.Zone = v
+		default:
+			if strings.HasPrefix(k, awsup.TagNameEtcdClusterPrefix) {
+				snapshot.EtcdCluster = strings.TrimPrefix(k, awsup.TagNameEtcdClusterPrefix)
+				spec, err := etcd.ParseEtcdClusterSpec(snapshot.EtcdCluster, v)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing etcd cluster tag %q on snapshot %q: %w", v, snapshot.ID, err)
+				}
+				snapshot.Member = spec.NodeName
+			}
+		}
+	}
+	if snapshot.EtcdCluster == "" {
+		return nil, nil
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshots
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+const (
+	gceLabelKopsVersion = "k8s-io-kops-version"
+	gceLabelReason      = "k8s-io-snapshot-reason"
+	gceLabelVolumeZone  = "k8s-io-volume-zone"
+	gceLabelVolumeType  = "k8s-io-volume-type"
+)
+
+// gceSnapshotter snapshots persistent disks.
+// The snapshots copy the labels of the disk, so that restored disks are found by etcd-manager like the original.
+type gceSnapshotter struct {
+	cloud gce.GCECloud
+}
+
+var _ Snapshotter = &gceSnapshotter{}
+
+func (s *gceSnapshotter) SnapshotVolume(ctx context.Context, volumeID string, reason string, requireDetached bool) (*Snapshot, error) {
+	disk, err := s.findDisk(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if requireDetached && len(disk.Users) != 0 {
+		return nil, fmt.Errorf("disk %q is still attached", volumeID)
+	}
+
+	zone := gce.LastComponent(disk.Zone)
+	labels := make(map[string]string)
+	for k, v := range disk.Labels {
+		labels[k] = v
+	}
+	labels[gceLabelKopsVersion] = gce.EncodeGCELabel(kops.Version)
+	labels[gceLabelReason] = gce.EncodeGCELabel(reason)
+	labels[gceLabelVolumeZone] = gce.EncodeGCELabel(zone)
+	labels[gceLabelVolumeType] = gce.EncodeGCELabel(gce.LastComponent(disk.Type))
+
+	createdAt := time.Now().UTC()
+	// Snapshot names are global, and limited to 63 characters
+	name := disk.Name
+	if len(name) > 48 {
+		name = strings.TrimRight(name[:48], "-")
+	}
+	name += "-" + createdAt.Format("20060102150405")
+
+	snapshot := &compute.Snapshot{
+		Name:        name,
+		Description: fmt.Sprintf("Snapshot of etcd disk %s, taken by kops %s before %s", disk.Name, kops.Version, reason),
+		Labels:      labels,
+	}
+	op, err := s.cloud.Compute().Disks().CreateSnapshot(s.cloud.Project(), zone, disk.Name, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot of disk %q: %w", disk.Name, err)
+	}
+	if err := s.cloud.WaitForOp(op); err != nil {
+		return nil, fmt.Errorf("error creating snapshot of disk %q: %w", disk.Name, err)
+	}
+
+	result, err := s.toSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	result.VolumeID = disk.Name
+	result.CreatedAt = createdAt
+	return result, nil
+}
+
+func (s *gceSnapshotter) ListSnapshots(ctx context.Context) ([]*Snapshot, error) {
+	l, err := s.cloud.Compute().Snapshots().List(ctx, s.cloud.Project())
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots: %w", err)
+	}
+
+	clusterLabels := s.cloud.Labels()
+	var snapshots []*Snapshot
+	for _, o := range l {
+		match := true
+		for k, v := range clusterLabels {
+			if o.Labels[k] != v {
+				match = false
+			}
+		}
+		if !match {
+			continue
+		}
+		snapshot, err := s.toSnapshot(o)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	SortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+func (s *gceSnapshotter) RestoreVolume(ctx context.Context, snapshot *Snapshot) (string, error) {
+	o := snapshot.obj.(*compute.Snapshot)
+	if snapshot.Zone == "" {
+		return "", fmt.Errorf("snapshot %s does not record the zone of its disk", snapshot.ID)
+	}
+
+	disk := &compute.Disk{
+		Name:           snapshot.VolumeID,
+		SourceSnapshot: o.SelfLink,
+		Labels:         make(map[string]string),
+	}
+	for k, v := range o.Labels {
+		switch k {
+		case gceLabelKopsVersion, gceLabelReason, gceLabelVolumeZone:
+		case gceLabelVolumeType:
+			diskType, err := gce.DecodeGCELabel(v)
+			if err != nil {
+				return "", fmt.Errorf("unexpected label %s=%s on snapshot %s", k, v, snapshot.ID)
+			}
+			disk.Type = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/diskTypes/%s", s.cloud.Project(), snapshot.Zone, diskType)
+		default:
+			disk.Labels[k] = v
+		}
+	}
+
+	op, err := s.cloud.Compute().Disks().Insert(s.cloud.Project(), snapshot.Zone, disk)
+	if err != nil {
+		return "", fmt.Errorf("error creating disk from snapshot %s: %w", snapshot.ID, err)
+	}
+	if err := s.cloud.WaitForOp(op); err != nil {
+		return "", fmt.Errorf("error creating disk from snapshot %s: %w", snapshot.ID, err)
+	}
+	return disk.Name, nil
+}
+
+// findDisk finds the disk by name in any zone of the project.
+func (s *gceSnapshotter) findDisk(ctx context.Context, name string) (*compute.Disk, error) {
+	scopedLists, err := s.cloud.Compute().Disks().AggregatedList(ctx, s.cloud.Project())
+	if err != nil {
+		return nil, fmt.Errorf("error listing disks: %w", err)
+	}
+	for _, scopedList := range scopedLists {
+		for _, disk := range scopedList.Disks {
+			if disk.Name == name {
+				return disk, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("disk %q not found", name)
+}
+
+// toSnapshot converts the GCE snapshot, returning nil if it is not of an etcd disk.
+func (s *gceSnapshotter) toSnapshot(o *compute.Snapshot) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ID:       o.Name,
+		VolumeID: gce.LastComponent(o.SourceDisk),
+		obj:      o,
+	}
+	if o.CreationTimestamp != "" {
+		createdAt, err := time.Parse(time.RFC3339, o.CreationTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected creation timestamp %q on snapshot %q", o.CreationTimestamp, o.Name)
+		}
+		snapshot.CreatedAt = createdAt
+	}
+	for k, v := range o.Labels {
+		var target *string
+		switch {
+		case k == gceLabelKopsVersion:
+			target = &snapshot.KopsVersion
+		case k == gceLabelReason:
+			target = &snapshot.Reason
+		case k == gceLabelVolumeZone:
+			target = &snapshot.Zone
+		case strings.HasPrefix(k, gce.GceLabelNameEtcdClusterPrefix):
+			snapshot.EtcdCluster = strings.TrimPrefix(k, gce.GceLabelNameEtcdClusterPrefix)
+		default:
+			continue
+		}
+		decoded, err := gce.DecodeGCELabel(v)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected label %s=%s on snapshot %q", k, v, o.Name)
+		}
+		if target != nil {
+			*target = decoded
+			continue
+		}
+		spec, err := etcd.ParseEtcdClusterSpec(snapshot.EtcdCluster, decoded)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing etcd cluster label %q on snapshot %q: %w", decoded, o.Name, err)
+		}
+		snapshot.Member = spec.NodeName
+	}
+	if snapshot.EtcdCluster == "" {
+		return nil, nil
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshots
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cindersnapshots "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+	cinder "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"k8s.io/kops"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+const (
+	openstackMetadataKopsVersion = "kops.k8s.io/kops-version"
+	openstackMetadataReason      = "kops.k8s.io/snapshot-reason"
+	openstackMetadataVolumeZone  = "kops.k8s.io/volume-zone"
+	openstackMetadataVolumeType  = "kops.k8s.io/volume-type"
+	openstackMetadataVolumeName  = "kops.k8s.io/volume-name"
+)
+
+// openstackSnapshotter snapshots Cinder volumes.
+// The snapshots copy the metadata of the volume, so that restored volumes are found by etcd-manager like the original.
+type openstackSnapshotter struct {
+	cloud openstack.OpenstackCloud
+}
+
+var _ Snapshotter = &openstackSnapshotter{}
+
+func (s *openstackSnapshotter) SnapshotVolume(ctx context.Context, volumeID string, reason string, requireDetached bool) (*Snapshot, error) {
+	volume, err := s.findVolume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if requireDetached && (volume.Status == "in-use" || len(volume.Attachments) != 0) {
+		return nil, fmt.Errorf("volume %q is still attached", volumeID)
+	}
+
+	metadata := make(map[string]string)
+	for k, v := range volume.Metadata {
+		metadata[k] = v
+	}
+	metadata[openstackMetadataKopsVersion] = kops.Version
+	metadata[openstackMetadataReason] = reason
+	metadata[openstackMetadataVolumeZone] = volume.AvailabilityZone
+	metadata[openstackMetadataVolumeType] = volume.VolumeType
+	metadata[openstackMetadataVolumeName] = volume.Name
+
+	createdAt := time.Now().UTC()
+	// Cinder refuses to snapshot an attached volume unless forced
+	o, err := s.cloud.CreateSnapshot(cindersnapshots.CreateOpts{
+		VolumeID:    volume.ID,
+		Force:       true,
+		Name:        volume.Name + "-" + createdAt.Format("20060102150405"),
+		Description: fmt.Sprintf("Snapshot of etcd volume %s, taken by kops %s before %s", volume.Name, kops.Version, reason),
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot of volume %q: %w", volumeID, err)
+	}
+
+	snapshot, err := s.toSnapshot(o)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("volume %q is not an etcd volume", volumeID)
+	}
+	if snapshot.CreatedAt.IsZero() {
+		snapshot.CreatedAt = createdAt
+	}
+	return snapshot, nil
+}
+
+func (s *openstackSnapshotter) ListSnapshots(ctx context.Context) ([]*Snapshot, error) {
+	l, err := s.cloud.ListSnapshots(cindersnapshots.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots: %w", err)
+	}
+
+	// Cinder cannot filter snapshots by metadata, so we filter by the cluster tags here
+	clusterTags := s.cloud.GetCloudTags()
+	var snapshots []*Snapshot
+	for i := range l {
+		o := &l[i]
+		match := true
+		for k, v := range clusterTags {
+			if o.Metadata[k] != v {
+				match = false
+			}
+		}
+		if !match {
+			continue
+		}
+		snapshot, err := s.toSnapshot(o)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	SortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+func (s *openstackSnapshotter) RestoreVolume(ctx context.Context, snapshot *Snapshot) (string, error) {
+	o := snapshot.obj.(*cindersnapshots.Snapshot)
+	if o.Status != "available" {
+		return "", fmt.Errorf("snapshot %s is %q, not available", snapshot.ID, o.Status)
+	}
+
+	opt := cinder.CreateOpts{
+		Size:             o.Size,
+		SnapshotID:       o.ID,
+		AvailabilityZone: snapshot.Zone,
+		Metadata:         make(map[string]string),
+	}
+	for k, v := range o.Metadata {
+		switch k {
+		case openstackMetadataKopsVersion, openstackMetadataReason, openstackMetadataVolumeZone:
+		case openstackMetadataVolumeType:
+			opt.VolumeType = v
+		case openstackMetadataVolumeName:
+			opt.Name = v
+		default:
+			opt.Metadata[k] = v
+		}
+	}
+
+	volume, err := s.cloud.CreateVolume(opt)
+	if err != nil {
+		return "", fmt.Errorf("error creating volume from snapshot %s: %w", snapshot.ID, err)
+	}
+	return volume.ID, nil
+}
+
+// findVolume finds the volume by ID among the volumes of the cluster.
+func (s *openstackSnapshotter) findVolume(volumeID string) (*cinder.Volume, error) {
+	volumes, err := s.cloud.ListVolumes(cinder.ListOpts{
+		Metadata: s.cloud.GetCloudTags(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %w", err)
+	}
+	for i := range volumes {
+		if volumes[i].ID == volumeID {
+			return &volumes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("volume %q not found", volumeID)
+}
+
+// toSnapshot converts the Cinder snapshot, returning nil if it is not of an etcd volume.
+func (s *openstackSnapshotter) toSnapshot(o *cindersnapshots.Snapshot) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ID:          o.ID,
+		VolumeID:    o.VolumeID,
+		Zone:        o.Metadata[openstackMetadataVolumeZone],
+		KopsVersion: o.Metadata[openstackMetadataKopsVersion],
+		Reason:      o.Metadata[openstackMetadataReason],
+		CreatedAt:   o.CreatedAt,
+		obj:         o,
+	}
+	for k, v := range o.Metadata {
+		if !strings.HasPrefix(k, openstack.TagNameEtcdClusterPrefix) {
+			continue
+		}
+		snapshot.EtcdCluster = strings.TrimPrefix(k, openstack.TagNameEtcdClusterPrefix)
+		spec, err := etcd.ParseEtcdClusterSpec(snapshot.EtcdCluster, v)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing etcd cluster metadata %q on snapshot %q: %w", v, o.ID, err)
+		}
+		snapshot.Member = spec.NodeName
+	}
+	if snapshot.EtcdCluster == "" {
+		return nil, nil
+	}
+	return snapshot, nil
+}
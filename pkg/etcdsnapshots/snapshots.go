@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshots
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+// The reasons for taking a snapshot, recorded on the snapshot.
+const (
+	ReasonDeleteCluster  = "delete-cluster"
+	ReasonRollingUpdate  = "rolling-update"
+	ReasonUpgradeCluster = "upgrade-cluster"
+)
+
+// Snapshot is a cloud snapshot of the volume of an etcd member.
+// Snapshots are a cloud-native fallback for the etcd-manager backups, which do not depend on the backup store.
+type Snapshot struct {
+	// ID is the cloud ID of the snapshot.
+	ID string `json:"id"`
+	// EtcdCluster is the name of the etcd cluster, e.g. main or events.
+	EtcdCluster string `json:"etcdCluster"`
+	// Member is the name of the etcd member whose volume was snapshotted.
+	Member string `json:"member"`
+	// VolumeID is the cloud ID of the volume that was snapshotted.
+	VolumeID string `json:"volumeID"`
+	// Zone is the zone of the volume that was snapshotted, where it is restored.
+	Zone string `json:"zone,omitempty"`
+	// KopsVersion is the version of kops that took the snapshot.
+	KopsVersion string `json:"kopsVersion,omitempty"`
+	// Reason is the operation the snapshot was taken before.
+	Reason string `json:"reason,omitempty"`
+	// CreatedAt is the time the snapshot was started.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// obj is the cloud object for the snapshot.
+	obj interface{}
+}
+
+// Snapshotter snapshots the etcd volumes of a cluster, and restores volumes from those snapshots.
+type Snapshotter interface {
+	// SnapshotVolume snapshots the volume of an etcd member, tagging the snapshot with the cluster and kops version.
+	// If requireDetached is true, the volume must not be attached to an instance.
+	SnapshotVolume(ctx context.Context, volumeID string, reason string, requireDetached bool) (*Snapshot, error)
+	// ListSnapshots lists the snapshots of the etcd volumes of the cluster.
+	ListSnapshots(ctx context.Context) ([]*Snapshot, error)
+	// RestoreVolume creates a volume for the etcd member from the snapshot, tagged as the original volume, and returns its ID.
+	RestoreVolume(ctx context.Context, snapshot *Snapshot) (string, error)
+}
+
+// NewSnapshotter builds the Snapshotter for the cloud.
+func NewSnapshotter(cloud fi.Cloud) (Snapshotter, error) {
+	switch cloud.ProviderID() {
+	case kops.CloudProviderAWS:
+		return &awsSnapshotter{cloud: cloud.(awsup.AWSCloud)}, nil
+	case kops.CloudProviderGCE:
+		return &gceSnapshotter{cloud: cloud.(gce.GCECloud)}, nil
+	case kops.CloudProviderOpenstack:
+		return &openstackSnapshotter{cloud: cloud.(openstack.OpenstackCloud)}, nil
+	default:
+		return nil, fmt.Errorf("snapshotting etcd volumes on %q is not (yet) supported", cloud.ProviderID())
+	}
+}
+
+// SnapshotCluster snapshots the volumes of all the etcd members of the cluster, as found by FindClusterStatus.
+func SnapshotCluster(ctx context.Context, cloud fi.Cloud, cluster *kops.Cluster, reason string) ([]*Snapshot, error) {
+	snapshotter, err := NewSnapshotter(cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := cloud.FindClusterStatus(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, etcdCluster := range status.EtcdClusters {
+		for _, member := range etcdCluster.Members {
+			klog.Infof("Snapshotting volume %s of etcd member %s/%s", member.VolumeID, etcdCluster.Name, member.Name)
+			snapshot, err := snapshotter.SnapshotVolume(ctx, member.VolumeID, reason, false)
+			if err != nil {
+				return snapshots, fmt.Errorf("error snapshotting volume %s of etcd member %s/%s: %w", member.VolumeID, etcdCluster.Name, member.Name, err)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	SortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+// RestoreVolumes creates the volumes of the etcd members from the snapshots, returning the IDs of the new volumes.
+// A member's volume must no longer exist, so that etcd-manager does not find two volumes for the same member.
+func RestoreVolumes(ctx context.Context, cloud fi.Cloud, cluster *kops.Cluster, snapshots []*Snapshot) ([]string, error) {
+	snapshotter, err := NewSnapshotter(cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := cloud.FindClusterStatus(cluster)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]string)
+	for _, etcdCluster := range status.EtcdClusters {
+		for _, member := range etcdCluster.Members {
+			existing[etcdCluster.Name+"/"+member.Name] = member.VolumeID
+		}
+	}
+
+	restoring := make(map[string]string)
+	for _, snapshot := range snapshots {
+		key := snapshot.EtcdCluster + "/" + snapshot.Member
+		if volumeID, found := existing[key]; found {
+			return nil, fmt.Errorf("volume %s of etcd member %s still exists; it must be deleted before restoring snapshot %s", volumeID, key, snapshot.ID)
+		}
+		if other, found := restoring[key]; found {
+			return nil, fmt.Errorf("snapshots %s and %s are both of etcd member %s", other, snapshot.ID, key)
+		}
+		restoring[key] = snapshot.ID
+	}
+
+	var volumeIDs []string
+	for _, snapshot := range snapshots {
+		klog.Infof("Restoring volume of etcd member %s/%s from snapshot %s", snapshot.EtcdCluster, snapshot.Member, snapshot.ID)
+		volumeID, err := snapshotter.RestoreVolume(ctx, snapshot)
+		if err != nil {
+			return volumeIDs, fmt.Errorf("error restoring snapshot %s: %w", snapshot.ID, err)
+		}
+		volumeIDs = append(volumeIDs, volumeID)
+	}
+	return volumeIDs, nil
+}
+
+// SortSnapshots sorts the snapshots by etcd cluster and member, most recent first.
+func SortSnapshots(snapshots []*Snapshot) {
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].EtcdCluster != snapshots[j].EtcdCluster {
+			return snapshots[i].EtcdCluster < snapshots[j].EtcdCluster
+		}
+		if snapshots[i].Member != snapshots[j].Member {
+			return snapshots[i].Member < snapshots[j].Member
+		}
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshots
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	cinder "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	gcemock "k8s.io/kops/cloudmock/gce"
+	"k8s.io/kops/cloudmock/openstack/mockblockstorage"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+func TestAWSSnapshotRestore(t *testing.T) {
+	ctx := context.TODO()
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	volumeTags := []ec2types.Tag{
+		{Key: fi.PtrTo("k8s.io/etcd/main"), Value: fi.PtrTo("a/a")},
+		{Key: fi.PtrTo("k8s.io/role/control-plane"), Value: fi.PtrTo("1")},
+		{Key: fi.PtrTo("Name"), Value: fi.PtrTo("a.etcd-main.example.com")},
+	}
+	volume, err := c.CreateVolume(ctx, &ec2.CreateVolumeInput{
+		AvailabilityZone: fi.PtrTo("us-east-1a"),
+		Size:             fi.PtrTo(int32(20)),
+		VolumeType:       ec2types.VolumeTypeGp3,
+		Iops:             fi.PtrTo(int32(3000)),
+		TagSpecifications: []ec2types.TagSpecification{
+			{ResourceType: ec2types.ResourceTypeVolume, Tags: volumeTags},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating volume: %v", err)
+	}
+	volumeID := fi.ValueOf(volume.VolumeId)
+
+	snapshotter, err := NewSnapshotter(cloud)
+	if err != nil {
+		t.Fatalf("error building snapshotter: %v", err)
+	}
+	snapshot, err := snapshotter.SnapshotVolume(ctx, volumeID, ReasonUpgradeCluster, false)
+	if err != nil {
+		t.Fatalf("error snapshotting volume: %v", err)
+	}
+	if snapshot.EtcdCluster != "main" || snapshot.Member != "a" || snapshot.Zone != "us-east-1a" || snapshot.KopsVersion != kops.Version || snapshot.Reason != ReasonUpgradeCluster {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	snapshots, err := snapshotter.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("error listing snapshots: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != snapshot.ID || snapshots[0].VolumeID != volumeID {
+		t.Fatalf("unexpected snapshots %+v", snapshots)
+	}
+
+	if _, err := c.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: volume.VolumeId}); err != nil {
+		t.Fatalf("error deleting volume: %v", err)
+	}
+
+	restoredID, err := snapshotter.RestoreVolume(ctx, snapshots[0])
+	if err != nil {
+		t.Fatalf("error restoring volume: %v", err)
+	}
+	restored := c.Volumes[restoredID]
+	if restored == nil {
+		t.Fatalf("restored volume %q not found", restoredID)
+	}
+	if fi.ValueOf(restored.SnapshotId) != snapshot.ID || fi.ValueOf(restored.AvailabilityZone) != "us-east-1a" || restored.VolumeType != ec2types.VolumeTypeGp3 || fi.ValueOf(restored.Iops) != 3000 {
+		t.Errorf("unexpected restored volume %+v", restored)
+	}
+
+	// The restored volume is tagged as the original volume, without the snapshot tags
+	response, err := c.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{restoredID}})
+	if err != nil {
+		t.Fatalf("error describing volume: %v", err)
+	}
+	actualTags := make(map[string]string)
+	for _, tag := range response.Volumes[0].Tags {
+		actualTags[fi.ValueOf(tag.Key)] = fi.ValueOf(tag.Value)
+	}
+	expectedTags := map[string]string{
+		"k8s.io/etcd/main":          "a/a",
+		"k8s.io/role/control-plane": "1",
+		"Name":                      "a.etcd-main.example.com",
+	}
+	if !reflect.DeepEqual(actualTags, expectedTags) {
+		t.Errorf("unexpected tags on restored volume, expected %v, got %v", expectedTags, actualTags)
+	}
+}
+
+func TestGCESnapshotRestore(t *testing.T) {
+	ctx := context.TODO()
+	cloud := gcemock.InstallMockGCECloud("us-central1", "testproject").WithLabels(map[string]string{
+		"k8s-io-cluster-name": "example-com",
+	}).(gce.GCECloud)
+
+	diskLabels := map[string]string{
+		"k8s-io-cluster-name":         "example-com",
+		"k8s-io-etcd-main":            gce.EncodeGCELabel("a/a"),
+		"k8s-io-role-control-plane":   "1",
+		gce.GceLabelNameInstanceGroup: "control-plane-us-central1-a",
+	}
+	if _, err := cloud.Compute().Disks().Insert("testproject", "us-central1-a", &compute.Disk{
+		Name:   "a-etcd-main-example-com",
+		SizeGb: 20,
+		Type:   "https://www.googleapis.com/compute/v1/projects/testproject/zones/us-central1-a/diskTypes/pd-ssd",
+		Labels: diskLabels,
+	}); err != nil {
+		t.Fatalf("error creating disk: %v", err)
+	}
+
+	snapshotter, err := NewSnapshotter(cloud)
+	if err != nil {
+		t.Fatalf("error building snapshotter: %v", err)
+	}
+	snapshot, err := snapshotter.SnapshotVolume(ctx, "a-etcd-main-example-com", ReasonRollingUpdate, false)
+	if err != nil {
+		t.Fatalf("error snapshotting disk: %v", err)
+	}
+	if snapshot.EtcdCluster != "main" || snapshot.Member != "a" || snapshot.Zone != "us-central1-a" || snapshot.KopsVersion != kops.Version || snapshot.Reason != ReasonRollingUpdate {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	snapshots, err := snapshotter.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("error listing snapshots: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != snapshot.ID || snapshots[0].VolumeID != "a-etcd-main-example-com" {
+		t.Fatalf("unexpected snapshots %+v", snapshots)
+	}
+
+	if _, err := cloud.Compute().Disks().Delete("testproject", "us-central1-a", "a-etcd-main-example-com"); err != nil {
+		t.Fatalf("error deleting disk: %v", err)
+	}
+
+	restoredID, err := snapshotter.RestoreVolume(ctx, snapshots[0])
+	if err != nil {
+		t.Fatalf("error restoring disk: %v", err)
+	}
+	if restoredID != "a-etcd-main-example-com" {
+		t.Errorf("expected disk to be restored with its original name, got %q", restoredID)
+	}
+	restored, err := cloud.Compute().Disks().Get("testproject", "us-central1-a", restoredID)
+	if err != nil {
+		t.Fatalf("error getting restored disk: %v", err)
+	}
+	if gce.LastComponent(restored.SourceSnapshot) != snapshot.ID || gce.LastComponent(restored.Type) != "pd-ssd" {
+		t.Errorf("unexpected restored disk %+v", restored)
+	}
+	if !reflect.DeepEqual(restored.Labels, diskLabels) {
+		t.Errorf("unexpected labels on restored disk, expected %v, got %v", diskLabels, restored.Labels)
+	}
+}
+
+func TestOpenstackSnapshotRestore(t *testing.T) {
+	ctx := context.TODO()
+	cloud := openstack.BuildMockOpenstackCloud("us-east1")
+	cloud.MockCinderClient = mockblockstorage.CreateClient()
+
+	volumeMetadata := map[string]string{
+		"k8s.io/etcd/main":          "a/a",
+		"k8s.io/role/control-plane": "1",
+		"KubernetesCluster":         "example.com",
+	}
+	volume, err := cloud.CreateVolume(cinder.CreateOpts{
+		Name:             "a.etcd-main.example.com",
+		Size:             20,
+		AvailabilityZone: "us-east1-a",
+		VolumeType:       "fast",
+		Metadata:         volumeMetadata,
+	})
+	if err != nil {
+		t.Fatalf("error creating volume: %v", err)
+	}
+
+	snapshotter, err := NewSnapshotter(cloud)
+	if err != nil {
+		t.Fatalf("error building snapshotter: %v", err)
+	}
+	snapshot, err := snapshotter.SnapshotVolume(ctx, volume.ID, ReasonDeleteCluster, false)
+	if err != nil {
+		t.Fatalf("error snapshotting volume: %v", err)
+	}
+	if snapshot.EtcdCluster != "main" || snapshot.Member != "a" || snapshot.Zone != "us-east1-a" || snapshot.KopsVersion != kops.Version || snapshot.Reason != ReasonDeleteCluster {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	snapshots, err := snapshotter.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("error listing snapshots: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != snapshot.ID || snapshots[0].VolumeID != volume.ID {
+		t.Fatalf("unexpected snapshots %+v", snapshots)
+	}
+
+	restoredID, err := snapshotter.RestoreVolume(ctx, snapshots[0])
+	if err != nil {
+		t.Fatalf("error restoring volume: %v", err)
+	}
+	volumes, err := cloud.ListVolumes(cinder.ListOpts{})
+	if err != nil {
+		t.Fatalf("error listing volumes: %v", err)
+	}
+	var restored *cinder.Volume
+	for i := range volumes {
+		if volumes[i].ID == restoredID {
+			restored = &volumes[i]
+		}
+	}
+	if restored == nil {
+		t.Fatalf("restored volume %q not found", restoredID)
+	}
+	if restored.SnapshotID != snapshot.ID || restored.AvailabilityZone != "us-east1-a" || restored.VolumeType != "fast" || restored.Size != 20 || restored.Name != "a.etcd-main.example.com" {
+		t.Errorf("unexpected restored volume %+v", restored)
+	}
+
+	// The restored volume has the metadata of the original volume, without the snapshot metadata
+	if !reflect.DeepEqual(restored.Metadata, volumeMetadata) {
+		t.Errorf("unexpected metadata on restored volume, expected %v, got %v", volumeMetadata, restored.Metadata)
+	}
+}
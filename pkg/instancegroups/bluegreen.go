@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/drain"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// waitForGreenNodes waits until the instance group has a ready node for each instance it had before the rolling update,
+// not counting the instances being replaced. Instances that could not be detached are not replaced by the cloud provider,
+// so they are deducted from the number of nodes to wait for.
+func (c *RollingUpdateCluster) waitForGreenNodes(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) error {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	blue := map[string]bool{}
+	for _, u := range update {
+		blue[u.ID] = true
+	}
+
+	for {
+		ready, required, err := c.countGreenNodes(ctx, group, blue)
+		if err != nil {
+			klog.Warningf("error counting the nodes of group %q: %v", group.HumanName, err)
+		} else if ready >= required {
+			klog.Infof("Group %q has %d ready replacement nodes.", group.HumanName, ready)
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("group %q did not have %d ready replacement nodes within %s", group.HumanName, required, c.ValidationTimeout)
+		}
+		klog.Infof("Waiting for group %q to have %d ready replacement nodes, %d are ready.", group.HumanName, required, ready)
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
+
+// countGreenNodes counts the ready nodes of the instances of the group that are not being replaced,
+// and how many of them there should be.
+func (c *RollingUpdateCluster) countGreenNodes(ctx context.Context, group *cloudinstances.CloudInstanceGroup, blue map[string]bool) (int, int, error) {
+	required := group.TargetSize
+
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, required, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	cloudGroups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes.Items)
+	if err != nil {
+		return 0, required, err
+	}
+
+	ready := 0
+	for _, cloudGroup := range cloudGroups {
+		if cloudGroup.InstanceGroup != group.InstanceGroup {
+			continue
+		}
+		var instances []*cloudinstances.CloudInstance
+		instances = append(instances, cloudGroup.Ready...)
+		instances = append(instances, cloudGroup.NeedUpdate...)
+		for _, instance := range instances {
+			if blue[instance.ID] {
+				if instance.Status != cloudinstances.CloudInstanceStatusDetached {
+					required--
+				}
+				continue
+			}
+			if instance.Node != nil && isNodeReady(instance.Node) {
+				ready++
+			}
+		}
+	}
+	return ready, required, nil
+}
+
+// cordonAll cordons the nodes of all the instances, so that pods evicted from one of them are not scheduled on another.
+func (c *RollingUpdateCluster) cordonAll(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) error {
+	helper := &drain.Helper{
+		Ctx:    c.Ctx,
+		Client: c.K8sClient,
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+
+	klog.Infof("Cordoning %d nodes in %q instancegroup.", len(update), group.InstanceGroup.Name)
+	for _, u := range update {
+		if u.Node == nil || u.Node.Spec.Unschedulable {
+			continue
+		}
+		if err := drain.RunCordonOrUncordon(helper, u.Node, true); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if c.FailOnDrainError {
+				return fmt.Errorf("failed to cordon node %q: %v", u.Node.Name, err)
+			}
+			klog.Infof("Ignoring error cordoning node %q: %v", u.Node.Name, err)
+		}
+	}
+	return nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
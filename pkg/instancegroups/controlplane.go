@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/wellknownports"
+)
+
+// rollingUpdateControlPlaneWithSurge replaces the instances of a control plane instance group one at a time,
+// creating the replacement of each instance before draining and terminating it.
+// The etcd members of a control plane instance are bound to its etcd volumes, so the replacement only takes
+// them over once the old instance is terminated: the etcd clusters keep all their members and at most one
+// of them is down at a time. The etcd members are checked before and after each step.
+// As its kube-apiserver uses the local etcd member, a replacement can only serve once it has taken over the volumes;
+// its kube-apiserver must be ready before the next instance is drained.
+func (c *RollingUpdateCluster) rollingUpdateControlPlaneWithSurge(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
+	// known holds the instances that are not the replacement of the instance being replaced,
+	// including the replacements created for the previous instances.
+	known := map[string]bool{}
+	for _, instance := range group.Ready {
+		known[instance.ID] = true
+	}
+	for _, instance := range group.NeedUpdate {
+		known[instance.ID] = true
+	}
+
+	for _, u := range update {
+		if err := c.waitForEtcdMembers(" before replacing instance " + u.ID); err != nil {
+			return err
+		}
+
+		var replacement *cloudinstances.CloudInstance
+		if u.Status != cloudinstances.CloudInstanceStatusDetached {
+			if err := c.detachInstance(u); err != nil {
+				// As for other instance groups, proceed without surging
+				klog.Errorf("Failed to detach instance %q, replacing it without surging: %v", u.ID, err)
+			} else {
+				var err error
+				replacement, err = c.waitForReplacement(group, known)
+				if err != nil {
+					return err
+				}
+				known[replacement.ID] = true
+				if err := c.waitForEtcdMembers(" after creating the replacement of instance " + u.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := c.drainTerminateAndWait(u, sleepAfterTerminate); err != nil {
+			return err
+		}
+
+		if err := c.maybeValidate(" after terminating instance", c.ValidateCount, group); err != nil {
+			return err
+		}
+
+		if err := c.waitForEtcdMembers(" after replacing instance " + u.ID); err != nil {
+			return err
+		}
+
+		if replacement != nil {
+			if err := c.waitForAPIServer(group, replacement.ID); err != nil {
+				return err
+			}
+		}
+
+		if c.Interactive {
+			nodeName := ""
+			if u.Node != nil {
+				nodeName = u.Node.Name
+			}
+
+			stopPrompting, err := promptInteractive(u.ID, nodeName)
+			if err != nil {
+				return err
+			}
+			if stopPrompting {
+				c.Interactive = false
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitForReplacement waits until the cloud provider runs an instance in the group that is not one of the known instances,
+// returning that instance.
+func (c *RollingUpdateCluster) waitForReplacement(group *cloudinstances.CloudInstanceGroup, known map[string]bool) (*cloudinstances.CloudInstance, error) {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	for {
+		cloudGroups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nil)
+		if err != nil {
+			klog.Warningf("error listing the instances of group %q: %v", group.HumanName, err)
+		}
+		for _, cloudGroup := range cloudGroups {
+			if cloudGroup.InstanceGroup != group.InstanceGroup {
+				continue
+			}
+			var instances []*cloudinstances.CloudInstance
+			instances = append(instances, cloudGroup.Ready...)
+			instances = append(instances, cloudGroup.NeedUpdate...)
+			for _, instance := range instances {
+				if !known[instance.ID] && instance.Status != cloudinstances.CloudInstanceStatusDetached {
+					klog.Infof("Created instance %q in group %q.", instance.ID, group.HumanName)
+					return instance, nil
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("no replacement instance was created in group %q within %s", group.HumanName, c.ValidationTimeout)
+		}
+		klog.Infof("Waiting for a replacement instance in group %q.", group.HumanName)
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
+
+// waitForAPIServer waits until the kube-apiserver of the control plane instance reports that it is ready.
+func (c *RollingUpdateCluster) waitForAPIServer(group *cloudinstances.CloudInstanceGroup, instanceID string) error {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	for {
+		err := c.checkAPIServer(ctx, group, instanceID)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("kube-apiserver of instance %q was not ready within %s: %w", instanceID, c.ValidationTimeout, err)
+		}
+		klog.Infof("kube-apiserver of instance %q is not ready, will retry in %q: %v.", instanceID, c.ValidateTickDuration, err)
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
+
+// checkAPIServer probes /readyz of the kube-apiserver of the control plane instance,
+// through the API server proxy to its kube-apiserver-healthcheck sidecar.
+func (c *RollingUpdateCluster) checkAPIServer(ctx context.Context, group *cloudinstances.CloudInstanceGroup, instanceID string) error {
+	restClient := c.K8sClient.Discovery().RESTClient()
+	if restClient == nil {
+		return nil
+	}
+
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	cloudGroups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes.Items)
+	if err != nil {
+		return fmt.Errorf("listing the instances of group %q: %w", group.HumanName, err)
+	}
+	var node *corev1.Node
+	for _, cloudGroup := range cloudGroups {
+		var instances []*cloudinstances.CloudInstance
+		instances = append(instances, cloudGroup.Ready...)
+		instances = append(instances, cloudGroup.NeedUpdate...)
+		for _, instance := range instances {
+			if instance.ID == instanceID {
+				node = instance.Node
+			}
+		}
+	}
+	if node == nil {
+		return fmt.Errorf("instance %q has not registered as a node", instanceID)
+	}
+
+	pod := fmt.Sprintf("kube-apiserver-%s:%d", node.Name, wellknownports.KubeAPIServerHealthCheck)
+	if _, err := restClient.Get().AbsPath("/api/v1/namespaces", metav1.NamespaceSystem, "pods", pod, "proxy", "readyz").DoRaw(ctx); err != nil {
+		return fmt.Errorf("kube-apiserver on node %q is not ready: %w", node.Name, err)
+	}
+	return nil
+}
+
+// waitForEtcdMembers waits until all the members of the etcd clusters are running.
+func (c *RollingUpdateCluster) waitForEtcdMembers(operation string) error {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	for {
+		err := c.checkEtcdMembers(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("etcd members were not healthy%s within %s: %w", operation, c.ValidationTimeout, err)
+		}
+		klog.Infof("etcd members are not healthy%s, will retry in %q: %v.", operation, c.ValidateTickDuration, err)
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
+
+// checkEtcdMembers checks that every etcd cluster has as many running etcd-manager pods on control plane nodes
+// as it has members, and that the API server reports etcd as ready.
+func (c *RollingUpdateCluster) checkEtcdMembers(ctx context.Context) error {
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/control-plane"})
+	if err != nil {
+		return fmt.Errorf("listing control plane nodes: %w", err)
+	}
+	controlPlaneNodes := map[string]bool{}
+	for _, node := range nodes.Items {
+		controlPlaneNodes[node.Name] = true
+	}
+
+	pods, err := c.K8sClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing kube-system pods: %w", err)
+	}
+
+	for _, etcdCluster := range c.Cluster.Spec.EtcdClusters {
+		app := "etcd-manager-" + etcdCluster.Name
+		running := 0
+		for _, pod := range pods.Items {
+			if pod.Labels["k8s-app"] != app || !controlPlaneNodes[pod.Spec.NodeName] || !isPodReady(&pod) {
+				continue
+			}
+			running++
+		}
+		if running < len(etcdCluster.Members) {
+			return fmt.Errorf("%d of %d members of etcd cluster %q are running", running, len(etcdCluster.Members), etcdCluster.Name)
+		}
+	}
+
+	if restClient := c.K8sClient.Discovery().RESTClient(); restClient != nil {
+		if _, err := restClient.Get().AbsPath("/readyz/etcd").DoRaw(ctx); err != nil {
+			return fmt.Errorf("API server does not report etcd as ready: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		if !container.Ready {
+			return false
+		}
+	}
+	return true
+}
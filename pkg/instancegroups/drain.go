@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+// drainDeleteAfterAnnotation is the namespace annotation holding how long a drain tries to evict the pods of the namespace
+// before deleting them, which bypasses their PodDisruptionBudgets.
+const drainDeleteAfterAnnotation = "kops.k8s.io/drain-delete-after"
+
+var (
+	// drainInitialAttemptTimeout is how long the first attempt to drain a node waits for its pods to go away.
+	drainInitialAttemptTimeout = 30 * time.Second
+	// drainMaxAttemptTimeout is the maximum time any attempt to drain a node waits for its pods to go away.
+	drainMaxAttemptTimeout = 5 * time.Minute
+)
+
+// runNodeDrain drains a node, retrying with exponential backoff until DrainTimeout has elapsed.
+// Each attempt waits twice as long as the previous one for the pods to be evicted, up to drainMaxAttemptTimeout.
+// After each failed attempt it reports the pods and PodDisruptionBudgets blocking the drain,
+// and deletes the pods of namespaces whose drain-delete-after grace period has elapsed.
+func (c *RollingUpdateCluster) runNodeDrain(helper *drain.Helper, nodeName string) error {
+	if c.DrainTimeout <= 0 {
+		// Without a timeout, draining only returns errors that retrying does not fix
+		helper.Timeout = 0
+		return drain.RunNodeDrain(helper, nodeName)
+	}
+
+	start := time.Now()
+	attemptTimeout := drainInitialAttemptTimeout
+	for attempt := 1; ; attempt++ {
+		helper.Timeout = attemptTimeout
+		if remaining := c.DrainTimeout - time.Since(start); remaining < helper.Timeout {
+			helper.Timeout = remaining
+		}
+
+		attemptStart := time.Now()
+		err := drain.RunNodeDrain(helper, nodeName)
+		if err == nil || apierrors.IsNotFound(err) {
+			return err
+		}
+
+		pods, blockers := c.findDrainBlockers(helper, nodeName)
+		if c.Ctx.Err() != nil || time.Since(start) >= c.DrainTimeout {
+			if len(blockers) > 0 {
+				return fmt.Errorf("%v; drain blocked by %s", err, strings.Join(blockers, ", "))
+			}
+			return err
+		}
+
+		klog.Warningf("Attempt %d to drain node %q did not complete within %s: %v", attempt, nodeName, helper.Timeout, err)
+		for _, blocker := range blockers {
+			klog.Warningf("Drain of node %q is blocked by %s.", nodeName, blocker)
+		}
+
+		c.deleteOverduePods(helper, nodeName, pods, time.Since(start))
+
+		// Errors other than timeouts return early; wait out the attempt before retrying
+		if wait := helper.Timeout - time.Since(attemptStart); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		attemptTimeout *= 2
+		if attemptTimeout > drainMaxAttemptTimeout {
+			attemptTimeout = drainMaxAttemptTimeout
+		}
+	}
+}
+
+// findDrainBlockers returns the pods remaining on a node, along with descriptions of them
+// and the PodDisruptionBudgets that prevent their eviction.
+func (c *RollingUpdateCluster) findDrainBlockers(helper *drain.Helper, nodeName string) ([]corev1.Pod, []string) {
+	list, errs := helper.GetPodsForDeletion(nodeName)
+	if errs != nil {
+		klog.Warningf("error listing the pods remaining on node %q: %v", nodeName, errs)
+		return nil, nil
+	}
+
+	pods := list.Pods()
+	pdbsByNamespace := make(map[string][]policyv1.PodDisruptionBudget)
+	var blockers []string
+	for _, pod := range pods {
+		pdbs, found := pdbsByNamespace[pod.Namespace]
+		if !found {
+			pdbList, err := c.K8sClient.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(c.Ctx, metav1.ListOptions{})
+			if err != nil {
+				klog.Warningf("error listing the PodDisruptionBudgets of namespace %q: %v", pod.Namespace, err)
+			} else {
+				pdbs = pdbList.Items
+			}
+			pdbsByNamespace[pod.Namespace] = pdbs
+		}
+
+		blocker := fmt.Sprintf("pod %s/%s", pod.Namespace, pod.Name)
+		for _, pdb := range pdbs {
+			if pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			blocker += fmt.Sprintf(" (PodDisruptionBudget %q allows no disruptions, %d of %d desired pods healthy)", pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+		}
+		blockers = append(blockers, blocker)
+	}
+	return pods, blockers
+}
+
+// deleteOverduePods deletes the pods whose namespace has a drain-delete-after grace period shorter than the time spent draining.
+func (c *RollingUpdateCluster) deleteOverduePods(helper *drain.Helper, nodeName string, pods []corev1.Pod, elapsed time.Duration) {
+	gracePeriods := make(map[string]time.Duration)
+	for _, pod := range pods {
+		gracePeriod, found := gracePeriods[pod.Namespace]
+		if !found {
+			gracePeriod = c.drainDeleteAfter(pod.Namespace)
+			gracePeriods[pod.Namespace] = gracePeriod
+		}
+		if gracePeriod <= 0 || elapsed < gracePeriod {
+			continue
+		}
+
+		klog.Warningf("Deleting pod %s/%s on node %q, which could not be evicted within %s.", pod.Namespace, pod.Name, nodeName, gracePeriod)
+		if err := helper.DeletePod(pod); err != nil && !apierrors.IsNotFound(err) {
+			klog.Warningf("error deleting pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		c.drainSummary.addForcedDeletion(nodeName, pod)
+	}
+}
+
+// drainDeleteAfter returns the drain-delete-after grace period of a namespace, or 0 if it has none.
+func (c *RollingUpdateCluster) drainDeleteAfter(namespace string) time.Duration {
+	ns, err := c.K8sClient.CoreV1().Namespaces().Get(c.Ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.Warningf("error getting namespace %q: %v", namespace, err)
+		return 0
+	}
+	value, found := ns.Annotations[drainDeleteAfterAnnotation]
+	if !found {
+		return 0
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		klog.Warningf("ignoring invalid %s annotation %q on namespace %q: %v", drainDeleteAfterAnnotation, value, namespace, err)
+		return 0
+	}
+	return gracePeriod
+}
+
+// drainSummary collects the pods deleted and the drains that failed during a rolling update, to report them at its end.
+type drainSummary struct {
+	mutex           sync.Mutex
+	forcedDeletions []string
+	failures        []string
+}
+
+func (s *drainSummary) addForcedDeletion(nodeName string, pod corev1.Pod) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.forcedDeletions = append(s.forcedDeletions, fmt.Sprintf("pod %s/%s on node %q", pod.Namespace, pod.Name, nodeName))
+}
+
+func (s *drainSummary) addFailure(nodeName string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures = append(s.failures, fmt.Sprintf("node %q: %v", nodeName, err))
+}
+
+// log reports the pods deleted and the drains that failed.
+func (s *drainSummary) log() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.forcedDeletions) > 0 {
+		klog.Warningf("Deleted %d pods that could not be evicted:", len(s.forcedDeletions))
+		for _, deletion := range s.forcedDeletions {
+			klog.Warningf("  %s", deletion)
+		}
+	}
+	if len(s.failures) > 0 {
+		klog.Warningf("Failed to drain %d nodes:", len(s.failures))
+		for _, failure := range s.failures {
+			klog.Warningf("  %s", failure)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+func getDrainTestSetup(t *testing.T) (*RollingUpdateCluster, *drain.Helper) {
+	c, _ := getTestSetup()
+	c.Ctx = context.Background()
+
+	objects := []runtime.Object{
+		&v1.Namespace{ObjectMeta: v1meta.ObjectMeta{Name: "default"}},
+		&v1.Namespace{ObjectMeta: v1meta.ObjectMeta{Name: "batch", Annotations: map[string]string{drainDeleteAfterAnnotation: "1m"}}},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       v1.PodSpec{NodeName: "node-1a.local"},
+		},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "job-1", Namespace: "batch", Labels: map[string]string{"app": "job"}},
+			Spec:       v1.PodSpec{NodeName: "node-1a.local"},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &v1meta.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0, CurrentHealthy: 2, DesiredHealthy: 2},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: v1meta.ObjectMeta{Name: "job", Namespace: "batch"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &v1meta.LabelSelector{MatchLabels: map[string]string{"app": "job"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+		},
+	}
+	for _, object := range objects {
+		if err := c.K8sClient.(*fake.Clientset).Tracker().Add(object); err != nil {
+			t.Fatalf("error adding %v: %v", object, err)
+		}
+	}
+
+	helper := &drain.Helper{
+		Ctx:                 c.Ctx,
+		Client:              c.K8sClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		Out:                 os.Stdout,
+		ErrOut:              os.Stderr,
+	}
+	return c, helper
+}
+
+func TestFindDrainBlockers(t *testing.T) {
+	c, helper := getDrainTestSetup(t)
+
+	pods, blockers := c.findDrainBlockers(helper, "node-1a.local")
+	assert.Len(t, pods, 2)
+	assert.ElementsMatch(t, []string{
+		"pod batch/job-1",
+		"pod default/web-1 (PodDisruptionBudget \"web\" allows no disruptions, 2 of 2 desired pods healthy)",
+	}, blockers)
+}
+
+func TestDeleteOverduePods(t *testing.T) {
+	c, helper := getDrainTestSetup(t)
+
+	pods, _ := c.findDrainBlockers(helper, "node-1a.local")
+
+	c.deleteOverduePods(helper, "node-1a.local", pods, 30*time.Second)
+	assert.Empty(t, c.drainSummary.forcedDeletions, "no pod deleted before the grace period")
+
+	c.deleteOverduePods(helper, "node-1a.local", pods, 2*time.Minute)
+	assert.Equal(t, []string{"pod batch/job-1 on node \"node-1a.local\""}, c.drainSummary.forcedDeletions)
+
+	_, err := c.K8sClient.CoreV1().Pods("batch").Get(c.Ctx, "job-1", v1meta.GetOptions{})
+	assert.Error(t, err, "pod in annotated namespace deleted")
+	_, err = c.K8sClient.CoreV1().Pods("default").Get(c.Ctx, "web-1", v1meta.GetOptions{})
+	assert.NoError(t, err, "pod in other namespace not deleted")
+}
+
+func TestRunNodeDrainReportsBlockers(t *testing.T) {
+	defer func(initial time.Duration) { drainInitialAttemptTimeout = initial }(drainInitialAttemptTimeout)
+	drainInitialAttemptTimeout = 10 * time.Millisecond
+
+	c, helper := getDrainTestSetup(t)
+	c.DrainTimeout = 50 * time.Millisecond
+	// The fake clientset does not support eviction
+	helper.DisableEviction = true
+
+	attempts := 0
+	c.K8sClient.(*fake.Clientset).PrependReactor("delete", "pods", func(action testingclient.Action) (bool, runtime.Object, error) {
+		if action.(testingclient.DeleteAction).GetName() == "web-1" {
+			attempts++
+			return true, nil, errors.New("disruption budget exceeded")
+		}
+		return false, nil, nil
+	})
+
+	err := c.runNodeDrain(helper, "node-1a.local")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "drain blocked by pod default/web-1 (PodDisruptionBudget \"web\" allows no disruptions")
+	}
+	assert.Greater(t, attempts, 1, "drain retried")
+}
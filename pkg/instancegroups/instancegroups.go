@@ -110,6 +110,9 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	update := group.NeedUpdate
 	if c.Force {
 		update = append(update, group.Ready...)
+	} else {
+		// Up to date spot instances that are flagged for interruption or rebalance are replaced too, first
+		update = append(update[:len(update):len(update)], group.NoticedSpotInstances()...)
 	}
 
 	if len(update) == 0 {
@@ -160,10 +163,19 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		maxSurge = 0
 	}
 
+	// The blue/green strategy surges all the instances needing update before draining any of them,
+	// then drains and terminates MaxUnavailable of them at a time
+	blueGreen := settings.Strategy == api.RollingUpdateStrategyBlueGreen && maxSurge > 0 && !c.Interactive && !c.CloudOnly
+	if blueGreen {
+		maxConcurrency = settings.MaxUnavailable.IntValue()
+	}
+
+	controlPlaneSurge := false
 	if group.InstanceGroup.Spec.Role == api.InstanceGroupRoleControlPlane && maxSurge != 0 {
-		// Control plane nodes are incapable of surging because they rely on registering themselves through
+		// Control plane nodes cannot surge like other nodes because they rely on registering themselves through
 		// the local apiserver. That apiserver depends on the local etcd, which relies on being
-		// joined to the etcd cluster.
+		// joined to the etcd cluster. Instead, they are replaced one at a time, see rollingUpdateControlPlaneWithSurge.
+		controlPlaneSurge = !c.CloudOnly
 		maxSurge = 0
 		maxConcurrency = settings.MaxUnavailable.IntValue()
 		if maxConcurrency == 0 {
@@ -198,7 +210,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 				// If noneReady, wait until after one node is detached and its replacement validates
 				// before detaching more in case the current spec does not result in usable nodes.
-				if numSurge == maxSurge || noneReady {
+				if numSurge == maxSurge || (noneReady && !blueGreen) {
 					// Wait for the minimum interval
 					klog.Infof("waiting for %v after detaching instance", sleepAfterTerminate)
 					time.Sleep(sleepAfterTerminate)
@@ -217,9 +229,31 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		return nil
 	}
 
+	if controlPlaneSurge {
+		return c.rollingUpdateControlPlaneWithSurge(group, update, sleepAfterTerminate)
+	}
+
+	if blueGreen {
+		if err := c.waitForGreenNodes(group, update); err != nil {
+			return err
+		}
+		if err := c.cordonAll(group, update); err != nil {
+			return err
+		}
+	}
+
 	terminateChan := make(chan error, maxConcurrency)
 
+	// On-demand instances are not drained while the spot capacity of the group is degraded
+	checkSpotCapacity := !c.CloudOnly && hasSpotInstances(group)
+
 	for uIdx, u := range update {
+		if checkSpotCapacity && u.Lifecycle == cloudinstances.LifecycleOnDemand {
+			if err := c.waitForSpotCapacity(group, update); err != nil {
+				return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+			}
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
 			terminateChan <- c.drainTerminateAndWait(m, sleepAfterTerminate)
 		}(u)
@@ -296,17 +330,21 @@ func prioritizeUpdate(update []*cloudinstances.CloudInstance) []*cloudinstances.
 	// The priorities are, in order:
 	//   attached before detached
 	//   TODO unhealthy before healthy
+	//   with an interruption notice or rebalance recommendation before without
 	//   NeedUpdate before Ready (preserve original order)
 	result := make([]*cloudinstances.CloudInstance, 0, len(update))
-	var detached []*cloudinstances.CloudInstance
+	var remaining, detached []*cloudinstances.CloudInstance
 	for _, u := range update {
 		if u.Status == cloudinstances.CloudInstanceStatusDetached {
 			detached = append(detached, u)
-		} else {
+		} else if u.InterruptionNotice() != "" {
 			result = append(result, u)
+		} else {
+			remaining = append(remaining, u)
 		}
 	}
 
+	result = append(result, remaining...)
 	result = append(result, detached...)
 	return result
 }
@@ -431,6 +469,7 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 			klog.Infof("Draining the node: %q.", nodeName)
 
 			if err := c.drainNode(u); err != nil {
+				c.drainSummary.addFailure(nodeName, err)
 				if c.FailOnDrainError {
 					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
 				}
@@ -666,7 +705,6 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		IgnoreAllDaemonSets: true,
 		Out:                 os.Stdout,
 		ErrOut:              os.Stderr,
-		Timeout:             c.DrainTimeout,
 
 		// We want to proceed even when pods are using emptyDir volumes
 		DeleteEmptyDirData: true,
@@ -704,7 +742,7 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		}
 	}
 
-	if err := drain.RunNodeDrain(helper, u.Node.Name); err != nil {
+	if err := c.runNodeDrain(helper, u.Node.Name); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
@@ -85,6 +85,9 @@ type RollingUpdateCluster struct {
 
 	// Options holds user-specified options
 	Options RollingUpdateOptions
+
+	// drainSummary collects what happened while draining nodes
+	drainSummary drainSummary
 }
 
 type RollingUpdateOptions struct {
@@ -112,6 +115,8 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		return nil
 	}
 
+	defer c.drainSummary.log()
+
 	var resultsMutex sync.Mutex
 	results := make(map[string]error)
 
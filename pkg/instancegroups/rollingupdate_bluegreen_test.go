@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/awsinterfaces"
+)
+
+// greenCloud lists the instances being replaced as detached, along with a number of replacements that have ready nodes.
+type greenCloud struct {
+	*awsup.MockAWSCloud
+	blue   []string
+	green  int
+	listed int
+}
+
+func (c *greenCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	c.listed++
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		group := &cloudinstances.CloudInstanceGroup{HumanName: ig.Name, InstanceGroup: ig}
+		for _, id := range c.blue {
+			group.NewCloudInstance(id, cloudinstances.CloudInstanceStatusDetached, nil)
+		}
+		// Replacements become ready one listing at a time
+		for i := 0; i < c.green && i < c.listed; i++ {
+			group.NewCloudInstance(fmt.Sprintf("%s-green-%d", ig.Name, i), cloudinstances.CloudInstanceStatusUpToDate, &v1.Node{
+				Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}},
+			})
+		}
+		groups[ig.Name] = group
+	}
+	return groups, nil
+}
+
+// recordTerminate records the termination of instances in a list of events.
+type recordTerminate struct {
+	awsinterfaces.EC2API
+	events *[]string
+}
+
+func (r *recordTerminate) TerminateInstances(ctx context.Context, input *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
+	if input.DryRun == nil || !*input.DryRun {
+		for _, id := range input.InstanceIds {
+			*r.events = append(*r.events, "terminate "+id)
+		}
+	}
+	return r.EC2API.TerminateInstances(ctx, input, optFns...)
+}
+
+func getBlueGreenTestSetup() (*RollingUpdateCluster, *awsup.MockAWSCloud, *countDetach, *greenCloud, map[string]*cloudinstances.CloudInstanceGroup, *[]string) {
+	c, cloud := getTestSetup()
+	c.ValidationTimeout = time.Second
+
+	var events []string
+	countDetach := &countDetach{AutoScalingAPI: cloud.MockAutoscaling}
+	cloud.MockAutoscaling = countDetach
+	cloud.MockEC2 = &recordTerminate{EC2API: &ec2IgnoreTags{EC2API: cloud.MockEC2}, events: &events}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 4, 4)
+	groups["node-1"].TargetSize = 4
+	two := intstr.FromInt(2)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxUnavailable: &two,
+		Strategy:       kopsapi.RollingUpdateStrategyBlueGreen,
+	}
+
+	green := &greenCloud{MockAWSCloud: cloud, blue: []string{"node-1a", "node-1b", "node-1c", "node-1d"}, green: 4}
+	c.Cloud = green
+
+	c.K8sClient.(*fake.Clientset).PrependReactor("patch", "nodes", func(action testingclient.Action) (bool, runtime.Object, error) {
+		patchAction := action.(testingclient.PatchAction)
+		if string(patchAction.GetPatch()) == cordonPatch {
+			events = append(events, fmt.Sprintf("cordon %s after %d listings", strings.TrimSuffix(patchAction.GetName(), ".local"), green.listed))
+		}
+		return false, nil, nil
+	})
+
+	return c, cloud, countDetach, green, groups, &events
+}
+
+func TestRollingUpdateBlueGreen(t *testing.T) {
+	c, cloud, countDetach, _, groups, events := getBlueGreenTestSetup()
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 4, countDetach.Count, "instances detached")
+	if assert.Len(t, *events, 8, "events") {
+		// All the blue nodes are cordoned once all the green nodes are ready, before any is terminated
+		assert.ElementsMatch(t, []string{
+			"cordon node-1a after 4 listings",
+			"cordon node-1b after 4 listings",
+			"cordon node-1c after 4 listings",
+			"cordon node-1d after 4 listings",
+		}, (*events)[:4])
+		for _, event := range (*events)[4:] {
+			assert.True(t, strings.HasPrefix(event, "terminate "), "event %q", event)
+		}
+	}
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateBlueGreenNotReady(t *testing.T) {
+	c, cloud, countDetach, green, groups, events := getBlueGreenTestSetup()
+	c.ValidationTimeout = 10 * time.Millisecond
+	green.green = 0
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "did not have 4 ready replacement nodes")
+	}
+
+	assert.Equal(t, 4, countDetach.Count, "instances detached")
+	assert.Empty(t, *events, "no node cordoned or terminated")
+	assertGroupInstanceCount(t, cloud, "node-1", 4)
+}
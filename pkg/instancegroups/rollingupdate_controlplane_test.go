@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// replacingCloud lists a new instance in each group for each detached instance, as the cloud provider does.
+// Each replacement only shows up from the second listing after its instance was detached,
+// so that earlier replacements are still listed while a new one is pending.
+type replacingCloud struct {
+	*awsup.MockAWSCloud
+	detached     *countDetach
+	pending      bool
+	replacements int
+}
+
+func (c *replacingCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	if c.replacements < c.detached.Count {
+		if c.pending {
+			c.replacements++
+		}
+		c.pending = !c.pending
+	}
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		group := &cloudinstances.CloudInstanceGroup{HumanName: ig.Name, InstanceGroup: ig}
+		for i := 1; i <= c.replacements; i++ {
+			group.NewCloudInstance(fmt.Sprintf("%s-replacement-%d", ig.Name, i), cloudinstances.CloudInstanceStatusUpToDate, nil)
+		}
+		groups[ig.Name] = group
+	}
+	return groups, nil
+}
+
+func getControlPlaneSurgeTestSetup(etcdMembers int) (*RollingUpdateCluster, *awsup.MockAWSCloud, *countDetach, map[string]*cloudinstances.CloudInstanceGroup) {
+	c, cloud := getTestSetup()
+	c.ValidationTimeout = time.Second
+
+	countDetach := &countDetach{AutoScalingAPI: cloud.MockAutoscaling}
+	cloud.MockAutoscaling = countDetach
+	cloud.MockEC2 = &ec2IgnoreTags{EC2API: cloud.MockEC2}
+	c.Cloud = &replacingCloud{MockAWSCloud: cloud, detached: countDetach}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "master-1", kopsapi.InstanceGroupRoleControlPlane, 3, 2)
+	one := intstr.FromInt(1)
+	groups["master-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &one,
+	}
+
+	etcdCluster := kopsapi.EtcdClusterSpec{Name: "main"}
+	for i := 0; i < etcdMembers; i++ {
+		etcdCluster.Members = append(etcdCluster.Members, kopsapi.EtcdMemberSpec{Name: string(rune('a' + i)), InstanceGroup: fi.PtrTo("master-1")})
+	}
+	c.Cluster.Spec.EtcdClusters = []kopsapi.EtcdClusterSpec{etcdCluster}
+
+	tracker := c.K8sClient.(*fake.Clientset).Tracker()
+	for _, name := range []string{"master-1a.local", "master-1b.local", "master-1c.local"} {
+		_ = tracker.Update(v1.SchemeGroupVersion.WithResource("nodes"), &v1.Node{
+			ObjectMeta: v1meta.ObjectMeta{Name: name, Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""}},
+		}, "")
+		_ = tracker.Add(&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "etcd-manager-main-" + name, Namespace: "kube-system", Labels: map[string]string{"k8s-app": "etcd-manager-main"}},
+			Spec:       v1.PodSpec{NodeName: name},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "etcd-manager", Ready: true}},
+			},
+		})
+	}
+	c.K8sClient.(*fake.Clientset).ClearActions()
+
+	return c, cloud, countDetach, groups
+}
+
+func TestRollingUpdateControlPlaneSurge(t *testing.T) {
+	c, cloud, countDetach, groups := getControlPlaneSurgeTestSetup(3)
+
+	// Record the number of replacements created when each node is cordoned
+	var replacementsWhenCordoned []int
+	c.K8sClient.(*fake.Clientset).PrependReactor("patch", "nodes", func(action testingclient.Action) (bool, runtime.Object, error) {
+		if string(action.(testingclient.PatchAction).GetPatch()) == cordonPatch {
+			replacementsWhenCordoned = append(replacementsWhenCordoned, c.Cloud.(*replacingCloud).replacements)
+		}
+		return false, nil, nil
+	})
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 2, countDetach.Count, "instances detached")
+	assert.Equal(t, []int{1, 2}, replacementsWhenCordoned, "replacement created before cordoning each node")
+	assertGroupInstanceCount(t, cloud, "master-1", 1)
+}
+
+func TestRollingUpdateControlPlaneSurgeEtcdUnhealthy(t *testing.T) {
+	c, cloud, countDetach, groups := getControlPlaneSurgeTestSetup(5)
+	c.ValidationTimeout = 10 * time.Millisecond
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "3 of 5 members of etcd cluster")
+	}
+
+	assert.Equal(t, 0, countDetach.Count, "instances detached")
+	assertGroupInstanceCount(t, cloud, "master-1", 3)
+}
+
+func TestCheckEtcdMembersIgnoresOtherNodes(t *testing.T) {
+	c, _, _, _ := getControlPlaneSurgeTestSetup(3)
+
+	tracker := c.K8sClient.(*fake.Clientset).Tracker()
+	_ = tracker.Delete(v1.SchemeGroupVersion.WithResource("nodes"), "", "master-1c.local")
+	assert.EqualError(t, c.checkEtcdMembers(context.Background()), "2 of 3 members of etcd cluster \"main\" are running")
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const rebalanceTaint = "aws-node-termination-handler/rebalance-recommendation"
+
+// spotCloud lists an up to date spot instance in each group. Its node is not ready for the first listings,
+// and has a rebalance recommendation if noticed is set.
+type spotCloud struct {
+	*awsup.MockAWSCloud
+	notReadyListings int
+	noticed          bool
+	listed           int
+}
+
+func (c *spotCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	c.listed++
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		group := &cloudinstances.CloudInstanceGroup{HumanName: ig.Name, InstanceGroup: ig}
+		ready := v1.ConditionTrue
+		if c.listed <= c.notReadyListings {
+			ready = v1.ConditionFalse
+		}
+		node := &v1.Node{
+			ObjectMeta: v1meta.ObjectMeta{Name: ig.Name + "-spot.local"},
+			Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}}},
+		}
+		if c.noticed {
+			node.Spec.Taints = []v1.Taint{{Key: rebalanceTaint, Effect: v1.TaintEffectNoSchedule}}
+		}
+		instance, _ := group.NewCloudInstance(ig.Name+"-spot", cloudinstances.CloudInstanceStatusUpToDate, node)
+		instance.Lifecycle = cloudinstances.LifecycleSpot
+		groups[ig.Name] = group
+	}
+	return groups, nil
+}
+
+func getSpotTestSetup(notReadyListings int) (*RollingUpdateCluster, *awsup.MockAWSCloud, *spotCloud, map[string]*cloudinstances.CloudInstanceGroup) {
+	c, cloud := getTestSetup()
+	c.ValidationTimeout = time.Second
+	spot := &spotCloud{MockAWSCloud: cloud, notReadyListings: notReadyListings}
+	c.Cloud = spot
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 2, 1)
+	zero := intstr.FromInt(0)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &zero,
+	}
+	groups["node-1"].NeedUpdate[0].Lifecycle = cloudinstances.LifecycleOnDemand
+	groups["node-1"].Ready[0].Lifecycle = cloudinstances.LifecycleSpot
+
+	return c, cloud, spot, groups
+}
+
+func TestRollingUpdateOnDemandWaitsForSpotCapacity(t *testing.T) {
+	c, cloud, spot, groups := getSpotTestSetup(2)
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 3, spot.listed, "spot capacity checked until no longer degraded")
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+}
+
+func TestRollingUpdateOnDemandSpotCapacityDegraded(t *testing.T) {
+	c, cloud, _, groups := getSpotTestSetup(1000)
+	c.ValidationTimeout = 10 * time.Millisecond
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "spot capacity of group \"node-1\" still degraded")
+		assert.Contains(t, err.Error(), "has no ready node")
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateIgnoresNoticeOfSpotNotBeingReplaced(t *testing.T) {
+	c, cloud, spot, groups := getSpotTestSetup(0)
+	// Rebalance recommendations can last for the life of an instance
+	spot.noticed = true
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 1, spot.listed, "spot capacity checked once")
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+}
+
+func TestRollingUpdateReplacesNoticedSpotInstances(t *testing.T) {
+	c, cloud, _, groups := getSpotTestSetup(0)
+	groups["node-1"].Ready[0].Node.Spec.Taints = []v1.Taint{{Key: rebalanceTaint, Effect: v1.TaintEffectNoSchedule}}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateReplacesNoticedSpotInstancesWithoutOtherUpdates(t *testing.T) {
+	c, cloud, _, groups := getSpotTestSetup(0)
+	groups["node-1"].NeedUpdate = nil
+	groups["node-1"].Ready[0].Node.Spec.Taints = []v1.Taint{{Key: rebalanceTaint, Effect: v1.TaintEffectNoSchedule}}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+}
+
+func TestPrioritizeUpdateInterruptionNotice(t *testing.T) {
+	noticed := &cloudinstances.CloudInstance{
+		ID:   "noticed",
+		Node: &v1.Node{Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: rebalanceTaint}}}},
+	}
+	first := &cloudinstances.CloudInstance{ID: "first", Node: &v1.Node{}}
+	second := &cloudinstances.CloudInstance{ID: "second"}
+	detached := &cloudinstances.CloudInstance{
+		ID:     "detached",
+		Status: cloudinstances.CloudInstanceStatusDetached,
+		Node:   &v1.Node{Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: rebalanceTaint}}}},
+	}
+
+	result := prioritizeUpdate([]*cloudinstances.CloudInstance{detached, first, noticed, second})
+	var ids []string
+	for _, u := range result {
+		ids = append(ids, u.ID)
+	}
+	assert.Equal(t, []string{"noticed", "first", "second", "detached"}, ids)
+}
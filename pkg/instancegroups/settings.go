@@ -37,18 +37,36 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxUnavailable == nil {
 			rollingUpdate.MaxUnavailable = def.MaxUnavailable
 		}
-		if rollingUpdate.MaxSurge == nil {
+		// Control plane instance groups only surge when configured to on the instance group
+		if rollingUpdate.MaxSurge == nil && group.Spec.Role != kops.InstanceGroupRoleControlPlane {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.Strategy == "" && group.Spec.Role != kops.InstanceGroupRoleControlPlane {
+			rollingUpdate.Strategy = def.Strategy
+		}
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
 		rollingUpdate.DrainAndTerminate = fi.PtrTo(true)
 	}
 
+	if rollingUpdate.Strategy == "" {
+		rollingUpdate.Strategy = kops.RollingUpdateStrategyRolling
+	}
+
+	if rollingUpdate.Strategy == kops.RollingUpdateStrategyBlueGreen {
+		// Surge all the instances, then replace MaxUnavailable of them at a time
+		surge := intstr.FromInt(numInstances)
+		rollingUpdate.MaxSurge = &surge
+		if rollingUpdate.MaxUnavailable == nil || (rollingUpdate.MaxUnavailable.Type == intstr.Int && rollingUpdate.MaxUnavailable.IntVal == 0) {
+			unavailable := intstr.FromInt(1)
+			rollingUpdate.MaxUnavailable = &unavailable
+		}
+	}
+
 	if rollingUpdate.MaxSurge == nil {
 		val := intstr.FromInt(0)
-		if cluster.GetCloudProvider() == kops.CloudProviderAWS && !featureflag.Spotinst.Enabled() && group.Spec.Manager != kops.InstanceManagerKarpenter {
+		if cluster.GetCloudProvider() == kops.CloudProviderAWS && !featureflag.Spotinst.Enabled() && group.Spec.Manager != kops.InstanceManagerKarpenter && group.Spec.Role != kops.InstanceGroupRoleControlPlane {
 			val = intstr.FromInt(1)
 		}
 		rollingUpdate.MaxSurge = &val
@@ -223,3 +223,77 @@ func TestAWSDefault(t *testing.T) {
 	assert.Equal(t, intstr.Int, resolved.MaxUnavailable.Type)
 	assert.Equal(t, int32(0), resolved.MaxUnavailable.IntVal)
 }
+
+func TestControlPlaneSurgeDefault(t *testing.T) {
+	surge := intstr.FromInt(1)
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{},
+			},
+			RollingUpdate: &kops.RollingUpdate{
+				MaxSurge: &surge,
+			},
+		},
+	}
+
+	resolved := resolveSettings(cluster, &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleControlPlane,
+		},
+	}, 1)
+	assert.Equal(t, int32(0), resolved.MaxSurge.IntVal, "cluster-wide MaxSurge does not apply to the control plane")
+	assert.Equal(t, int32(1), resolved.MaxUnavailable.IntVal)
+
+	resolved = resolveSettings(cluster, &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleControlPlane,
+			RollingUpdate: &kops.RollingUpdate{
+				MaxSurge: &surge,
+			},
+		},
+	}, 1)
+	assert.Equal(t, int32(1), resolved.MaxSurge.IntVal, "instance group MaxSurge applies to the control plane")
+	assert.Equal(t, int32(0), resolved.MaxUnavailable.IntVal)
+}
+
+func TestBlueGreen(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{},
+			},
+			RollingUpdate: &kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyBlueGreen,
+			},
+		},
+	}
+
+	resolved := resolveSettings(cluster, &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+		},
+	}, 5)
+	assert.Equal(t, kops.RollingUpdateStrategyBlueGreen, resolved.Strategy)
+	assert.Equal(t, int32(5), resolved.MaxSurge.IntVal, "surges all instances")
+	assert.Equal(t, int32(1), resolved.MaxUnavailable.IntVal)
+
+	unavailable := intstr.FromString("40%")
+	resolved = resolveSettings(cluster, &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+			RollingUpdate: &kops.RollingUpdate{
+				MaxUnavailable: &unavailable,
+			},
+		},
+	}, 5)
+	assert.Equal(t, int32(5), resolved.MaxSurge.IntVal, "surges all instances")
+	assert.Equal(t, int32(2), resolved.MaxUnavailable.IntVal)
+
+	resolved = resolveSettings(cluster, &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleControlPlane,
+		},
+	}, 3)
+	assert.Equal(t, kops.RollingUpdateStrategyRolling, resolved.Strategy, "cluster-wide Strategy does not apply to the control plane")
+}
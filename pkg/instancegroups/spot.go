@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// hasSpotInstances returns true if any of the instances of the group is a spot instance.
+func hasSpotInstances(group *cloudinstances.CloudInstanceGroup) bool {
+	for _, instances := range [][]*cloudinstances.CloudInstance{group.Ready, group.NeedUpdate} {
+		for _, instance := range instances {
+			if instance.Lifecycle == cloudinstances.LifecycleSpot {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// waitForSpotCapacity waits until every spot instance of the group has a ready node and none of the spot instances
+// being replaced by this update is still waiting for its interruption notice or rebalance recommendation to be handled,
+// so that on-demand capacity is not drained while the spot capacity is degraded.
+func (c *RollingUpdateCluster) waitForSpotCapacity(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) error {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	replacing := make(map[string]bool)
+	for _, u := range update {
+		replacing[u.ID] = true
+	}
+
+	for {
+		degraded, err := c.findDegradedSpotInstances(ctx, group, replacing)
+		if err != nil {
+			klog.Warningf("error checking the spot instances of group %q: %v", group.HumanName, err)
+		} else if len(degraded) == 0 {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			if err != nil {
+				return fmt.Errorf("unable to check the spot capacity of group %q within %s: %w", group.HumanName, c.ValidationTimeout, err)
+			}
+			return fmt.Errorf("spot capacity of group %q still degraded after %s: %s", group.HumanName, c.ValidationTimeout, strings.Join(degraded, ", "))
+		}
+		if err == nil {
+			klog.Infof("Not draining on-demand instances of group %q while its spot capacity is degraded: %s.", group.HumanName, strings.Join(degraded, ", "))
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.ValidateTickDuration):
+		}
+	}
+}
+
+// findDegradedSpotInstances describes the spot instances of the group that have no ready node, and those being replaced
+// that have received an interruption notice or rebalance recommendation. Notices on the other instances are ignored,
+// as rebalance recommendations can last for the life of an instance. Detached instances are being replaced, so they are ignored.
+func (c *RollingUpdateCluster) findDegradedSpotInstances(ctx context.Context, group *cloudinstances.CloudInstanceGroup, replacing map[string]bool) ([]string, error) {
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	cloudGroups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var degraded []string
+	for _, cloudGroup := range cloudGroups {
+		if cloudGroup.InstanceGroup != group.InstanceGroup {
+			continue
+		}
+		var instances []*cloudinstances.CloudInstance
+		instances = append(instances, cloudGroup.Ready...)
+		instances = append(instances, cloudGroup.NeedUpdate...)
+		for _, instance := range instances {
+			if instance.Lifecycle != cloudinstances.LifecycleSpot || instance.Status == cloudinstances.CloudInstanceStatusDetached {
+				continue
+			}
+			if notice := instance.InterruptionNotice(); notice != "" && replacing[instance.ID] {
+				degraded = append(degraded, fmt.Sprintf("instance %q has received a %s notice", instance.ID, notice))
+			} else if instance.Node == nil || !isNodeReady(instance.Node) {
+				degraded = append(degraded, fmt.Sprintf("instance %q has no ready node", instance.ID))
+			}
+		}
+	}
+	return degraded, nil
+}
@@ -65,6 +65,9 @@ var _ fi.CloudupModelBuilder = &AutoscalingGroupModelBuilder{}
 // Build is responsible for constructing the aws autoscaling group from the kops spec
 func (b *AutoscalingGroupModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
+		// The lifecycle overrides of the instance group apply to the tasks built for it
+		c := c.WithLifecycleOverrideRules(fi.LifecycleOverrideRulesFromSpec(ig.Spec.LifecycleOverrides))
+
 		name := b.AutoscalingGroupName(ig)
 
 		if featureflag.SpotinstHybrid.Enabled() {
@@ -227,8 +227,14 @@ func (b *BastionModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		}
 	}
 
+	sshAccessVariable := &awstasks.CIDRsVariable{
+		Name:        "ssh_access",
+		Description: "CIDRs allowed to SSH to the instances",
+		CIDRs:       b.Cluster.Spec.SSHAccess,
+	}
+	numSubnetCIDRs := len(sshAllowedCIDRs)
 	sshAllowedCIDRs = append(sshAllowedCIDRs, b.Cluster.Spec.SSHAccess...)
-	for _, cidr := range sshAllowedCIDRs {
+	for i, cidr := range sshAllowedCIDRs {
 		// Allow incoming SSH traffic to the NLB
 		// TODO: Could we get away without an NLB here?  Tricky to fix if dns-controller breaks though...
 		{
@@ -240,6 +246,9 @@ func (b *BastionModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 				FromPort:      fi.PtrTo(int32(22)),
 				ToPort:        fi.PtrTo(int32(22)),
 			}
+			if i >= numSubnetCIDRs {
+				t.CIDRsVariable = sshAccessVariable
+			}
 			t.SetCidrOrPrefix(cidr)
 			AddDirectionalGroupRule(c, t)
 		}
@@ -59,6 +59,11 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.CloudupModelBuilderContext) err
 		// But I think we can always add more permissions in this case later, but we can't easily take them away
 		klog.V(2).Infof("bastion is in use; won't configure SSH access to control-plane / worker node instances")
 	} else {
+		sshAccessVariable := &awstasks.CIDRsVariable{
+			Name:        "ssh_access",
+			Description: "CIDRs allowed to SSH to the instances",
+			CIDRs:       b.Cluster.Spec.SSHAccess,
+		}
 		for _, sshAccess := range b.Cluster.Spec.SSHAccess {
 			for _, masterGroup := range masterGroups {
 				suffix := masterGroup.Suffix
@@ -69,6 +74,7 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.CloudupModelBuilderContext) err
 					Protocol:      fi.PtrTo("tcp"),
 					FromPort:      fi.PtrTo(int32(22)),
 					ToPort:        fi.PtrTo(int32(22)),
+					CIDRsVariable: sshAccessVariable,
 				}
 				t.SetCidrOrPrefix(sshAccess)
 				AddDirectionalGroupRule(c, t)
@@ -83,6 +89,7 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.CloudupModelBuilderContext) err
 					Protocol:      fi.PtrTo("tcp"),
 					FromPort:      fi.PtrTo(int32(22)),
 					ToPort:        fi.PtrTo(int32(22)),
+					CIDRsVariable: sshAccessVariable,
 				}
 				t.SetCidrOrPrefix(sshAccess)
 				AddDirectionalGroupRule(c, t)
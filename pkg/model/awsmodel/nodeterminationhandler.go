@@ -78,6 +78,8 @@ type NodeTerminationHandlerBuilder struct {
 func (b *NodeTerminationHandlerBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
 		if ig.Spec.Manager == kops.InstanceManagerCloudGroup {
+			// The lifecycle overrides of the instance group apply to its lifecycle hook
+			c := c.WithLifecycleOverrideRules(fi.LifecycleOverrideRulesFromSpec(ig.Spec.LifecycleOverrides))
 			err := b.configureASG(c, ig)
 			if err != nil {
 				return err
@@ -160,6 +160,9 @@ func (b *SpotInstanceGroupModelBuilder) Build(c *fi.CloudupModelBuilderContext)
 	var err error
 
 	for _, ig := range b.InstanceGroups {
+		// The lifecycle overrides of the instance group apply to the tasks built for it
+		c := c.WithLifecycleOverrideRules(fi.LifecycleOverrideRulesFromSpec(ig.Spec.LifecycleOverrides))
+
 		name := b.AutoscalingGroupName(ig)
 
 		if featureflag.SpotinstHybrid.Enabled() {
@@ -17,7 +17,6 @@ limitations under the License.
 package model
 
 import (
-	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"sort"
@@ -117,7 +116,10 @@ func (b *BootstrapScript) kubeEnv(ig *kops.InstanceGroup, c *fi.CloudupContext)
 	if err != nil {
 		return nil, fmt.Errorf("error converting nodeup config to yaml: %v", err)
 	}
-	sum256 := sha256.Sum256(configData)
+	sum256, err := nodeup.HashConfig(config)
+	if err != nil {
+		return nil, err
+	}
 	bootConfig.NodeupConfigHash = base64.StdEncoding.EncodeToString(sum256[:])
 	b.nodeupConfig.Resource = fi.NewBytesResource(configData)
 
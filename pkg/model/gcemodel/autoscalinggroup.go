@@ -281,6 +281,9 @@ func (b *AutoscalingGroupModelBuilder) splitToZones(ig *kops.InstanceGroup) (map
 
 func (b *AutoscalingGroupModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
+		// The lifecycle overrides of the instance group apply to the tasks built for it
+		c := c.WithLifecycleOverrideRules(fi.LifecycleOverrideRulesFromSpec(ig.Spec.LifecycleOverrides))
+
 		subnets, err := b.GatherSubnets(ig)
 		if err != nil {
 			return err
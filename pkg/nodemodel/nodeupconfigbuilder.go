@@ -207,6 +207,23 @@ func NewNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 	return &configBuilder, nil
 }
 
+// buildAssetVerification returns the signatures nodeup verifies the assets with before installing them.
+func (n *nodeUpConfigBuilder) buildAssetVerification() *nodeup.AssetVerificationConfig {
+	verification := &nodeup.AssetVerificationConfig{
+		Signatures: make(map[string]string),
+	}
+	for _, assetsByArch := range []map[architectures.Architecture][]*assets.MirroredAsset{n.assets, n.protokubeAsset, n.channelsAsset} {
+		for _, archAssets := range assetsByArch {
+			for _, a := range archAssets {
+				if a.Hash != nil && a.Signature != "" {
+					verification.Signatures[a.Hash.Hex()] = a.Signature
+				}
+			}
+		}
+	}
+	return verification
+}
+
 // BuildConfig returns the NodeUp config and auxiliary config.
 func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, wellKnownAddresses model.WellKnownAddresses, keysets map[string]*fi.Keyset) (*nodeup.Config, *nodeup.BootConfig, error) {
 	cluster := n.cluster
@@ -310,6 +327,11 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, wellKnownAddre
 		}
 	}
 
+	if cluster.Spec.Assets != nil && cluster.Spec.Assets.Verification != nil && cluster.Spec.Assets.Verification.Files {
+		config.AssetVerification = n.buildAssetVerification()
+		bootConfig.AssetVerificationPublicKey = cluster.Spec.Assets.Verification.PublicKey
+	}
+
 	if hasAPIServer {
 		config.ApiserverAdditionalIPs = wellKnownAddresses[wellknownservices.KubeAPIServer]
 	}
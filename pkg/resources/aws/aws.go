@@ -593,6 +593,7 @@ func ListVolumes(cloud fi.Cloud, vpcID, clusterName string) ([]*resources.Resour
 			Type:    "volume",
 			Deleter: DeleteVolume,
 			Shared:  HasSharedTag(string(ec2types.ResourceTypeVolume)+":"+id, volume.Tags, clusterName),
+			Obj:     volume,
 		}
 
 		var blocks []string
@@ -1273,6 +1274,9 @@ func ListAutoScalingGroups(cloud fi.Cloud, vpcID, clusterName string) ([]*resour
 		if asg.LaunchTemplate != nil {
 			blocks = append(blocks, TypeAutoscalingLaunchConfig+":"+aws.ToString(asg.LaunchTemplate.LaunchTemplateName))
 		}
+		for _, arn := range asg.TargetGroupARNs {
+			blocks = append(blocks, TypeTargetGroup+":"+arn)
+		}
 
 		resourceTracker.Blocks = blocks
 
@@ -1659,6 +1663,13 @@ func ListTargetGroups(cloud fi.Cloud, vpcID, clusterName string) ([]*resources.R
 			Obj:     tg,
 		}
 
+		// The target group can only be deleted once the listeners of its load balancers are gone
+		var blocked []string
+		for _, arn := range tg.LoadBalancerArns {
+			blocked = append(blocked, TypeLoadBalancer+":"+arn)
+		}
+		resourceTracker.Blocked = blocked
+
 		resourceTrackers = append(resourceTrackers, resourceTracker)
 	}
 	return resourceTrackers, nil
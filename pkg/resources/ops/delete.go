@@ -29,6 +29,15 @@ import (
 
 // DeleteResources deletes the resources, as previously collected by ListResources
 func DeleteResources(cloud fi.Cloud, resourceMap map[string]*resources.Resource, count int, interval, wait time.Duration) error {
+	return deleteResources(cloud, resourceMap, count, interval, wait, nil)
+}
+
+// DeleteResourcesTracked deletes the resources like DeleteResources, recording each deletion in the manifest.
+func DeleteResourcesTracked(cloud fi.Cloud, resourceMap map[string]*resources.Resource, count int, interval, wait time.Duration, manifest *DeletionManifest) error {
+	return deleteResources(cloud, resourceMap, count, interval, wait, manifest.RecordDeleted)
+}
+
+func deleteResources(cloud fi.Cloud, resourceMap map[string]*resources.Resource, count int, interval, wait time.Duration, onDeleted func(r *resources.Resource)) error {
 	depMap := make(map[string][]string)
 
 	done := make(map[string]*resources.Resource)
@@ -148,6 +157,9 @@ func DeleteResources(cloud fi.Cloud, resourceMap map[string]*resources.Resource,
 							k := t.Type + ":" + t.ID
 							delete(failed, k)
 							done[k] = t
+							if onDeleted != nil {
+								onDeleted(t)
+							}
 						}
 						mutex.Unlock()
 					}
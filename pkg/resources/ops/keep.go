@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/etcdsnapshots"
+	"k8s.io/kops/pkg/resources"
+	awsresources "k8s.io/kops/pkg/resources/aws"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// KeepVPC keeps the network of the cluster: the VPC, its subnets, route tables and gateways.
+	KeepVPC = "vpc"
+	// KeepDNS keeps the DNS records of the cluster.
+	KeepDNS = "dns"
+	// KeepEtcdVolumes snapshots the etcd volumes before they are deleted.
+	KeepEtcdVolumes = "etcd-volumes"
+)
+
+// KeepFilters are the supported filters for resources to preserve when deleting a cluster.
+var KeepFilters = []string{KeepVPC, KeepDNS, KeepEtcdVolumes}
+
+// keptTypes are the types of resources kept by each filter, on each cloud.
+var keptTypes = map[string][]string{
+	KeepVPC: {
+		// AWS
+		string(ec2types.ResourceTypeVpc),
+		string(ec2types.ResourceTypeSubnet),
+		string(ec2types.ResourceTypeRouteTable),
+		"internet-gateway",
+		"egress-only-internet-gateway",
+		awsresources.TypeNatGateway,
+		awsresources.TypeElasticIp,
+		"dhcp-options",
+		// GCE
+		"Network",
+		"Subnet",
+		"Router",
+	},
+	KeepDNS: {
+		// AWS
+		"route53-record",
+		// GCE
+		"DNSRecord",
+	},
+}
+
+// ValidateKeepFilters checks that the keep filters are supported on the cloud.
+func ValidateKeepFilters(cloud fi.Cloud, keep []string) error {
+	for _, k := range keep {
+		if !slices.Contains(KeepFilters, k) {
+			return fmt.Errorf("unknown keep filter %q, supported filters are %s", k, strings.Join(KeepFilters, ", "))
+		}
+		if k == KeepEtcdVolumes && cloud.ProviderID() != kops.CloudProviderAWS {
+			return fmt.Errorf("keeping etcd volumes as snapshots on %q is not (yet) supported", cloud.ProviderID())
+		}
+	}
+	return nil
+}
+
+// KeepResources removes the resources selected by the keep filters from resourceMap, and returns them.
+// Resources that cannot be deleted while a kept resource exists are kept too, e.g. the VPC of a kept subnet.
+func KeepResources(resourceMap map[string]*resources.Resource, keep []string) map[string]*resources.Resource {
+	types := make(map[string]bool)
+	for _, k := range keep {
+		for _, t := range keptTypes[k] {
+			types[t] = true
+		}
+	}
+
+	kept := make(map[string]*resources.Resource)
+	for k, r := range resourceMap {
+		if types[r.Type] {
+			kept[k] = r
+		}
+	}
+
+	resolve := resourceKeyResolver(resourceMap)
+	for {
+		changed := false
+		for k, r := range resourceMap {
+			if _, found := kept[k]; found {
+				continue
+			}
+			for _, blocked := range r.Blocked {
+				if _, found := kept[resolve(blocked)]; found {
+					klog.V(2).Infof("keeping %s, as it cannot be deleted before %s", k, blocked)
+					kept[k] = r
+					changed = true
+					break
+				}
+			}
+		}
+		for k, r := range kept {
+			for _, block := range r.Blocks {
+				blocked := resolve(block)
+				if _, found := kept[blocked]; found {
+					continue
+				}
+				if b, found := resourceMap[blocked]; found {
+					klog.V(2).Infof("keeping %s, as it cannot be deleted before %s", blocked, k)
+					kept[blocked] = b
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	for k := range kept {
+		delete(resourceMap, k)
+	}
+	return kept
+}
+
+// SnapshotEtcdVolumes makes the etcd volumes in resourceMap snapshot themselves before they are deleted.
+// The snapshots are recorded in the manifest, so that a resumed deletion does not snapshot a volume twice.
+func SnapshotEtcdVolumes(cloud fi.Cloud, resourceMap map[string]*resources.Resource, manifest *DeletionManifest) error {
+	if cloud.ProviderID() != kops.CloudProviderAWS {
+		return fmt.Errorf("keeping etcd volumes as snapshots on %q is not (yet) supported", cloud.ProviderID())
+	}
+	snapshotter, err := etcdsnapshots.NewSnapshotter(cloud)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range resourceMap {
+		if r.Type != "volume" || !isEtcdVolume(r) {
+			continue
+		}
+		deleter := r.Deleter
+		r.Deleter = func(cloud fi.Cloud, r *resources.Resource) error {
+			if manifest.SnapshotID(r) == "" {
+				// The volume must be detached, so that the snapshot is not taken while it is being written to
+				snapshot, err := snapshotter.SnapshotVolume(context.TODO(), r.ID, etcdsnapshots.ReasonDeleteCluster, true)
+				if err != nil {
+					return err
+				}
+				klog.Infof("Created snapshot %s of volume %s", snapshot.ID, r.ID)
+				manifest.RecordSnapshot(r, snapshot.ID)
+			}
+			return deleter(cloud, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/pkg/resources"
+	awsresources "k8s.io/kops/pkg/resources/aws"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestKeepResources(t *testing.T) {
+	resourceMap := map[string]*resources.Resource{}
+	for _, r := range []*resources.Resource{
+		{Type: "vpc", ID: "vpc-1"},
+		{Type: "subnet", ID: "subnet-1", Blocks: []string{"vpc:vpc-1"}},
+		{Type: awsresources.TypeNatGateway, ID: "nat-1", Blocks: []string{awsresources.TypeElasticIp + ":eipalloc-1"}},
+		{Type: awsresources.TypeElasticIp, ID: "eipalloc-1"},
+		// Blocks a kept resource, so can still be deleted
+		{Type: "instance", ID: "i-1", Blocks: []string{"subnet:subnet-1"}},
+		// Must be deleted before a kept resource
+		{Type: "security-group", ID: "sg-1", Blocks: []string{"vpc:vpc-1"}},
+		{Type: "internet-gateway", ID: "igw-1"},
+		// Cannot be deleted before a kept resource, so is kept too
+		{Type: "keypair", ID: "key-1", Blocked: []string{"internet-gateway:igw-1"}},
+		{Type: "route53-record", ID: "Z1/A/api.example.com"},
+	} {
+		resourceMap[r.Type+":"+r.ID] = r
+	}
+
+	kept := KeepResources(resourceMap, []string{KeepVPC})
+
+	expectedKept := []string{
+		awsresources.TypeElasticIp + ":eipalloc-1",
+		"internet-gateway:igw-1",
+		"keypair:key-1",
+		awsresources.TypeNatGateway + ":nat-1",
+		"subnet:subnet-1",
+		"vpc:vpc-1",
+	}
+	if actual := sortedKeys(kept); !reflect.DeepEqual(actual, expectedKept) {
+		t.Errorf("unexpected kept resources, expected %v, got %v", expectedKept, actual)
+	}
+	expectedDeleted := []string{
+		"instance:i-1",
+		"route53-record:Z1/A/api.example.com",
+		"security-group:sg-1",
+	}
+	if actual := sortedKeys(resourceMap); !reflect.DeepEqual(actual, expectedDeleted) {
+		t.Errorf("unexpected resources to delete, expected %v, got %v", expectedDeleted, actual)
+	}
+}
+
+func TestSnapshotEtcdVolumes(t *testing.T) {
+	ctx := context.TODO()
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	etcdTags := []ec2types.Tag{
+		{Key: fi.PtrTo("k8s.io/etcd/main"), Value: fi.PtrTo("a/a")},
+		{Key: fi.PtrTo("Name"), Value: fi.PtrTo("a.etcd-main.example.com")},
+	}
+	resourceMap := map[string]*resources.Resource{}
+	for _, name := range []string{"a.etcd-main.example.com", "pvc-1234"} {
+		var tags []ec2types.Tag
+		if name != "pvc-1234" {
+			tags = etcdTags
+		}
+		volume, err := c.CreateVolume(ctx, &ec2.CreateVolumeInput{
+			Size: fi.PtrTo(int32(20)),
+			TagSpecifications: []ec2types.TagSpecification{
+				{ResourceType: ec2types.ResourceTypeVolume, Tags: tags},
+			},
+		})
+		if err != nil {
+			t.Fatalf("error creating volume: %v", err)
+		}
+		id := fi.ValueOf(volume.VolumeId)
+		resourceMap["volume:"+id] = &resources.Resource{
+			Type:    "volume",
+			ID:      id,
+			Name:    name,
+			Deleter: awsresources.DeleteVolume,
+			Obj:     ec2types.Volume{VolumeId: volume.VolumeId, Tags: tags},
+		}
+	}
+
+	memfs := vfs.NewMemFSContext()
+	path := vfs.NewMemFSPath(memfs, "memfs://state/example.com/deletion.yaml")
+	manifest := NewDeletionManifest(path, "example.com", []string{KeepEtcdVolumes})
+	if err := manifest.AddResources(resourceMap, DeletionPending); err != nil {
+		t.Fatalf("error recording resources: %v", err)
+	}
+	if err := SnapshotEtcdVolumes(cloud, resourceMap, manifest); err != nil {
+		t.Fatalf("error wrapping volumes: %v", err)
+	}
+	if err := DeleteResourcesTracked(cloud, resourceMap, 1, time.Millisecond, time.Minute, manifest); err != nil {
+		t.Fatalf("error deleting resources: %v", err)
+	}
+
+	if len(c.Volumes) != 0 {
+		t.Errorf("expected all volumes to be deleted, got %v", c.Volumes)
+	}
+	if len(c.Snapshots) != 1 {
+		t.Fatalf("expected a single snapshot, got %v", c.Snapshots)
+	}
+
+	// The manifest can be read back, with the progress of the deletion
+	read, err := ReadDeletionManifest(ctx, path)
+	if err != nil {
+		t.Fatalf("error reading manifest: %v", err)
+	}
+	var snapshots []string
+	for _, r := range read.Resources {
+		if r.Status != DeletionDone {
+			t.Errorf("expected %s to be deleted, got %q", r.ID, r.Status)
+		}
+		if r.SnapshotID != "" {
+			snapshots = append(snapshots, r.Name+"="+r.SnapshotID)
+		}
+	}
+	if expected := []string{"a.etcd-main.example.com=snap-1"}; !reflect.DeepEqual(snapshots, expected) {
+		t.Errorf("unexpected snapshots, expected %v, got %v", expected, snapshots)
+	}
+	if !reflect.DeepEqual(read.Keep, []string{KeepEtcdVolumes}) {
+		t.Errorf("unexpected keep filters %v", read.Keep)
+	}
+}
+
+func TestReadDeletionManifestNotFound(t *testing.T) {
+	memfs := vfs.NewMemFSContext()
+	manifest, err := ReadDeletionManifest(context.TODO(), vfs.NewMemFSPath(memfs, "memfs://state/example.com/deletion.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest != nil {
+		t.Errorf("expected no manifest, got %v", manifest)
+	}
+}
+
+func sortedKeys(m map[string]*resources.Resource) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DeletionPending is the status of a resource that has not been deleted yet.
+	DeletionPending = "pending"
+	// DeletionDone is the status of a resource that has been deleted.
+	DeletionDone = "deleted"
+	// DeletionKept is the status of a resource that is kept by a keep filter.
+	DeletionKept = "kept"
+)
+
+// DeletionManifest records the progress of deleting the resources of a cluster,
+// so that an interrupted deletion can be resumed, and audited.
+type DeletionManifest struct {
+	// ClusterName is the name of the cluster being deleted.
+	ClusterName string `json:"clusterName"`
+	// StartedAt is the time the deletion was first started.
+	StartedAt time.Time `json:"startedAt"`
+	// CompletedAt is the time all the cloud resources were deleted.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	// Keep are the keep filters the deletion was started with.
+	Keep []string `json:"keep,omitempty"`
+	// Resources are the resources of the cluster, and the progress deleting each one.
+	Resources []*DeletionManifestResource `json:"resources,omitempty"`
+
+	mutex sync.Mutex
+	// path is where the manifest is persisted; the manifest is not persisted if nil.
+	path vfs.Path
+}
+
+// DeletionManifestResource is the progress deleting a single resource.
+type DeletionManifestResource struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status"`
+	// SnapshotID is the ID of the snapshot taken of a volume before it was deleted.
+	SnapshotID string `json:"snapshotID,omitempty"`
+}
+
+// NewDeletionManifest builds a manifest for starting the deletion of a cluster, which is persisted to path if not nil.
+func NewDeletionManifest(path vfs.Path, clusterName string, keep []string) *DeletionManifest {
+	return &DeletionManifest{
+		ClusterName: clusterName,
+		StartedAt:   time.Now().UTC(),
+		Keep:        keep,
+		path:        path,
+	}
+}
+
+// ReadDeletionManifest reads the manifest of an interrupted deletion from path, returning nil if there is none.
+func ReadDeletionManifest(ctx context.Context, path vfs.Path) (*DeletionManifest, error) {
+	b, err := path.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading deletion manifest %s: %w", path, err)
+	}
+	manifest := &DeletionManifest{path: path}
+	if err := yaml.Unmarshal(b, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing deletion manifest %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// AddResources records the resources with the status, keeping the status of resources already deleted.
+func (m *DeletionManifest) AddResources(resourceMap map[string]*resources.Resource, status string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, r := range resourceMap {
+		entry := m.find(r)
+		if entry == nil {
+			entry = &DeletionManifestResource{
+				Type: r.Type,
+				ID:   r.ID,
+				Name: r.Name,
+			}
+			m.Resources = append(m.Resources, entry)
+		}
+		if entry.Status != DeletionDone {
+			entry.Status = status
+		}
+	}
+	sort.Slice(m.Resources, func(i, j int) bool {
+		if m.Resources[i].Type != m.Resources[j].Type {
+			return m.Resources[i].Type < m.Resources[j].Type
+		}
+		return m.Resources[i].ID < m.Resources[j].ID
+	})
+	return m.write()
+}
+
+// RecordDeleted records that the resource has been deleted.
+func (m *DeletionManifest) RecordDeleted(r *resources.Resource) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if entry := m.find(r); entry != nil {
+		entry.Status = DeletionDone
+	}
+	if err := m.write(); err != nil {
+		klog.Warningf("%v", err)
+	}
+}
+
+// RecordSnapshot records the snapshot taken of the resource.
+func (m *DeletionManifest) RecordSnapshot(r *resources.Resource, snapshotID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if entry := m.find(r); entry != nil {
+		entry.SnapshotID = snapshotID
+	}
+	if err := m.write(); err != nil {
+		klog.Warningf("%v", err)
+	}
+}
+
+// SnapshotID returns the ID of the snapshot taken of the resource, or "" if there is none.
+func (m *DeletionManifest) SnapshotID(r *resources.Resource) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if entry := m.find(r); entry != nil {
+		return entry.SnapshotID
+	}
+	return ""
+}
+
+// Complete records that all the cloud resources have been deleted.
+func (m *DeletionManifest) Complete() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now().UTC()
+	m.CompletedAt = &now
+	return m.write()
+}
+
+func (m *DeletionManifest) find(r *resources.Resource) *DeletionManifestResource {
+	for _, entry := range m.Resources {
+		if entry.Type == r.Type && entry.ID == r.ID {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (m *DeletionManifest) write() error {
+	if m.path == nil {
+		return nil
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error serializing deletion manifest: %w", err)
+	}
+	if err := m.path.WriteFile(context.TODO(), bytes.NewReader(b), nil); err != nil {
+		return fmt.Errorf("error writing deletion manifest %s: %w", m.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/resources"
+	awsresources "k8s.io/kops/pkg/resources/aws"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// ListOrphanedResources collects the resources of the cluster that are no longer produced by its tasks,
+// as built for the current cluster spec.
+// Only the kinds of resources that are left behind when instance groups or load balancers are removed are considered,
+// and resources that are still in use by other resources of the cluster are skipped.
+func ListOrphanedResources(cloud fi.Cloud, cluster *kops.Cluster, tasks map[string]fi.CloudupTask) (map[string]*resources.Resource, error) {
+	var isOrphan func(r *resources.Resource) bool
+	switch cloud.ProviderID() {
+	case kops.CloudProviderAWS:
+		targetGroups, err := awsup.ListELBV2TargetGroups(context.TODO(), cloud.(awsup.AWSCloud))
+		if err != nil {
+			return nil, fmt.Errorf("error listing target groups: %w", err)
+		}
+		targetGroupTags := make(map[string]map[string]string)
+		for _, tg := range targetGroups {
+			tags := make(map[string]string)
+			for _, tag := range tg.Tags {
+				tags[fi.ValueOf(tag.Key)] = fi.ValueOf(tag.Value)
+			}
+			targetGroupTags[tg.ARN] = tags
+		}
+		isOrphan = awsOrphanFilter(tasks, targetGroupTags)
+	default:
+		return nil, fmt.Errorf("finding orphaned resources on %q not (yet) supported", cloud.ProviderID())
+	}
+
+	allResources, err := ListResources(cloud, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOrphans(allResources, isOrphan), nil
+}
+
+// findOrphans returns the resources matching isOrphan, except those that must outlive a resource that is not an orphan.
+func findOrphans(allResources map[string]*resources.Resource, isOrphan func(r *resources.Resource) bool) map[string]*resources.Resource {
+	orphans := make(map[string]*resources.Resource)
+	for k, r := range allResources {
+		if r.Shared || r.Done {
+			continue
+		}
+		if isOrphan(r) {
+			orphans[k] = r
+		}
+	}
+
+	// blockedBy maps each resource to the resources that must be deleted before it, as in DeleteResources.
+	resolve := resourceKeyResolver(allResources)
+	blockedBy := make(map[string][]string)
+	for k, r := range allResources {
+		for _, block := range r.Blocks {
+			blocked := resolve(block)
+			blockedBy[blocked] = append(blockedBy[blocked], k)
+		}
+		for _, blocked := range r.Blocked {
+			blockedBy[k] = append(blockedBy[k], resolve(blocked))
+		}
+	}
+
+	// Keep going until no more orphans are found to be in use, as keeping one can keep its own dependencies in use.
+	for {
+		changed := false
+		for k := range orphans {
+			for _, dep := range blockedBy[k] {
+				if _, found := allResources[dep]; !found {
+					continue
+				}
+				if _, found := orphans[dep]; found {
+					continue
+				}
+				klog.V(2).Infof("not treating %s as orphaned, as it is in use by %s", k, dep)
+				delete(orphans, k)
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return orphans
+		}
+	}
+}
+
+// awsOrphanFilter matches the AWS resources that kOps creates per instance group or per load balancer,
+// but which are not produced by the tasks.
+// The tags of the target groups are passed by ARN, as they are not part of the listed resources.
+func awsOrphanFilter(tasks map[string]fi.CloudupTask, targetGroupTags map[string]map[string]string) func(r *resources.Resource) bool {
+	managed := map[string]map[string]bool{
+		awsresources.TypeAutoscalingLaunchConfig:   {},
+		string(ec2types.ResourceTypeSecurityGroup): {},
+		awsresources.TypeTargetGroup:               {},
+		"iam-instance-profile":                     {},
+		"iam-role":                                 {},
+		"volume":                                   {},
+	}
+	for _, task := range tasks {
+		switch t := task.(type) {
+		case *awstasks.LaunchTemplate:
+			managed[awsresources.TypeAutoscalingLaunchConfig][fi.ValueOf(t.Name)] = true
+		case *awstasks.SecurityGroup:
+			managed[string(ec2types.ResourceTypeSecurityGroup)][fi.ValueOf(t.Name)] = true
+		case *awstasks.TargetGroup:
+			managed[awsresources.TypeTargetGroup][fi.ValueOf(t.Name)] = true
+		case *awstasks.IAMInstanceProfile:
+			managed["iam-instance-profile"][fi.ValueOf(t.Name)] = true
+		case *awstasks.IAMRole:
+			managed["iam-role"][fi.ValueOf(t.Name)] = true
+		case *awstasks.EBSVolume:
+			managed["volume"][fi.ValueOf(t.Name)] = true
+		}
+	}
+
+	return func(r *resources.Resource) bool {
+		names, found := managed[r.Type]
+		if !found || r.Name == "" || names[r.Name] {
+			return false
+		}
+		switch r.Type {
+		case "volume":
+			return isOrphanableVolume(r)
+		case string(ec2types.ResourceTypeSecurityGroup):
+			sg, ok := r.Obj.(ec2types.SecurityGroup)
+			if !ok {
+				return false
+			}
+			tags := make(map[string]string)
+			for _, tag := range sg.Tags {
+				tags[fi.ValueOf(tag.Key)] = fi.ValueOf(tag.Value)
+			}
+			return !isControllerResource(tags)
+		case awsresources.TypeTargetGroup:
+			tags, found := targetGroupTags[r.ID]
+			return found && !isControllerResource(tags)
+		}
+		return true
+	}
+}
+
+// isControllerResource matches the resources created for Services and Ingresses by cloud-controller-manager
+// or the AWS Load Balancer Controller. They carry the cluster tag, but are not managed by kOps.
+func isControllerResource(tags map[string]string) bool {
+	for k := range tags {
+		if k == "kubernetes.io/service-name" {
+			return true
+		}
+		for _, prefix := range []string{"elbv2.k8s.aws/", "service.k8s.aws/", "ingress.k8s.aws/"} {
+			if strings.HasPrefix(k, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceKeyResolver returns a function resolving the dependencies of resources to their keys in allResources.
+// Dependencies may refer to resources either by ID or by name, as some resources refer to others by name.
+func resourceKeyResolver(allResources map[string]*resources.Resource) func(dep string) string {
+	keysByName := make(map[string]string)
+	for k, r := range allResources {
+		if r.Name != "" {
+			keysByName[r.Type+":"+r.Name] = k
+		}
+	}
+	return func(dep string) string {
+		if _, found := allResources[dep]; found {
+			return dep
+		}
+		if k, found := keysByName[dep]; found {
+			return k
+		}
+		return dep
+	}
+}
+
+// isOrphanableVolume matches detached etcd volumes, so that volumes created for PersistentVolumes are never matched.
+func isOrphanableVolume(r *resources.Resource) bool {
+	volume, ok := r.Obj.(ec2types.Volume)
+	if !ok || len(volume.Attachments) != 0 {
+		return false
+	}
+	return isEtcdVolume(r)
+}
+
+// isEtcdVolume matches the EBS volumes of etcd members.
+func isEtcdVolume(r *resources.Resource) bool {
+	volume, ok := r.Obj.(ec2types.Volume)
+	if !ok {
+		return false
+	}
+	for _, tag := range volume.Tags {
+		if strings.HasPrefix(fi.ValueOf(tag.Key), awsup.TagNameEtcdClusterPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops/pkg/resources"
+	awsresources "k8s.io/kops/pkg/resources/aws"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestFindOrphansAWS(t *testing.T) {
+	tasks := map[string]fi.CloudupTask{
+		"LaunchTemplate/nodes.example.com":  &awstasks.LaunchTemplate{Name: fi.PtrTo("nodes.example.com")},
+		"SecurityGroup/nodes.example.com":   &awstasks.SecurityGroup{Name: fi.PtrTo("nodes.example.com")},
+		"EBSVolume/a.etcd-main.example.com": &awstasks.EBSVolume{Name: fi.PtrTo("a.etcd-main.example.com")},
+	}
+
+	etcdTags := []ec2types.Tag{{Key: fi.PtrTo("k8s.io/etcd/main"), Value: fi.PtrTo("a/a")}}
+	allResources := map[string]*resources.Resource{}
+	for _, r := range []*resources.Resource{
+		// Still produced by the tasks
+		{Type: awsresources.TypeAutoscalingLaunchConfig, ID: "lt-1", Name: "nodes.example.com"},
+		{Type: "security-group", ID: "sg-1", Name: "nodes.example.com", Obj: ec2types.SecurityGroup{}},
+		{Type: "volume", ID: "vol-1", Name: "a.etcd-main.example.com", Obj: ec2types.Volume{Tags: etcdTags}},
+		// Orphaned
+		{Type: awsresources.TypeAutoscalingLaunchConfig, ID: "lt-2", Name: "old.example.com"},
+		{Type: "security-group", ID: "sg-2", Name: "old.example.com", Obj: ec2types.SecurityGroup{}},
+		{Type: "volume", ID: "vol-2", Name: "b.etcd-main.example.com", Obj: ec2types.Volume{Tags: etcdTags}},
+		// Orphaned, but still in use by an autoscaling group that refers to it by name
+		{Type: awsresources.TypeAutoscalingLaunchConfig, ID: "lt-3", Name: "manual.example.com"},
+		{Type: "autoscaling-group", ID: "manual.example.com", Name: "manual.example.com", Blocks: []string{awsresources.TypeAutoscalingLaunchConfig + ":manual.example.com"}},
+		// Orphaned, but still in use by an instance
+		{Type: "security-group", ID: "sg-3", Name: "inuse.example.com", Obj: ec2types.SecurityGroup{}},
+		{Type: "instance", ID: "i-1", Blocks: []string{"security-group:sg-3"}},
+		// Not owned by the cluster
+		{Type: "security-group", ID: "sg-4", Name: "shared.example.com", Shared: true, Obj: ec2types.SecurityGroup{}},
+		// Attached etcd volume
+		{Type: "volume", ID: "vol-3", Name: "c.etcd-main.example.com", Obj: ec2types.Volume{Tags: etcdTags, Attachments: []ec2types.VolumeAttachment{{}}}},
+		// Volume of a PersistentVolume
+		{Type: "volume", ID: "vol-4", Name: "pvc-1234", Obj: ec2types.Volume{}},
+		// Orphaned target group
+		{Type: awsresources.TypeTargetGroup, ID: "arn:tg-1", Name: "tcp-old-example-com"},
+		// Orphaned, but still in use by a load balancer
+		{Type: awsresources.TypeTargetGroup, ID: "arn:tg-2", Name: "tcp-inuse-example-com", Blocked: []string{awsresources.TypeLoadBalancer + ":arn:lb-1"}},
+		{Type: awsresources.TypeLoadBalancer, ID: "arn:lb-1", Name: "api-example-com"},
+		// Created by the load balancer controller and cloud-controller-manager for Services
+		{Type: "security-group", ID: "sg-5", Name: "k8s-default-web", Obj: ec2types.SecurityGroup{Tags: []ec2types.Tag{{Key: fi.PtrTo("elbv2.k8s.aws/cluster"), Value: fi.PtrTo("example.com")}}}},
+		{Type: awsresources.TypeTargetGroup, ID: "arn:tg-3", Name: "k8s-default-web-1234"},
+		// Not a kind of resource left behind by removing instance groups
+		{Type: "vpc", ID: "vpc-1", Name: "example.com"},
+	} {
+		allResources[r.Type+":"+r.ID] = r
+	}
+
+	targetGroupTags := map[string]map[string]string{
+		"arn:tg-1": {"KubernetesCluster": "example.com"},
+		"arn:tg-2": {"KubernetesCluster": "example.com"},
+		"arn:tg-3": {"KubernetesCluster": "example.com", "kubernetes.io/service-name": "default/web"},
+	}
+	orphans := findOrphans(allResources, awsOrphanFilter(tasks, targetGroupTags))
+
+	var actual []string
+	for k := range orphans {
+		actual = append(actual, k)
+	}
+	sort.Strings(actual)
+	expected := []string{
+		awsresources.TypeAutoscalingLaunchConfig + ":lt-2",
+		"security-group:sg-2",
+		awsresources.TypeTargetGroup + ":arn:tg-1",
+		"volume:vol-2",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected orphans, expected %v, got %v", expected, actual)
+	}
+}
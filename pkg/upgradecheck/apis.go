@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/blang/semver/v4"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// removedAPI is an API version of a resource removed from Kubernetes.
+type removedAPI struct {
+	Group       string
+	Version     string
+	Resource    string
+	RemovedIn   semver.Version
+	Replacement string
+}
+
+func (a *removedAPI) groupVersion() string {
+	if a.Group == "" {
+		return a.Version
+	}
+	return a.Group + "/" + a.Version
+}
+
+// removedAPIs are the API versions removed from the Kubernetes versions supported by kOps,
+// from https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var removedAPIs = []removedAPI{
+	{"batch", "v1beta1", "cronjobs", semver.MustParse("1.25.0"), "batch/v1"},
+	{"discovery.k8s.io", "v1beta1", "endpointslices", semver.MustParse("1.25.0"), "discovery.k8s.io/v1"},
+	{"events.k8s.io", "v1beta1", "events", semver.MustParse("1.25.0"), "events.k8s.io/v1"},
+	{"autoscaling", "v2beta1", "horizontalpodautoscalers", semver.MustParse("1.25.0"), "autoscaling/v2"},
+	{"policy", "v1beta1", "poddisruptionbudgets", semver.MustParse("1.25.0"), "policy/v1"},
+	{"policy", "v1beta1", "podsecuritypolicies", semver.MustParse("1.25.0"), ""},
+	{"node.k8s.io", "v1beta1", "runtimeclasses", semver.MustParse("1.25.0"), "node.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta1", "flowschemas", semver.MustParse("1.26.0"), "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta1", "prioritylevelconfigurations", semver.MustParse("1.26.0"), "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"autoscaling", "v2beta2", "horizontalpodautoscalers", semver.MustParse("1.26.0"), "autoscaling/v2"},
+	{"storage.k8s.io", "v1beta1", "csistoragecapacities", semver.MustParse("1.27.0"), "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta2", "flowschemas", semver.MustParse("1.29.0"), "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta2", "prioritylevelconfigurations", semver.MustParse("1.29.0"), "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta3", "flowschemas", semver.MustParse("1.32.0"), "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io", "v1beta3", "prioritylevelconfigurations", semver.MustParse("1.32.0"), "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// removedByUpgrade returns whether the upgrade from the current to the target version removes an API.
+func (c *checker) removedByUpgrade(removedIn semver.Version) bool {
+	return minor(c.current).LT(removedIn) && !minor(c.TargetVersion).LT(removedIn)
+}
+
+// checkRemovedAPIs looks for clients and objects still using API versions removed by the upgrade.
+// Objects are stored independently of the API version used to write them, so the check relies on the
+// deprecated API requests counted by the apiserver and on the last applied configuration of the objects.
+func (c *checker) checkRemovedAPIs(ctx context.Context) error {
+	if c.K8sClient == nil {
+		c.add(CheckRemovedAPIs, StatusWarn, "", "not connected to the cluster; skipped the check of the live objects")
+		return nil
+	}
+	restClient := c.K8sClient.Discovery().RESTClient()
+	if restClient == nil {
+		c.add(CheckRemovedAPIs, StatusWarn, "", "kubernetes client does not support raw requests; skipped the check of the live objects")
+		return nil
+	}
+
+	metrics, err := restClient.Get().AbsPath("/metrics").Do(ctx).Raw()
+	if err != nil {
+		c.add(CheckRemovedAPIs, StatusWarn, "", "unable to read the apiserver metrics of deprecated API requests: %v", err)
+	} else {
+		seen := make(map[string]bool)
+		for _, request := range parseDeprecatedAPIRequests(metrics) {
+			removedIn, err := semver.ParseTolerant(request.removedRelease)
+			if err != nil || !c.removedByUpgrade(removedIn) || seen[request.groupVersion+"/"+request.resource] {
+				continue
+			}
+			seen[request.groupVersion+"/"+request.resource] = true
+			c.add(CheckRemovedAPIs, StatusFail, request.groupVersion+"/"+request.resource,
+				"clients requested %s %s since the apiserver started, which is removed in Kubernetes %s", request.groupVersion, request.resource, request.removedRelease)
+		}
+	}
+
+	for i := range removedAPIs {
+		api := &removedAPIs[i]
+		if !c.removedByUpgrade(api.RemovedIn) {
+			continue
+		}
+		if err := c.checkRemovedAPIObjects(ctx, api); err != nil {
+			c.add(CheckRemovedAPIs, StatusWarn, api.groupVersion()+"/"+api.Resource, "unable to list %s: %v", api.Resource, err)
+		}
+	}
+	return nil
+}
+
+// objectList holds the fields of objects read by the check.
+type objectList struct {
+	Items []struct {
+		Metadata struct {
+			Name        string            `json:"name"`
+			Namespace   string            `json:"namespace"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// checkRemovedAPIObjects flags the objects last applied with a removed API version,
+// or any object of a resource removed without replacement.
+// The objects are listed through the removed version, which the current version still serves.
+func (c *checker) checkRemovedAPIObjects(ctx context.Context, api *removedAPI) error {
+	path := "/apis/" + api.groupVersion() + "/" + api.Resource
+	if api.Group == "" {
+		path = "/api/" + api.groupVersion() + "/" + api.Resource
+	}
+
+	raw, err := c.K8sClient.Discovery().RESTClient().Get().AbsPath(path).Do(ctx).Raw()
+	if err != nil {
+		return err
+	}
+	list := &objectList{}
+	if err := json.Unmarshal(raw, list); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for _, item := range list.Items {
+		name := item.Metadata.Name
+		if item.Metadata.Namespace != "" {
+			name = item.Metadata.Namespace + "/" + name
+		}
+		object := api.Resource + "/" + name
+		if api.Replacement == "" {
+			c.add(CheckRemovedAPIs, StatusFail, object, "%s are removed in Kubernetes %s without replacement; delete them before upgrading", api.Resource, api.RemovedIn)
+			continue
+		}
+		lastApplied := item.Metadata.Annotations[corev1.LastAppliedConfigAnnotation]
+		if lastApplied == "" {
+			continue
+		}
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+			continue
+		}
+		if applied.APIVersion == api.groupVersion() {
+			c.add(CheckRemovedAPIs, StatusFail, object, "last applied with %s, which is removed in Kubernetes %s; update its manifest to %s", applied.APIVersion, api.RemovedIn, api.Replacement)
+		}
+	}
+	return nil
+}
+
+type deprecatedAPIRequest struct {
+	groupVersion   string
+	resource       string
+	removedRelease string
+}
+
+var metricLabelRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseDeprecatedAPIRequests parses the apiserver_requested_deprecated_apis metric.
+func parseDeprecatedAPIRequests(metrics []byte) []deprecatedAPIRequest {
+	prefix := []byte("apiserver_requested_deprecated_apis{")
+
+	var requests []deprecatedAPIRequest
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.HasPrefix(line, prefix) {
+			continue
+		}
+		labels := make(map[string]string)
+		for _, match := range metricLabelRegexp.FindAllSubmatch(line, -1) {
+			labels[string(match[1])] = string(match[2])
+		}
+		if labels["removed_release"] == "" {
+			continue
+		}
+		groupVersion := labels["version"]
+		if labels["group"] != "" {
+			groupVersion = labels["group"] + "/" + groupVersion
+		}
+		requests = append(requests, deprecatedAPIRequest{
+			groupVersion:   groupVersion,
+			resource:       labels["resource"],
+			removedRelease: labels["removed_release"],
+		})
+	}
+	return requests
+}
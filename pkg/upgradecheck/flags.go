@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// deprecatedFlag is a flag of a Kubernetes component that is deprecated or removed.
+type deprecatedFlag struct {
+	Component string
+	Flag      string
+	// DeprecatedIn is the version deprecating the flag, if it is not removed yet.
+	DeprecatedIn *semver.Version
+	// RemovedIn is the version removing the flag.
+	RemovedIn *semver.Version
+	Message   string
+}
+
+func version(s string) *semver.Version {
+	v := semver.MustParse(s)
+	return &v
+}
+
+// deprecatedFlags are the flags configurable in the cluster spec that are deprecated or removed
+// in the Kubernetes versions supported by kOps.
+var deprecatedFlags = []deprecatedFlag{
+	{Component: "kubelet", Flag: "network-plugin", RemovedIn: version("1.24.0"), Message: "removed with dockershim; the network plugin is configured in the container runtime"},
+	{Component: "kubelet", Flag: "network-plugin-mtu", RemovedIn: version("1.24.0"), Message: "removed with dockershim"},
+	{Component: "kubelet", Flag: "image-pull-progress-deadline", RemovedIn: version("1.24.0"), Message: "removed with dockershim"},
+	{Component: "kubelet", Flag: "pod-infra-container-image", DeprecatedIn: version("1.27.0"), Message: "the sandbox image is configured in the container runtime"},
+	{Component: "kube-apiserver", Flag: "insecure-port", RemovedIn: version("1.24.0"), Message: "removed with the insecure serving"},
+	{Component: "kube-apiserver", Flag: "address", RemovedIn: version("1.24.0"), Message: "removed with the insecure serving"},
+	{Component: "kube-apiserver", Flag: "insecure-bind-address", RemovedIn: version("1.24.0"), Message: "removed with the insecure serving"},
+	{Component: "kube-apiserver", Flag: "admission-control", RemovedIn: version("1.26.0"), Message: "replaced by enableAdmissionPlugins"},
+	{Component: "kube-controller-manager", Flag: "experimental-cluster-signing-duration", RemovedIn: version("1.25.0"), Message: "replaced by clusterSigningDuration"},
+	{Component: "kube-controller-manager", Flag: "pod-eviction-timeout", RemovedIn: version("1.27.0"), Message: "evictions are driven by the taint based eviction tolerations"},
+}
+
+// componentConfig is the configuration of a component in the cluster or instance group spec.
+type componentConfig struct {
+	component string
+	object    string
+	path      string
+	config    interface{}
+}
+
+// checkDeprecatedFlags flags the component flags set in the cluster and instance group specs that
+// are deprecated in the target version, or removed by the upgrade.
+func (c *checker) checkDeprecatedFlags(ctx context.Context) error {
+	spec := &c.Cluster.Spec
+	cluster := "Cluster/" + c.Cluster.Name
+	configs := []componentConfig{
+		{"kubelet", cluster, "spec.kubelet", spec.Kubelet},
+		{"kubelet", cluster, "spec.controlPlaneKubelet", spec.ControlPlaneKubelet},
+		{"kube-apiserver", cluster, "spec.kubeAPIServer", spec.KubeAPIServer},
+		{"kube-controller-manager", cluster, "spec.kubeControllerManager", spec.KubeControllerManager},
+		{"kube-scheduler", cluster, "spec.kubeScheduler", spec.KubeScheduler},
+		{"kube-proxy", cluster, "spec.kubeProxy", spec.KubeProxy},
+	}
+	for _, ig := range c.InstanceGroups {
+		configs = append(configs, componentConfig{"kubelet", "InstanceGroup/" + ig.Name, "spec.kubelet", ig.Spec.Kubelet})
+	}
+
+	target := minor(c.TargetVersion)
+	for _, config := range configs {
+		setFlags := flagsSet(config.config)
+		for i := range deprecatedFlags {
+			flag := &deprecatedFlags[i]
+			if flag.Component != config.component {
+				continue
+			}
+			field, found := setFlags[flag.Flag]
+			if !found {
+				continue
+			}
+			object := config.object + " " + config.path + "." + field
+			switch {
+			case flag.RemovedIn != nil && c.removedByUpgrade(*flag.RemovedIn):
+				c.add(CheckDeprecatedFlags, StatusFail, object, "%s flag --%s is removed in Kubernetes %s: %s", flag.Component, flag.Flag, flag.RemovedIn, flag.Message)
+			case flag.RemovedIn != nil && !target.LT(*flag.RemovedIn):
+				c.add(CheckDeprecatedFlags, StatusWarn, object, "%s flag --%s was removed in Kubernetes %s and has no effect: %s", flag.Component, flag.Flag, flag.RemovedIn, flag.Message)
+			case flag.DeprecatedIn != nil && !target.LT(*flag.DeprecatedIn):
+				c.add(CheckDeprecatedFlags, StatusWarn, object, "%s flag --%s is deprecated since Kubernetes %s: %s", flag.Component, flag.Flag, flag.DeprecatedIn, flag.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// flagsSet returns the json names of the fields set in a component configuration, by the name of their flag.
+func flagsSet(config interface{}) map[string]string {
+	set := make(map[string]string)
+
+	v := reflect.ValueOf(config)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return set
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		flag := field.Tag.Get("flag")
+		if flag == "" || v.Field(i).IsZero() {
+			continue
+		}
+		set[flag] = strings.Split(field.Tag.Get("json"), ",")[0]
+	}
+	return set
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/apis/kops/util"
+)
+
+// maxKubeletSkew returns the number of minor versions the kubelet can be older than the apiserver,
+// from https://kubernetes.io/releases/version-skew-policy/
+func maxKubeletSkew(apiserver semver.Version) uint64 {
+	if apiserver.LT(semver.Version{Major: 1, Minor: 28}) {
+		return 2
+	}
+	return 3
+}
+
+// checkVersionSkew checks the version skew between the control plane and the nodes after the upgrade.
+func (c *checker) checkVersionSkew(ctx context.Context) error {
+	target := minor(c.TargetVersion)
+
+	controlPlane := c.current
+	if c.K8sClient != nil {
+		serverVersion, err := c.K8sClient.Discovery().ServerVersion()
+		if err != nil {
+			return fmt.Errorf("getting the apiserver version: %w", err)
+		}
+		live, err := util.ParseKubernetesVersion(serverVersion.GitVersion)
+		if err != nil {
+			c.add(CheckVersionSkew, StatusWarn, "", "unable to parse the apiserver version %q", serverVersion.GitVersion)
+		} else {
+			controlPlane = *live
+			if !minor(controlPlane).EQ(minor(c.current)) {
+				c.add(CheckVersionSkew, StatusWarn, "", "the apiserver runs Kubernetes %s while the cluster spec has %s; finish the previous upgrade first", controlPlane, c.current)
+			}
+		}
+	}
+
+	if target.GT(minor(controlPlane)) && target.Minor-controlPlane.Minor > 1 {
+		c.add(CheckVersionSkew, StatusFail, "", "the control plane runs Kubernetes %s and can only be upgraded one minor version at a time; upgrade to %d.%d first",
+			controlPlane, controlPlane.Major, controlPlane.Minor+1)
+	}
+
+	if c.K8sClient == nil {
+		c.add(CheckVersionSkew, StatusWarn, "", "not connected to the cluster; skipped the check of the kubelet versions")
+		return nil
+	}
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	skew := maxKubeletSkew(target)
+	for _, node := range nodes.Items {
+		kubeletVersion := node.Status.NodeInfo.KubeletVersion
+		kubelet, err := util.ParseKubernetesVersion(kubeletVersion)
+		if err != nil {
+			c.add(CheckVersionSkew, StatusWarn, "Node/"+node.Name, "unable to parse the kubelet version %q", kubeletVersion)
+			continue
+		}
+		switch {
+		case minor(*kubelet).GT(target):
+			c.add(CheckVersionSkew, StatusFail, "Node/"+node.Name, "kubelet %s is newer than the target version %s", kubelet, c.TargetVersion)
+		case kubelet.Minor+skew < target.Minor:
+			c.add(CheckVersionSkew, StatusFail, "Node/"+node.Name, "kubelet %s would be more than %d minor versions older than the control plane; roll the node first", kubelet, skew)
+		}
+	}
+	return nil
+}
+
+// checkAddons checks that the addons of the channels in the cluster spec have a version for the target version.
+func (c *checker) checkAddons(ctx context.Context) error {
+	for _, addon := range c.Cluster.Spec.Addons {
+		location, err := url.Parse(addon.Manifest)
+		if err != nil || !location.IsAbs() {
+			c.add(CheckAddons, StatusWarn, addon.Manifest, "unable to check the addons of channel %q, which is not a URL", addon.Manifest)
+			continue
+		}
+		loaded, err := channels.LoadAddons(c.VFSContext, addon.Manifest, location)
+		if err != nil {
+			c.add(CheckAddons, StatusWarn, addon.Manifest, "unable to load the addons: %v", err)
+			continue
+		}
+		currentMenu, err := loaded.GetCurrent(c.current)
+		if err != nil {
+			return err
+		}
+		targetMenu, err := loaded.GetCurrent(c.TargetVersion)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for name := range currentMenu.Addons {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if targetMenu.Addons[name] == nil {
+				c.add(CheckAddons, StatusFail, addon.Manifest, "addon %q has no version supporting Kubernetes %s", name, c.TargetVersion)
+			}
+		}
+	}
+	return nil
+}
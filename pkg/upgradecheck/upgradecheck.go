@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass means the check found nothing blocking the upgrade.
+	StatusPass Status = "PASS"
+	// StatusWarn means the check found something to look at that does not block the upgrade.
+	StatusWarn Status = "WARN"
+	// StatusFail means the check found something that blocks the upgrade.
+	StatusFail Status = "FAIL"
+)
+
+// Names of the checks.
+const (
+	CheckKopsVersion     = "KopsVersion"
+	CheckRemovedAPIs     = "RemovedAPIs"
+	CheckDeprecatedFlags = "DeprecatedFlags"
+	CheckVersionSkew     = "VersionSkew"
+	CheckAddons          = "Addons"
+	CheckNetworking      = "Networking"
+	CheckClusterSpec     = "ClusterSpec"
+)
+
+// Result is a finding of a check.
+type Result struct {
+	Check   string `json:"check"`
+	Status  Status `json:"status"`
+	Object  string `json:"object,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of the checks for an upgrade.
+type Report struct {
+	CurrentVersion string    `json:"currentVersion"`
+	TargetVersion  string    `json:"targetVersion"`
+	Results        []*Result `json:"results"`
+}
+
+// Passed returns true if no check failed.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Options configures the checks for an upgrade.
+type Options struct {
+	Cluster        *kopsapi.Cluster
+	InstanceGroups []*kopsapi.InstanceGroup
+	// TargetVersion is the Kubernetes version to upgrade to.
+	TargetVersion semver.Version
+	// KopsVersion is the version of kOps doing the upgrade; defaults to the running version.
+	KopsVersion string
+	// K8sClient is the client of the live cluster; the checks of the live cluster are skipped if nil.
+	K8sClient  kubernetes.Interface
+	VFSContext *vfs.VFSContext
+}
+
+// checker accumulates the results of the checks.
+type checker struct {
+	*Options
+	current semver.Version
+	results []*Result
+}
+
+func (c *checker) add(check string, status Status, object string, format string, args ...interface{}) {
+	c.results = append(c.results, &Result{
+		Check:   check,
+		Status:  status,
+		Object:  object,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Run checks that the cluster can be upgraded to the target Kubernetes version.
+func Run(ctx context.Context, options *Options) (*Report, error) {
+	current, err := util.ParseKubernetesVersion(options.Cluster.Spec.KubernetesVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubernetesVersion %q: %w", options.Cluster.Spec.KubernetesVersion, err)
+	}
+	if options.KopsVersion == "" {
+		options.KopsVersion = kops.Version
+	}
+
+	c := &checker{Options: options, current: *current}
+	checks := []struct {
+		names []string
+		run   func(ctx context.Context) error
+	}{
+		{[]string{CheckKopsVersion}, c.checkKopsVersion},
+		{[]string{CheckRemovedAPIs}, c.checkRemovedAPIs},
+		{[]string{CheckDeprecatedFlags}, c.checkDeprecatedFlags},
+		{[]string{CheckVersionSkew}, c.checkVersionSkew},
+		{[]string{CheckAddons}, c.checkAddons},
+		{[]string{CheckNetworking, CheckClusterSpec}, c.checkClusterSpec},
+	}
+	for _, check := range checks {
+		n := len(c.results)
+		if err := check.run(ctx); err != nil {
+			return nil, fmt.Errorf("running check %s: %w", strings.Join(check.names, ","), err)
+		}
+		// Report the checks that found nothing, so that the report lists every check.
+		found := make(map[string]bool)
+		for _, result := range c.results[n:] {
+			found[result.Check] = true
+		}
+		for _, name := range check.names {
+			if !found[name] {
+				c.add(name, StatusPass, "", "no issues found")
+			}
+		}
+	}
+
+	return &Report{
+		CurrentVersion: current.String(),
+		TargetVersion:  options.TargetVersion.String(),
+		Results:        c.results,
+	}, nil
+}
+
+// minor returns the major and minor version of a Kubernetes version, for comparisons ignoring the patch version.
+func minor(v semver.Version) semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor}
+}
+
+// checkKopsVersion checks that this version of kOps supports the target version.
+func (c *checker) checkKopsVersion(ctx context.Context) error {
+	target := minor(c.TargetVersion)
+	if target.LT(minor(c.current)) {
+		c.add(CheckKopsVersion, StatusFail, "", "downgrading Kubernetes from %s to %s is not supported", c.current, c.TargetVersion)
+	}
+
+	kopsVersion, err := semver.ParseTolerant(c.KopsVersion)
+	if err != nil {
+		c.add(CheckKopsVersion, StatusWarn, "", "unable to parse kOps version %q", c.KopsVersion)
+		return nil
+	}
+	if target.GT(minor(kopsVersion)) {
+		c.add(CheckKopsVersion, StatusFail, "", "kOps %s does not support Kubernetes %s; upgrade kOps first", c.KopsVersion, c.TargetVersion)
+	}
+	return nil
+}
+
+// checkClusterSpec validates the cluster spec with the target version,
+// reporting the networking errors separately as they follow the support matrix of the CNIs.
+func (c *checker) checkClusterSpec(ctx context.Context) error {
+	cluster := c.Cluster.DeepCopy()
+	cluster.Spec.KubernetesVersion = c.TargetVersion.String()
+
+	networkingPath := "spec.networking."
+	for _, err := range validation.ValidateCluster(cluster, false, c.VFSContext) {
+		if strings.HasPrefix(err.Field, networkingPath) {
+			c.add(CheckNetworking, StatusFail, err.Field, "%s", err.ErrorBody())
+		} else {
+			c.add(CheckClusterSpec, StatusFail, err.Field, "%s", err.ErrorBody())
+		}
+	}
+	return nil
+}
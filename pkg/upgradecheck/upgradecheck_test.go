@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradecheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// summary returns the check, status and object of the results.
+func summary(report *Report) []string {
+	var results []string
+	for _, result := range report.Results {
+		results = append(results, fmt.Sprintf("%s %s %s", result.Check, result.Status, result.Object))
+	}
+	return results
+}
+
+func TestRunWithoutCluster(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.KubernetesVersion = "1.26.3"
+	cluster.Spec.Networking.Flannel = &kopsapi.FlannelNetworkingSpec{}
+	cluster.Spec.KubeControllerManager = &kopsapi.KubeControllerManagerConfig{
+		PodEvictionTimeout: &metav1.Duration{},
+	}
+	ig := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	ig.Spec.Kubelet = &kopsapi.KubeletConfigSpec{
+		PodInfraContainerImage: "registry.k8s.io/pause:3.9",
+	}
+
+	report, err := Run(context.Background(), &Options{
+		Cluster:        cluster,
+		InstanceGroups: []*kopsapi.InstanceGroup{&ig},
+		TargetVersion:  semver.MustParse("1.28.0"),
+		KopsVersion:    "1.30.0",
+		VFSContext:     vfs.NewVFSContext(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"KopsVersion PASS ",
+		"RemovedAPIs WARN ",
+		"DeprecatedFlags FAIL Cluster/minimal.example.com spec.kubeControllerManager.podEvictionTimeout",
+		"DeprecatedFlags WARN InstanceGroup/nodes spec.kubelet.podInfraContainerImage",
+		"VersionSkew FAIL ",
+		"VersionSkew WARN ",
+		"Addons PASS ",
+		"Networking FAIL spec.networking.flannel",
+		"ClusterSpec PASS ",
+	}, summary(report))
+	assert.False(t, report.Passed())
+	assert.Equal(t, "1.26.3", report.CurrentVersion)
+	assert.Equal(t, "1.28.0", report.TargetVersion)
+}
+
+func TestRunKopsVersion(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.KubernetesVersion = "1.30.1"
+
+	report, err := Run(context.Background(), &Options{
+		Cluster:       cluster,
+		TargetVersion: semver.MustParse("1.31.0"),
+		KopsVersion:   "1.30.0",
+		VFSContext:    vfs.NewVFSContext(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Result{
+		Check:   CheckKopsVersion,
+		Status:  StatusFail,
+		Message: "kOps 1.30.0 does not support Kubernetes 1.31.0; upgrade kOps first",
+	}, report.Results[0])
+}
+
+const testAddons = `
+kind: Addons
+metadata:
+  name: example
+spec:
+  addons:
+  - name: example.addons.k8s.io
+    version: 1.0.0
+    manifest: v1.0.0.yaml
+    kubernetesVersion: "<1.25.0"
+  - name: portable.addons.k8s.io
+    version: 1.0.0
+    manifest: v1.0.0.yaml
+`
+
+const testMetrics = `# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="status",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.29",resource="flowschemas",subresource="",version="v1beta2"} 1
+`
+
+func TestRunWithCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/version":
+			fmt.Fprint(w, `{"major": "1", "minor": "24", "gitVersion": "v1.24.3"}`)
+		case "/metrics":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, testMetrics)
+		case "/api/v1/nodes":
+			fmt.Fprint(w, `{"kind": "NodeList", "apiVersion": "v1", "items": [
+				{"metadata": {"name": "control-plane"}, "status": {"nodeInfo": {"kubeletVersion": "v1.24.3"}}},
+				{"metadata": {"name": "old-node"}, "status": {"nodeInfo": {"kubeletVersion": "v1.22.5"}}}
+			]}`)
+		case "/apis/batch/v1beta1/cronjobs":
+			fmt.Fprint(w, `{"items": [
+				{"metadata": {"name": "legacy", "namespace": "default", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"batch/v1beta1\",\"kind\":\"CronJob\"}"}}},
+				{"metadata": {"name": "current", "namespace": "default", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"batch/v1\",\"kind\":\"CronJob\"}"}}}
+			]}`)
+		case "/apis/policy/v1beta1/podsecuritypolicies":
+			fmt.Fprint(w, `{"items": [{"metadata": {"name": "restricted"}}]}`)
+		case "/apis/discovery.k8s.io/v1beta1/endpointslices", "/apis/events.k8s.io/v1beta1/events",
+			"/apis/autoscaling/v2beta1/horizontalpodautoscalers", "/apis/policy/v1beta1/poddisruptionbudgets":
+			fmt.Fprint(w, `{"items": []}`)
+		case "/apis/node.k8s.io/v1beta1/runtimeclasses":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	k8sClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	addonsPath, err := vfsContext.BuildVfsPath("memfs://addons/example/addons.yaml")
+	require.NoError(t, err)
+	require.NoError(t, addonsPath.WriteFile(context.Background(), bytes.NewReader([]byte(testAddons)), nil))
+
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.KubernetesVersion = "1.24.3"
+	cluster.Spec.Addons = []kopsapi.AddonSpec{
+		{Manifest: "memfs://addons/example/addons.yaml"},
+		{Manifest: "legacy"},
+	}
+	cluster.Spec.KubeAPIServer = &kopsapi.KubeAPIServerConfig{
+		InsecurePort: fi.PtrTo(int32(8080)),
+	}
+
+	report, err := Run(context.Background(), &Options{
+		Cluster:       cluster,
+		TargetVersion: semver.MustParse("1.25.0"),
+		KopsVersion:   "1.30.0",
+		K8sClient:     k8sClient,
+		VFSContext:    vfsContext,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"KopsVersion PASS ",
+		"RemovedAPIs FAIL policy/v1beta1/podsecuritypolicies",
+		"RemovedAPIs FAIL cronjobs/default/legacy",
+		"RemovedAPIs FAIL podsecuritypolicies/restricted",
+		"RemovedAPIs WARN node.k8s.io/v1beta1/runtimeclasses",
+		"DeprecatedFlags WARN Cluster/minimal.example.com spec.kubeAPIServer.insecurePort",
+		"VersionSkew FAIL Node/old-node",
+		"Addons FAIL memfs://addons/example/addons.yaml",
+		"Addons WARN legacy",
+		"Networking PASS ",
+		"ClusterSpec PASS ",
+	}, summary(report))
+	assert.Equal(t, "kubelet 1.22.5 would be more than 2 minor versions older than the control plane; roll the node first", report.Results[6].Message)
+	assert.Equal(t, "addon \"example.addons.k8s.io\" has no version supporting Kubernetes 1.25.0", report.Results[7].Message)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+)
+
+// RecommendedVersion returns the version the channel recommends for a minor version of Kubernetes.
+func RecommendedVersion(channel *kops.Channel, major, minor uint64) (*semver.Version, error) {
+	version := semver.Version{Major: major, Minor: minor}
+	spec := kops.FindKubernetesVersionSpec(channel.Spec.KubernetesVersions, version)
+	if spec == nil || spec.RecommendedVersion == "" {
+		return nil, fmt.Errorf("channel has no recommended version for Kubernetes %d.%d", major, minor)
+	}
+	recommended, err := util.ParseKubernetesVersion(spec.RecommendedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing RecommendedVersion %q from channel: %w", spec.RecommendedVersion, err)
+	}
+	if recommended.Major != major || recommended.Minor != minor {
+		return nil, fmt.Errorf("channel has no recommended version for Kubernetes %d.%d, only %s", major, minor, recommended)
+	}
+	return recommended, nil
+}
+
+// ResolveVersion parses the version to upgrade to.
+// A minor version, such as 1.31, resolves to the version the channel recommends for it.
+func ResolveVersion(channel *kops.Channel, s string) (*semver.Version, error) {
+	if strings.Count(strings.TrimPrefix(s, "v"), ".") == 1 {
+		v, err := semver.ParseTolerant(s)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Kubernetes version %q: %w", s, err)
+		}
+		return RecommendedVersion(channel, v.Major, v.Minor)
+	}
+	return util.ParseKubernetesVersion(s)
+}
+
+// Plan returns the Kubernetes versions to upgrade through from the current version, one minor version at a time,
+// ending with the target version. The intermediate versions are those the channel recommends for each minor version.
+func Plan(channel *kops.Channel, current, target semver.Version) ([]semver.Version, error) {
+	if target.Major != current.Major {
+		return nil, fmt.Errorf("cannot plan an upgrade from Kubernetes %s to %s", current, target)
+	}
+	if target.LT(current) {
+		return nil, fmt.Errorf("downgrading Kubernetes from %s to %s is not supported", current, target)
+	}
+	if target.EQ(current) {
+		return nil, nil
+	}
+
+	var versions []semver.Version
+	for minor := current.Minor + 1; minor < target.Minor; minor++ {
+		version, err := RecommendedVersion(channel, current.Major, minor)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+	return append(versions, target), nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const testChannel = `
+spec:
+  kubernetesVersions:
+  - range: ">=1.31.0"
+    recommendedVersion: 1.31.1
+  - range: ">=1.30.0"
+    recommendedVersion: 1.30.5
+  - range: ">=1.29.0"
+    recommendedVersion: 1.29.9
+  - range: ">=1.27.0"
+    recommendedVersion: 1.27.16
+`
+
+func TestPlan(t *testing.T) {
+	channel, err := kops.ParseChannel([]byte(testChannel))
+	require.NoError(t, err)
+
+	grid := []struct {
+		current  string
+		target   string
+		expected []string
+		err      string
+	}{
+		{current: "1.28.3", target: "1.31.1", expected: []string{"1.29.9", "1.30.5", "1.31.1"}},
+		{current: "1.30.1", target: "1.31.0", expected: []string{"1.31.0"}},
+		{current: "1.30.1", target: "1.30.5", expected: []string{"1.30.5"}},
+		{current: "1.30.5", target: "1.30.5"},
+		{current: "1.31.1", target: "1.30.5", err: "downgrading Kubernetes from 1.31.1 to 1.30.5 is not supported"},
+		{current: "1.26.1", target: "1.30.5", err: "channel has no recommended version for Kubernetes 1.28, only 1.27.16"},
+	}
+	for _, g := range grid {
+		t.Run(g.current+"-"+g.target, func(t *testing.T) {
+			plan, err := Plan(channel, semver.MustParse(g.current), semver.MustParse(g.target))
+			if g.err != "" {
+				require.EqualError(t, err, g.err)
+				return
+			}
+			require.NoError(t, err)
+			var versions []string
+			for _, v := range plan {
+				versions = append(versions, v.String())
+			}
+			assert.Equal(t, g.expected, versions)
+		})
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	channel, err := kops.ParseChannel([]byte(testChannel))
+	require.NoError(t, err)
+
+	v, err := ResolveVersion(channel, "1.30")
+	require.NoError(t, err)
+	assert.Equal(t, "1.30.5", v.String())
+
+	v, err = ResolveVersion(channel, "v1.30.2")
+	require.NoError(t, err)
+	assert.Equal(t, "1.30.2", v.String())
+
+	_, err = ResolveVersion(channel, "1.32")
+	require.Error(t, err)
+}
+
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	path, err := vfsContext.BuildVfsPath("memfs://state/example.com/upgrade.yaml")
+	require.NoError(t, err)
+
+	progress, err := ReadProgress(ctx, path)
+	require.NoError(t, err)
+	assert.Nil(t, progress)
+
+	progress = NewProgress(path, "example.com", "1.29.3", []string{"1.30.5", "1.31.1"})
+	require.NoError(t, progress.RecordDone(ctx, "1.30.5", StepPreflight))
+	require.NoError(t, progress.RecordDone(ctx, "1.30.5", StepUpgrade))
+	require.Error(t, progress.RecordDone(ctx, "1.32.0", StepUpgrade))
+
+	progress, err = ReadProgress(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "1.31.1", progress.To)
+	assert.True(t, progress.IsDone("1.30.5", StepUpgrade))
+	assert.False(t, progress.IsDone("1.30.5", StepUpdate))
+	assert.False(t, progress.IsDone("1.31.1", StepUpgrade))
+	assert.Nil(t, progress.CompletedAt)
+
+	require.NoError(t, progress.Complete(ctx))
+	progress, err = ReadProgress(ctx, path)
+	require.NoError(t, err)
+	assert.NotNil(t, progress.CompletedAt)
+}
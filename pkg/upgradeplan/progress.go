@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// The steps of upgrading to each version, in order.
+const (
+	// StepPreflight checks that the cluster can be upgraded to the version.
+	StepPreflight = "preflight"
+	// StepUpgrade sets the version in the cluster spec, as kops upgrade cluster does.
+	StepUpgrade = "upgrade"
+	// StepUpdate applies the cluster spec, as kops update cluster does.
+	StepUpdate = "update"
+	// StepRollingUpdateControlPlane replaces the control plane nodes.
+	StepRollingUpdateControlPlane = "rolling-update-control-plane"
+	// StepRollingUpdateNodes replaces the other nodes.
+	StepRollingUpdateNodes = "rolling-update-nodes"
+	// StepPrune removes the objects no longer used after the rolling update, as kops update cluster --prune does.
+	StepPrune = "prune"
+	// StepValidate validates the cluster.
+	StepValidate = "validate"
+)
+
+// Steps are the steps of upgrading to each version, in order.
+var Steps = []string{
+	StepPreflight,
+	StepUpgrade,
+	StepUpdate,
+	StepRollingUpdateControlPlane,
+	StepRollingUpdateNodes,
+	StepPrune,
+	StepValidate,
+}
+
+// Progress records the progress of upgrading a cluster through several Kubernetes versions,
+// so that an interrupted upgrade can be resumed.
+type Progress struct {
+	// ClusterName is the name of the cluster being upgraded.
+	ClusterName string `json:"clusterName"`
+	// From is the Kubernetes version the upgrade started from.
+	From string `json:"from"`
+	// To is the Kubernetes version to upgrade to.
+	To string `json:"to"`
+	// StartedAt is the time the upgrade was first started.
+	StartedAt time.Time `json:"startedAt"`
+	// CompletedAt is the time the cluster was validated with the last version.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	// Versions are the versions to upgrade through, and the steps completed for each one.
+	Versions []*VersionProgress `json:"versions"`
+
+	// path is where the progress is persisted; the progress is not persisted if nil.
+	path vfs.Path
+}
+
+// VersionProgress is the progress of upgrading to a single version.
+type VersionProgress struct {
+	Version string `json:"version"`
+	// Completed are the steps completed.
+	Completed []string `json:"completed,omitempty"`
+}
+
+// NewProgress builds the progress for starting an upgrade through versions, which is persisted to path if not nil.
+func NewProgress(path vfs.Path, clusterName string, from string, versions []string) *Progress {
+	p := &Progress{
+		ClusterName: clusterName,
+		From:        from,
+		StartedAt:   time.Now().UTC(),
+		path:        path,
+	}
+	for _, version := range versions {
+		p.Versions = append(p.Versions, &VersionProgress{Version: version})
+	}
+	if len(versions) != 0 {
+		p.To = versions[len(versions)-1]
+	}
+	return p
+}
+
+// ReadProgress reads the progress of an upgrade from path, returning nil if there is none.
+func ReadProgress(ctx context.Context, path vfs.Path) (*Progress, error) {
+	b, err := path.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading upgrade progress %s: %w", path, err)
+	}
+	p := &Progress{path: path}
+	if err := yaml.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("error parsing upgrade progress %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// IsDone returns true if the step of upgrading to the version is completed.
+func (p *Progress) IsDone(version, step string) bool {
+	for _, v := range p.Versions {
+		if v.Version != version {
+			continue
+		}
+		for _, completed := range v.Completed {
+			if completed == step {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RecordDone records that the step of upgrading to the version is completed.
+func (p *Progress) RecordDone(ctx context.Context, version, step string) error {
+	for _, v := range p.Versions {
+		if v.Version == version {
+			v.Completed = append(v.Completed, step)
+			return p.write(ctx)
+		}
+	}
+	return fmt.Errorf("version %s is not part of the upgrade", version)
+}
+
+// Complete records that the upgrade is completed.
+func (p *Progress) Complete(ctx context.Context) error {
+	now := time.Now().UTC()
+	p.CompletedAt = &now
+	return p.write(ctx)
+}
+
+func (p *Progress) write(ctx context.Context) error {
+	if p.path == nil {
+		return nil
+	}
+	b, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("error serializing upgrade progress: %w", err)
+	}
+	if err := p.path.WriteFile(ctx, bytes.NewReader(b), nil); err != nil {
+		return fmt.Errorf("error writing upgrade progress %s: %w", p.path, err)
+	}
+	return nil
+}
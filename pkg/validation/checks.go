@@ -0,0 +1,364 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// Check is an additional check run when validating a cluster.
+// The failures it returns gate rolling updates like the built-in checks do.
+type Check interface {
+	// Check returns the validation failures found by the check.
+	// An error means the check could not run, and is reported as a failure.
+	Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error)
+}
+
+// CheckContext holds the cluster being validated.
+type CheckContext struct {
+	Cluster   *kops.Cluster
+	K8sClient kubernetes.Interface
+	// Nodes are the nodes registered with the cluster.
+	Nodes []v1.Node
+	// NodeInstanceGroups maps the names of the nodes matched to cloud instances to their instance groups.
+	NodeInstanceGroups map[string]*kops.InstanceGroup
+}
+
+// CheckFactory builds a check from its configuration in the cluster spec.
+type CheckFactory func(config map[string]string) (Check, error)
+
+var (
+	checkFactoriesMutex sync.Mutex
+	checkFactories      = make(map[string]CheckFactory)
+)
+
+// RegisterCheck registers a check that can be configured as a plugin validation check in the cluster spec.
+func RegisterCheck(name string, factory CheckFactory) {
+	checkFactoriesMutex.Lock()
+	defer checkFactoriesMutex.Unlock()
+
+	if _, found := checkFactories[name]; found {
+		panic(fmt.Sprintf("validation check %q is already registered", name))
+	}
+	checkFactories[name] = factory
+}
+
+// RegisteredChecks returns the names of the registered checks.
+func RegisteredChecks() []string {
+	checkFactoriesMutex.Lock()
+	defer checkFactoriesMutex.Unlock()
+
+	var names []string
+	for name := range checkFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// buildChecks builds the checks configured in the cluster spec.
+func buildChecks(specs []kops.ValidationCheckSpec) ([]namedCheck, error) {
+	var checks []namedCheck
+	for _, spec := range specs {
+		var check Check
+		switch {
+		case spec.Deployments != nil:
+			check = &deploymentsCheck{spec: spec.Deployments}
+		case spec.HTTPGet != nil:
+			check = &httpGetCheck{spec: spec.HTTPGet}
+		case spec.PodDisruptionBudgets != nil:
+			check = &podDisruptionBudgetsCheck{spec: spec.PodDisruptionBudgets}
+		case spec.CustomResources != nil:
+			check = &customResourcesCheck{spec: spec.CustomResources}
+		case spec.NodeConditions != nil:
+			check = &nodeConditionsCheck{spec: spec.NodeConditions}
+		case spec.Plugin != nil:
+			checkFactoriesMutex.Lock()
+			factory := checkFactories[spec.Plugin.Name]
+			checkFactoriesMutex.Unlock()
+			if factory == nil {
+				return nil, fmt.Errorf("validation check %q uses unknown plugin %q; registered plugins: %v", spec.Name, spec.Plugin.Name, RegisteredChecks())
+			}
+			var err error
+			check, err = factory(spec.Plugin.Config)
+			if err != nil {
+				return nil, fmt.Errorf("building validation check %q: %w", spec.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("validation check %q has no check configured", spec.Name)
+		}
+		checks = append(checks, namedCheck{name: spec.Name, check: check})
+	}
+	return checks, nil
+}
+
+// runChecks runs the checks, reporting the checks that could not run as failures.
+func (v *ValidationCluster) runChecks(ctx context.Context, checks []namedCheck, c *CheckContext) {
+	for _, check := range checks {
+		failures, err := check.check.Check(ctx, c)
+		if err != nil {
+			v.addError(&ValidationError{
+				Kind:    "ValidationCheck",
+				Name:    check.name,
+				Message: fmt.Sprintf("validation check %q could not run: %v", check.name, err),
+			})
+			continue
+		}
+		for _, failure := range failures {
+			failure.Message = fmt.Sprintf("validation check %q: %s", check.name, failure.Message)
+			v.addError(failure)
+		}
+	}
+}
+
+type deploymentsCheck struct {
+	spec *kops.DeploymentsValidationCheck
+}
+
+func (d *deploymentsCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	deployments, err := c.K8sClient.AppsV1().Deployments(d.spec.Namespace).List(ctx, metav1.ListOptions{LabelSelector: d.spec.Selector})
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	var failures []*ValidationError
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.AvailableReplicas < replicas {
+			failures = append(failures, &ValidationError{
+				Kind:    "Deployment",
+				Name:    deployment.Namespace + "/" + deployment.Name,
+				Message: fmt.Sprintf("deployment %q has %d of %d replicas available", deployment.Namespace+"/"+deployment.Name, deployment.Status.AvailableReplicas, replicas),
+			})
+		}
+	}
+	return failures, nil
+}
+
+type httpGetCheck struct {
+	spec *kops.HTTPGetValidationCheck
+}
+
+func (h *httpGetCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	timeout := 10 * time.Second
+	if h.spec.Timeout != nil {
+		timeout = h.spec.Timeout.Duration
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: h.spec.InsecureSkipVerify},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.spec.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %q: %w", h.spec.URL, err)
+	}
+	failure := &ValidationError{
+		Kind: "HTTPGet",
+		Name: h.spec.URL,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		failure.Message = fmt.Sprintf("GET %q failed: %v", h.spec.URL, err)
+		return []*ValidationError{failure}, nil
+	}
+	resp.Body.Close()
+
+	if h.spec.ExpectedStatus != nil {
+		if resp.StatusCode != int(*h.spec.ExpectedStatus) {
+			failure.Message = fmt.Sprintf("GET %q returned status %d, expected %d", h.spec.URL, resp.StatusCode, *h.spec.ExpectedStatus)
+			return []*ValidationError{failure}, nil
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		failure.Message = fmt.Sprintf("GET %q returned status %d", h.spec.URL, resp.StatusCode)
+		return []*ValidationError{failure}, nil
+	}
+	return nil, nil
+}
+
+type podDisruptionBudgetsCheck struct {
+	spec *kops.PodDisruptionBudgetsValidationCheck
+}
+
+func (p *podDisruptionBudgetsCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	pdbs, err := c.K8sClient.PolicyV1().PodDisruptionBudgets(p.spec.Namespace).List(ctx, metav1.ListOptions{LabelSelector: p.spec.Selector})
+	if err != nil {
+		return nil, fmt.Errorf("listing pod disruption budgets: %w", err)
+	}
+
+	var failures []*ValidationError
+	for _, pdb := range pdbs.Items {
+		// A PodDisruptionBudget without pods does not block any eviction.
+		if pdb.Status.ExpectedPods > 0 && pdb.Status.DisruptionsAllowed <= 0 {
+			failures = append(failures, &ValidationError{
+				Kind:    "PodDisruptionBudget",
+				Name:    pdb.Namespace + "/" + pdb.Name,
+				Message: fmt.Sprintf("pod disruption budget %q allows no disruptions (%d of %d pods healthy)", pdb.Namespace+"/"+pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods),
+			})
+		}
+	}
+	return failures, nil
+}
+
+type customResourcesCheck struct {
+	spec *kops.CustomResourcesValidationCheck
+}
+
+// customResourceList holds the fields of custom resources read by the check.
+type customResourceList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func (r *customResourcesCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	gv, err := schema.ParseGroupVersion(r.spec.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parsing apiVersion %q: %w", r.spec.APIVersion, err)
+	}
+	path := "/apis/" + gv.Group + "/" + gv.Version
+	if gv.Group == "" {
+		path = "/api/" + gv.Version
+	}
+	if r.spec.Namespace != "" {
+		path += "/namespaces/" + r.spec.Namespace
+	}
+	path += "/" + r.spec.Resource
+
+	restClient := c.K8sClient.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("kubernetes client does not support listing %s", r.spec.Resource)
+	}
+	req := restClient.Get().AbsPath(path)
+	if r.spec.Selector != "" {
+		req = req.Param("labelSelector", r.spec.Selector)
+	}
+	raw, err := req.Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", r.spec.Resource, err)
+	}
+	list := &customResourceList{}
+	if err := json.Unmarshal(raw, list); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", r.spec.Resource, err)
+	}
+
+	conditionType := r.spec.Condition
+	if conditionType == "" {
+		conditionType = "Ready"
+	}
+
+	var failures []*ValidationError
+	for _, item := range list.Items {
+		name := item.Metadata.Name
+		if item.Metadata.Namespace != "" {
+			name = item.Metadata.Namespace + "/" + name
+		}
+		message := fmt.Sprintf("%s %q has no %s condition", r.spec.Resource, name, conditionType)
+		for _, condition := range item.Status.Conditions {
+			if condition.Type != conditionType {
+				continue
+			}
+			if condition.Status == string(metav1.ConditionTrue) {
+				message = ""
+			} else {
+				message = fmt.Sprintf("%s %q is not %s: %s", r.spec.Resource, name, conditionType, condition.Message)
+			}
+			break
+		}
+		if message != "" {
+			failures = append(failures, &ValidationError{
+				Kind:    r.spec.Resource,
+				Name:    name,
+				Message: message,
+			})
+		}
+	}
+	return failures, nil
+}
+
+type nodeConditionsCheck struct {
+	spec *kops.NodeConditionsValidationCheck
+}
+
+func (n *nodeConditionsCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	types := make(map[v1.NodeConditionType]bool)
+	for _, conditionType := range n.spec.Types {
+		types[v1.NodeConditionType(conditionType)] = true
+	}
+
+	var failures []*ValidationError
+	for _, node := range c.Nodes {
+		// Nodes that are not matched to an instance group are reported by the node validation.
+		ig := c.NodeInstanceGroups[node.Name]
+		if ig == nil {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if !types[condition.Type] || condition.Status != v1.ConditionTrue {
+				continue
+			}
+			message := fmt.Sprintf("node %q has condition %s", node.Name, condition.Type)
+			if condition.Reason != "" {
+				message += " (" + condition.Reason + ")"
+			}
+			if condition.Message != "" {
+				message += ": " + condition.Message
+			}
+			failures = append(failures, &ValidationError{
+				Kind:          "Node",
+				Name:          node.Name,
+				Message:       message,
+				InstanceGroup: ig,
+			})
+		}
+	}
+	return failures, nil
+}
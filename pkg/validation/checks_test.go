@@ -0,0 +1,262 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func testChecks(t *testing.T, specs []kopsapi.ValidationCheckSpec, k8sClient kubernetes.Interface) []*ValidationError {
+	checks, err := buildChecks(specs)
+	require.NoError(t, err)
+
+	v := &ValidationCluster{}
+	v.runChecks(context.Background(), checks, &CheckContext{Cluster: &kopsapi.Cluster{}, K8sClient: k8sClient})
+	return v.Failures
+}
+
+func Test_DeploymentsCheck(t *testing.T) {
+	deployment := func(name string, replicas, available int32) runtime.Object {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ingress", Labels: map[string]string{"app": "ingress"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(replicas)},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+	k8sClient := fake.NewSimpleClientset(
+		deployment("available", 2, 2),
+		deployment("unavailable", 3, 1),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "unselected", Namespace: "ingress"},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(1))},
+		},
+	)
+
+	failures := testChecks(t, []kopsapi.ValidationCheckSpec{
+		{Name: "ingress", Deployments: &kopsapi.DeploymentsValidationCheck{Selector: "app=ingress"}},
+	}, k8sClient)
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Deployment",
+			Name:    "ingress/unavailable",
+			Message: "validation check \"ingress\": deployment \"ingress/unavailable\" has 1 of 3 replicas available",
+		},
+	}, failures)
+}
+
+func Test_PodDisruptionBudgetsCheck(t *testing.T) {
+	pdb := func(name string, expected, allowed int32) runtime.Object {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     policyv1.PodDisruptionBudgetStatus{ExpectedPods: expected, CurrentHealthy: expected, DisruptionsAllowed: allowed},
+		}
+	}
+	k8sClient := fake.NewSimpleClientset(
+		pdb("allows", 3, 1),
+		pdb("blocks", 2, 0),
+		pdb("empty", 0, 0),
+	)
+
+	failures := testChecks(t, []kopsapi.ValidationCheckSpec{
+		{Name: "pdbs", PodDisruptionBudgets: &kopsapi.PodDisruptionBudgetsValidationCheck{}},
+	}, k8sClient)
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "PodDisruptionBudget",
+			Name:    "default/blocks",
+			Message: "validation check \"pdbs\": pod disruption budget \"default/blocks\" allows no disruptions (2 of 2 pods healthy)",
+		},
+	}, failures)
+}
+
+func Test_HTTPGetCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/nocontent":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	failures := testChecks(t, []kopsapi.ValidationCheckSpec{
+		{Name: "healthy", HTTPGet: &kopsapi.HTTPGetValidationCheck{URL: server.URL + "/healthz"}},
+		{Name: "unhealthy", HTTPGet: &kopsapi.HTTPGetValidationCheck{URL: server.URL + "/unhealthy"}},
+		{Name: "unexpected", HTTPGet: &kopsapi.HTTPGetValidationCheck{URL: server.URL + "/healthz", ExpectedStatus: fi.PtrTo(int32(http.StatusNoContent))}},
+		{Name: "expected", HTTPGet: &kopsapi.HTTPGetValidationCheck{URL: server.URL + "/nocontent", ExpectedStatus: fi.PtrTo(int32(http.StatusNoContent))}},
+	}, fake.NewSimpleClientset())
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "HTTPGet",
+			Name:    server.URL + "/unhealthy",
+			Message: fmt.Sprintf("validation check \"unhealthy\": GET %q returned status 503", server.URL+"/unhealthy"),
+		},
+		{
+			Kind:    "HTTPGet",
+			Name:    server.URL + "/healthz",
+			Message: fmt.Sprintf("validation check \"unexpected\": GET %q returned status 200, expected 204", server.URL+"/healthz"),
+		},
+	}, failures)
+}
+
+func Test_CustomResourcesCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/cert-manager.io/v1/namespaces/default/certificates" || r.URL.Query().Get("labelSelector") != "app=web" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items": [
+			{"metadata": {"name": "ready", "namespace": "default"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+			{"metadata": {"name": "expired", "namespace": "default"}, "status": {"conditions": [{"type": "Ready", "status": "False", "message": "certificate expired"}]}},
+			{"metadata": {"name": "new", "namespace": "default"}}
+		]}`)
+	}))
+	defer server.Close()
+
+	k8sClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	failures := testChecks(t, []kopsapi.ValidationCheckSpec{
+		{Name: "certificates", CustomResources: &kopsapi.CustomResourcesValidationCheck{APIVersion: "cert-manager.io/v1", Resource: "certificates", Namespace: "default", Selector: "app=web"}},
+		{Name: "missing", CustomResources: &kopsapi.CustomResourcesValidationCheck{APIVersion: "example.com/v1", Resource: "widgets"}},
+	}, k8sClient)
+
+	require.Len(t, failures, 3)
+	assert.Equal(t, &ValidationError{
+		Kind:    "certificates",
+		Name:    "default/expired",
+		Message: "validation check \"certificates\": certificates \"default/expired\" is not Ready: certificate expired",
+	}, failures[0])
+	assert.Equal(t, &ValidationError{
+		Kind:    "certificates",
+		Name:    "default/new",
+		Message: "validation check \"certificates\": certificates \"default/new\" has no Ready condition",
+	}, failures[1])
+	assert.Equal(t, "ValidationCheck", failures[2].Kind)
+	assert.Equal(t, "missing", failures[2].Name)
+}
+
+func Test_NodeConditionsCheck(t *testing.T) {
+	node := func(name string, conditions ...v1.NodeCondition) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     v1.NodeStatus{Conditions: append([]v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}, conditions...)},
+		}
+	}
+	nodes := &kopsapi.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+
+	checks, err := buildChecks([]kopsapi.ValidationCheckSpec{
+		{Name: "node-problems", NodeConditions: &kopsapi.NodeConditionsValidationCheck{Types: []string{"KernelDeadlock", "ReadonlyFilesystem"}}},
+	})
+	require.NoError(t, err)
+
+	v := &ValidationCluster{}
+	v.runChecks(context.Background(), checks, &CheckContext{
+		Cluster:   &kopsapi.Cluster{},
+		K8sClient: fake.NewSimpleClientset(),
+		Nodes: []v1.Node{
+			node("healthy", v1.NodeCondition{Type: "KernelDeadlock", Status: v1.ConditionFalse}),
+			node("deadlocked", v1.NodeCondition{Type: "KernelDeadlock", Status: v1.ConditionTrue, Reason: "DockerHung", Message: "task docker blocked"}),
+			node("other", v1.NodeCondition{Type: "FrequentKubeletRestart", Status: v1.ConditionTrue}),
+			node("unmatched", v1.NodeCondition{Type: "ReadonlyFilesystem", Status: v1.ConditionTrue}),
+		},
+		NodeInstanceGroups: map[string]*kopsapi.InstanceGroup{
+			"healthy":    nodes,
+			"deadlocked": nodes,
+			"other":      nodes,
+		},
+	})
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:          "Node",
+			Name:          "deadlocked",
+			Message:       "validation check \"node-problems\": node \"deadlocked\" has condition KernelDeadlock (DockerHung): task docker blocked",
+			InstanceGroup: nodes,
+		},
+	}, v.Failures)
+}
+
+type testPluginCheck struct {
+	config map[string]string
+}
+
+func (p *testPluginCheck) Check(ctx context.Context, c *CheckContext) ([]*ValidationError, error) {
+	if p.config["fail"] == "error" {
+		return nil, fmt.Errorf("plugin error")
+	}
+	return []*ValidationError{
+		{
+			Kind:    "Plugin",
+			Name:    c.Cluster.Name,
+			Message: p.config["message"],
+		},
+	}, nil
+}
+
+func Test_PluginCheck(t *testing.T) {
+	RegisterCheck("test-plugin", func(config map[string]string) (Check, error) {
+		return &testPluginCheck{config: config}, nil
+	})
+
+	failures := testChecks(t, []kopsapi.ValidationCheckSpec{
+		{Name: "custom", Plugin: &kopsapi.PluginValidationCheck{Name: "test-plugin", Config: map[string]string{"message": "custom failure"}}},
+		{Name: "broken", Plugin: &kopsapi.PluginValidationCheck{Name: "test-plugin", Config: map[string]string{"fail": "error"}}},
+	}, fake.NewSimpleClientset())
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Plugin",
+			Message: "validation check \"custom\": custom failure",
+		},
+		{
+			Kind:    "ValidationCheck",
+			Name:    "broken",
+			Message: "validation check \"broken\" could not run: plugin error",
+		},
+	}, failures)
+
+	_, err := buildChecks([]kopsapi.ValidationCheckSpec{
+		{Name: "unknown", Plugin: &kopsapi.PluginValidationCheck{Name: "unknown-plugin"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown plugin \"unknown-plugin\"")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// Metrics exposes the results of cluster validations as Prometheus metrics,
+// so that alerts use the same definition of a healthy cluster as rolling updates.
+type Metrics struct {
+	runs                 *prometheus.CounterVec
+	healthy              prometheus.Gauge
+	lastRun              prometheus.Gauge
+	failures             *prometheus.GaugeVec
+	targetSize           *prometheus.GaugeVec
+	cloudInstances       *prometheus.GaugeVec
+	nodes                *prometheus.GaugeVec
+	readyNodes           *prometheus.GaugeVec
+	registeredNodesRatio *prometheus.GaugeVec
+}
+
+// NewMetrics builds the validation metrics of a cluster and registers them.
+func NewMetrics(registerer prometheus.Registerer, clusterName string) (*Metrics, error) {
+	labels := prometheus.Labels{"cluster": clusterName}
+	igLabels := []string{"instance_group", "role"}
+	m := &Metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "kops_validation_runs_total",
+			Help:        "Number of cluster validations, by result: healthy, unhealthy or error.",
+			ConstLabels: labels,
+		}, []string{"result"}),
+		healthy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "kops_validation_cluster_healthy",
+			Help:        "Whether the last cluster validation passed, which is what rolling updates wait for.",
+			ConstLabels: labels,
+		}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "kops_validation_last_run_timestamp_seconds",
+			Help:        "Time of the last cluster validation.",
+			ConstLabels: labels,
+		}),
+		failures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_failures",
+			Help:        "Number of failures of the last cluster validation, by kind and instance group.",
+			ConstLabels: labels,
+		}, []string{"kind", "instance_group"}),
+		targetSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_instance_group_target_size",
+			Help:        "Number of instances the cloud provider is asked to run for the instance group.",
+			ConstLabels: labels,
+		}, igLabels),
+		cloudInstances: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_instance_group_cloud_instances",
+			Help:        "Number of instances the cloud provider runs for the instance group.",
+			ConstLabels: labels,
+		}, igLabels),
+		nodes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_instance_group_nodes",
+			Help:        "Number of instances of the instance group registered as nodes.",
+			ConstLabels: labels,
+		}, igLabels),
+		readyNodes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_instance_group_ready_nodes",
+			Help:        "Number of ready nodes of the instance group.",
+			ConstLabels: labels,
+		}, igLabels),
+		registeredNodesRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_instance_group_registered_nodes_ratio",
+			Help:        "Ratio of the registered nodes of the instance group to its cloud instances.",
+			ConstLabels: labels,
+		}, igLabels),
+	}
+
+	for _, collector := range []prometheus.Collector{m.runs, m.healthy, m.lastRun, m.failures, m.targetSize, m.cloudInstances, m.nodes, m.readyNodes, m.registeredNodesRatio} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Record updates the metrics with the result of a cluster validation.
+// When the validation could not run, the cluster is reported as unhealthy
+// and the metrics of the previous validation are kept.
+func (m *Metrics) Record(result *ValidationCluster, err error, now time.Time) {
+	m.lastRun.Set(float64(now.Unix()))
+	if err != nil {
+		m.runs.WithLabelValues("error").Inc()
+		m.healthy.Set(0)
+		return
+	}
+
+	if len(result.Failures) == 0 {
+		m.runs.WithLabelValues("healthy").Inc()
+		m.healthy.Set(1)
+	} else {
+		m.runs.WithLabelValues("unhealthy").Inc()
+		m.healthy.Set(0)
+	}
+
+	m.failures.Reset()
+	for _, failure := range result.Failures {
+		ig := ""
+		if failure.InstanceGroup != nil {
+			ig = failure.InstanceGroup.Name
+		}
+		m.failures.WithLabelValues(failure.Kind, ig).Inc()
+	}
+
+	for _, vec := range []*prometheus.GaugeVec{m.targetSize, m.cloudInstances, m.nodes, m.readyNodes, m.registeredNodesRatio} {
+		vec.Reset()
+	}
+	for _, group := range result.InstanceGroups {
+		m.targetSize.WithLabelValues(group.Name, group.Role).Set(float64(group.TargetSize))
+		m.cloudInstances.WithLabelValues(group.Name, group.Role).Set(float64(group.CloudInstances))
+		m.nodes.WithLabelValues(group.Name, group.Role).Set(float64(group.Nodes))
+		m.readyNodes.WithLabelValues(group.Name, group.Role).Set(float64(group.ReadyNodes))
+		// Bastions do not register as nodes
+		if group.Role == kops.InstanceGroupRoleBastion.ToLowerString() {
+			continue
+		}
+		ratio := 1.0
+		if group.CloudInstances > 0 {
+			ratio = float64(group.Nodes) / float64(group.CloudInstances)
+		}
+		m.registeredNodesRatio.WithLabelValues(group.Name, group.Role).Set(ratio)
+	}
+}
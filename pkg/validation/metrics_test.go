@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+func scrapeMetrics(t *testing.T, registry *prometheus.Registry) string {
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func Test_Metrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(registry, "example.com")
+	require.NoError(t, err)
+
+	nodes := &kopsapi.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+	now := time.Unix(1700000000, 0)
+	metrics.Record(&ValidationCluster{
+		Failures: []*ValidationError{
+			{Kind: "Machine", Name: "i-00001", InstanceGroup: nodes},
+			{Kind: "Node", Name: "node-1", InstanceGroup: nodes},
+			{Kind: "Node", Name: "node-2", InstanceGroup: nodes},
+			{Kind: "Pod", Name: "kube-system/coredns"},
+		},
+		InstanceGroups: []*ValidationInstanceGroup{
+			{Name: "bastions", Role: "bastion", TargetSize: 1, CloudInstances: 1},
+			{Name: "nodes", Role: "node", TargetSize: 4, CloudInstances: 4, Nodes: 3, ReadyNodes: 1},
+		},
+	}, nil, now)
+
+	scraped := scrapeMetrics(t, registry)
+	for _, expected := range []string{
+		`kops_validation_runs_total{cluster="example.com",result="unhealthy"} 1`,
+		`kops_validation_cluster_healthy{cluster="example.com"} 0`,
+		`kops_validation_last_run_timestamp_seconds{cluster="example.com"} 1.7e+09`,
+		`kops_validation_failures{cluster="example.com",instance_group="",kind="Pod"} 1`,
+		`kops_validation_failures{cluster="example.com",instance_group="nodes",kind="Machine"} 1`,
+		`kops_validation_failures{cluster="example.com",instance_group="nodes",kind="Node"} 2`,
+		`kops_validation_instance_group_target_size{cluster="example.com",instance_group="nodes",role="node"} 4`,
+		`kops_validation_instance_group_cloud_instances{cluster="example.com",instance_group="nodes",role="node"} 4`,
+		`kops_validation_instance_group_nodes{cluster="example.com",instance_group="nodes",role="node"} 3`,
+		`kops_validation_instance_group_ready_nodes{cluster="example.com",instance_group="nodes",role="node"} 1`,
+		`kops_validation_instance_group_registered_nodes_ratio{cluster="example.com",instance_group="nodes",role="node"} 0.75`,
+	} {
+		assert.Contains(t, scraped, expected)
+	}
+	assert.NotContains(t, scraped, `kops_validation_instance_group_registered_nodes_ratio{cluster="example.com",instance_group="bastions"`)
+
+	// A validation that cannot run is reported as unhealthy
+	metrics.Record(&ValidationCluster{}, nil, now)
+	metrics.Record(nil, fmt.Errorf("cannot reach the cluster"), now)
+
+	scraped = scrapeMetrics(t, registry)
+	for _, expected := range []string{
+		`kops_validation_runs_total{cluster="example.com",result="healthy"} 1`,
+		`kops_validation_runs_total{cluster="example.com",result="error"} 1`,
+		`kops_validation_cluster_healthy{cluster="example.com"} 0`,
+	} {
+		assert.Contains(t, scraped, expected)
+	}
+	assert.NotContains(t, scraped, "kops_validation_failures{")
+}
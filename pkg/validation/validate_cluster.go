@@ -42,6 +42,8 @@ type ValidationCluster struct {
 	Failures []*ValidationError `json:"failures,omitempty"`
 
 	Nodes []*ValidationNode `json:"nodes,omitempty"`
+
+	InstanceGroups []*ValidationInstanceGroup `json:"instanceGroups,omitempty"`
 }
 
 // ValidationError holds a validation failure
@@ -64,6 +66,7 @@ type clusterValidatorImpl struct {
 	instanceGroups []*kops.InstanceGroup
 	host           string
 	k8sClient      kubernetes.Interface
+	checks         []namedCheck
 }
 
 func (v *ValidationCluster) addError(failure *ValidationError) {
@@ -79,6 +82,20 @@ type ValidationNode struct {
 	Status   v1.ConditionStatus `json:"status,omitempty"`
 }
 
+// ValidationInstanceGroup counts the instances and the nodes of an instance group
+type ValidationInstanceGroup struct {
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+	// TargetSize is the number of instances the cloud provider is asked to run
+	TargetSize int `json:"targetSize"`
+	// CloudInstances is the number of instances the cloud provider is running, excluding detached instances
+	CloudInstances int `json:"cloudInstances"`
+	// Nodes is the number of instances that are registered as nodes
+	Nodes int `json:"nodes"`
+	// ReadyNodes is the number of registered nodes that are ready
+	ReadyNodes int `json:"readyNodes"`
+}
+
 // hasPlaceHolderIP checks if the API DNS has been updated.
 func hasPlaceHolderIP(host string) (string, error) {
 	apiAddr, err := url.Parse(host)
@@ -112,12 +129,18 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		return nil, fmt.Errorf("no InstanceGroup objects found")
 	}
 
+	checks, err := buildChecks(cluster.Spec.ValidationChecks)
+	if err != nil {
+		return nil, err
+	}
+
 	return &clusterValidatorImpl{
 		cluster:        cluster,
 		cloud:          cloud,
 		instanceGroups: instanceGroups,
 		host:           host,
 		k8sClient:      k8sClient,
+		checks:         checks,
 	}, nil
 }
 
@@ -170,6 +193,13 @@ func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", v.cluster.Name, err)
 	}
 
+	validation.runChecks(ctx, v.checks, &CheckContext{
+		Cluster:            v.cluster,
+		K8sClient:          v.k8sClient,
+		Nodes:              nodeList.Items,
+		NodeInstanceGroups: nodeInstanceGroupMapping,
+	})
+
 	return validation, nil
 }
 
@@ -291,6 +321,17 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 				numNodes++
 			}
 		}
+		role := cloudGroup.InstanceGroup.Spec.Role.ToLowerString()
+		if role == "" {
+			role = "node"
+		}
+		group := &ValidationInstanceGroup{
+			Name:           cloudGroup.InstanceGroup.Name,
+			Role:           role,
+			TargetSize:     cloudGroup.TargetSize,
+			CloudInstances: numNodes,
+		}
+		v.InstanceGroups = append(v.InstanceGroups, group)
 		if numNodes < cloudGroup.TargetSize {
 			v.addError(&ValidationError{
 				Kind: "InstanceGroup",
@@ -332,11 +373,7 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 			}
 
 			nodeInstanceGroupMapping[node.Name] = cloudGroup.InstanceGroup
-
-			role := cloudGroup.InstanceGroup.Spec.Role.ToLowerString()
-			if role == "" {
-				role = "node"
-			}
+			group.Nodes++
 
 			n := &ValidationNode{
 				Name:     node.Name,
@@ -349,6 +386,7 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 			ready := isNodeReady(node)
 			if ready {
 				readyNodes = append(readyNodes, *node)
+				group.ReadyNodes++
 			}
 
 			switch n.Role {
@@ -381,5 +419,9 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 		}
 	}
 
+	sort.Slice(v.InstanceGroups, func(i, j int) bool {
+		return v.InstanceGroups[i].Name < v.InstanceGroups[j].Name
+	})
+
 	return readyNodes, nodeInstanceGroupMapping
 }
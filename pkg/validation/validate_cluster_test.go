@@ -332,6 +332,9 @@ func Test_ValidateNodeNotReady(t *testing.T) {
 		}, v.Failures[0]) {
 		printDebug(t, v)
 	}
+	assert.Equal(t, []*ValidationInstanceGroup{
+		{Name: "node-1", Role: "node", TargetSize: 2, CloudInstances: 2, Nodes: 2, ReadyNodes: 1},
+	}, v.InstanceGroups)
 }
 
 func Test_ValidateMastersNotEnough(t *testing.T) {
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"slices"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// acceleratorDefaults holds the defaults for each accelerator vendor
+var acceleratorDefaults = map[kops.AcceleratorVendor]kops.AcceleratorSpec{
+	kops.AcceleratorVendorAWSNeuron: {
+		Name:          "neuron",
+		Packages:      []string{"aws-neuronx-dkms"},
+		KernelModules: []string{"neuron"},
+		DevicePlugin: &kops.DevicePluginSpec{
+			Image:        "public.ecr.aws/neuron/neuron-device-plugin:2.22.4.0",
+			ResourceName: "aws.amazon.com/neuron",
+		},
+	},
+	kops.AcceleratorVendorAMD: {
+		Name:          "amd-gpu",
+		Packages:      []string{"amdgpu-dkms"},
+		KernelModules: []string{"amdgpu"},
+		DevicePlugin: &kops.DevicePluginSpec{
+			Image:        "docker.io/rocm/k8s-device-plugin:1.25.2.8",
+			ResourceName: "amd.com/gpu",
+		},
+	},
+}
+
+// populateAccelerators fills in the vendor defaults of the accelerators,
+// and sets the node labels and taints the device plugins rely on.
+func populateAccelerators(ig *kops.InstanceGroup) {
+	for i := range ig.Spec.Accelerators {
+		accelerator := &ig.Spec.Accelerators[i]
+
+		defaults := acceleratorDefaults[accelerator.Vendor]
+		if accelerator.Name == "" {
+			accelerator.Name = defaults.Name
+		}
+		if len(accelerator.Packages) == 0 {
+			accelerator.Packages = slices.Clone(defaults.Packages)
+		}
+		if len(accelerator.KernelModules) == 0 {
+			accelerator.KernelModules = slices.Clone(defaults.KernelModules)
+		}
+		if defaults.DevicePlugin != nil {
+			if accelerator.DevicePlugin == nil {
+				accelerator.DevicePlugin = &kops.DevicePluginSpec{}
+			}
+			if accelerator.DevicePlugin.Image == "" {
+				accelerator.DevicePlugin.Image = defaults.DevicePlugin.Image
+			}
+			if accelerator.DevicePlugin.ResourceName == "" {
+				accelerator.DevicePlugin.ResourceName = defaults.DevicePlugin.ResourceName
+			}
+		}
+
+		if ig.Spec.NodeLabels == nil {
+			ig.Spec.NodeLabels = make(map[string]string)
+		}
+		ig.Spec.NodeLabels[kops.NodeLabelAcceleratorPrefix+accelerator.Name] = "1"
+
+		if accelerator.DevicePlugin != nil && accelerator.DevicePlugin.ResourceName != "" {
+			hasTaint := false
+			for _, taint := range ig.Spec.Taints {
+				if strings.HasPrefix(taint, accelerator.DevicePlugin.ResourceName+":") || strings.HasPrefix(taint, accelerator.DevicePlugin.ResourceName+"=") {
+					hasTaint = true
+				}
+			}
+			if !hasTaint {
+				ig.Spec.Taints = append(ig.Spec.Taints, accelerator.DevicePlugin.ResourceName+":NoSchedule")
+			}
+		}
+	}
+}
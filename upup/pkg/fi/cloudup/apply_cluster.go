@@ -56,6 +56,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/bootstrapchannelbuilder"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
@@ -105,6 +106,9 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOutput is where the dry-run target reports the changes; it defaults to stdout.
+	DryRunOutput io.Writer
+
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
@@ -133,6 +137,14 @@ type ApplyClusterCmd struct {
 
 	// DeletionProcessing controls whether we process deletions.
 	DeletionProcessing fi.DeletionProcessingMode
+
+	// TerraformImportExisting generates terraform import blocks for resources that already exist,
+	// when using the terraform target.
+	TerraformImportExisting bool
+
+	// TerraformModule writes a reusable terraform module, exposing environment-specific values as variables,
+	// when using the terraform target.
+	TerraformModule bool
 }
 
 // ApplyResults holds information about an ApplyClusterCmd operation.
@@ -157,6 +169,15 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 			return nil, fmt.Errorf("DO Terraform requires the DOTerraform feature flag to be enabled")
 		}
 	}
+	if c.TerraformImportExisting && c.TargetName != TargetTerraform {
+		return nil, fmt.Errorf("importing existing resources is only supported with the terraform target")
+	}
+	if c.TerraformModule && c.TargetName != TargetTerraform {
+		return nil, fmt.Errorf("writing a module is only supported with the terraform target")
+	}
+	if c.TargetName == TargetCloudformation && c.Cloud.ProviderID() != kops.CloudProviderAWS {
+		return nil, fmt.Errorf("cloud provider %v does not support the cloudformation target", c.Cloud.ProviderID())
+	}
 	if c.InstanceGroups == nil {
 		list, err := c.Clientset.InstanceGroupsFor(c.Cluster).List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -697,7 +718,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 			return nil, fmt.Errorf("unknown cloudprovider %q", cluster.GetCloudProvider())
 		}
 	}
-	c.TaskMap, err = l.BuildTasks(ctx, c.LifecycleOverrides)
+
+	lifecycleOverrideRules := fi.LifecycleOverrideRulesFromSpec(cluster.Spec.LifecycleOverrides)
+	c.TaskMap, err = l.BuildTasks(ctx, c.LifecycleOverrides, lifecycleOverrideRules)
 	if err != nil {
 		return nil, fmt.Errorf("error building tasks: %v", err)
 	}
@@ -732,6 +755,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 	case TargetTerraform:
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, project, outDir, cluster.Spec.Target)
+		tf.ImportExisting = c.TerraformImportExisting
+		tf.Module = c.TerraformModule
+		tf.ClusterName = cluster.ObjectMeta.Name
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraformWriter.LiteralFromStringValue(cloud.Region())); err != nil {
@@ -761,8 +787,29 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 		// Terraform tracks & performs deletions itself
 		deletionProcessingMode = fi.DeletionProcessingModeIgnore
 
+	case TargetCloudformation:
+		cf := cloudformation.NewCloudformationTarget(cloud, c.OutDir)
+
+		if err := cf.AddOutputVariable("Region", cloudformation.LiteralString(cloud.Region())); err != nil {
+			return nil, err
+		}
+		if err := cf.AddOutputVariable("ClusterName", cloudformation.LiteralString(cluster.ObjectMeta.Name)); err != nil {
+			return nil, err
+		}
+
+		target = cf
+
+		// Can cause conflicts with CloudFormation management
+		shouldPrecreateDNS = false
+
+		// CloudFormation tracks & performs deletions itself
+		deletionProcessingMode = fi.DeletionProcessingModeIgnore
+
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		if c.GetAssets {
 			out = io.Discard
 		}
@@ -777,7 +824,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 	c.Target = target
 
 	if target.DefaultCheckExisting() {
-		c.TaskMap, err = l.FindDeletions(cloud, c.LifecycleOverrides)
+		c.TaskMap, err = l.FindDeletions(cloud, c.LifecycleOverrides, lifecycleOverrideRules)
 		if err != nil {
 			return nil, fmt.Errorf("error finding deletions: %w", err)
 		}
@@ -30,6 +30,7 @@ import (
 
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/util/pkg/maps"
@@ -963,8 +964,8 @@ type terraformAutoscalingGroup struct {
 	Name                    *string                                          `cty:"name"`
 	LaunchConfigurationName *terraformWriter.Literal                         `cty:"launch_configuration"`
 	LaunchTemplate          *terraformAutoscalingLaunchTemplateSpecification `cty:"launch_template"`
-	MaxSize                 *int32                                           `cty:"max_size"`
-	MinSize                 *int32                                           `cty:"min_size"`
+	MaxSize                 *terraformWriter.Literal                         `cty:"max_size"`
+	MinSize                 *terraformWriter.Literal                         `cty:"min_size"`
 	MixedInstancesPolicy    []*terraformMixedInstancesPolicy                 `cty:"mixed_instances_policy"`
 	VPCZoneIdentifier       []*terraformWriter.Literal                       `cty:"vpc_zone_identifier"`
 	Tags                    []*terraformASGTag                               `cty:"tag"`
@@ -983,8 +984,6 @@ type terraformAutoscalingGroup struct {
 func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *AutoscalingGroup) error {
 	tf := &terraformAutoscalingGroup{
 		Name:                e.Name,
-		MinSize:             e.MinSize,
-		MaxSize:             e.MaxSize,
 		MetricsGranularity:  e.Granularity,
 		EnabledMetrics:      aws.StringSlice(e.Metrics),
 		InstanceProtection:  e.InstanceProtection,
@@ -992,6 +991,23 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		CapacityRebalance:   e.CapacityRebalance,
 	}
 
+	if e.MinSize != nil {
+		minSize, err := t.ModuleVariable(t.ResourceVariableName(fi.ValueOf(e.Name), "min_size"), "number",
+			fmt.Sprintf("Minimum size of the %s autoscaling group", fi.ValueOf(e.Name)), terraformWriter.LiteralFromIntValue(*e.MinSize))
+		if err != nil {
+			return err
+		}
+		tf.MinSize = minSize
+	}
+	if e.MaxSize != nil {
+		maxSize, err := t.ModuleVariable(t.ResourceVariableName(fi.ValueOf(e.Name), "max_size"), "number",
+			fmt.Sprintf("Maximum size of the %s autoscaling group", fi.ValueOf(e.Name)), terraformWriter.LiteralFromIntValue(*e.MaxSize))
+		if err != nil {
+			return err
+		}
+		tf.MaxSize = maxSize
+	}
+
 	for _, s := range e.Subnets {
 		tf.VPCZoneIdentifier = append(tf.VPCZoneIdentifier, s.TerraformLink())
 	}
@@ -1115,6 +1131,162 @@ func (e *AutoscalingGroup) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_autoscaling_group", fi.ValueOf(e.Name), "id")
 }
 
+type cloudformationASGTag struct {
+	Key               *string `json:"Key"`
+	Value             *string `json:"Value"`
+	PropagateAtLaunch *bool   `json:"PropagateAtLaunch"`
+}
+
+type cloudformationAutoscalingLaunchTemplateSpecification struct {
+	// LaunchTemplateID is the ID of the template to use.
+	LaunchTemplateID *cloudformation.Literal `json:"LaunchTemplateId"`
+	// Version is the version of the Launch Template to use.
+	Version *cloudformation.Literal `json:"Version"`
+}
+
+type cloudformationAutoscalingMixedInstancesPolicyLaunchTemplateOverride struct {
+	// InstanceType is the instance to use
+	InstanceType *string `json:"InstanceType,omitempty"`
+}
+
+type cloudformationAutoscalingMixedInstancesPolicyLaunchTemplate struct {
+	// LaunchTemplateSpecification is the definition for a LT
+	LaunchTemplateSpecification *cloudformationAutoscalingLaunchTemplateSpecification `json:"LaunchTemplateSpecification"`
+	// Overrides are the machine type overrides
+	Overrides []*cloudformationAutoscalingMixedInstancesPolicyLaunchTemplateOverride `json:"Overrides,omitempty"`
+}
+
+type cloudformationAutoscalingInstanceDistribution struct {
+	// OnDemandAllocationStrategy
+	OnDemandAllocationStrategy *string `json:"OnDemandAllocationStrategy,omitempty"`
+	// OnDemandBaseCapacity is the base ondemand requirement
+	OnDemandBaseCapacity *int32 `json:"OnDemandBaseCapacity,omitempty"`
+	// OnDemandPercentageAboveBaseCapacity is the percentage above base for on-demand instances
+	OnDemandPercentageAboveBaseCapacity *int32 `json:"OnDemandPercentageAboveBaseCapacity,omitempty"`
+	// SpotAllocationStrategy is the spot allocation stratergy
+	SpotAllocationStrategy *string `json:"SpotAllocationStrategy,omitempty"`
+	// SpotInstancePools is the number of pools
+	SpotInstancePools *int32 `json:"SpotInstancePools,omitempty"`
+	// SpotMaxPrice is the max bid on spot instance, defaults to demand value
+	SpotMaxPrice *string `json:"SpotMaxPrice,omitempty"`
+}
+
+type cloudformationMixedInstancesPolicy struct {
+	// LaunchTemplate is the launch template spec
+	LaunchTemplate *cloudformationAutoscalingMixedInstancesPolicyLaunchTemplate `json:"LaunchTemplate"`
+	// InstancesDistribution is the distribution strategy
+	InstancesDistribution *cloudformationAutoscalingInstanceDistribution `json:"InstancesDistribution,omitempty"`
+}
+
+type cloudformationAutoscalingMetricsCollection struct {
+	Granularity *string  `json:"Granularity"`
+	Metrics     []string `json:"Metrics,omitempty"`
+}
+
+type cloudformationAutoscalingGroup struct {
+	Name                 *string                                               `json:"AutoScalingGroupName,omitempty"`
+	LaunchTemplate       *cloudformationAutoscalingLaunchTemplateSpecification `json:"LaunchTemplate,omitempty"`
+	MaxSize              *int32                                                `json:"MaxSize,omitempty"`
+	MinSize              *int32                                                `json:"MinSize,omitempty"`
+	MixedInstancesPolicy *cloudformationMixedInstancesPolicy                   `json:"MixedInstancesPolicy,omitempty"`
+	VPCZoneIdentifier    []*cloudformation.Literal                             `json:"VPCZoneIdentifier,omitempty"`
+	Tags                 []*cloudformationASGTag                               `json:"Tags,omitempty"`
+	MetricsCollection    []*cloudformationAutoscalingMetricsCollection         `json:"MetricsCollection,omitempty"`
+	InstanceProtection   *bool                                                 `json:"NewInstancesProtectedFromScaleIn,omitempty"`
+	LoadBalancerNames    []*cloudformation.Literal                             `json:"LoadBalancerNames,omitempty"`
+	TargetGroupARNs      []*cloudformation.Literal                             `json:"TargetGroupARNs,omitempty"`
+	MaxInstanceLifetime  *int32                                                `json:"MaxInstanceLifetime,omitempty"`
+	CapacityRebalance    *bool                                                 `json:"CapacityRebalance,omitempty"`
+}
+
+// RenderCloudformation is responsible for rendering the cloudformation template
+func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *AutoscalingGroup) error {
+	cf := &cloudformationAutoscalingGroup{
+		Name:                e.Name,
+		MinSize:             e.MinSize,
+		MaxSize:             e.MaxSize,
+		InstanceProtection:  e.InstanceProtection,
+		MaxInstanceLifetime: e.MaxInstanceLifetime,
+		CapacityRebalance:   e.CapacityRebalance,
+	}
+
+	if e.Granularity != nil || len(e.Metrics) != 0 {
+		cf.MetricsCollection = []*cloudformationAutoscalingMetricsCollection{
+			{
+				Granularity: e.Granularity,
+				Metrics:     e.Metrics,
+			},
+		}
+	}
+
+	for _, s := range e.Subnets {
+		cf.VPCZoneIdentifier = append(cf.VPCZoneIdentifier, s.CloudformationLink())
+	}
+
+	for _, k := range maps.SortedKeys(e.Tags) {
+		v := e.Tags[k]
+		cf.Tags = append(cf.Tags, &cloudformationASGTag{
+			Key:               fi.PtrTo(k),
+			Value:             fi.PtrTo(v),
+			PropagateAtLaunch: fi.PtrTo(true),
+		})
+	}
+
+	for _, k := range e.LoadBalancers {
+		cf.LoadBalancerNames = append(cf.LoadBalancerNames, k.CloudformationLink())
+	}
+	cloudformation.SortLiterals(cf.LoadBalancerNames)
+
+	for _, tg := range e.TargetGroups {
+		cf.TargetGroupARNs = append(cf.TargetGroupARNs, tg.CloudformationLink())
+	}
+	cloudformation.SortLiterals(cf.TargetGroupARNs)
+
+	if e.UseMixedInstancesPolicy() {
+		cf.MixedInstancesPolicy = &cloudformationMixedInstancesPolicy{
+			LaunchTemplate: &cloudformationAutoscalingMixedInstancesPolicyLaunchTemplate{
+				LaunchTemplateSpecification: &cloudformationAutoscalingLaunchTemplateSpecification{
+					LaunchTemplateID: e.LaunchTemplate.CloudformationLink(),
+					Version:          e.LaunchTemplate.CloudformationVersion(),
+				},
+			},
+			InstancesDistribution: &cloudformationAutoscalingInstanceDistribution{
+				OnDemandAllocationStrategy:          e.MixedOnDemandAllocationStrategy,
+				OnDemandBaseCapacity:                e.MixedOnDemandBase,
+				OnDemandPercentageAboveBaseCapacity: e.MixedOnDemandAboveBase,
+				SpotAllocationStrategy:              e.MixedSpotAllocationStrategy,
+				SpotInstancePools:                   e.MixedSpotInstancePools,
+				SpotMaxPrice:                        e.MixedSpotMaxPrice,
+			},
+		}
+
+		for _, x := range e.MixedInstanceOverrides {
+			cf.MixedInstancesPolicy.LaunchTemplate.Overrides = append(cf.MixedInstancesPolicy.LaunchTemplate.Overrides, &cloudformationAutoscalingMixedInstancesPolicyLaunchTemplateOverride{InstanceType: fi.PtrTo(x)})
+		}
+	} else if e.LaunchTemplate != nil {
+		cf.LaunchTemplate = &cloudformationAutoscalingLaunchTemplateSpecification{
+			LaunchTemplateID: e.LaunchTemplate.CloudformationLink(),
+			Version:          e.LaunchTemplate.CloudformationVersion(),
+		}
+	} else {
+		return fmt.Errorf("could not find one of launch configuration, mixed instances policy, or launch template")
+	}
+
+	if e.SuspendProcesses != nil && len(*e.SuspendProcesses) != 0 {
+		// CloudFormation only suspends processes during stack updates
+		klog.Warningf("suspended processes for autoscaling group %q are not supported by cloudformation", fi.ValueOf(e.Name))
+	}
+
+	// The warm pool is rendered by the WarmPool task
+
+	return t.RenderResource("AWS::AutoScaling::AutoScalingGroup", *e.Name, cf)
+}
+
+// CloudformationLink returns the name of the autoscaling group
+func (e *AutoscalingGroup) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::AutoScaling::AutoScalingGroup", fi.ValueOf(e.Name))
+}
+
 func (e *AutoscalingGroup) FindDeletions(context *fi.CloudupContext) ([]fi.CloudupDeletion, error) {
 	return e.deletions, nil
 }
@@ -1163,3 +1335,13 @@ func (d *deleteAutoscalingTargetGroupAttachment) Item() string {
 func (d *deleteAutoscalingTargetGroupAttachment) DeferDeletion() bool {
 	return true
 }
+
+var _ terraform.Importable = &AutoscalingGroup{}
+
+func (e *AutoscalingGroup) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*AutoscalingGroup)
+	if a.Name != nil {
+		t.AddImport("aws_autoscaling_group", *e.Name, *a.Name)
+	}
+	return nil
+}
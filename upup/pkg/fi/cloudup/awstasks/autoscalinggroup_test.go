@@ -419,6 +419,74 @@ terraform {
 	doRenderTests(t, "RenderTerraform", cases)
 }
 
+func TestAutoscalingGroupTerraformModuleRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &AutoscalingGroup{
+				Name:           fi.PtrTo("nodes.example.com"),
+				LaunchTemplate: &LaunchTemplate{Name: fi.PtrTo("nodes.example.com")},
+				MaxSize:        fi.PtrTo(int32(10)),
+				MinSize:        fi.PtrTo(int32(1)),
+				Subnets: []*Subnet{
+					{
+						Name: fi.PtrTo("test-sg"),
+						ID:   fi.PtrTo("sg-1111"),
+					},
+				},
+				Tags: map[string]string{
+					"cluster": "example.com",
+				},
+			},
+			Expected: `variable "cluster_name" {
+  default     = "example.com"
+  description = "Name of the cluster"
+  type        = string
+}
+
+variable "nodes_max_size" {
+  default     = 10
+  description = "Maximum size of the nodes.example.com autoscaling group"
+  type        = number
+}
+
+variable "nodes_min_size" {
+  default     = 1
+  description = "Minimum size of the nodes.example.com autoscaling group"
+  type        = number
+}
+
+resource "aws_autoscaling_group" "nodes-example-com" {
+  launch_template {
+    id      = aws_launch_template.nodes-example-com.id
+    version = aws_launch_template.nodes-example-com.latest_version
+  }
+  max_size = var.nodes_max_size
+  min_size = var.nodes_min_size
+  name     = "nodes.${var.cluster_name}"
+  tag {
+    key                 = "cluster"
+    propagate_at_launch = true
+    value               = var.cluster_name
+  }
+  vpc_zone_identifier = [aws_subnet.test-sg.id]
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	doRenderTests(t, "RenderTerraformModule", cases)
+}
+
 func TestTGsARNsChunks(t *testing.T) {
 	var tgsARNs []string
 	for i := 0; i < 30; i++ {
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -165,9 +166,43 @@ func (_ *AutoscalingLifecycleHook) RenderTerraform(t *terraform.TerraformTarget,
 	return t.RenderResource("aws_autoscaling_lifecycle_hook", *e.Name, tf)
 }
 
+type cloudformationASGLifecycleHook struct {
+	Name                 *string                 `json:"LifecycleHookName"`
+	AutoScalingGroupName *cloudformation.Literal `json:"AutoScalingGroupName"`
+	DefaultResult        *string                 `json:"DefaultResult,omitempty"`
+	HeartbeatTimeout     *int32                  `json:"HeartbeatTimeout,omitempty"`
+	LifecycleTransition  *string                 `json:"LifecycleTransition"`
+}
+
+func (_ *AutoscalingLifecycleHook) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *AutoscalingLifecycleHook) error {
+	if !fi.ValueOf(e.Enabled) {
+		return nil
+	}
+
+	cf := &cloudformationASGLifecycleHook{
+		Name:                 e.GetHookName(),
+		AutoScalingGroupName: e.AutoscalingGroup.CloudformationLink(),
+		DefaultResult:        e.DefaultResult,
+		HeartbeatTimeout:     e.HeartbeatTimeout,
+		LifecycleTransition:  e.LifecycleTransition,
+	}
+
+	return t.RenderResource("AWS::AutoScaling::LifecycleHook", *e.Name, cf)
+}
+
 func (h *AutoscalingLifecycleHook) GetHookName() *string {
 	if h.HookName != nil {
 		return h.HookName
 	}
 	return h.Name
 }
+
+var _ terraform.Importable = &AutoscalingLifecycleHook{}
+
+func (e *AutoscalingLifecycleHook) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	if e.AutoscalingGroup == nil || e.AutoscalingGroup.Name == nil {
+		return nil
+	}
+	t.AddImport("aws_autoscaling_lifecycle_hook", *e.Name, *e.AutoscalingGroup.Name+"/"+*e.GetHookName())
+	return nil
+}
@@ -31,8 +31,10 @@ import (
 	"k8s.io/kops/pkg/wellknownservices"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+	"k8s.io/kops/util/pkg/maps"
 	"k8s.io/kops/util/pkg/slice"
 )
 
@@ -764,3 +766,174 @@ func (e *ClassicLoadBalancer) TerraformLink(params ...string) *terraformWriter.L
 	}
 	return terraformWriter.LiteralProperty("aws_elb", *e.Name, prop)
 }
+
+type cloudformationClassicLoadBalancer struct {
+	LoadBalancerName         *string                                              `json:"LoadBalancerName"`
+	Listeners                []*cloudformationClassicLoadBalancerListener         `json:"Listeners"`
+	SecurityGroups           []*cloudformation.Literal                            `json:"SecurityGroups,omitempty"`
+	Subnets                  []*cloudformation.Literal                            `json:"Subnets,omitempty"`
+	Scheme                   *string                                              `json:"Scheme,omitempty"`
+	HealthCheck              *cloudformationClassicLoadBalancerHealthCheck        `json:"HealthCheck,omitempty"`
+	AccessLoggingPolicy      *cloudformationClassicLoadBalancerAccessLog          `json:"AccessLoggingPolicy,omitempty"`
+	ConnectionDrainingPolicy *cloudformationClassicLoadBalancerConnectionDraining `json:"ConnectionDrainingPolicy,omitempty"`
+	ConnectionSettings       *cloudformationClassicLoadBalancerConnectionSettings `json:"ConnectionSettings,omitempty"`
+	CrossZone                *bool                                                `json:"CrossZone,omitempty"`
+	Tags                     []cloudformation.Tag                                 `json:"Tags,omitempty"`
+}
+
+type cloudformationClassicLoadBalancerListener struct {
+	InstancePort     string  `json:"InstancePort"`
+	InstanceProtocol string  `json:"InstanceProtocol"`
+	LoadBalancerPort string  `json:"LoadBalancerPort"`
+	Protocol         string  `json:"Protocol"`
+	SSLCertificateID *string `json:"SSLCertificateId,omitempty"`
+}
+
+type cloudformationClassicLoadBalancerHealthCheck struct {
+	Target             *string `json:"Target"`
+	HealthyThreshold   string  `json:"HealthyThreshold"`
+	UnhealthyThreshold string  `json:"UnhealthyThreshold"`
+	Interval           string  `json:"Interval"`
+	Timeout            string  `json:"Timeout"`
+}
+
+type cloudformationClassicLoadBalancerAccessLog struct {
+	EmitInterval   *int32  `json:"EmitInterval,omitempty"`
+	Enabled        *bool   `json:"Enabled"`
+	S3BucketName   *string `json:"S3BucketName"`
+	S3BucketPrefix *string `json:"S3BucketPrefix,omitempty"`
+}
+
+type cloudformationClassicLoadBalancerConnectionDraining struct {
+	Enabled *bool  `json:"Enabled"`
+	Timeout *int32 `json:"Timeout,omitempty"`
+}
+
+type cloudformationClassicLoadBalancerConnectionSettings struct {
+	IdleTimeout *int32 `json:"IdleTimeout"`
+}
+
+func (_ *ClassicLoadBalancer) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *ClassicLoadBalancer) error {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		return nil
+	}
+
+	cloud := t.Cloud.(awsup.AWSCloud)
+
+	if e.LoadBalancerName == nil {
+		return fi.RequiredField("LoadBalancerName")
+	}
+
+	cf := &cloudformationClassicLoadBalancer{
+		LoadBalancerName: e.LoadBalancerName,
+	}
+	if fi.ValueOf(e.Scheme) == "internal" {
+		cf.Scheme = fi.PtrTo("internal")
+	}
+
+	for _, subnet := range e.Subnets {
+		cf.Subnets = append(cf.Subnets, subnet.CloudformationLink())
+	}
+	cloudformation.SortLiterals(cf.Subnets)
+
+	for _, sg := range e.SecurityGroups {
+		cf.SecurityGroups = append(cf.SecurityGroups, sg.CloudformationLink())
+	}
+	cloudformation.SortLiterals(cf.SecurityGroups)
+
+	for _, loadBalancerPort := range maps.SortedKeys(e.Listeners) {
+		listener := e.Listeners[loadBalancerPort]
+		if _, err := strconv.ParseInt(loadBalancerPort, 10, 64); err != nil {
+			return fmt.Errorf("error parsing load balancer listener port: %q", loadBalancerPort)
+		}
+
+		cfListener := &cloudformationClassicLoadBalancerListener{
+			InstancePort:     strconv.Itoa(int(listener.InstancePort)),
+			LoadBalancerPort: loadBalancerPort,
+		}
+		if listener.SSLCertificateID != "" {
+			cfListener.InstanceProtocol = "SSL"
+			cfListener.Protocol = "SSL"
+			cfListener.SSLCertificateID = &listener.SSLCertificateID
+		} else {
+			cfListener.InstanceProtocol = "TCP"
+			cfListener.Protocol = "TCP"
+		}
+		cf.Listeners = append(cf.Listeners, cfListener)
+	}
+
+	if e.HealthCheck != nil {
+		cf.HealthCheck = &cloudformationClassicLoadBalancerHealthCheck{
+			Target:             e.HealthCheck.Target,
+			HealthyThreshold:   strconv.Itoa(int(fi.ValueOf(e.HealthCheck.HealthyThreshold))),
+			UnhealthyThreshold: strconv.Itoa(int(fi.ValueOf(e.HealthCheck.UnhealthyThreshold))),
+			Interval:           strconv.Itoa(int(fi.ValueOf(e.HealthCheck.Interval))),
+			Timeout:            strconv.Itoa(int(fi.ValueOf(e.HealthCheck.Timeout))),
+		}
+	}
+
+	if e.AccessLog != nil && fi.ValueOf(e.AccessLog.Enabled) {
+		cf.AccessLoggingPolicy = &cloudformationClassicLoadBalancerAccessLog{
+			EmitInterval:   e.AccessLog.EmitInterval,
+			Enabled:        e.AccessLog.Enabled,
+			S3BucketName:   e.AccessLog.S3BucketName,
+			S3BucketPrefix: e.AccessLog.S3BucketPrefix,
+		}
+	}
+
+	if e.ConnectionDraining != nil {
+		cf.ConnectionDrainingPolicy = &cloudformationClassicLoadBalancerConnectionDraining{
+			Enabled: e.ConnectionDraining.Enabled,
+			Timeout: e.ConnectionDraining.Timeout,
+		}
+	}
+
+	if e.ConnectionSettings != nil {
+		cf.ConnectionSettings = &cloudformationClassicLoadBalancerConnectionSettings{
+			IdleTimeout: e.ConnectionSettings.IdleTimeout,
+		}
+	}
+
+	if e.CrossZoneLoadBalancing != nil {
+		cf.CrossZone = e.CrossZoneLoadBalancing.Enabled
+	}
+
+	tags := cloud.BuildTags(e.Name)
+	for k, v := range e.Tags {
+		tags[k] = v
+	}
+	cf.Tags = cloudformation.BuildTags(tags)
+
+	return t.RenderResource("AWS::ElasticLoadBalancing::LoadBalancer", *e.Name, cf)
+}
+
+// CloudformationLink returns the name of the load balancer
+func (e *ClassicLoadBalancer) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.LoadBalancerName == nil {
+			klog.Fatalf("Name must be set, if LB is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing LB with name %q", *e.LoadBalancerName)
+		return cloudformation.LiteralString(*e.LoadBalancerName)
+	}
+
+	return cloudformation.Ref("AWS::ElasticLoadBalancing::LoadBalancer", *e.Name)
+}
+
+func (e *ClassicLoadBalancer) CloudformationAlias() (dnsName *cloudformation.Literal, hostedZoneID *cloudformation.Literal) {
+	return cloudformation.GetAtt("AWS::ElasticLoadBalancing::LoadBalancer", *e.Name, "DNSName"),
+		cloudformation.GetAtt("AWS::ElasticLoadBalancing::LoadBalancer", *e.Name, "CanonicalHostedZoneNameID")
+}
+
+var _ terraform.Importable = &ClassicLoadBalancer{}
+
+func (e *ClassicLoadBalancer) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*ClassicLoadBalancer)
+	if a.LoadBalancerName != nil {
+		t.AddImport("aws_elb", *e.Name, *a.LoadBalancerName)
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -49,6 +50,7 @@ type DNSTarget interface {
 	getDNSName() *string
 	getHostedZoneId() *string
 	TerraformLink(...string) *terraformWriter.Literal
+	CloudformationAlias() (dnsName *cloudformation.Literal, hostedZoneID *cloudformation.Literal)
 }
 
 func (e *DNSName) Find(c *fi.CloudupContext) (*DNSName, error) {
@@ -286,3 +288,50 @@ func (_ *DNSName) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 func (e *DNSName) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route53_record", *e.Name)
 }
+
+type cloudformationRoute53Record struct {
+	Name            *string                 `json:"Name"`
+	Type            *string                 `json:"Type"`
+	TTL             *string                 `json:"TTL,omitempty"`
+	ResourceRecords []string                `json:"ResourceRecords,omitempty"`
+	AliasTarget     *cloudformationAlias    `json:"AliasTarget,omitempty"`
+	HostedZoneID    *cloudformation.Literal `json:"HostedZoneId"`
+}
+
+type cloudformationAlias struct {
+	DNSName              *cloudformation.Literal `json:"DNSName"`
+	HostedZoneID         *cloudformation.Literal `json:"HostedZoneId"`
+	EvaluateTargetHealth *bool                   `json:"EvaluateTargetHealth"`
+}
+
+func (_ *DNSName) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *DNSName) error {
+	cf := &cloudformationRoute53Record{
+		Name:         e.ResourceName,
+		HostedZoneID: e.Zone.CloudformationLink(),
+		Type:         e.ResourceType,
+	}
+
+	if e.TargetLoadBalancer != nil {
+		dnsName, hostedZoneID := e.TargetLoadBalancer.CloudformationAlias()
+		cf.AliasTarget = &cloudformationAlias{
+			DNSName:              dnsName,
+			EvaluateTargetHealth: aws.Bool(false),
+			HostedZoneID:         hostedZoneID,
+		}
+	}
+
+	return t.RenderResource("AWS::Route53::RecordSet", *e.Name, cf)
+}
+
+var _ terraform.Importable = &DNSName{}
+
+func (e *DNSName) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	if e.Zone == nil || e.Zone.ZoneID == nil {
+		return nil
+	}
+	// Records are imported using ZONEID_NAME_TYPE
+	zoneID := strings.TrimPrefix(*e.Zone.ZoneID, "/hostedzone/")
+	name := strings.TrimSuffix(fi.ValueOf(e.ResourceName), ".")
+	t.AddImport("aws_route53_record", *e.Name, zoneID+"_"+name+"_"+fi.ValueOf(e.ResourceType))
+	return nil
+}
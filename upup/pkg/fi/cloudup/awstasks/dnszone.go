@@ -30,6 +30,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -301,3 +302,44 @@ func (e *DNSZone) TerraformLink() *terraformWriter.Literal {
 
 	return terraformWriter.LiteralSelfLink("aws_route53_zone", *e.Name)
 }
+
+func (_ *DNSZone) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *DNSZone) error {
+	ctx := context.TODO()
+	cloud := t.Cloud.(awsup.AWSCloud)
+
+	dnsName := fi.ValueOf(e.DNSName)
+
+	// As with terraform, we only support re-using an existing zone
+	klog.Infof("Check for existing route53 zone to re-use with name %q", dnsName)
+	z, err := e.findExisting(ctx, cloud)
+	if err != nil {
+		return err
+	}
+	if z == nil {
+		return fmt.Errorf("Creation of Route53 hosted zones is not supported for cloudformation")
+	}
+
+	klog.Infof("Existing zone %q found; will configure CloudFormation to reuse", aws.ToString(z.HostedZone.Name))
+	e.ZoneID = z.HostedZone.Id
+
+	// CloudFormation has no resource for associating a VPC with an existing hosted zone
+	if e.PrivateVPC != nil {
+		if e.PrivateVPC.ID != nil {
+			for _, vpc := range z.VPCs {
+				if aws.ToString(vpc.VPCId) == *e.PrivateVPC.ID {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("route53 zone %q must be associated with the cluster VPC before using cloudformation", aws.ToString(z.HostedZone.Name))
+	}
+
+	return nil
+}
+
+func (e *DNSZone) CloudformationLink() *cloudformation.Literal {
+	if e.ZoneID == nil {
+		klog.Fatalf("ZoneID must be set for route53 zone %q", fi.ValueOf(e.Name))
+	}
+	return cloudformation.LiteralString(strings.TrimPrefix(*e.ZoneID, "/hostedzone/"))
+}
@@ -23,6 +23,7 @@ import (
 
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 
@@ -265,3 +266,40 @@ func (e *EBSVolume) PreRun(c *fi.CloudupContext) error {
 
 	return nil
 }
+
+type cloudformationVolume struct {
+	AvailabilityZone *string              `json:"AvailabilityZone"`
+	Size             *int32               `json:"Size,omitempty"`
+	Type             ec2types.VolumeType  `json:"VolumeType,omitempty"`
+	Iops             *int32               `json:"Iops,omitempty"`
+	Throughput       *int32               `json:"Throughput,omitempty"`
+	KmsKeyId         *string              `json:"KmsKeyId,omitempty"`
+	Encrypted        *bool                `json:"Encrypted,omitempty"`
+	Tags             []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (_ *EBSVolume) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EBSVolume) error {
+	cf := &cloudformationVolume{
+		AvailabilityZone: e.AvailabilityZone,
+		Size:             e.SizeGB,
+		Type:             e.VolumeType,
+		Iops:             e.VolumeIops,
+		Throughput:       e.VolumeThroughput,
+		KmsKeyId:         e.KmsKeyId,
+		Encrypted:        e.Encrypted,
+		Tags:             cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::Volume", *e.Name, cf)
+}
+
+var _ terraform.Importable = &EBSVolume{}
+
+func (e *EBSVolume) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*EBSVolume)
+	if a.ID != nil {
+		tfName, _ := e.TerraformName()
+		t.AddImport("aws_ebs_volume", tfName, *a.ID)
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -223,3 +224,65 @@ func (e *EgressOnlyInternetGateway) TerraformLink() *terraformWriter.Literal {
 
 	return terraformWriter.LiteralProperty("aws_egress_only_internet_gateway", *e.Name, "id")
 }
+
+type cloudformationEgressOnlyInternetGateway struct {
+	VPCID *cloudformation.Literal `json:"VpcId"`
+}
+
+func (_ *EgressOnlyInternetGateway) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EgressOnlyInternetGateway) error {
+	ctx := context.TODO()
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		// But ... attempt to discover the ID so CloudformationLink works
+		if e.ID == nil {
+			request := &ec2.DescribeEgressOnlyInternetGatewaysInput{}
+			vpcID := fi.ValueOf(e.VPC.ID)
+			if vpcID == "" {
+				return fmt.Errorf("VPC ID is required when EgressOnlyInternetGateway is shared")
+			}
+			request.Filters = []ec2types.Filter{awsup.NewEC2Filter("attachment.vpc-id", vpcID)}
+			igw, err := findEgressOnlyInternetGateway(ctx, t.Cloud.(awsup.AWSCloud), request)
+			if err != nil {
+				return err
+			}
+			if igw == nil {
+				klog.Warningf("Cannot find egress-only internet gateway for VPC %q", vpcID)
+			} else {
+				e.ID = igw.EgressOnlyInternetGatewayId
+			}
+		}
+		return nil
+	}
+
+	// AWS::EC2::EgressOnlyInternetGateway does not support tags
+	cf := &cloudformationEgressOnlyInternetGateway{
+		VPCID: e.VPC.CloudformationLink(),
+	}
+
+	return t.RenderResource("AWS::EC2::EgressOnlyInternetGateway", *e.Name, cf)
+}
+
+func (e *EgressOnlyInternetGateway) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if EgressOnlyInternetGateway is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing EgressOnlyInternetGateway with id %q", *e.ID)
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::EgressOnlyInternetGateway", *e.Name)
+}
+
+var _ terraform.Importable = &EgressOnlyInternetGateway{}
+
+func (e *EgressOnlyInternetGateway) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*EgressOnlyInternetGateway)
+	if a.ID != nil {
+		t.AddImport("aws_egress_only_internet_gateway", *e.Name, *a.ID)
+	}
+	return nil
+}
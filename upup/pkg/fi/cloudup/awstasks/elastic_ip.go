@@ -28,6 +28,7 @@ import (
 
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -305,3 +306,47 @@ func (e *ElasticIP) TerraformLink() *terraformWriter.Literal {
 
 	return terraformWriter.LiteralProperty("aws_eip", *e.Name, "id")
 }
+
+type cloudformationElasticIP struct {
+	Domain *string              `json:"Domain"`
+	Tags   []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (_ *ElasticIP) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *ElasticIP) error {
+	if fi.ValueOf(e.Shared) {
+		if e.ID == nil {
+			return fmt.Errorf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		klog.V(4).Infof("reusing existing ElasticIP with id %q", aws.ToString(e.ID))
+		return nil
+	}
+
+	cf := &cloudformationElasticIP{
+		Domain: aws.String("vpc"),
+		Tags:   cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::EIP", *e.Name, cf)
+}
+
+// CloudformationAllocationID returns the allocation ID of the Elastic IP
+func (e *ElasticIP) CloudformationAllocationID() *cloudformation.Literal {
+	if fi.ValueOf(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.GetAtt("AWS::EC2::EIP", *e.Name, "AllocationId")
+}
+
+var _ terraform.Importable = &ElasticIP{}
+
+func (e *ElasticIP) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*ElasticIP)
+	if a.ID != nil {
+		t.AddImport("aws_eip", *e.Name, *a.ID)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package awstasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -27,6 +28,7 @@ import (
 	awsResources "k8s.io/kops/pkg/resources/aws"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -155,3 +157,36 @@ func (_ *EventBridgeRule) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 func (eb *EventBridgeRule) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_cloudwatch_event_rule", fi.ValueOf(eb.Name), "id")
 }
+
+type cloudformationEventBridgeRule struct {
+	Name         *string                            `json:"Name"`
+	EventPattern json.RawMessage                    `json:"EventPattern"`
+	State        string                             `json:"State"`
+	Targets      []*cloudformationEventBridgeTarget `json:"Targets,omitempty"`
+}
+
+func (_ *EventBridgeRule) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EventBridgeRule) error {
+	pattern := []byte(fi.ValueOf(e.EventPattern))
+	if !json.Valid(pattern) {
+		return fmt.Errorf("event pattern for EventBridge rule %q is not valid JSON", fi.ValueOf(e.Name))
+	}
+
+	// AWS::Events::Rule does not support tags; targets are added by the EventBridgeTarget task
+	cf := &cloudformationEventBridgeRule{
+		Name:         e.Name,
+		EventPattern: json.RawMessage(pattern),
+		State:        "ENABLED",
+	}
+
+	return t.RenderResource("AWS::Events::Rule", *e.Name, cf)
+}
+
+var _ terraform.Importable = &EventBridgeRule{}
+
+func (e *EventBridgeRule) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*EventBridgeRule)
+	if a.Name != nil {
+		t.AddImport("aws_cloudwatch_event_rule", *e.Name, *a.Name)
+	}
+	return nil
+}
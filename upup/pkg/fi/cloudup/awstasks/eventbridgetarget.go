@@ -28,6 +28,7 @@ import (
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -141,3 +142,35 @@ func (_ *EventBridgeTarget) RenderTerraform(t *terraform.TerraformTarget, a, e,
 
 	return t.RenderResource("aws_cloudwatch_event_target", *e.Name, tf)
 }
+
+type cloudformationEventBridgeTarget struct {
+	ID  *string                 `json:"Id"`
+	Arn *cloudformation.Literal `json:"Arn"`
+}
+
+func (_ *EventBridgeTarget) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EventBridgeTarget) error {
+	target := &cloudformationEventBridgeTarget{
+		ID:  aws.String("1"),
+		Arn: e.SQSQueue.CloudformationLink(),
+	}
+
+	// CloudFormation only supports targets as a property of the rule
+	return t.UpdateResource("AWS::Events::Rule", *e.Rule.Name, func(properties interface{}) error {
+		rule, ok := properties.(*cloudformationEventBridgeRule)
+		if !ok {
+			return fmt.Errorf("unexpected properties type %T for EventBridge rule %q", properties, *e.Rule.Name)
+		}
+		rule.Targets = append(rule.Targets, target)
+		return nil
+	})
+}
+
+var _ terraform.Importable = &EventBridgeTarget{}
+
+func (e *EventBridgeTarget) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*EventBridgeTarget)
+	if a.ID != nil && e.Rule != nil && e.Rule.Name != nil {
+		t.AddImport("aws_cloudwatch_event_target", *e.Name, *e.Rule.Name+"/"+*a.ID)
+	}
+	return nil
+}
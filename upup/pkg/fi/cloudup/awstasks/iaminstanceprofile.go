@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 
@@ -187,3 +188,16 @@ func (e *IAMInstanceProfile) TerraformLink() *terraformWriter.Literal {
 	}
 	return terraformWriter.LiteralProperty("aws_iam_instance_profile", *e.Name, "id")
 }
+
+func (_ *IAMInstanceProfile) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMInstanceProfile) error {
+	// Done on IAMInstanceProfileRole
+	return nil
+}
+
+// CloudformationLink returns the name of the instance profile
+func (e *IAMInstanceProfile) CloudformationLink() *cloudformation.Literal {
+	if fi.ValueOf(e.Shared) {
+		return cloudformation.LiteralString(fi.ValueOf(e.Name))
+	}
+	return cloudformation.Ref("AWS::IAM::InstanceProfile", *e.Name)
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -124,3 +125,28 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
+
+type cloudformationIAMInstanceProfile struct {
+	InstanceProfileName *string                   `json:"InstanceProfileName"`
+	Roles               []*cloudformation.Literal `json:"Roles"`
+}
+
+func (_ *IAMInstanceProfileRole) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMInstanceProfileRole) error {
+	// AWS::IAM::InstanceProfile does not support tags
+	cf := &cloudformationIAMInstanceProfile{
+		InstanceProfileName: e.InstanceProfile.Name,
+		Roles:               []*cloudformation.Literal{e.Role.CloudformationLink()},
+	}
+
+	return t.RenderResource("AWS::IAM::InstanceProfile", *e.InstanceProfile.Name, cf)
+}
+
+var _ terraform.Importable = &IAMInstanceProfileRole{}
+
+func (e *IAMInstanceProfileRole) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	if e.InstanceProfile == nil || e.InstanceProfile.Name == nil || fi.ValueOf(e.InstanceProfile.Shared) {
+		return nil
+	}
+	t.AddImport("aws_iam_instance_profile", *e.InstanceProfile.Name, *e.InstanceProfile.Name)
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -248,3 +249,46 @@ func (p *IAMOIDCProvider) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 func (e *IAMOIDCProvider) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_openid_connect_provider", *e.Name, "arn")
 }
+
+type cloudformationIAMOIDCProvider struct {
+	URL            *string              `json:"Url"`
+	ClientIDList   []string             `json:"ClientIdList"`
+	ThumbprintList []string             `json:"ThumbprintList"`
+	Tags           []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (p *IAMOIDCProvider) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMOIDCProvider) error {
+	if err := t.AddOutputVariable("IAMOpenIDConnectProviderArn", e.CloudformationLink()); err != nil {
+		return err
+	}
+
+	issuerSubs := strings.SplitAfter(aws.ToString(e.URL), "://")
+	issuer := issuerSubs[len(issuerSubs)-1]
+	if err := t.AddOutputVariable("IAMOpenIDConnectProviderIssuer", cloudformation.LiteralString(issuer)); err != nil {
+		return err
+	}
+
+	cf := &cloudformationIAMOIDCProvider{
+		URL:            e.URL,
+		ClientIDList:   e.ClientIDs,
+		ThumbprintList: e.Thumbprints,
+		Tags:           cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::IAM::OIDCProvider", *e.Name, cf)
+}
+
+// CloudformationLink returns the ARN of the OIDC provider
+func (e *IAMOIDCProvider) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::IAM::OIDCProvider", *e.Name)
+}
+
+var _ terraform.Importable = &IAMOIDCProvider{}
+
+func (e *IAMOIDCProvider) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*IAMOIDCProvider)
+	if a.arn != nil {
+		t.AddImport("aws_iam_openid_connect_provider", *e.Name, *a.arn)
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -369,3 +370,51 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 func (e *IAMRole) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_role", *e.Name, "name")
 }
+
+type cloudformationIAMRole struct {
+	RoleName                 *string              `json:"RoleName"`
+	AssumeRolePolicyDocument json.RawMessage      `json:"AssumeRolePolicyDocument"`
+	PermissionsBoundary      *string              `json:"PermissionsBoundary,omitempty"`
+	ManagedPolicyArns        []string             `json:"ManagedPolicyArns,omitempty"`
+	Tags                     []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (_ *IAMRole) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMRole) error {
+	policy, err := cloudformation.JSONResource(e.RolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("error rendering RolePolicyDocument: %v", err)
+	}
+
+	cf := &cloudformationIAMRole{
+		RoleName:                 e.Name,
+		AssumeRolePolicyDocument: policy,
+		PermissionsBoundary:      e.PermissionsBoundary,
+		Tags:                     cloudformation.BuildTags(e.Tags),
+	}
+
+	if fi.ValueOf(e.ExportWithID) != "" {
+		if err := t.AddOutputVariable(*e.ExportWithID+"RoleArn", cloudformation.GetAtt("AWS::IAM::Role", *e.Name, "Arn")); err != nil {
+			return err
+		}
+		if err := t.AddOutputVariable(*e.ExportWithID+"RoleName", e.CloudformationLink()); err != nil {
+			return err
+		}
+	}
+
+	return t.RenderResource("AWS::IAM::Role", *e.Name, cf)
+}
+
+// CloudformationLink returns the name of the role
+func (e *IAMRole) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::IAM::Role", *e.Name)
+}
+
+var _ terraform.Importable = &IAMRole{}
+
+func (e *IAMRole) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*IAMRole)
+	if a.Name != nil {
+		t.AddImport("aws_iam_role", *e.Name, *a.Name)
+	}
+	return nil
+}
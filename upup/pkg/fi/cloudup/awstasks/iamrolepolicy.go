@@ -31,6 +31,7 @@ import (
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -358,3 +359,75 @@ func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 func (e *IAMRolePolicy) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_iam_role_policy", *e.Name)
 }
+
+type cloudformationIAMRolePolicy struct {
+	PolicyName     *string                 `json:"PolicyName"`
+	RoleName       *cloudformation.Literal `json:"RoleName"`
+	PolicyDocument json.RawMessage         `json:"PolicyDocument"`
+}
+
+func (_ *IAMRolePolicy) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMRolePolicy) error {
+	if e.ExternalPolicies != nil && len(*e.ExternalPolicies) > 0 {
+		// CloudFormation only supports attaching managed policies as a property of the role
+		err := t.UpdateResource("AWS::IAM::Role", *e.Role.Name, func(properties interface{}) error {
+			role, ok := properties.(*cloudformationIAMRole)
+			if !ok {
+				return fmt.Errorf("unexpected properties type %T for IAM role %q", properties, *e.Role.Name)
+			}
+			role.ManagedPolicyArns = append(role.ManagedPolicyArns, *e.ExternalPolicies...)
+			sort.Strings(role.ManagedPolicyArns)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error attaching external policies: %w", err)
+		}
+	}
+
+	policyString, err := e.policyDocumentString()
+	if err != nil {
+		return fmt.Errorf("error rendering PolicyDocument: %v", err)
+	}
+
+	if policyString == "" {
+		// A deletion; we simply don't render; cloudformation will observe the removal
+		return nil
+	}
+
+	policy, err := cloudformation.JSONResource(e.PolicyDocument)
+	if err != nil {
+		return fmt.Errorf("error rendering PolicyDocument: %v", err)
+	}
+
+	cf := &cloudformationIAMRolePolicy{
+		PolicyName:     e.Name,
+		RoleName:       e.Role.CloudformationLink(),
+		PolicyDocument: policy,
+	}
+
+	return t.RenderResource("AWS::IAM::RolePolicy", *e.Name, cf)
+}
+
+var _ terraform.Importable = &IAMRolePolicy{}
+
+func (e *IAMRolePolicy) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*IAMRolePolicy)
+	if e.Role == nil || e.Role.Name == nil {
+		return nil
+	}
+	roleName := *e.Role.Name
+
+	if a.ExternalPolicies != nil {
+		for _, policy := range *a.ExternalPolicies {
+			h := fnv.New32a()
+			h.Write([]byte(policy))
+
+			name := fmt.Sprintf("%s-%d", *e.Name, h.Sum32())
+			t.AddImport("aws_iam_role_policy_attachment", name, roleName+"/"+policy)
+		}
+	}
+
+	if a.PolicyDocument != nil {
+		t.AddImport("aws_iam_role_policy", *e.Name, roleName+":"+*e.Name)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
@@ -311,3 +312,15 @@ func (e *Instance) TerraformLink() *terraformWriter.Literal {
 
 	return terraformWriter.LiteralSelfLink("aws_instance", *e.Name)
 }
+
+func (e *Instance) CloudformationLink() *cloudformation.Literal {
+	if fi.ValueOf(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if NAT Instance is shared: %s", e)
+		}
+
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::Instance", *e.Name)
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -236,3 +237,86 @@ func (e *InternetGateway) TerraformLink() *terraformWriter.Literal {
 
 	return terraformWriter.LiteralProperty("aws_internet_gateway", *e.Name, "id")
 }
+
+type cloudformationInternetGateway struct {
+	Tags []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+type cloudformationVPCGatewayAttachment struct {
+	VPCID             *cloudformation.Literal `json:"VpcId"`
+	InternetGatewayID *cloudformation.Literal `json:"InternetGatewayId"`
+}
+
+func (_ *InternetGateway) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *InternetGateway) error {
+	ctx := context.TODO()
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		// But ... attempt to discover the ID so CloudformationLink works
+		if e.ID == nil {
+			request := &ec2.DescribeInternetGatewaysInput{}
+			vpcID := fi.ValueOf(e.VPC.ID)
+			if vpcID == "" {
+				return fmt.Errorf("VPC ID is required when InternetGateway is shared")
+			}
+			request.Filters = []ec2types.Filter{awsup.NewEC2Filter("attachment.vpc-id", vpcID)}
+			igw, err := findInternetGateway(ctx, t.Cloud.(awsup.AWSCloud), request)
+			if err != nil {
+				return err
+			}
+			if igw == nil {
+				klog.Warningf("Cannot find internet gateway for VPC %q", vpcID)
+			} else {
+				e.ID = igw.InternetGatewayId
+			}
+		}
+		return nil
+	}
+
+	cf := &cloudformationInternetGateway{
+		Tags: cloudformation.BuildTags(e.Tags),
+	}
+	if err := t.RenderResource("AWS::EC2::InternetGateway", *e.Name, cf); err != nil {
+		return err
+	}
+
+	// CloudFormation attaches the gateway to the VPC with a separate resource
+	attachment := &cloudformationVPCGatewayAttachment{
+		VPCID:             e.VPC.CloudformationLink(),
+		InternetGatewayID: e.CloudformationLink(),
+	}
+	return t.RenderResource("AWS::EC2::VPCGatewayAttachment", *e.Name, attachment)
+}
+
+func (e *InternetGateway) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if InternetGateway is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing InternetGateway with id %q", *e.ID)
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::InternetGateway", *e.Name)
+}
+
+// CloudformationDependency returns the logical ID of the VPC attachment, which routes
+// through the gateway must depend on; it is empty if the gateway is not managed by us.
+func (e *InternetGateway) CloudformationDependency() string {
+	if fi.ValueOf(e.Shared) {
+		return ""
+	}
+	return cloudformation.LogicalID("AWS::EC2::VPCGatewayAttachment", *e.Name)
+}
+
+var _ terraform.Importable = &InternetGateway{}
+
+func (e *InternetGateway) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*InternetGateway)
+	if a.ID != nil {
+		t.AddImport("aws_internet_gateway", *e.Name, *a.ID)
+	}
+	return nil
+}
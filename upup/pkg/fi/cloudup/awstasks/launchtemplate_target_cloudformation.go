@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"encoding/base64"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/util/pkg/maps"
+)
+
+type cloudformationLaunchTemplateNetworkInterface struct {
+	// AssociatePublicIPAddress associates a public ip address with the network interface. Boolean value.
+	AssociatePublicIPAddress *bool `json:"AssociatePublicIpAddress,omitempty"`
+	// DeleteOnTermination indicates whether the network interface should be destroyed on instance termination.
+	DeleteOnTermination *bool `json:"DeleteOnTermination,omitempty"`
+	// DeviceIndex is the position of the network interface in the attachment order.
+	DeviceIndex int32 `json:"DeviceIndex"`
+	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
+	Ipv6AddressCount *int32 `json:"Ipv6AddressCount,omitempty"`
+	// SecurityGroups is a list of security group ids.
+	SecurityGroups []*cloudformation.Literal `json:"Groups,omitempty"`
+}
+
+type cloudformationLaunchTemplateMonitoring struct {
+	// Enabled indicates that monitoring is enabled
+	Enabled *bool `json:"Enabled,omitempty"`
+}
+
+type cloudformationLaunchTemplatePlacement struct {
+	// Tenancy is the tenancy of the instance. Can be default, dedicated, or host.
+	Tenancy *ec2types.Tenancy `json:"Tenancy,omitempty"`
+}
+
+type cloudformationLaunchTemplateIAMProfile struct {
+	// Name is the name of the profile
+	Name *cloudformation.Literal `json:"Name,omitempty"`
+}
+
+type cloudformationLaunchTemplateMarketOptionsSpotOptions struct {
+	// BlockDurationMinutes is required duration in minutes. This value must be a multiple of 60.
+	BlockDurationMinutes *int32 `json:"BlockDurationMinutes,omitempty"`
+	// InstanceInterruptionBehavior is the behavior when a Spot Instance is interrupted. Can be hibernate, stop, or terminate
+	InstanceInterruptionBehavior *ec2types.InstanceInterruptionBehavior `json:"InstanceInterruptionBehavior,omitempty"`
+	// MaxPrice is the maximum hourly price you're willing to pay for the Spot Instances
+	MaxPrice *string `json:"MaxPrice,omitempty"`
+}
+
+type cloudformationLaunchTemplateMarketOptions struct {
+	// MarketType is the option type
+	MarketType *string `json:"MarketType,omitempty"`
+	// SpotOptions are the set of options
+	SpotOptions *cloudformationLaunchTemplateMarketOptionsSpotOptions `json:"SpotOptions,omitempty"`
+}
+
+type cloudformationLaunchTemplateBlockDeviceEBS struct {
+	// VolumeType is the ebs type to use
+	VolumeType *string `json:"VolumeType,omitempty"`
+	// VolumeSize is the volume size
+	VolumeSize *int32 `json:"VolumeSize,omitempty"`
+	// IOPS is the provisioned IOPS
+	IOPS *int32 `json:"Iops,omitempty"`
+	// Throughput is the gp3 volume throughput
+	Throughput *int32 `json:"Throughput,omitempty"`
+	// DeleteOnTermination indicates the volume should die with the instance
+	DeleteOnTermination *bool `json:"DeleteOnTermination,omitempty"`
+	// Encrypted indicates the device should be encrypted
+	Encrypted *bool `json:"Encrypted,omitempty"`
+	// KmsKeyID is the encryption key identifier for the volume
+	KmsKeyID *string `json:"KmsKeyId,omitempty"`
+}
+
+type cloudformationLaunchTemplateBlockDevice struct {
+	// DeviceName is the name of the device
+	DeviceName *string `json:"DeviceName,omitempty"`
+	// VirtualName is used for the ephemeral devices
+	VirtualName *string `json:"VirtualName,omitempty"`
+	// EBS defines the ebs spec
+	EBS *cloudformationLaunchTemplateBlockDeviceEBS `json:"Ebs,omitempty"`
+}
+
+type cloudformationLaunchTemplateCreditSpecification struct {
+	CPUCredits *string `json:"CpuCredits,omitempty"`
+}
+
+type cloudformationLaunchTemplateTagSpecification struct {
+	// ResourceType is the type of resource to tag.
+	ResourceType *string `json:"ResourceType,omitempty"`
+	// Tags are the tags to apply to the resource.
+	Tags []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+type cloudformationLaunchTemplateInstanceMetadata struct {
+	// HTTPEndpoint enables or disables the HTTP metadata endpoint on instances.
+	HTTPEndpoint *string `json:"HttpEndpoint,omitempty"`
+	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
+	HTTPPutResponseHopLimit *int32 `json:"HttpPutResponseHopLimit,omitempty"`
+	// HTTPTokens is the state of token usage for your instance metadata requests.
+	HTTPTokens *ec2types.LaunchTemplateHttpTokensState `json:"HttpTokens,omitempty"`
+	// HTTPProtocolIPv6 enables the IPv6 instance metadata endpoint
+	HTTPProtocolIPv6 *ec2types.LaunchTemplateInstanceMetadataProtocolIpv6 `json:"HttpProtocolIpv6,omitempty"`
+}
+
+type cloudformationLaunchTemplateData struct {
+	// BlockDeviceMappings is the device mappings
+	BlockDeviceMappings []*cloudformationLaunchTemplateBlockDevice `json:"BlockDeviceMappings,omitempty"`
+	// CreditSpecification is the credit option for CPU Usage on some instance types
+	CreditSpecification *cloudformationLaunchTemplateCreditSpecification `json:"CreditSpecification,omitempty"`
+	// EBSOptimized indicates if the root device is ebs optimized
+	EBSOptimized *bool `json:"EbsOptimized,omitempty"`
+	// IAMInstanceProfile is the IAM profile to assign to the nodes
+	IAMInstanceProfile *cloudformationLaunchTemplateIAMProfile `json:"IamInstanceProfile,omitempty"`
+	// ImageID is the ami to use for the instances
+	ImageID *string `json:"ImageId,omitempty"`
+	// InstanceType is the type of instance
+	InstanceType *ec2types.InstanceType `json:"InstanceType,omitempty"`
+	// KeyName is the ssh key to use
+	KeyName *cloudformation.Literal `json:"KeyName,omitempty"`
+	// MarketOptions are the spot pricing options
+	MarketOptions *cloudformationLaunchTemplateMarketOptions `json:"InstanceMarketOptions,omitempty"`
+	// MetadataOptions are the instance metadata options.
+	MetadataOptions *cloudformationLaunchTemplateInstanceMetadata `json:"MetadataOptions,omitempty"`
+	// Monitoring are the instance monitoring options
+	Monitoring *cloudformationLaunchTemplateMonitoring `json:"Monitoring,omitempty"`
+	// NetworkInterfaces are the networking options
+	NetworkInterfaces []*cloudformationLaunchTemplateNetworkInterface `json:"NetworkInterfaces,omitempty"`
+	// Placement are the tenancy options
+	Placement *cloudformationLaunchTemplatePlacement `json:"Placement,omitempty"`
+	// TagSpecifications are the tags to apply to a resource when it is created.
+	TagSpecifications []*cloudformationLaunchTemplateTagSpecification `json:"TagSpecifications,omitempty"`
+	// UserData is the base64 encoded user data for the instances
+	UserData *string `json:"UserData,omitempty"`
+}
+
+type cloudformationLaunchTemplate struct {
+	// LaunchTemplateName is the name of the launch template
+	LaunchTemplateName *string `json:"LaunchTemplateName,omitempty"`
+	// LaunchTemplateData is the data for the launch template
+	LaunchTemplateData *cloudformationLaunchTemplateData `json:"LaunchTemplateData,omitempty"`
+	// TagSpecifications are the tags applied to the launch template itself
+	TagSpecifications []*cloudformationLaunchTemplateTagSpecification `json:"TagSpecifications,omitempty"`
+}
+
+// CloudformationLink returns the cloudformation reference
+func (t *LaunchTemplate) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::LaunchTemplate", fi.ValueOf(t.Name))
+}
+
+// CloudformationVersion returns the cloudformation version reference
+func (t *LaunchTemplate) CloudformationVersion() *cloudformation.Literal {
+	return cloudformation.GetAtt("AWS::EC2::LaunchTemplate", fi.ValueOf(t.Name), "LatestVersionNumber")
+}
+
+// RenderCloudformation is responsible for rendering the cloudformation template
+func (t *LaunchTemplate) RenderCloudformation(target *cloudformation.CloudformationTarget, a, e, changes *LaunchTemplate) error {
+	var err error
+
+	cloud := target.Cloud.(awsup.AWSCloud)
+
+	var image *string
+	if e.ImageID != nil {
+		im, err := cloud.ResolveImage(fi.ValueOf(e.ImageID))
+		if err != nil {
+			return err
+		}
+		image = im.ImageId
+	}
+
+	data := &cloudformationLaunchTemplateData{
+		EBSOptimized: e.RootVolumeOptimization,
+		ImageID:      image,
+		InstanceType: e.InstanceType,
+		MetadataOptions: &cloudformationLaunchTemplateInstanceMetadata{
+			HTTPEndpoint:            fi.PtrTo("enabled"),
+			HTTPTokens:              e.HTTPTokens,
+			HTTPPutResponseHopLimit: e.HTTPPutResponseHopLimit,
+			HTTPProtocolIPv6:        e.HTTPProtocolIPv6,
+		},
+		NetworkInterfaces: []*cloudformationLaunchTemplateNetworkInterface{
+			{
+				AssociatePublicIPAddress: e.AssociatePublicIP,
+				DeleteOnTermination:      fi.PtrTo(true),
+				Ipv6AddressCount:         e.IPv6AddressCount,
+			},
+		},
+	}
+
+	if fi.ValueOf(e.SpotPrice) != "" {
+		data.MarketOptions = &cloudformationLaunchTemplateMarketOptions{
+			MarketType: fi.PtrTo("spot"),
+			SpotOptions: &cloudformationLaunchTemplateMarketOptionsSpotOptions{
+				BlockDurationMinutes:         e.SpotDurationInMinutes,
+				InstanceInterruptionBehavior: e.InstanceInterruptionBehavior,
+				MaxPrice:                     e.SpotPrice,
+			},
+		}
+	}
+	if fi.ValueOf(e.CPUCredits) != "" {
+		data.CreditSpecification = &cloudformationLaunchTemplateCreditSpecification{
+			CPUCredits: e.CPUCredits,
+		}
+	}
+	for _, x := range e.SecurityGroups {
+		data.NetworkInterfaces[0].SecurityGroups = append(data.NetworkInterfaces[0].SecurityGroups, x.CloudformationLink())
+	}
+	if e.SSHKey != nil {
+		data.KeyName = e.SSHKey.CloudformationLink()
+	}
+	if e.Tenancy != nil {
+		data.Placement = &cloudformationLaunchTemplatePlacement{Tenancy: e.Tenancy}
+	}
+	if e.InstanceMonitoring != nil {
+		data.Monitoring = &cloudformationLaunchTemplateMonitoring{Enabled: e.InstanceMonitoring}
+	}
+	if e.IAMInstanceProfile != nil {
+		data.IAMInstanceProfile = &cloudformationLaunchTemplateIAMProfile{
+			Name: e.IAMInstanceProfile.CloudformationLink(),
+		}
+	}
+	if e.UserData != nil {
+		d, err := fi.ResourceAsBytes(e.UserData)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			data.UserData = fi.PtrTo(base64.StdEncoding.EncodeToString(d))
+		}
+	}
+	devices, err := e.buildRootDevice(cloud)
+	if err != nil {
+		return err
+	}
+	for _, n := range maps.SortedKeys(devices) {
+		x := devices[n]
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, &cloudformationLaunchTemplateBlockDevice{
+			DeviceName: fi.PtrTo(n),
+			EBS: &cloudformationLaunchTemplateBlockDeviceEBS{
+				DeleteOnTermination: fi.PtrTo(true),
+				Encrypted:           x.EbsEncrypted,
+				KmsKeyID:            x.EbsKmsKey,
+				IOPS:                x.EbsVolumeIops,
+				Throughput:          x.EbsVolumeThroughput,
+				VolumeSize:          x.EbsVolumeSize,
+				VolumeType:          fi.PtrTo(string(x.EbsVolumeType)),
+			},
+		})
+	}
+	additionals, err := buildAdditionalDevices(e.BlockDeviceMappings)
+	if err != nil {
+		return err
+	}
+	for _, n := range maps.SortedKeys(additionals) {
+		x := additionals[n]
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, &cloudformationLaunchTemplateBlockDevice{
+			DeviceName: fi.PtrTo(n),
+			EBS: &cloudformationLaunchTemplateBlockDeviceEBS{
+				DeleteOnTermination: fi.PtrTo(true),
+				Encrypted:           x.EbsEncrypted,
+				IOPS:                x.EbsVolumeIops,
+				Throughput:          x.EbsVolumeThroughput,
+				KmsKeyID:            x.EbsKmsKey,
+				VolumeSize:          x.EbsVolumeSize,
+				VolumeType:          fi.PtrTo(string(x.EbsVolumeType)),
+			},
+		})
+	}
+
+	devices, err = buildEphemeralDevices(cloud, fi.ValueOf(e.InstanceType))
+	if err != nil {
+		return err
+	}
+	for _, n := range maps.SortedKeys(devices) {
+		x := devices[n]
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, &cloudformationLaunchTemplateBlockDevice{
+			VirtualName: x.VirtualName,
+			DeviceName:  fi.PtrTo(n),
+		})
+	}
+
+	cf := &cloudformationLaunchTemplate{
+		LaunchTemplateName: e.Name,
+		LaunchTemplateData: data,
+	}
+
+	if e.Tags != nil {
+		tags := cloudformation.BuildTags(e.Tags)
+		data.TagSpecifications = append(data.TagSpecifications, &cloudformationLaunchTemplateTagSpecification{
+			ResourceType: fi.PtrTo("instance"),
+			Tags:         tags,
+		})
+		data.TagSpecifications = append(data.TagSpecifications, &cloudformationLaunchTemplateTagSpecification{
+			ResourceType: fi.PtrTo("volume"),
+			Tags:         tags,
+		})
+		cf.TagSpecifications = append(cf.TagSpecifications, &cloudformationLaunchTemplateTagSpecification{
+			ResourceType: fi.PtrTo("launch-template"),
+			Tags:         tags,
+		})
+	}
+
+	return target.RenderResource("AWS::EC2::LaunchTemplate", fi.ValueOf(e.Name), cf)
+}
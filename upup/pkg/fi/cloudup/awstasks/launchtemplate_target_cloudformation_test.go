@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestLaunchTemplateCloudformationRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &LaunchTemplate{
+				Name:              fi.PtrTo("test"),
+				AssociatePublicIP: fi.PtrTo(true),
+				IAMInstanceProfile: &IAMInstanceProfile{
+					Name: fi.PtrTo("nodes"),
+				},
+				ID:                           fi.PtrTo("test-11"),
+				InstanceMonitoring:           fi.PtrTo(true),
+				InstanceType:                 fi.PtrTo(ec2types.InstanceTypeT2Medium),
+				SpotPrice:                    fi.PtrTo("0.1"),
+				SpotDurationInMinutes:        fi.PtrTo(int32(60)),
+				InstanceInterruptionBehavior: fi.PtrTo(ec2types.InstanceInterruptionBehaviorHibernate),
+				RootVolumeOptimization:       fi.PtrTo(true),
+				RootVolumeIops:               fi.PtrTo(int32(100)),
+				RootVolumeSize:               fi.PtrTo(int32(64)),
+				SSHKey: &SSHKey{
+					Name:      fi.PtrTo("newkey"),
+					PublicKey: fi.NewStringResource("newkey"),
+				},
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.PtrTo("nodes-1"), ID: fi.PtrTo("1111")},
+					{Name: fi.PtrTo("nodes-2"), ID: fi.PtrTo("2222")},
+				},
+				Tenancy:                 fi.PtrTo(ec2types.TenancyDedicated),
+				HTTPTokens:              fi.PtrTo(ec2types.LaunchTemplateHttpTokensStateOptional),
+				HTTPPutResponseHopLimit: fi.PtrTo(int32(1)),
+			},
+			Expected: `{
+  "AWSTemplateFormatVersion": "2010-09-09",
+  "Description": "Generated by kOps",
+  "Resources": {
+    "AWSEC2LaunchTemplatetest": {
+      "Type": "AWS::EC2::LaunchTemplate",
+      "Properties": {
+        "LaunchTemplateName": "test",
+        "LaunchTemplateData": {
+          "EbsOptimized": true,
+          "IamInstanceProfile": {
+            "Name": {
+              "Ref": "AWSIAMInstanceProfilenodes"
+            }
+          },
+          "InstanceType": "t2.medium",
+          "KeyName": {
+            "Ref": "AWSEC2KeyPairnewkey"
+          },
+          "InstanceMarketOptions": {
+            "MarketType": "spot",
+            "SpotOptions": {
+              "BlockDurationMinutes": 60,
+              "InstanceInterruptionBehavior": "hibernate",
+              "MaxPrice": "0.1"
+            }
+          },
+          "MetadataOptions": {
+            "HttpEndpoint": "enabled",
+            "HttpPutResponseHopLimit": 1,
+            "HttpTokens": "optional"
+          },
+          "Monitoring": {
+            "Enabled": true
+          },
+          "NetworkInterfaces": [
+            {
+              "AssociatePublicIpAddress": true,
+              "DeleteOnTermination": true,
+              "DeviceIndex": 0,
+              "Groups": [
+                {
+                  "Ref": "AWSEC2SecurityGroupnodes1"
+                },
+                {
+                  "Ref": "AWSEC2SecurityGroupnodes2"
+                }
+              ]
+            }
+          ],
+          "Placement": {
+            "Tenancy": "dedicated"
+          }
+        }
+      }
+    }
+  }
+}
+`,
+		},
+	}
+	doRenderTests(t, "RenderCloudformation", cases)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package awstasks
 
 import (
+	"fmt"
+
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -145,7 +147,7 @@ type terraformLaunchTemplate struct {
 	// ImageID is the ami to use for the instances
 	ImageID *string `cty:"image_id"`
 	// InstanceType is the type of instance
-	InstanceType *ec2types.InstanceType `cty:"instance_type"`
+	InstanceType *terraformWriter.Literal `cty:"instance_type"`
 	// KeyName is the ssh key to use
 	KeyName *terraformWriter.Literal `cty:"key_name"`
 	// MarketOptions are the spot pricing options
@@ -195,7 +197,6 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		Name:         e.Name,
 		EBSOptimized: e.RootVolumeOptimization,
 		ImageID:      image,
-		InstanceType: e.InstanceType,
 		Lifecycle:    &terraform.Lifecycle{CreateBeforeDestroy: fi.PtrTo(true)},
 		MetadataOptions: &terraformLaunchTemplateInstanceMetadata{
 			// See issue https://github.com/hashicorp/terraform-provider-aws/issues/12564.
@@ -213,6 +214,15 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		},
 	}
 
+	if e.InstanceType != nil {
+		instanceType, err := target.ModuleVariable(target.ResourceVariableName(fi.ValueOf(e.Name), "instance_type"), "string",
+			fmt.Sprintf("Machine type of the %s instances", fi.ValueOf(e.Name)), terraformWriter.LiteralFromStringValue(string(*e.InstanceType)))
+		if err != nil {
+			return err
+		}
+		tf.InstanceType = instanceType
+	}
+
 	if fi.ValueOf(e.SpotPrice) != "" {
 		marketSpotOptions := terraformLaunchTemplateMarketOptionsSpotOptions{
 			BlockDurationMinutes:         e.SpotDurationInMinutes,
@@ -328,3 +338,13 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 
 	return target.RenderResource("aws_launch_template", fi.ValueOf(e.Name), tf)
 }
+
+var _ terraform.Importable = &LaunchTemplate{}
+
+func (e *LaunchTemplate) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*LaunchTemplate)
+	if a.ID != nil {
+		t.AddImport("aws_launch_template", fi.ValueOf(e.Name), *a.ID)
+	}
+	return nil
+}
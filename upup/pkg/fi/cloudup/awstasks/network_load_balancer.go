@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/wellknownservices"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -699,6 +700,82 @@ func (e *NetworkLoadBalancer) TerraformLink(params ...string) *terraformWriter.L
 }
 
 // FindDeletions schedules deletion of the corresponding legacy classic load balancer when it no longer has targets.
+type cloudformationNetworkLoadBalancer struct {
+	Name                   string                                            `json:"Name"`
+	Scheme                 elbv2types.LoadBalancerSchemeEnum                 `json:"Scheme,omitempty"`
+	Type                   elbv2types.LoadBalancerTypeEnum                   `json:"Type"`
+	IPAddressType          *elbv2types.IpAddressType                         `json:"IpAddressType,omitempty"`
+	SecurityGroups         []*cloudformation.Literal                         `json:"SecurityGroups,omitempty"`
+	SubnetMappings         []*cloudformationNetworkLoadBalancerSubnetMapping `json:"SubnetMappings,omitempty"`
+	LoadBalancerAttributes []*cloudformationLoadBalancerAttribute            `json:"LoadBalancerAttributes,omitempty"`
+	Tags                   []cloudformation.Tag                              `json:"Tags,omitempty"`
+}
+
+type cloudformationNetworkLoadBalancerSubnetMapping struct {
+	Subnet             *cloudformation.Literal `json:"SubnetId"`
+	AllocationID       *string                 `json:"AllocationId,omitempty"`
+	PrivateIPv4Address *string                 `json:"PrivateIPv4Address,omitempty"`
+}
+
+type cloudformationLoadBalancerAttribute struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+func (_ *NetworkLoadBalancer) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *NetworkLoadBalancer) error {
+	cf := &cloudformationNetworkLoadBalancer{
+		Name:   *e.LoadBalancerBaseName,
+		Scheme: e.Scheme,
+		Type:   elbv2types.LoadBalancerTypeEnumNetwork,
+		Tags:   cloudformation.BuildTags(e.Tags),
+	}
+	if e.IpAddressType == elbv2types.IpAddressTypeDualstack {
+		cf.IPAddressType = &e.IpAddressType
+	}
+
+	for _, subnetMapping := range e.SubnetMappings {
+		cf.SubnetMappings = append(cf.SubnetMappings, &cloudformationNetworkLoadBalancerSubnetMapping{
+			Subnet:             subnetMapping.Subnet.CloudformationLink(),
+			AllocationID:       subnetMapping.AllocationID,
+			PrivateIPv4Address: subnetMapping.PrivateIPv4Address,
+		})
+	}
+
+	for _, sg := range e.SecurityGroups {
+		cf.SecurityGroups = append(cf.SecurityGroups, sg.CloudformationLink())
+	}
+	cloudformation.SortLiterals(cf.SecurityGroups)
+
+	cf.LoadBalancerAttributes = append(cf.LoadBalancerAttributes, &cloudformationLoadBalancerAttribute{
+		Key:   "load_balancing.cross_zone.enabled",
+		Value: strconv.FormatBool(fi.ValueOf(e.CrossZoneLoadBalancing)),
+	})
+	if e.AccessLog != nil && fi.ValueOf(e.AccessLog.Enabled) {
+		cf.LoadBalancerAttributes = append(cf.LoadBalancerAttributes, &cloudformationLoadBalancerAttribute{
+			Key:   "access_logs.s3.enabled",
+			Value: "true",
+		}, &cloudformationLoadBalancerAttribute{
+			Key:   "access_logs.s3.bucket",
+			Value: fi.ValueOf(e.AccessLog.S3BucketName),
+		}, &cloudformationLoadBalancerAttribute{
+			Key:   "access_logs.s3.prefix",
+			Value: fi.ValueOf(e.AccessLog.S3BucketPrefix),
+		})
+	}
+
+	return t.RenderResource("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name, cf)
+}
+
+// CloudformationLink returns the ARN of the load balancer
+func (e *NetworkLoadBalancer) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name)
+}
+
+func (e *NetworkLoadBalancer) CloudformationAlias() (dnsName *cloudformation.Literal, hostedZoneID *cloudformation.Literal) {
+	return cloudformation.GetAtt("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name, "DNSName"),
+		cloudformation.GetAtt("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name, "CanonicalHostedZoneID")
+}
+
 func (e *NetworkLoadBalancer) FindDeletions(context *fi.CloudupContext) ([]fi.CloudupDeletion, error) {
 	var deletions []fi.CloudupDeletion
 
@@ -807,3 +884,13 @@ func (d *deleteNLB) Item() string {
 func (d *deleteNLB) DeferDeletion() bool {
 	return true
 }
+
+var _ terraform.Importable = &NetworkLoadBalancer{}
+
+func (e *NetworkLoadBalancer) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*NetworkLoadBalancer)
+	if a.loadBalancerArn != "" {
+		t.AddImport("aws_lb", e.TerraformName(), a.loadBalancerArn)
+	}
+	return nil
+}
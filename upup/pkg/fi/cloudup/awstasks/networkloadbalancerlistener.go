@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -261,3 +262,60 @@ func (e *NetworkLoadBalancerListener) TerraformName() string {
 	tfName := fmt.Sprintf("%v-%v", e.NetworkLoadBalancer.TerraformName(), e.Port)
 	return tfName
 }
+
+type cloudformationNetworkLoadBalancerListener struct {
+	LoadBalancer   *cloudformation.Literal                            `json:"LoadBalancerArn"`
+	Port           int64                                              `json:"Port"`
+	Protocol       elbv2types.ProtocolEnum                            `json:"Protocol"`
+	Certificates   []*cloudformationNetworkLoadBalancerCertificate    `json:"Certificates,omitempty"`
+	SSLPolicy      *string                                            `json:"SslPolicy,omitempty"`
+	DefaultActions []*cloudformationNetworkLoadBalancerListenerAction `json:"DefaultActions"`
+}
+
+type cloudformationNetworkLoadBalancerCertificate struct {
+	CertificateARN string `json:"CertificateArn"`
+}
+
+type cloudformationNetworkLoadBalancerListenerAction struct {
+	Type           elbv2types.ActionTypeEnum `json:"Type"`
+	TargetGroupARN *cloudformation.Literal   `json:"TargetGroupArn"`
+}
+
+func (_ *NetworkLoadBalancerListener) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *NetworkLoadBalancerListener) error {
+	if e.TargetGroup == nil {
+		return fi.RequiredField("TargetGroup")
+	}
+	cf := &cloudformationNetworkLoadBalancerListener{
+		LoadBalancer: e.NetworkLoadBalancer.CloudformationLink(),
+		Port:         int64(e.Port),
+		DefaultActions: []*cloudformationNetworkLoadBalancerListenerAction{
+			{
+				Type:           elbv2types.ActionTypeEnumForward,
+				TargetGroupARN: e.TargetGroup.CloudformationLink(),
+			},
+		},
+	}
+	if e.SSLCertificateID != "" {
+		cf.Certificates = []*cloudformationNetworkLoadBalancerCertificate{
+			{CertificateARN: e.SSLCertificateID},
+		}
+		cf.Protocol = elbv2types.ProtocolEnumTls
+		if e.SSLPolicy != "" {
+			cf.SSLPolicy = &e.SSLPolicy
+		}
+	} else {
+		cf.Protocol = elbv2types.ProtocolEnumTcp
+	}
+
+	return t.RenderResource("AWS::ElasticLoadBalancingV2::Listener", e.TerraformName(), cf)
+}
+
+var _ terraform.Importable = &NetworkLoadBalancerListener{}
+
+func (e *NetworkLoadBalancerListener) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*NetworkLoadBalancerListener)
+	if a.listenerArn != "" {
+		t.AddImport("aws_lb_listener", e.TerraformName(), a.listenerArn)
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -39,6 +40,7 @@ func doRenderTests(t *testing.T, method string, cases []*renderTest) {
 	for i, c := range cases {
 		var filename string
 		var target interface{}
+		renderMethod := method
 
 		cloud := awsup.BuildMockAWSCloud("eu-west-2", "abc")
 
@@ -46,6 +48,16 @@ func doRenderTests(t *testing.T, method string, cases []*renderTest) {
 		case "RenderTerraform":
 			target = terraform.NewTerraformTarget(cloud, "test", outdir, nil)
 			filename = "kubernetes.tf"
+		case "RenderTerraformModule":
+			tf := terraform.NewTerraformTarget(cloud, "test", outdir, nil)
+			tf.Module = true
+			tf.ClusterName = "example.com"
+			target = tf
+			filename = "kubernetes.tf"
+			renderMethod = "RenderTerraform"
+		case "RenderCloudformation":
+			target = cloudformation.NewCloudformationTarget(cloud, outdir)
+			filename = cloudformation.TemplateFileName
 		default:
 			t.Errorf("unknown render method: %s", method)
 			t.FailNow()
@@ -59,7 +71,7 @@ func doRenderTests(t *testing.T, method string, cases []*renderTest) {
 
 		err := func() error {
 			// @step: invoke the rendering method of the target
-			resp := reflect.ValueOf(c.Resource).MethodByName(method).Call(inputs)
+			resp := reflect.ValueOf(c.Resource).MethodByName(renderMethod).Call(inputs)
 			if err := resp[0].Interface(); err != nil {
 				return err.(error)
 			}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -326,3 +327,63 @@ func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Rou
 	name := fmt.Sprintf("route-%v", *e.Name)
 	return t.RenderResource("aws_route", name, tf)
 }
+
+type cloudformationRoute struct {
+	RouteTableID                *cloudformation.Literal `json:"RouteTableId"`
+	CIDR                        *string                 `json:"DestinationCidrBlock,omitempty"`
+	IPv6CIDR                    *string                 `json:"DestinationIpv6CidrBlock,omitempty"`
+	EgressOnlyInternetGatewayID *cloudformation.Literal `json:"EgressOnlyInternetGatewayId,omitempty"`
+	InternetGatewayID           *cloudformation.Literal `json:"GatewayId,omitempty"`
+	NATGatewayID                *cloudformation.Literal `json:"NatGatewayId,omitempty"`
+	TransitGatewayID            *string                 `json:"TransitGatewayId,omitempty"`
+	InstanceID                  *cloudformation.Literal `json:"InstanceId,omitempty"`
+	VPCPeeringConnectionID      *string                 `json:"VpcPeeringConnectionId,omitempty"`
+}
+
+func (_ *Route) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *Route) error {
+	cf := &cloudformationRoute{
+		RouteTableID: e.RouteTable.CloudformationLink(),
+		CIDR:         e.CIDR,
+		IPv6CIDR:     e.IPv6CIDR,
+	}
+
+	var dependsOn []string
+	if e.EgressOnlyInternetGateway == nil && e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil && e.VPCPeeringConnectionID == nil {
+		return fmt.Errorf("missing target for route")
+	} else if e.EgressOnlyInternetGateway != nil {
+		cf.EgressOnlyInternetGatewayID = e.EgressOnlyInternetGateway.CloudformationLink()
+	} else if e.InternetGateway != nil {
+		cf.InternetGatewayID = e.InternetGateway.CloudformationLink()
+		// The gateway must be attached to the VPC before we can route through it
+		if dependency := e.InternetGateway.CloudformationDependency(); dependency != "" {
+			dependsOn = append(dependsOn, dependency)
+		}
+	} else if e.NatGateway != nil {
+		cf.NATGatewayID = e.NatGateway.CloudformationLink()
+	} else if e.TransitGatewayID != nil {
+		cf.TransitGatewayID = e.TransitGatewayID
+	} else if e.VPCPeeringConnectionID != nil {
+		cf.VPCPeeringConnectionID = e.VPCPeeringConnectionID
+	}
+
+	if e.Instance != nil {
+		cf.InstanceID = e.Instance.CloudformationLink()
+	}
+
+	return t.RenderResource("AWS::EC2::Route", *e.Name, cf, dependsOn...)
+}
+
+var _ terraform.Importable = &Route{}
+
+func (e *Route) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	if e.RouteTable == nil || e.RouteTable.ID == nil {
+		return nil
+	}
+	destination := fi.ValueOf(e.CIDR)
+	if destination == "" {
+		destination = fi.ValueOf(e.IPv6CIDR)
+	}
+	// Routes are imported using the route table ID and the destination
+	t.AddImport("aws_route", fmt.Sprintf("route-%v", *e.Name), *e.RouteTable.ID+"_"+destination)
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -219,3 +220,31 @@ func (_ *RouteTable) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 func (e *RouteTable) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_route_table", *e.Name, "id")
 }
+
+type cloudformationRouteTable struct {
+	VPCID *cloudformation.Literal `json:"VpcId"`
+	Tags  []cloudformation.Tag    `json:"Tags,omitempty"`
+}
+
+func (_ *RouteTable) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *RouteTable) error {
+	cf := &cloudformationRouteTable{
+		VPCID: e.VPC.CloudformationLink(),
+		Tags:  cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::RouteTable", *e.Name, cf)
+}
+
+func (e *RouteTable) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::RouteTable", *e.Name)
+}
+
+var _ terraform.Importable = &RouteTable{}
+
+func (e *RouteTable) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*RouteTable)
+	if a.ID != nil {
+		t.AddImport("aws_route_table", *e.Name, *a.ID)
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -208,3 +209,28 @@ func (_ *RouteTableAssociation) RenderTerraform(t *terraform.TerraformTarget, a,
 func (e *RouteTableAssociation) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route_table_association", *e.Name)
 }
+
+type cloudformationRouteTableAssociation struct {
+	SubnetID     *cloudformation.Literal `json:"SubnetId"`
+	RouteTableID *cloudformation.Literal `json:"RouteTableId"`
+}
+
+func (_ *RouteTableAssociation) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *RouteTableAssociation) error {
+	cf := &cloudformationRouteTableAssociation{
+		SubnetID:     e.Subnet.CloudformationLink(),
+		RouteTableID: e.RouteTable.CloudformationLink(),
+	}
+
+	return t.RenderResource("AWS::EC2::SubnetRouteTableAssociation", *e.Name, cf)
+}
+
+var _ terraform.Importable = &RouteTableAssociation{}
+
+func (e *RouteTableAssociation) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*RouteTableAssociation)
+	if a.Subnet == nil || a.Subnet.ID == nil || a.RouteTable == nil || a.RouteTable.ID == nil {
+		return nil
+	}
+	t.AddImport("aws_route_table_association", *e.Name, *a.Subnet.ID+"/"+*a.RouteTable.ID)
+	return nil
+}
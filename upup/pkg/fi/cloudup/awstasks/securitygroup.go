@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -228,6 +229,44 @@ func (e *SecurityGroup) TerraformLink() *terraformWriter.Literal {
 
 // deleteSecurityGroupRule tracks a securitygrouprule that we're going to delete
 // It implements fi.CloudupDeletion
+type cloudformationSecurityGroup struct {
+	Name        *string                 `json:"GroupName"`
+	VPCID       *cloudformation.Literal `json:"VpcId"`
+	Description *string                 `json:"GroupDescription"`
+	Tags        []cloudformation.Tag    `json:"Tags,omitempty"`
+}
+
+func (_ *SecurityGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *SecurityGroup) error {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		return nil
+	}
+
+	cf := &cloudformationSecurityGroup{
+		Name:        e.Name,
+		VPCID:       e.VPC.CloudformationLink(),
+		Description: e.Description,
+		Tags:        cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::SecurityGroup", *e.Name, cf)
+}
+
+func (e *SecurityGroup) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		if e.ID != nil {
+			return cloudformation.LiteralString(*e.ID)
+		} else {
+			klog.Warningf("ID not set on shared security group %v", e)
+		}
+	}
+
+	return cloudformation.Ref("AWS::EC2::SecurityGroup", *e.Name)
+}
+
 type deleteSecurityGroupRule struct {
 	rule *ec2types.SecurityGroupRule
 }
@@ -447,3 +486,13 @@ func (r *PortRemovalRule) Matches(permission *ec2types.SecurityGroupRule) bool {
 	}
 	return true
 }
+
+var _ terraform.Importable = &SecurityGroup{}
+
+func (e *SecurityGroup) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*SecurityGroup)
+	if a.ID != nil {
+		t.AddImport("aws_security_group", *e.Name, *a.ID)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -54,6 +55,25 @@ type SecurityGroupRule struct {
 	Egress *bool
 
 	Tags map[string]string
+
+	// CIDRsVariable is set when the rule is one of a set of rules differing only in their CIDR.
+	// When writing a terraform module, the set is rendered as a single rule per security group,
+	// allowing the CIDRs of a module variable.
+	CIDRsVariable *CIDRsVariable
+}
+
+// CIDRsVariable describes a terraform module variable holding a list of CIDRs or prefix lists.
+type CIDRsVariable struct {
+	Name        string
+	Description string
+	// CIDRs is the default value of the variable.
+	CIDRs []string
+}
+
+var _ fi.CloudupHasDependencies = &CIDRsVariable{}
+
+func (e *CIDRsVariable) GetDependencies(tasks map[string]fi.CloudupTask) []fi.CloudupTask {
+	return nil
 }
 
 func (e *SecurityGroupRule) Find(c *fi.CloudupContext) (*SecurityGroupRule, error) {
@@ -134,6 +154,7 @@ func (e *SecurityGroupRule) Find(c *fi.CloudupContext) (*SecurityGroupRule, erro
 
 		// Avoid spurious changes
 		actual.Lifecycle = e.Lifecycle
+		actual.CIDRsVariable = e.CIDRsVariable
 
 		e.ID = actual.ID
 
@@ -351,10 +372,10 @@ type terraformSecurityGroupIngress struct {
 	FromPort *int32 `cty:"from_port"`
 	ToPort   *int32 `cty:"to_port"`
 
-	Protocol       *string  `cty:"protocol"`
-	CIDRBlocks     []string `cty:"cidr_blocks"`
-	IPv6CIDRBlocks []string `cty:"ipv6_cidr_blocks"`
-	PrefixListIDs  []string `cty:"prefix_list_ids"`
+	Protocol       *string                  `cty:"protocol"`
+	CIDRBlocks     *terraformWriter.Literal `cty:"cidr_blocks"`
+	IPv6CIDRBlocks *terraformWriter.Literal `cty:"ipv6_cidr_blocks"`
+	PrefixListIDs  *terraformWriter.Literal `cty:"prefix_list_ids"`
 }
 
 func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SecurityGroupRule) error {
@@ -388,15 +409,160 @@ func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e,
 		tf.SourceGroup = e.SourceGroup.TerraformLink()
 	}
 
+	if t.Module && e.CIDRsVariable != nil {
+		cidrs, err := t.AddVariable(e.CIDRsVariable.Name, &terraformWriter.Variable{
+			Type:        terraformWriter.LiteralTokens("list(string)"),
+			Description: fi.PtrTo(e.CIDRsVariable.Description),
+			Default:     terraformWriter.LiteralListExpression(stringLiterals(e.CIDRsVariable.CIDRs)...),
+		})
+		if err != nil {
+			return err
+		}
+		// Split the variable the same way as SetCidrOrPrefix
+		tf.CIDRBlocks = terraformWriter.LiteralTokens(fmt.Sprintf("[for cidr in %s : cidr if substr(cidr, 0, 3) != \"pl-\" && length(regexall(\":\", cidr)) == 0]", cidrs.String))
+		tf.IPv6CIDRBlocks = terraformWriter.LiteralTokens(fmt.Sprintf("[for cidr in %s : cidr if substr(cidr, 0, 3) != \"pl-\" && length(regexall(\":\", cidr)) > 0]", cidrs.String))
+		tf.PrefixListIDs = terraformWriter.LiteralTokens(fmt.Sprintf("[for cidr in %s : cidr if substr(cidr, 0, 3) == \"pl-\"]", cidrs.String))
+
+		// All the rules of the set render the same resource
+		name := e.CIDRsVariable.Name + "-" + fi.ValueOf(e.SecurityGroup.Name)
+		if fi.ValueOf(e.Egress) {
+			name += "-egress"
+		}
+		return t.RenderSharedResource("aws_security_group_rule", name, tf)
+	}
+
 	if e.CIDR != nil {
-		tf.CIDRBlocks = append(tf.CIDRBlocks, *e.CIDR)
+		tf.CIDRBlocks = terraformWriter.LiteralListExpression(terraformWriter.LiteralFromStringValue(*e.CIDR))
 	}
 	if e.IPv6CIDR != nil {
-		tf.IPv6CIDRBlocks = append(tf.IPv6CIDRBlocks, *e.IPv6CIDR)
+		tf.IPv6CIDRBlocks = terraformWriter.LiteralListExpression(terraformWriter.LiteralFromStringValue(*e.IPv6CIDR))
 	}
 	if e.PrefixList != nil {
-		tf.PrefixListIDs = append(tf.PrefixListIDs, *e.PrefixList)
+		tf.PrefixListIDs = terraformWriter.LiteralListExpression(terraformWriter.LiteralFromStringValue(*e.PrefixList))
 	}
 
 	return t.RenderResource("aws_security_group_rule", *e.Name, tf)
 }
+
+func stringLiterals(values []string) []*terraformWriter.Literal {
+	var literals []*terraformWriter.Literal
+	for _, v := range values {
+		literals = append(literals, terraformWriter.LiteralFromStringValue(v))
+	}
+	return literals
+}
+
+type cloudformationSecurityGroupRule struct {
+	SecurityGroup *cloudformation.Literal `json:"GroupId"`
+
+	SourceGroup      *cloudformation.Literal `json:"SourceSecurityGroupId,omitempty"`
+	DestinationGroup *cloudformation.Literal `json:"DestinationSecurityGroupId,omitempty"`
+
+	FromPort *int32 `json:"FromPort,omitempty"`
+	ToPort   *int32 `json:"ToPort,omitempty"`
+
+	Protocol                *string `json:"IpProtocol"`
+	CIDR                    *string `json:"CidrIp,omitempty"`
+	IPv6CIDR                *string `json:"CidrIpv6,omitempty"`
+	SourcePrefixListID      *string `json:"SourcePrefixListId,omitempty"`
+	DestinationPrefixListID *string `json:"DestinationPrefixListId,omitempty"`
+}
+
+func (_ *SecurityGroupRule) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *SecurityGroupRule) error {
+	egress := fi.ValueOf(e.Egress)
+
+	cf := &cloudformationSecurityGroupRule{
+		SecurityGroup: e.SecurityGroup.CloudformationLink(),
+		FromPort:      e.FromPort,
+		ToPort:        e.ToPort,
+		Protocol:      e.Protocol,
+		CIDR:          e.CIDR,
+		IPv6CIDR:      e.IPv6CIDR,
+	}
+
+	if e.Protocol == nil {
+		cf.Protocol = fi.PtrTo("-1")
+		cf.FromPort = nil
+		cf.ToPort = nil
+
+		if egress && fi.ValueOf(e.CIDR) == "0.0.0.0/0" && e.SourceGroup == nil {
+			// EC2 creates this rule for every new security group, and refuses duplicates
+			klog.V(4).Infof("skipping default egress rule %q", fi.ValueOf(e.Name))
+			return nil
+		}
+	} else {
+		if cf.FromPort == nil {
+			cf.FromPort = fi.PtrTo(int32(0))
+		}
+		if cf.ToPort == nil {
+			cf.ToPort = fi.PtrTo(int32(65535))
+		}
+	}
+
+	if e.SourceGroup != nil {
+		if egress {
+			cf.DestinationGroup = e.SourceGroup.CloudformationLink()
+		} else {
+			cf.SourceGroup = e.SourceGroup.CloudformationLink()
+		}
+	}
+
+	if e.PrefixList != nil {
+		if egress {
+			cf.DestinationPrefixListID = e.PrefixList
+		} else {
+			cf.SourcePrefixListID = e.PrefixList
+		}
+	}
+
+	resourceType := "AWS::EC2::SecurityGroupIngress"
+	if egress {
+		resourceType = "AWS::EC2::SecurityGroupEgress"
+	}
+	return t.RenderResource(resourceType, *e.Name, cf)
+}
+
+var _ terraform.Importable = &SecurityGroupRule{}
+
+func (e *SecurityGroupRule) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	if e.SecurityGroup == nil || e.SecurityGroup.ID == nil {
+		return nil
+	}
+
+	// Rules are imported using SGID_TYPE_PROTOCOL_FROMPORT_TOPORT_SOURCE
+	ruleType := "ingress"
+	if fi.ValueOf(e.Egress) {
+		ruleType = "egress"
+	}
+	protocol := fi.ValueOf(e.Protocol)
+	fromPort := fi.ValueOf(e.FromPort)
+	toPort := fi.ValueOf(e.ToPort)
+	if e.Protocol == nil || protocol == "-1" {
+		protocol = "all"
+		fromPort = 0
+		toPort = 65536
+	} else if e.ToPort == nil {
+		toPort = 65535
+	}
+
+	var source string
+	switch {
+	case e.SourceGroup != nil:
+		if e.SourceGroup.ID == nil {
+			return nil
+		}
+		source = *e.SourceGroup.ID
+	case e.CIDR != nil:
+		source = *e.CIDR
+	case e.IPv6CIDR != nil:
+		source = *e.IPv6CIDR
+	case e.PrefixList != nil:
+		source = *e.PrefixList
+	default:
+		return nil
+	}
+
+	id := fmt.Sprintf("%s_%s_%s_%d_%d_%s", *e.SecurityGroup.ID, ruleType, protocol, fromPort, toPort, source)
+	t.AddImport("aws_security_group_rule", *e.Name, id)
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+func TestSecurityGroupRuleTerraformModuleRender(t *testing.T) {
+	outdir := t.TempDir()
+	cloud := awsup.BuildMockAWSCloud("eu-west-2", "abc")
+	target := terraform.NewTerraformTarget(cloud, "test", outdir, nil)
+	target.Module = true
+
+	sg := &SecurityGroup{Name: fi.PtrTo("nodes.example.com")}
+	sshAccess := &CIDRsVariable{
+		Name:        "ssh_access",
+		Description: "CIDRs allowed to SSH to the instances",
+		CIDRs:       []string{"10.0.0.0/8", "2001:db8::/32"},
+	}
+	for _, cidr := range sshAccess.CIDRs {
+		rule := &SecurityGroupRule{
+			Name:          fi.PtrTo("ssh-" + cidr),
+			SecurityGroup: sg,
+			Protocol:      fi.PtrTo("tcp"),
+			FromPort:      fi.PtrTo(int32(22)),
+			ToPort:        fi.PtrTo(int32(22)),
+			CIDRsVariable: sshAccess,
+		}
+		rule.SetCidrOrPrefix(cidr)
+		if err := rule.RenderTerraform(target, nil, rule, rule); err != nil {
+			t.Fatalf("unexpected error rendering %s: %v", cidr, err)
+		}
+	}
+
+	if err := target.Finish(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := os.ReadFile(path.Join(outdir, "kubernetes.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `variable "ssh_access" {
+  default     = ["10.0.0.0/8", "2001:db8::/32"]
+  description = "CIDRs allowed to SSH to the instances"
+  type        = list(string)
+}
+
+resource "aws_security_group_rule" "ssh_access-nodes-example-com" {
+  cidr_blocks       = [for cidr in var.ssh_access : cidr if substr(cidr, 0, 3) != "pl-" && length(regexall(":", cidr)) == 0]
+  from_port         = 22
+  ipv6_cidr_blocks  = [for cidr in var.ssh_access : cidr if substr(cidr, 0, 3) != "pl-" && length(regexall(":", cidr)) > 0]
+  prefix_list_ids   = [for cidr in var.ssh_access : cidr if substr(cidr, 0, 3) == "pl-"]
+  protocol          = "tcp"
+  security_group_id = aws_security_group.nodes-example-com.id
+  to_port           = 22
+  type              = "ingress"
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected output, diff:\n%s", diff.FormatDiff(expected, string(actual)))
+	}
+}
@@ -32,6 +32,7 @@ import (
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -219,6 +220,49 @@ func (e *SQS) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_sqs_queue", *e.Name, "arn")
 }
 
+type cloudformationSQSQueue struct {
+	QueueName              *string              `json:"QueueName"`
+	MessageRetentionPeriod int                  `json:"MessageRetentionPeriod,omitempty"`
+	Tags                   []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+type cloudformationSQSQueuePolicy struct {
+	Queues         []*cloudformation.Literal `json:"Queues"`
+	PolicyDocument json.RawMessage           `json:"PolicyDocument"`
+}
+
+func (_ *SQS) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *SQS) error {
+	cf := &cloudformationSQSQueue{
+		QueueName:              e.Name,
+		MessageRetentionPeriod: e.MessageRetentionPeriod,
+		Tags:                   cloudformation.BuildTags(e.Tags),
+	}
+	if err := t.RenderResource("AWS::SQS::Queue", *e.Name, cf); err != nil {
+		return err
+	}
+
+	if e.Policy == nil {
+		return nil
+	}
+
+	policy, err := cloudformation.JSONResource(e.Policy)
+	if err != nil {
+		return fmt.Errorf("error rendering Policy: %v", err)
+	}
+
+	// CloudFormation sets the queue policy with a separate resource
+	queuePolicy := &cloudformationSQSQueuePolicy{
+		Queues:         []*cloudformation.Literal{cloudformation.Ref("AWS::SQS::Queue", *e.Name)},
+		PolicyDocument: policy,
+	}
+	return t.RenderResource("AWS::SQS::QueuePolicy", *e.Name, queuePolicy)
+}
+
+// CloudformationLink returns the ARN of the queue
+func (e *SQS) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.GetAtt("AWS::SQS::Queue", *e.Name, "Arn")
+}
+
 // intersectSQSTags does the same thing as intersectTags, but takes different input because SQS tags are listed differently
 func intersectSQSTags(tags map[string]string, desired map[string]string) map[string]string {
 	if tags == nil {
@@ -236,3 +280,13 @@ func intersectSQSTags(tags map[string]string, desired map[string]string) map[str
 	}
 	return actual
 }
+
+var _ terraform.Importable = &SQS{}
+
+func (e *SQS) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*SQS)
+	if a.URL != nil {
+		t.AddImport("aws_sqs_queue", *e.Name, *a.URL)
+	}
+	return nil
+}
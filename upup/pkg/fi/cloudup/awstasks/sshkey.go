@@ -29,6 +29,7 @@ import (
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -232,3 +233,52 @@ func (e *SSHKey) TerraformLink() *terraformWriter.Literal {
 func (e *SSHKey) NoSSHKey() bool {
 	return e.ID == nil && e.Name == nil && e.PublicKey == nil && e.KeyFingerprint == nil
 }
+
+type cloudformationSSHKey struct {
+	KeyName           *string              `json:"KeyName"`
+	PublicKeyMaterial string               `json:"PublicKeyMaterial"`
+	Tags              []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (_ *SSHKey) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *SSHKey) error {
+	// We don't want to render a key definition when we're using one that already exists
+	if e.IsExistingKey() {
+		return nil
+	}
+
+	publicKey, err := fi.ResourceAsString(e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("error rendering PublicKey: %v", err)
+	}
+
+	cf := &cloudformationSSHKey{
+		KeyName:           e.Name,
+		PublicKeyMaterial: strings.TrimSpace(publicKey),
+		Tags:              cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::KeyPair", *e.Name, cf)
+}
+
+// CloudformationLink returns the name of the key pair
+func (e *SSHKey) CloudformationLink() *cloudformation.Literal {
+	if e.NoSSHKey() {
+		return nil
+	}
+	if e.IsExistingKey() {
+		return cloudformation.LiteralString(*e.Name)
+	}
+
+	return cloudformation.Ref("AWS::EC2::KeyPair", *e.Name)
+}
+
+var _ terraform.Importable = &SSHKey{}
+
+func (e *SSHKey) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*SSHKey)
+	if a.Name != nil {
+		tfName := strings.Replace(*e.Name, ":", "", -1)
+		t.AddImport("aws_key_pair", tfName, *a.Name)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -473,6 +474,109 @@ func (e *Subnet) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_subnet", *e.Name, "id")
 }
 
+type cloudformationSubnet struct {
+	VPCID                         *cloudformation.Literal                            `json:"VpcId"`
+	CIDR                          *string                                            `json:"CidrBlock,omitempty"`
+	IPv6CIDR                      *cloudformation.Literal                            `json:"Ipv6CidrBlock,omitempty"`
+	IPv6Native                    *bool                                              `json:"Ipv6Native,omitempty"`
+	AssignIPv6AddressOnCreation   *bool                                              `json:"AssignIpv6AddressOnCreation,omitempty"`
+	AvailabilityZone              *string                                            `json:"AvailabilityZone,omitempty"`
+	EnableDNS64                   *bool                                              `json:"EnableDns64,omitempty"`
+	PrivateDNSNameOptionsOnLaunch *cloudformationSubnetPrivateDNSNameOptionsOnLaunch `json:"PrivateDnsNameOptionsOnLaunch,omitempty"`
+	Tags                          []cloudformation.Tag                               `json:"Tags,omitempty"`
+}
+
+type cloudformationSubnetPrivateDNSNameOptionsOnLaunch struct {
+	EnableResourceNameDNSAAAARecord *bool   `json:"EnableResourceNameDnsAAAARecord,omitempty"`
+	EnableResourceNameDNSARecord    *bool   `json:"EnableResourceNameDnsARecord,omitempty"`
+	HostnameType                    *string `json:"HostnameType,omitempty"`
+}
+
+func (_ *Subnet) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *Subnet) error {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		// We won't apply changes, but our validation (kops update) will still warn
+		return nil
+	}
+
+	var dependsOn []string
+	var ipv6CIDR *cloudformation.Literal
+	if strings.HasPrefix(aws.ToString(e.IPv6CIDR), "/") {
+		if e.VPC.IPv6CIDR != nil {
+			subnetIPv6CIDR, err := calculateSubnetCIDR(e.VPC.IPv6CIDR, e.IPv6CIDR)
+			if err != nil {
+				return err
+			}
+			ipv6CIDR = cloudformation.LiteralString(*subnetIPv6CIDR)
+		} else {
+			if fi.ValueOf(e.VPC.Shared) {
+				return fmt.Errorf("IPv6 CIDR of shared VPC %q is not known", fi.ValueOf(e.VPC.ID))
+			}
+			newSize, netNum, err := utils.ParseCIDRNotation(*e.IPv6CIDR)
+			if err != nil {
+				return fmt.Errorf("error parsing CIDR subnet: %v", err)
+			}
+			vpcIPv6CIDR := cloudformation.Select(0, cloudformation.GetAtt("AWS::EC2::VPC", *e.VPC.Name, "Ipv6CidrBlocks"))
+			ipv6CIDR = cloudformation.Select(int(netNum), cloudformation.Cidr(vpcIPv6CIDR, int(netNum)+1, 128-newSize))
+		}
+		if e.AmazonIPv6CIDR != nil {
+			dependsOn = append(dependsOn, e.AmazonIPv6CIDR.CloudformationDependency())
+		}
+	} else if e.IPv6CIDR != nil {
+		ipv6CIDR = cloudformation.LiteralString(aws.ToString(e.IPv6CIDR))
+	}
+	if e.VPCCIDRBlock != nil && !fi.ValueOf(e.VPCCIDRBlock.Shared) {
+		dependsOn = append(dependsOn, e.VPCCIDRBlock.CloudformationDependency())
+	}
+
+	cf := &cloudformationSubnet{
+		VPCID:            e.VPC.CloudformationLink(),
+		CIDR:             e.CIDR,
+		IPv6CIDR:         ipv6CIDR,
+		AvailabilityZone: e.AvailabilityZone,
+		Tags:             cloudformation.BuildTags(e.Tags),
+	}
+	if fi.ValueOf(e.CIDR) == "" {
+		cf.EnableDNS64 = fi.PtrTo(true)
+		cf.IPv6Native = fi.PtrTo(true)
+	}
+	if fi.ValueOf(e.IPv6CIDR) != "" {
+		cf.AssignIPv6AddressOnCreation = fi.PtrTo(true)
+	}
+	if e.ResourceBasedNaming != nil {
+		hostnameType := ec2types.HostnameTypeIpName
+		if *e.ResourceBasedNaming {
+			hostnameType = ec2types.HostnameTypeResourceName
+		}
+		cf.PrivateDNSNameOptionsOnLaunch = &cloudformationSubnetPrivateDNSNameOptionsOnLaunch{
+			HostnameType: fi.PtrTo(string(hostnameType)),
+		}
+		if fi.ValueOf(e.CIDR) != "" {
+			cf.PrivateDNSNameOptionsOnLaunch.EnableResourceNameDNSARecord = e.ResourceBasedNaming
+		}
+		if fi.ValueOf(e.IPv6CIDR) != "" {
+			cf.PrivateDNSNameOptionsOnLaunch.EnableResourceNameDNSAAAARecord = e.ResourceBasedNaming
+		}
+	}
+
+	return t.RenderResource("AWS::EC2::Subnet", *e.Name, cf, dependsOn...)
+}
+
+func (e *Subnet) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if subnet is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing subnet with id %q", *e.ID)
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::Subnet", *e.Name)
+}
+
 func (e *Subnet) FindDeletions(c *fi.CloudupContext) ([]fi.CloudupDeletion, error) {
 	if e.ID == nil || aws.ToBool(e.Shared) {
 		return nil, nil
@@ -572,3 +676,13 @@ func calculateSubnetCIDR(vpcCIDR, subnetCIDR *string) (*string, error) {
 
 	return aws.String(newCIDR), nil
 }
+
+var _ terraform.Importable = &Subnet{}
+
+func (e *Subnet) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*Subnet)
+	if a.ID != nil {
+		t.AddImport("aws_subnet", *e.Name, *a.ID)
+	}
+	return nil
+}
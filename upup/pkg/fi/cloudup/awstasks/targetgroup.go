@@ -29,8 +29,10 @@ import (
 	"k8s.io/kops/pkg/truncate"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+	"k8s.io/kops/util/pkg/maps"
 )
 
 const (
@@ -484,6 +486,64 @@ func (e *TargetGroup) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_lb_target_group", *e.Name, "id")
 }
 
+type cloudformationTargetGroup struct {
+	Name                       string                                 `json:"Name"`
+	Port                       int32                                  `json:"Port"`
+	Protocol                   elbv2types.ProtocolEnum                `json:"Protocol"`
+	VPCID                      *cloudformation.Literal                `json:"VpcId"`
+	HealthCheckIntervalSeconds int32                                  `json:"HealthCheckIntervalSeconds"`
+	HealthyThresholdCount      int32                                  `json:"HealthyThresholdCount"`
+	UnhealthyThresholdCount    int32                                  `json:"UnhealthyThresholdCount"`
+	HealthCheckProtocol        elbv2types.ProtocolEnum                `json:"HealthCheckProtocol"`
+	TargetGroupAttributes      []*cloudformationLoadBalancerAttribute `json:"TargetGroupAttributes,omitempty"`
+	Tags                       []cloudformation.Tag                   `json:"Tags,omitempty"`
+}
+
+func (_ *TargetGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *TargetGroup) error {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		return nil
+	}
+
+	if e.VPC == nil {
+		return fmt.Errorf("Missing VPC task from target group:\n%v\n%v", e, e.VPC)
+	}
+
+	cf := &cloudformationTargetGroup{
+		Name:                       *e.Name,
+		Port:                       *e.Port,
+		Protocol:                   e.Protocol,
+		VPCID:                      e.VPC.CloudformationLink(),
+		HealthCheckIntervalSeconds: *e.Interval,
+		HealthyThresholdCount:      *e.HealthyThreshold,
+		UnhealthyThresholdCount:    *e.UnhealthyThreshold,
+		HealthCheckProtocol:        elbv2types.ProtocolEnumTcp,
+		Tags:                       cloudformation.BuildTags(e.Tags),
+	}
+
+	for _, attr := range maps.SortedKeys(e.Attributes) {
+		cf.TargetGroupAttributes = append(cf.TargetGroupAttributes, &cloudformationLoadBalancerAttribute{
+			Key:   attr,
+			Value: e.Attributes[attr],
+		})
+	}
+
+	return t.RenderResource("AWS::ElasticLoadBalancingV2::TargetGroup", *e.Name, cf)
+}
+
+// CloudformationLink returns the ARN of the target group
+func (e *TargetGroup) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.ARN != nil {
+			return cloudformation.LiteralString(*e.ARN)
+		} else {
+			klog.Warningf("ID not set on shared Target Group %v", e)
+		}
+	}
+	return cloudformation.Ref("AWS::ElasticLoadBalancingV2::TargetGroup", *e.Name)
+}
+
 var _ fi.CloudupProducesDeletions = &TargetGroup{}
 
 // FindDeletions is responsible for finding launch templates which can be deleted
@@ -542,3 +602,13 @@ func (d *deleteTargetGroup) Item() string {
 func (d *deleteTargetGroup) DeferDeletion() bool {
 	return true
 }
+
+var _ terraform.Importable = &TargetGroup{}
+
+func (e *TargetGroup) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*TargetGroup)
+	if a.ARN != nil {
+		t.AddImport("aws_lb_target_group", *e.Name, *a.ARN)
+	}
+	return nil
+}
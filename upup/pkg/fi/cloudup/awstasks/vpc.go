@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -368,6 +369,50 @@ func (e *VPC) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_vpc", *e.Name, "id")
 }
 
+type cloudformationVPC struct {
+	CIDR               *string              `json:"CidrBlock,omitempty"`
+	EnableDNSHostnames *bool                `json:"EnableDnsHostnames,omitempty"`
+	EnableDNSSupport   *bool                `json:"EnableDnsSupport,omitempty"`
+	Tags               []cloudformation.Tag `json:"Tags,omitempty"`
+}
+
+func (_ *VPC) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPC) error {
+	if err := t.AddOutputVariable("VPCId", e.CloudformationLink()); err != nil {
+		return err
+	}
+
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		// Not cloudformation owned / managed
+		// We won't apply changes, but our validation (kops update) will still warn
+		return nil
+	}
+
+	// The Amazon provided IPv6 CIDR block is added by the VPCAmazonIPv6CIDRBlock task
+	cf := &cloudformationVPC{
+		CIDR:               e.CIDR,
+		EnableDNSHostnames: e.EnableDNSHostnames,
+		EnableDNSSupport:   e.EnableDNSSupport,
+		Tags:               cloudformation.BuildTags(e.Tags),
+	}
+
+	return t.RenderResource("AWS::EC2::VPC", *e.Name, cf)
+}
+
+func (e *VPC) CloudformationLink() *cloudformation.Literal {
+	shared := fi.ValueOf(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if VPC is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing VPC with id %q", *e.ID)
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::VPC", *e.Name)
+}
+
 type deleteVPCCIDRBlock struct {
 	vpcID         *string
 	cidrBlock     *string
@@ -400,3 +445,13 @@ func (d *deleteVPCCIDRBlock) Item() string {
 func (d *deleteVPCCIDRBlock) DeferDeletion() bool {
 	return false // TODO: should we defer this?
 }
+
+var _ terraform.Importable = &VPC{}
+
+func (e *VPC) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*VPC)
+	if a.ID != nil {
+		t.AddImport("aws_vpc", *e.Name, *a.ID)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -116,3 +117,27 @@ func (_ *VPCDHCPOptionsAssociation) RenderTerraform(t *terraform.TerraformTarget
 
 	return t.RenderResource("aws_vpc_dhcp_options_association", *e.Name, tf)
 }
+
+type cloudformationVPCDHCPOptionsAssociation struct {
+	VPCID         *cloudformation.Literal `json:"VpcId"`
+	DHCPOptionsID *cloudformation.Literal `json:"DhcpOptionsId"`
+}
+
+func (_ *VPCDHCPOptionsAssociation) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCDHCPOptionsAssociation) error {
+	cf := &cloudformationVPCDHCPOptionsAssociation{
+		VPCID:         e.VPC.CloudformationLink(),
+		DHCPOptionsID: e.DHCPOptions.CloudformationLink(),
+	}
+
+	return t.RenderResource("AWS::EC2::VPCDHCPOptionsAssociation", *e.Name, cf)
+}
+
+var _ terraform.Importable = &VPCDHCPOptionsAssociation{}
+
+func (e *VPCDHCPOptionsAssociation) TerraformImports(t *terraform.TerraformTarget, actual fi.CloudupTask) error {
+	a := actual.(*VPCDHCPOptionsAssociation)
+	if a.VPC != nil && a.VPC.ID != nil {
+		t.AddImport("aws_vpc_dhcp_options_association", *e.Name, *a.VPC.ID)
+	}
+	return nil
+}
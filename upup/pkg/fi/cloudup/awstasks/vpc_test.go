@@ -18,7 +18,11 @@ package awstasks
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -27,6 +31,7 @@ import (
 	"k8s.io/kops/cloudmock/aws/mockec2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
 func TestVPCCreate(t *testing.T) {
@@ -202,3 +207,69 @@ func TestSharedVPCAdditionalCIDR(t *testing.T) {
 		}
 	}
 }
+
+func TestVPCTerraformImportExisting(t *testing.T) {
+	ctx := context.TODO()
+
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func() map[string]fi.CloudupTask {
+		vpc1 := &VPC{
+			Name:      s("vpc1"),
+			Lifecycle: fi.LifecycleSync,
+			CIDR:      s("172.21.0.0/16"),
+			Tags:      map[string]string{"Name": "vpc1"},
+		}
+		sg1 := &SecurityGroup{
+			Name:        s("sg1"),
+			Lifecycle:   fi.LifecycleSync,
+			Description: s("Description"),
+			VPC:         vpc1,
+			Tags:        map[string]string{"Name": "sg1"},
+		}
+		return map[string]fi.CloudupTask{
+			"vpc1": vpc1,
+			"sg1":  sg1,
+		}
+	}
+
+	var vpcID, sgID string
+	{
+		allTasks := buildTasks()
+		runTasks(t, cloud, allTasks)
+		vpcID = fi.ValueOf(allTasks["vpc1"].(*VPC).ID)
+		sgID = fi.ValueOf(allTasks["sg1"].(*SecurityGroup).ID)
+	}
+
+	outDir := t.TempDir()
+	allTasks := buildTasks()
+	target := terraform.NewTerraformTarget(cloud, "", outDir, nil)
+	target.ImportExisting = true
+
+	context, err := fi.NewCloudupContext(ctx, fi.DeletionProcessingModeIgnore, target, nil, cloud, nil, nil, nil, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+	if err := target.Finish(allTasks); err != nil {
+		t.Fatalf("unexpected error during Finish: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(outDir, "kubernetes.tf"))
+	if err != nil {
+		t.Fatalf("error reading terraform output: %v", err)
+	}
+	for _, expected := range []string{
+		fmt.Sprintf("import {\n  id = %q\n  to = aws_security_group.sg1\n}\n", sgID),
+		fmt.Sprintf("import {\n  id = %q\n  to = aws_vpc.vpc1\n}\n", vpcID),
+	} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected terraform output to contain %q, got:\n%s", expected, string(b))
+		}
+	}
+}
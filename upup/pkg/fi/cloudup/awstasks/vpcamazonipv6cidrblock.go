@@ -25,6 +25,7 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -113,6 +114,31 @@ func (_ *VPCAmazonIPv6CIDRBlock) RenderTerraform(t *terraform.TerraformTarget, a
 	return nil
 }
 
+type cloudformationVPCAmazonIPv6CIDRBlock struct {
+	VPCID                       *cloudformation.Literal `json:"VpcId"`
+	AmazonProvidedIpv6CidrBlock *bool                   `json:"AmazonProvidedIpv6CidrBlock"`
+}
+
+func (_ *VPCAmazonIPv6CIDRBlock) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCAmazonIPv6CIDRBlock) error {
+	if aws.ToBool(e.Shared) {
+		// VPC not owned by kOps, no changes will be applied
+		return nil
+	}
+
+	cf := &cloudformationVPCAmazonIPv6CIDRBlock{
+		VPCID:                       e.VPC.CloudformationLink(),
+		AmazonProvidedIpv6CidrBlock: aws.Bool(true),
+	}
+
+	return t.RenderResource("AWS::EC2::VPCCidrBlock", *e.Name, cf)
+}
+
+// CloudformationDependency returns the logical ID of the CIDR block association,
+// which resources using the VPC's IPv6 CIDR must depend on.
+func (e *VPCAmazonIPv6CIDRBlock) CloudformationDependency() string {
+	return cloudformation.LogicalID("AWS::EC2::VPCCidrBlock", *e.Name)
+}
+
 func findVPCIPv6CIDR(cloud awsup.AWSCloud, vpcID *string) (*string, error) {
 	vpc, err := cloud.DescribeVPC(aws.ToString(vpcID))
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -165,3 +166,33 @@ func (_ *VPCCIDRBlock) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 	name := fmt.Sprintf("cidr-%v", *e.Name)
 	return t.RenderResource("aws_vpc_ipv4_cidr_block_association", name, tf)
 }
+
+type cloudformationVPCCIDRBlock struct {
+	VPCID     *cloudformation.Literal `json:"VpcId"`
+	CIDRBlock *string                 `json:"CidrBlock"`
+}
+
+func (_ *VPCCIDRBlock) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCCIDRBlock) error {
+	shared := aws.ToBool(e.Shared)
+	if shared && a == nil {
+		// VPC not owned by kOps, no changes will be applied
+		// Verify that the CIDR block was found.
+		return fmt.Errorf("CIDR block %q not found", aws.ToString(e.CIDRBlock))
+	}
+	if shared {
+		return nil
+	}
+
+	cf := &cloudformationVPCCIDRBlock{
+		VPCID:     e.VPC.CloudformationLink(),
+		CIDRBlock: e.CIDRBlock,
+	}
+
+	return t.RenderResource("AWS::EC2::VPCCidrBlock", *e.Name, cf)
+}
+
+// CloudformationDependency returns the logical ID of the CIDR block association,
+// which subnets within the CIDR block must depend on.
+func (e *VPCCIDRBlock) CloudformationDependency() string {
+	return cloudformation.LogicalID("AWS::EC2::VPCCidrBlock", *e.Name)
+}
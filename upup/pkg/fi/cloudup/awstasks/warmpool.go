@@ -23,6 +23,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -149,3 +150,23 @@ func (*WarmPool) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *WarmPool) error
 func (_ *WarmPool) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *WarmPool) error {
 	return nil
 }
+
+type cloudformationWarmPool struct {
+	AutoScalingGroupName     *cloudformation.Literal `json:"AutoScalingGroupName"`
+	MinSize                  *int32                  `json:"MinSize,omitempty"`
+	MaxGroupPreparedCapacity *int32                  `json:"MaxGroupPreparedCapacity,omitempty"`
+}
+
+func (_ *WarmPool) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *WarmPool) error {
+	if !fi.ValueOf(e.Enabled) {
+		return nil
+	}
+
+	cf := &cloudformationWarmPool{
+		AutoScalingGroupName:     e.AutoscalingGroup.CloudformationLink(),
+		MinSize:                  &e.MinSize,
+		MaxGroupPreparedCapacity: e.MaxSize,
+	}
+
+	return t.RenderResource("AWS::AutoScaling::WarmPool", *e.Name, cf)
+}
@@ -1145,6 +1145,12 @@ func buildCloudInstance(i autoscalingtypes.Instance, instances map[string]*ec2ty
 
 func addCloudInstanceData(cm *cloudinstances.CloudInstance, instance *ec2types.Instance) {
 	cm.MachineType = string(instance.InstanceType)
+	if instance.InstanceLifecycle == ec2types.InstanceLifecycleTypeSpot {
+		cm.Lifecycle = cloudinstances.LifecycleSpot
+	} else {
+		cm.Lifecycle = cloudinstances.LifecycleOnDemand
+	}
+	cm.LaunchTime = aws.ToTime(instance.LaunchTime)
 	for _, tag := range instance.Tags {
 		key := aws.ToString(tag.Key)
 		if !strings.HasPrefix(key, TagNameRolePrefix) {
@@ -112,6 +112,24 @@ func FindRegion(cluster *kops.Cluster) (string, error) {
 	return region, nil
 }
 
+// ZoneToRegion maps an AWS zone name, including local and wavelength zones, to its region name,
+// returning an error if it cannot be mapped
+func ZoneToRegion(zone string) (string, error) {
+	tokens := strings.Split(zone, "-")
+	for i, token := range tokens {
+		if i < 2 || token == "" || token[0] < '0' || token[0] > '9' {
+			continue
+		}
+		// The region ends with the number following its name, such as "us-east-1" in "us-east-1a" or "us-west-2-lax-1a"
+		n := 1
+		for n < len(token) && token[n] >= '0' && token[n] <= '9' {
+			n++
+		}
+		return strings.Join(tokens[:i], "-") + "-" + token[:n], nil
+	}
+	return "", fmt.Errorf("invalid AWS zone: %q", zone)
+}
+
 // FindEC2Tag find the value of the tag with the specified key
 func FindEC2Tag(tags []ec2types.Tag, key string) (string, bool) {
 	for _, tag := range tags {
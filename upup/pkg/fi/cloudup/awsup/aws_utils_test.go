@@ -68,6 +68,32 @@ func TestFindRegion(t *testing.T) {
 	}
 }
 
+func TestZoneToRegion(t *testing.T) {
+	grid := map[string]string{
+		"us-east-1a":              "us-east-1",
+		"ap-southeast-2c":         "ap-southeast-2",
+		"us-gov-west-1a":          "us-gov-west-1",
+		"us-west-2-lax-1a":        "us-west-2",
+		"us-east-1-wl1-bos-wlz-1": "us-east-1",
+		"us-east":                 "",
+		"1a":                      "",
+	}
+	for zone, expected := range grid {
+		region, err := ZoneToRegion(zone)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("expected error mapping zone %q, got region %q", zone, region)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error mapping zone %q: %v", zone, err)
+		} else if region != expected {
+			t.Errorf("unexpected region for zone %q: %q vs %q", zone, expected, region)
+		}
+	}
+}
+
 func TestEC2TagSpecification(t *testing.T) {
 	cases := []struct {
 		Name          string
@@ -67,6 +67,13 @@ func (m *AWSMachineTypeInfo) EphemeralDevices() []*EphemeralDevice {
 	return disks
 }
 
+// ResetMachineTypeInfo clears the cached machine type information, which depends on the cloud it was described by
+func ResetMachineTypeInfo() {
+	machineTypeMutex.Lock()
+	machineTypeInfo = nil
+	machineTypeMutex.Unlock()
+}
+
 func GetMachineTypeInfo(c AWSCloud, machineType ec2types.InstanceType) (*AWSMachineTypeInfo, error) {
 	machineTypeMutex.Lock()
 	defer machineTypeMutex.Unlock()
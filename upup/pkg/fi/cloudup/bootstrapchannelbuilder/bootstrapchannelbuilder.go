@@ -657,6 +657,31 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.CloudupModelBuilderContext)
 		}
 	}
 
+	hasAccelerators := false
+	for _, ig := range b.KopsModelContext.InstanceGroups {
+		if len(ig.Spec.Accelerators) != 0 {
+			hasAccelerators = true
+			break
+		}
+	}
+
+	if hasAccelerators {
+		key := "accelerators.addons.k8s.io"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addon := addons.Add(&channelsapi.AddonSpec{
+				Name:     fi.PtrTo(key),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.PtrTo(location),
+				Id:       id,
+			})
+			addon.BuildPrune = true
+		}
+	}
+
 	if b.Cluster.Spec.CloudProvider.AWS != nil {
 		if b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController != nil && fi.ValueOf(b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController.Enabled) {
 
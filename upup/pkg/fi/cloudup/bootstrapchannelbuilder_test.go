@@ -36,6 +36,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/bootstrapchannelbuilder"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/text"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -55,6 +56,7 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 	runChannelBuilderTest(t, "metrics-server/insecure-1.19", []string{"metrics-server.addons.k8s.io-k8s-1.11"})
 	runChannelBuilderTest(t, "metrics-server/secure-1.19", []string{"metrics-server.addons.k8s.io-k8s-1.11"})
 	runChannelBuilderTest(t, "coredns", []string{"coredns.addons.k8s.io-k8s-1.12"})
+	runChannelBuilderTest(t, "accelerators", []string{"accelerators.addons.k8s.io-k8s-1.16"})
 }
 
 func TestBootstrapChannelBuilder_ServiceAccountIAM(t *testing.T) {
@@ -90,12 +92,25 @@ func runChannelBuilderTest(t *testing.T, key string, addonManifests []string) {
 	if err != nil {
 		t.Fatalf("error reading cluster yaml file %q: %v", clusterYamlPath, err)
 	}
-	obj, _, err := kopscodecs.Decode(clusterYaml, nil)
+	sections := text.SplitContentToSections(clusterYaml)
+	obj, _, err := kopscodecs.Decode(sections[0], nil)
 	if err != nil {
 		t.Fatalf("error parsing cluster yaml %q: %v", clusterYamlPath, err)
 	}
 	cluster := obj.(*kopsapi.Cluster)
 
+	// Any further documents are additional instance groups
+	var instanceGroups []*kopsapi.InstanceGroup
+	for _, section := range sections[1:] {
+		obj, _, err := kopscodecs.Decode(section, nil)
+		if err != nil {
+			t.Fatalf("error parsing cluster yaml %q: %v", clusterYamlPath, err)
+		}
+		ig := obj.(*kopsapi.InstanceGroup)
+		populateAccelerators(ig)
+		instanceGroups = append(instanceGroups, ig)
+	}
+
 	cloud, err := BuildCloud(cluster)
 	if err != nil {
 		t.Fatalf("error from BuildCloud: %v", err)
@@ -152,6 +167,7 @@ func runChannelBuilderTest(t *testing.T, key string, addonManifests []string) {
 			},
 		},
 	}
+	kopsModel.InstanceGroups = append(kopsModel.InstanceGroups, instanceGroups...)
 
 	tf := &TemplateFunctions{
 		KopsModelContext: kopsModel,
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/util/pkg/maps"
+)
+
+// Literal represents a value in a CloudFormation template; either a plain
+// string or an intrinsic function such as Ref or Fn::GetAtt.
+type Literal struct {
+	value interface{}
+}
+
+var _ json.Marshaler = &Literal{}
+
+func (l *Literal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.value)
+}
+
+// String returns a stable representation of the literal, used for sorting.
+func (l *Literal) String() string {
+	b, err := json.Marshal(l.value)
+	if err != nil {
+		return fmt.Sprintf("%v", l.value)
+	}
+	return string(b)
+}
+
+// LiteralString constructs a Literal holding a plain string value.
+func LiteralString(s string) *Literal {
+	return &Literal{value: s}
+}
+
+// Ref constructs a Literal referencing another resource in the template.
+// The value of a Ref depends on the resource type; for most EC2 resources it is the ID.
+func Ref(resourceType, resourceName string) *Literal {
+	return &Literal{value: map[string]interface{}{
+		"Ref": LogicalID(resourceType, resourceName),
+	}}
+}
+
+// GetAtt constructs a Literal referencing an attribute of another resource in the template.
+func GetAtt(resourceType, resourceName, attribute string) *Literal {
+	return &Literal{value: map[string]interface{}{
+		"Fn::GetAtt": []string{LogicalID(resourceType, resourceName), attribute},
+	}}
+}
+
+// Base64 constructs a Literal that base64 encodes the supplied string when the stack is deployed.
+func Base64(s string) *Literal {
+	return &Literal{value: map[string]interface{}{
+		"Fn::Base64": s,
+	}}
+}
+
+// Select constructs a Literal picking the element at index from the supplied list.
+func Select(index int, list *Literal) *Literal {
+	return &Literal{value: map[string]interface{}{
+		"Fn::Select": []interface{}{fmt.Sprintf("%d", index), list},
+	}}
+}
+
+// Cidr constructs a Literal computing count CIDR blocks of cidrBits host bits within ipBlock.
+func Cidr(ipBlock *Literal, count int, cidrBits int) *Literal {
+	return &Literal{value: map[string]interface{}{
+		"Fn::Cidr": []interface{}{ipBlock, fmt.Sprintf("%d", count), fmt.Sprintf("%d", cidrBits)},
+	}}
+}
+
+// LogicalID returns the CloudFormation logical ID for a resource.
+// Logical IDs must be alphanumeric, so we strip all other characters.
+func LogicalID(resourceType, resourceName string) string {
+	var b strings.Builder
+	for _, r := range resourceType + resourceName {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SortLiterals sorts a list of Literal, by their JSON representation.  It does so in-place
+func SortLiterals(v []*Literal) {
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].String() < v[j].String()
+	})
+}
+
+// Tag is a CloudFormation resource tag.
+type Tag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+// BuildTags converts a map of tags to the list form used by CloudFormation, sorted by key.
+func BuildTags(tags map[string]string) []Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	var cfTags []Tag
+	for _, k := range maps.SortedKeys(tags) {
+		cfTags = append(cfTags, Tag{Key: k, Value: tags[k]})
+	}
+	return cfTags
+}
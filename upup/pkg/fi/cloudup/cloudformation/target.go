@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TemplateFileName is the name of the file the template is written to, within the output directory.
+const TemplateFileName = "kubernetes.json"
+
+// CloudformationTarget renders tasks to an AWS CloudFormation template.
+type CloudformationTarget struct {
+	Cloud fi.Cloud
+
+	outDir string
+
+	// mutex protects the following items (resources & outputs)
+	mutex sync.Mutex
+	// resources is a map of CloudFormation resources, keyed by logical ID
+	resources map[string]*cloudformationResource
+	// outputs is a map of CloudFormation template outputs, keyed by name
+	outputs map[string]*cloudformationOutput
+}
+
+type cloudformationResource struct {
+	Type       string      `json:"Type"`
+	Properties interface{} `json:"Properties"`
+	DependsOn  []string    `json:"DependsOn,omitempty"`
+}
+
+type cloudformationOutput struct {
+	Value *Literal `json:"Value"`
+}
+
+type cloudformationTemplate struct {
+	AWSTemplateFormatVersion string                             `json:"AWSTemplateFormatVersion"`
+	Description              string                             `json:"Description,omitempty"`
+	Outputs                  map[string]*cloudformationOutput   `json:"Outputs,omitempty"`
+	Resources                map[string]*cloudformationResource `json:"Resources"`
+}
+
+func NewCloudformationTarget(cloud fi.Cloud, outDir string) *CloudformationTarget {
+	return &CloudformationTarget{
+		Cloud:     cloud,
+		outDir:    outDir,
+		resources: make(map[string]*cloudformationResource),
+		outputs:   make(map[string]*cloudformationOutput),
+	}
+}
+
+var _ fi.CloudupTarget = &CloudformationTarget{}
+
+// RenderResource adds a resource of the given type to the template.
+// The resource can be referenced by other resources using Ref or GetAtt with the same type and name.
+// dependsOn lists the logical IDs of resources that must be created first, where CloudFormation
+// cannot infer the ordering from references.
+func (t *CloudformationTarget) RenderResource(resourceType string, resourceName string, properties interface{}, dependsOn ...string) error {
+	id := LogicalID(resourceType, resourceName)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.resources[id] != nil {
+		return fmt.Errorf("duplicate cloudformation resource %q (%s %q)", id, resourceType, resourceName)
+	}
+	t.resources[id] = &cloudformationResource{
+		Type:       resourceType,
+		Properties: properties,
+		DependsOn:  dependsOn,
+	}
+	return nil
+}
+
+// UpdateResource calls fn with the properties of a previously rendered resource, so that tasks
+// can add properties that CloudFormation only accepts inline (e.g. the targets of an events rule).
+func (t *CloudformationTarget) UpdateResource(resourceType string, resourceName string, fn func(properties interface{}) error) error {
+	id := LogicalID(resourceType, resourceName)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	r := t.resources[id]
+	if r == nil {
+		return fmt.Errorf("cloudformation resource %q (%s %q) not found", id, resourceType, resourceName)
+	}
+	return fn(r.Properties)
+}
+
+// AddOutputVariable adds an output value to the template.
+func (t *CloudformationTarget) AddOutputVariable(key string, literal *Literal) error {
+	name := LogicalID("", key)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.outputs[name] != nil {
+		return fmt.Errorf("duplicate output: %q", name)
+	}
+	t.outputs[name] = &cloudformationOutput{Value: literal}
+	return nil
+}
+
+// JSONResource reads a JSON document from a resource, for properties such as IAM policy
+// documents that CloudFormation expects as objects rather than strings.
+func JSONResource(r fi.Resource) (json.RawMessage, error) {
+	b, err := fi.ResourceAsBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("resource is not valid JSON")
+	}
+	return json.RawMessage(b), nil
+}
+
+func (t *CloudformationTarget) DefaultCheckExisting() bool {
+	return false
+}
+
+// Render returns the template as indented JSON.
+func (t *CloudformationTarget) Render() ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	template := &cloudformationTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Description:              "Generated by kOps",
+		Resources:                t.resources,
+	}
+	if len(t.outputs) != 0 {
+		template.Outputs = t.outputs
+	}
+
+	b, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling cloudformation template: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+func (t *CloudformationTarget) Finish(taskMap map[string]fi.CloudupTask) error {
+	b, err := t.Render()
+	if err != nil {
+		return err
+	}
+
+	p := path.Join(t.outDir, TemplateFileName)
+	if err := os.MkdirAll(path.Dir(p), os.FileMode(0o755)); err != nil {
+		return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
+	}
+	if err := os.WriteFile(p, b, os.FileMode(0o644)); err != nil {
+		return fmt.Errorf("error writing cloudformation template to output file %q: %v", p, err)
+	}
+	klog.Infof("CloudFormation output is in %s", p)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/diff"
+)
+
+type testSubnet struct {
+	VpcId     *Literal `json:"VpcId"`
+	CidrBlock string   `json:"CidrBlock"`
+	Tags      []Tag    `json:"Tags,omitempty"`
+}
+
+func TestLogicalID(t *testing.T) {
+	grid := []struct {
+		resourceType string
+		resourceName string
+		expected     string
+	}{
+		{"AWS::EC2::VPC", "minimal.example.com", "AWSEC2VPCminimalexamplecom"},
+		{"AWS::EC2::Subnet", "us-test-1a.minimal.example.com", "AWSEC2Subnetustest1aminimalexamplecom"},
+		{"", "VPCId", "VPCId"},
+	}
+	for _, g := range grid {
+		actual := LogicalID(g.resourceType, g.resourceName)
+		if actual != g.expected {
+			t.Errorf("LogicalID(%q, %q): expected %q, got %q", g.resourceType, g.resourceName, g.expected, actual)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	target := NewCloudformationTarget(nil, t.TempDir())
+
+	if err := target.RenderResource("AWS::EC2::Subnet", "us-test-1a.example.com", &testSubnet{
+		VpcId:     Ref("AWS::EC2::VPC", "example.com"),
+		CidrBlock: "172.20.32.0/19",
+		Tags:      BuildTags(map[string]string{"Name": "us-test-1a.example.com", "KubernetesCluster": "example.com"}),
+	}, "AWSEC2VPCCidrBlockexamplecom"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := target.RenderResource("AWS::EC2::Subnet", "us-test-1a.example.com", &testSubnet{}); err == nil {
+		t.Errorf("expected error rendering duplicate resource")
+	}
+	if err := target.UpdateResource("AWS::EC2::Subnet", "us-test-1a.example.com", func(properties interface{}) error {
+		properties.(*testSubnet).CidrBlock = "172.20.64.0/19"
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := target.AddOutputVariable("subnet_us-test-1a_id", Ref("AWS::EC2::Subnet", "us-test-1a.example.com")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual, err := target.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{
+  "AWSTemplateFormatVersion": "2010-09-09",
+  "Description": "Generated by kOps",
+  "Outputs": {
+    "subnetustest1aid": {
+      "Value": {
+        "Ref": "AWSEC2Subnetustest1aexamplecom"
+      }
+    }
+  },
+  "Resources": {
+    "AWSEC2Subnetustest1aexamplecom": {
+      "Type": "AWS::EC2::Subnet",
+      "Properties": {
+        "VpcId": {
+          "Ref": "AWSEC2VPCexamplecom"
+        },
+        "CidrBlock": "172.20.64.0/19",
+        "Tags": [
+          {
+            "Key": "KubernetesCluster",
+            "Value": "example.com"
+          },
+          {
+            "Key": "Name",
+            "Value": "us-test-1a.example.com"
+          }
+        ]
+      },
+      "DependsOn": [
+        "AWSEC2VPCCidrBlockexamplecom"
+      ]
+    }
+  }
+}
+`
+	if string(actual) != expected {
+		t.Log(diff.FormatDiff(expected, string(actual)))
+		t.Errorf("unexpected template")
+	}
+}
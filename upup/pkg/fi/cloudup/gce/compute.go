@@ -41,6 +41,7 @@ type ComputeClient interface {
 	InstanceGroupManagers() InstanceGroupManagerClient
 	TargetPools() TargetPoolClient
 	Disks() DiskClient
+	Snapshots() SnapshotClient
 	RegionBackendServices() RegionBackendServiceClient
 }
 
@@ -168,6 +169,12 @@ func (c *computeClientImpl) Disks() DiskClient {
 	}
 }
 
+func (c *computeClientImpl) Snapshots() SnapshotClient {
+	return &snapshotClientImpl{
+		srv: c.srv.Snapshots,
+	}
+}
+
 type ProjectClient interface {
 	Get(project string) (*compute.Project, error)
 }
@@ -602,6 +609,7 @@ type InstanceClient interface {
 	List(ctx context.Context, project, zone string) ([]*compute.Instance, error)
 	Delete(project, zone, name string) (*compute.Operation, error)
 	SetMetadata(project, zone, name string, metadata *compute.Metadata) (*compute.Operation, error)
+	SetLabels(project, zone, name string, labels *compute.InstancesSetLabelsRequest) (*compute.Operation, error)
 }
 
 type instanceClientImpl struct {
@@ -637,6 +645,10 @@ func (c *instanceClientImpl) SetMetadata(project, zone, name string, metadata *c
 	return c.srv.SetMetadata(project, zone, name, metadata).Do()
 }
 
+func (c *instanceClientImpl) SetLabels(project, zone, name string, labels *compute.InstancesSetLabelsRequest) (*compute.Operation, error) {
+	return c.srv.SetLabels(project, zone, name, labels).Do()
+}
+
 type InstanceTemplateClient interface {
 	Insert(project string, template *compute.InstanceTemplate) (*compute.Operation, error)
 	Delete(project, name string) (*compute.Operation, error)
@@ -675,6 +687,7 @@ type InstanceGroupManagerClient interface {
 	List(ctx context.Context, project, zone string) ([]*compute.InstanceGroupManager, error)
 	ListManagedInstances(ctx context.Context, project, zone, name string) ([]*compute.ManagedInstance, error)
 	RecreateInstances(project, zone, name, id string) (*compute.Operation, error)
+	DeleteInstances(project, zone, name, id string) (*compute.Operation, error)
 	SetTargetPools(project, zone, name string, targetPools []string) (*compute.Operation, error)
 	SetInstanceTemplate(project, zone, name, instanceTemplateURL string) (*compute.Operation, error)
 	Resize(project, zone, name string, newSize int64) (*compute.Operation, error)
@@ -729,6 +742,15 @@ func (c *instanceGroupManagerClientImpl) RecreateInstances(project, zone, name,
 	return c.srv.RecreateInstances(project, zone, name, req).Do()
 }
 
+func (c *instanceGroupManagerClientImpl) DeleteInstances(project, zone, name, id string) (*compute.Operation, error) {
+	req := &compute.InstanceGroupManagersDeleteInstancesRequest{
+		Instances: []string{
+			id,
+		},
+	}
+	return c.srv.DeleteInstances(project, zone, name, req).Do()
+}
+
 func (c *instanceGroupManagerClientImpl) SetTargetPools(project, zone, name string, targetPools []string) (*compute.Operation, error) {
 	req := &compute.InstanceGroupManagersSetTargetPoolsRequest{
 		TargetPools: targetPools,
@@ -795,6 +817,7 @@ type DiskClient interface {
 	List(ctx context.Context, project, zone string) ([]*compute.Disk, error)
 	AggregatedList(ctx context.Context, project string) ([]compute.DisksScopedList, error)
 	SetLabels(project, zone, name string, req *compute.ZoneSetLabelsRequest) error
+	CreateSnapshot(project, zone, name string, snapshot *compute.Snapshot) (*compute.Operation, error)
 }
 
 type diskClientImpl struct {
@@ -843,3 +866,28 @@ func (c *diskClientImpl) SetLabels(project, zone, name string, req *compute.Zone
 	_, err := c.srv.SetLabels(project, zone, name, req).Do()
 	return err
 }
+
+func (c *diskClientImpl) CreateSnapshot(project, zone, name string, snapshot *compute.Snapshot) (*compute.Operation, error) {
+	return c.srv.CreateSnapshot(project, zone, name, snapshot).Do()
+}
+
+type SnapshotClient interface {
+	List(ctx context.Context, project string) ([]*compute.Snapshot, error)
+}
+
+type snapshotClientImpl struct {
+	srv *compute.SnapshotsService
+}
+
+var _ SnapshotClient = &snapshotClientImpl{}
+
+func (c *snapshotClientImpl) List(ctx context.Context, project string) ([]*compute.Snapshot, error) {
+	var snapshots []*compute.Snapshot
+	if err := c.srv.List(project).Pages(ctx, func(page *compute.SnapshotList) error {
+		snapshots = append(snapshots, page.Items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strings"
+	"time"
 
 	compute "google.golang.org/api/compute/v1"
 	v1 "k8s.io/api/core/v1"
@@ -49,6 +50,9 @@ func deleteCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup)
 
 // DeleteInstance deletes a GCE instance
 func (c *gceCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	if i.Status == cloudinstances.CloudInstanceStatusDetached {
+		return deleteDetachedCloudInstance(c, i)
+	}
 	return recreateCloudInstance(c, i)
 }
 
@@ -57,10 +61,81 @@ func (c *gceCloudImplementation) DeregisterInstance(i *cloudinstances.CloudInsta
 	return nil
 }
 
-// DetachInstance is not implemented yet. It needs to cause a cloud instance to no longer be counted against the group's size limits.
+// DetachInstance causes a cloud instance to no longer be counted against the group's size limits,
+// by labeling it and resizing its MIG so that the MIG creates a replacement.
 func (c *gceCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
-	klog.V(8).Info("gce cloud provider DetachInstance not implemented yet")
-	return fmt.Errorf("gce cloud provider does not support surging")
+	return detachCloudInstance(c, i)
+}
+
+func detachCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
+	if i.Status == cloudinstances.CloudInstanceStatusDetached {
+		return nil
+	}
+
+	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
+	migURL, err := ParseGoogleCloudURL(mig.SelfLink)
+	if err != nil {
+		return err
+	}
+	instanceURL, err := ParseGoogleCloudURL(i.ID)
+	if err != nil {
+		return err
+	}
+
+	instance, err := c.Compute().Instances().Get(instanceURL.Project, instanceURL.Zone, instanceURL.Name)
+	if err != nil {
+		return fmt.Errorf("error getting Instance %s: %v", i.ID, err)
+	}
+	labels := make(map[string]string)
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	labels[GceLabelNameDetachedInstance] = migURL.Name
+	op, err := c.Compute().Instances().SetLabels(instanceURL.Project, instanceURL.Zone, instanceURL.Name, &compute.InstancesSetLabelsRequest{
+		Labels:           labels,
+		LabelFingerprint: instance.LabelFingerprint,
+	})
+	if err != nil {
+		return fmt.Errorf("error labeling Instance %s: %v", i.ID, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return err
+	}
+
+	// The target size may have changed since the MIG was listed
+	current, err := c.Compute().InstanceGroupManagers().Get(migURL.Project, migURL.Zone, migURL.Name)
+	if err != nil {
+		return fmt.Errorf("error getting MIG %s: %v", migURL.Name, err)
+	}
+	klog.V(2).Infof("Resizing MIG %s to %d to replace GCE Instance %s", migURL.Name, current.TargetSize+1, i.ID)
+	op, err = c.Compute().InstanceGroupManagers().Resize(migURL.Project, migURL.Zone, migURL.Name, current.TargetSize+1)
+	if err != nil {
+		return fmt.Errorf("error resizing MIG %s: %v", migURL.Name, err)
+	}
+	return c.WaitForOp(op)
+}
+
+// deleteDetachedCloudInstance deletes a detached instance, shrinking its MIG back to its size before the instance was detached
+func deleteDetachedCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
+	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
+
+	klog.V(2).Infof("Deleting detached GCE Instance %s in MIG %s", i.ID, mig.Name)
+
+	migURL, err := ParseGoogleCloudURL(mig.SelfLink)
+	if err != nil {
+		return err
+	}
+
+	op, err := c.Compute().InstanceGroupManagers().DeleteInstances(migURL.Project, migURL.Zone, migURL.Name, i.ID)
+	if err != nil {
+		if IsNotFound(err) {
+			klog.Infof("Instance not found, assuming deleted: %q", i.ID)
+			return nil
+		}
+		return fmt.Errorf("error deleting Instance %s: %v", i.ID, err)
+	}
+
+	return c.WaitForOp(op)
 }
 
 // recreateCloudInstance recreates the specified instances, managed by an InstanceGroupManager
@@ -182,6 +257,9 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					CloudInstanceGroup: g,
 				}
 				addCloudInstanceData(cm, instance)
+				if _, found := instance.Labels[GceLabelNameDetachedInstance]; found {
+					cm.Status = cloudinstances.CloudInstanceStatusDetached
+				}
 
 				// Try first by provider ID
 				providerID := "gce://" + project + "/" + zoneName + "/" + name
@@ -266,6 +344,14 @@ func matchInstanceGroup(mig *compute.InstanceGroupManager, c *kops.Cluster, inst
 
 func addCloudInstanceData(cm *cloudinstances.CloudInstance, instance *compute.Instance) {
 	cm.MachineType = LastComponent(instance.MachineType)
+	if instance.Scheduling != nil && (instance.Scheduling.Preemptible || instance.Scheduling.ProvisioningModel == "SPOT") {
+		cm.Lifecycle = cloudinstances.LifecycleSpot
+	} else {
+		cm.Lifecycle = cloudinstances.LifecycleOnDemand
+	}
+	if t, err := time.Parse(time.RFC3339, instance.CreationTimestamp); err == nil {
+		cm.LaunchTime = t
+	}
 	cm.Status = instance.Status
 	if instance.Status == "RUNNING" {
 		cm.State = cloudinstances.CloudInstanceStatusUpToDate
@@ -37,11 +37,12 @@ func (l *Loader) Init() {
 	l.tasks = make(map[string]fi.CloudupTask)
 }
 
-func (l *Loader) BuildTasks(ctx context.Context, lifecycleOverrides map[string]fi.Lifecycle) (map[string]fi.CloudupTask, error) {
+func (l *Loader) BuildTasks(ctx context.Context, lifecycleOverrides map[string]fi.Lifecycle, lifecycleOverrideRules []fi.LifecycleOverrideRule) (map[string]fi.CloudupTask, error) {
 	for _, builder := range l.Builders {
 		context := &fi.CloudupModelBuilderContext{
-			Tasks:              l.tasks,
-			LifecycleOverrides: lifecycleOverrides,
+			Tasks:                  l.tasks,
+			LifecycleOverrides:     lifecycleOverrides,
+			LifecycleOverrideRules: lifecycleOverrideRules,
 		}
 		context = context.WithContext(ctx)
 		err := builder.Build(context)
@@ -115,12 +116,13 @@ func (l *Loader) processDeferrals() error {
 	return nil
 }
 
-func (l *Loader) FindDeletions(cloud fi.Cloud, lifecycleOverrides map[string]fi.Lifecycle) (map[string]fi.CloudupTask, error) {
+func (l *Loader) FindDeletions(cloud fi.Cloud, lifecycleOverrides map[string]fi.Lifecycle, lifecycleOverrideRules []fi.LifecycleOverrideRule) (map[string]fi.CloudupTask, error) {
 	for _, builder := range l.Builders {
 		if hasDeletions, ok := builder.(fi.HasDeletions); ok {
 			context := &fi.CloudupModelBuilderContext{
-				Tasks:              l.tasks,
-				LifecycleOverrides: lifecycleOverrides,
+				Tasks:                  l.tasks,
+				LifecycleOverrides:     lifecycleOverrides,
+				LifecycleOverrideRules: lifecycleOverrideRules,
 			}
 			if err := hasDeletions.FindDeletions(context, cloud); err != nil {
 				return nil, err
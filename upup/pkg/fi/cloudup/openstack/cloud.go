@@ -27,6 +27,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	cindersnapshots "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	cinder "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	az "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
@@ -138,6 +139,12 @@ type OpenstackCloud interface {
 	// DeleteVolume will delete volume
 	DeleteVolume(volumeID string) error
 
+	// ListSnapshots will return the Cinder snapshots which match the options
+	ListSnapshots(opt cindersnapshots.ListOptsBuilder) ([]cindersnapshots.Snapshot, error)
+
+	// CreateSnapshot will create a new Cinder snapshot
+	CreateSnapshot(opt cindersnapshots.CreateOptsBuilder) (*cindersnapshots.Snapshot, error)
+
 	// ListSecurityGroups will return the Neutron security groups which match the options
 	ListSecurityGroups(opt sg.ListOpts) ([]sg.SecGroup, error)
 
@@ -22,6 +22,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 
 	"github.com/gophercloud/gophercloud"
+	cindersnapshots "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	cinder "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	az "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
@@ -237,6 +238,10 @@ func (c *MockCloud) CreateVolume(opt cinder.CreateOptsBuilder) (*cinder.Volume,
 	return createVolume(c, opt)
 }
 
+func (c *MockCloud) CreateSnapshot(opt cindersnapshots.CreateOptsBuilder) (*cindersnapshots.Snapshot, error) {
+	return createSnapshot(c, opt)
+}
+
 func (c *MockCloud) DefaultInstanceType(cluster *kops.Cluster, ig *kops.InstanceGroup) (string, error) {
 	return defaultInstanceType(c, cluster, ig)
 }
@@ -485,6 +490,10 @@ func (c *MockCloud) ListVolumes(opt cinder.ListOptsBuilder) ([]cinder.Volume, er
 	return listVolumes(c, opt)
 }
 
+func (c *MockCloud) ListSnapshots(opt cindersnapshots.ListOptsBuilder) ([]cindersnapshots.Snapshot, error) {
+	return listSnapshots(c, opt)
+}
+
 func (c *MockCloud) SetExternalNetwork(name *string) {
 	c.extNetworkName = name
 }
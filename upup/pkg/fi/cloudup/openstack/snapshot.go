@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"fmt"
+
+	cindersnapshots "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func (c *openstackCloud) ListSnapshots(opt cindersnapshots.ListOptsBuilder) ([]cindersnapshots.Snapshot, error) {
+	return listSnapshots(c, opt)
+}
+
+func listSnapshots(c OpenstackCloud, opt cindersnapshots.ListOptsBuilder) ([]cindersnapshots.Snapshot, error) {
+	var snapshots []cindersnapshots.Snapshot
+
+	done, err := vfs.RetryWithBackoff(readBackoff, func() (bool, error) {
+		allPages, err := cindersnapshots.List(c.BlockStorageClient(), opt).AllPages()
+		if err != nil {
+			return false, fmt.Errorf("error listing snapshots %v: %v", opt, err)
+		}
+
+		s, err := cindersnapshots.ExtractSnapshots(allPages)
+		if err != nil {
+			return false, fmt.Errorf("error extracting snapshots from pages: %v", err)
+		}
+		snapshots = s
+		return true, nil
+	})
+	if err != nil {
+		return snapshots, err
+	} else if done {
+		return snapshots, nil
+	} else {
+		return snapshots, wait.ErrWaitTimeout
+	}
+}
+
+func (c *openstackCloud) CreateSnapshot(opt cindersnapshots.CreateOptsBuilder) (*cindersnapshots.Snapshot, error) {
+	return createSnapshot(c, opt)
+}
+
+func createSnapshot(c OpenstackCloud, opt cindersnapshots.CreateOptsBuilder) (*cindersnapshots.Snapshot, error) {
+	var snapshot *cindersnapshots.Snapshot
+
+	done, err := vfs.RetryWithBackoff(writeBackoff, func() (bool, error) {
+		s, err := cindersnapshots.Create(c.BlockStorageClient(), opt).Extract()
+		if err != nil {
+			return false, fmt.Errorf("error creating snapshot %v: %v", opt, err)
+		}
+		snapshot = s
+		return true, nil
+	})
+	if err != nil {
+		return snapshot, err
+	} else if done {
+		return snapshot, nil
+	} else {
+		return snapshot, wait.ErrWaitTimeout
+	}
+}
@@ -235,6 +235,43 @@ func TestPopulateInstanceGroup_AddTaints(t *testing.T) {
 	}
 }
 
+func TestPopulateInstanceGroup_Accelerators(t *testing.T) {
+	_, cluster := buildMinimalCluster()
+	input := buildMinimalNodeInstanceGroup()
+	input.Spec.MachineType = "inf2.xlarge"
+	input.Spec.Accelerators = []kopsapi.AcceleratorSpec{
+		{Vendor: kopsapi.AcceleratorVendorAWSNeuron},
+	}
+
+	channel := &kopsapi.Channel{}
+
+	cloud, err := BuildCloud(cluster)
+	if err != nil {
+		t.Fatalf("error from BuildCloud: %v", err)
+	}
+	output, err := PopulateInstanceGroupSpec(cluster, input, cloud, channel)
+	if err != nil {
+		t.Fatalf("error from PopulateInstanceGroupSpec: %v", err)
+	}
+
+	accelerator := output.Spec.Accelerators[0]
+	if accelerator.Name != "neuron" {
+		t.Errorf("Expected default name %q, got %q", "neuron", accelerator.Name)
+	}
+	if accelerator.DevicePlugin == nil || accelerator.DevicePlugin.ResourceName != "aws.amazon.com/neuron" {
+		t.Errorf("Expected default device plugin, got %+v", accelerator.DevicePlugin)
+	}
+	if output.Spec.NodeLabels["accelerator.kops.k8s.io/neuron"] != "1" {
+		t.Errorf("Expected accelerator node label, got %v", output.Spec.NodeLabels)
+	}
+	if len(output.Spec.Taints) != 1 || output.Spec.Taints[0] != "aws.amazon.com/neuron:NoSchedule" {
+		t.Errorf("Expected accelerator taint, got %v", output.Spec.Taints)
+	}
+	if len(input.Spec.Accelerators[0].Packages) != 0 {
+		t.Errorf("Expected input to be unchanged, got %+v", input.Spec.Accelerators[0])
+	}
+}
+
 func expectErrorFromPopulateInstanceGroup(t *testing.T, cluster *kopsapi.Cluster, g *kopsapi.InstanceGroup, channel *kopsapi.Channel, message string) {
 	cloud, err := BuildCloud(cluster)
 	if err != nil {
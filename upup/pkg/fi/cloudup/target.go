@@ -17,7 +17,8 @@ limitations under the License.
 package cloudup
 
 const (
-	TargetDirect    = "direct"
-	TargetDryRun    = "dryrun"
-	TargetTerraform = "terraform"
+	TargetDirect         = "direct"
+	TargetDryRun         = "dryrun"
+	TargetTerraform      = "terraform"
+	TargetCloudformation = "cloudformation"
 )
@@ -119,6 +119,7 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	dest["GetCloudProvider"] = cluster.GetCloudProvider
 	dest["GetInstanceGroup"] = tf.GetInstanceGroup
 	dest["GetNodeInstanceGroups"] = tf.GetNodeInstanceGroups
+	dest["Accelerators"] = tf.Accelerators
 	dest["GetClusterAutoscalerNodeGroups"] = tf.GetClusterAutoscalerNodeGroups
 	dest["HasHighlyAvailableControlPlane"] = tf.HasHighlyAvailableControlPlane
 	dest["ControlPlaneControllerReplicas"] = tf.ControlPlaneControllerReplicas
@@ -930,6 +931,26 @@ func (tf *TemplateFunctions) OpenStackCSITag() string {
 	return tag
 }
 
+// Accelerators returns the accelerators of all the instance groups, sorted by name.
+// Instance groups share the device plugin of accelerators with the same name.
+func (tf *TemplateFunctions) Accelerators() []kops.AcceleratorSpec {
+	var accelerators []kops.AcceleratorSpec
+	names := sets.NewString()
+	for _, ig := range tf.KopsModelContext.InstanceGroups {
+		for _, accelerator := range ig.Spec.Accelerators {
+			if names.Has(accelerator.Name) {
+				continue
+			}
+			names.Insert(accelerator.Name)
+			accelerators = append(accelerators, accelerator)
+		}
+	}
+	sort.Slice(accelerators, func(i, j int) bool {
+		return accelerators[i].Name < accelerators[j].Name
+	})
+	return accelerators
+}
+
 // GetNodeInstanceGroups returns a map containing the defined instance groups of role "Node".
 func (tf *TemplateFunctions) GetNodeInstanceGroups() map[string]kops.InstanceGroupSpec {
 	nodegroups := make(map[string]kops.InstanceGroupSpec)
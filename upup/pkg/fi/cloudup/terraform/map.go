@@ -123,3 +123,59 @@ func quote(s string) string {
 	b.WriteRune('"')
 	return b.String()
 }
+
+// mergedMap is a map merged on top of a map-valued expression, so that the map's own keys take precedence.
+// Example:
+//
+//	key = merge(var.tags, {
+//	  "key1" = "value1"
+//	})
+type mergedMap struct {
+	base    *terraformWriter.Literal
+	members *mapStringLiteral
+}
+
+func (m *mergedMap) IsSingleValue() bool {
+	return false
+}
+
+func (m *mergedMap) Write(buffer *bytes.Buffer, indent int, key string) {
+	if len(m.members.members) == 0 {
+		writeIndent(buffer, indent)
+		buffer.WriteString(key)
+		buffer.WriteString(" = ")
+		buffer.WriteString(m.base.String)
+		buffer.WriteString("\n")
+		return
+	}
+	var b bytes.Buffer
+	m.members.Write(&b, indent, key)
+	s := strings.TrimSuffix(b.String(), "}\n")
+	s = strings.Replace(s, " = {\n", " = merge("+m.base.String+", {\n", 1)
+	buffer.WriteString(s)
+	buffer.WriteString("})\n")
+}
+
+// mergeTags replaces the tags maps of e and its nested blocks with the tags merged on top of base,
+// returning whether any were found.
+func mergeTags(e element, base *terraformWriter.Literal) bool {
+	found := false
+	switch e := e.(type) {
+	case *object:
+		for k, field := range e.field {
+			if members, ok := field.(*mapStringLiteral); ok && k == "tags" {
+				e.field[k] = &mergedMap{base: base, members: members}
+				found = true
+			} else if mergeTags(field, base) {
+				found = true
+			}
+		}
+	case *sliceObject:
+		for _, member := range e.members {
+			if mergeTags(member, base) {
+				found = true
+			}
+		}
+	}
+	return found
+}
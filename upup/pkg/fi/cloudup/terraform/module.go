@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// clusterNameVariable is the module variable holding the name of the cluster.
+const clusterNameVariable = "cluster_name"
+
+var (
+	quotedStringRegexp = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	fileLiteralRegexp  = regexp.MustCompile(`^(file|filebase64)\("\$\{path\.module\}/(data/[^"]+)"\)$`)
+)
+
+// clusterNameRewriter replaces the cluster name in the elements of a module with references to the cluster_name variable,
+// so that the module can be instantiated for clusters with other names.
+// The cluster name is also replaced in the contents of the data files, when they are read by terraform,
+// and the hashes of those contents recorded in other data files are recomputed by terraform.
+type clusterNameRewriter struct {
+	clusterName string
+	files       map[string][]byte
+
+	// hashes maps the hashes of the data files containing the cluster name to their paths,
+	// both as base64 (as nodeup verifies its config) and hex (as channels records manifests).
+	hashes map[string]string
+	// contents memoizes the expressions of the rewritten data file contents, by path.
+	contents map[string]string
+}
+
+// addClusterNameVariable declares the module variable holding the name of the cluster,
+// returning the rewriter that references it, or nil if the cluster name should be left as is.
+func (t *TerraformTarget) addClusterNameVariable() (*clusterNameRewriter, error) {
+	if !t.Module || t.ClusterName == "" {
+		return nil, nil
+	}
+	if _, err := t.AddVariable(clusterNameVariable, &terraformWriter.Variable{
+		Type:        terraformWriter.LiteralTokens("string"),
+		Description: fi.PtrTo("Name of the cluster"),
+		Default:     terraformWriter.LiteralFromStringValue(t.ClusterName),
+	}); err != nil {
+		return nil, err
+	}
+
+	r := &clusterNameRewriter{
+		clusterName: t.ClusterName,
+		files:       t.Files,
+		hashes:      make(map[string]string),
+		contents:    make(map[string]string),
+	}
+	for p, data := range t.Files {
+		if !strings.HasPrefix(p, "data/") || !strings.Contains(string(data), t.ClusterName) {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		r.hashes[base64.StdEncoding.EncodeToString(sum[:])] = p
+		r.hashes[hex.EncodeToString(sum[:])] = p
+	}
+	return r, nil
+}
+
+// element returns the element with the cluster name replaced, leaving e itself unchanged.
+func (r *clusterNameRewriter) element(e element) element {
+	if r == nil {
+		return e
+	}
+	switch e := e.(type) {
+	case *object:
+		o := &object{field: make(map[string]element, len(e.field))}
+		for k, field := range e.field {
+			o.field[k] = r.element(field)
+		}
+		return o
+	case *sliceObject:
+		s := &sliceObject{members: make([]element, len(e.members))}
+		for i, member := range e.members {
+			s.members[i] = r.element(member)
+		}
+		return s
+	case *mapStringLiteral:
+		return r.mapStringLiteral(e)
+	case *mergedMap:
+		return &mergedMap{base: e.base, members: r.mapStringLiteral(e.members)}
+	case *terraformWriter.Literal:
+		return r.literal(e)
+	default:
+		return e
+	}
+}
+
+func (r *clusterNameRewriter) mapStringLiteral(m *mapStringLiteral) *mapStringLiteral {
+	o := &mapStringLiteral{members: make(map[string]*terraformWriter.Literal, len(m.members))}
+	for k, v := range m.members {
+		o.members[strings.ReplaceAll(k, r.clusterName, "${var."+clusterNameVariable+"}")] = r.literal(v)
+	}
+	return o
+}
+
+// literal returns the literal with the cluster name replaced in its strings, and in the contents of the data file it reads.
+func (r *clusterNameRewriter) literal(l *terraformWriter.Literal) *terraformWriter.Literal {
+	if r == nil || l == nil {
+		return l
+	}
+	if match := fileLiteralRegexp.FindStringSubmatch(l.String); match != nil {
+		content := r.fileContent(match[2])
+		if content == "" {
+			return l
+		}
+		if match[1] == "filebase64" {
+			content = "base64encode(" + content + ")"
+		}
+		return &terraformWriter.Literal{String: content}
+	}
+	s := quotedStringRegexp.ReplaceAllStringFunc(l.String, func(quoted string) string {
+		// The paths of the data files are named after the cluster that generated the module
+		if strings.HasPrefix(quoted, `"${path.module}/`) {
+			return quoted
+		}
+		if quoted == `"`+r.clusterName+`"` {
+			return "var." + clusterNameVariable
+		}
+		return strings.ReplaceAll(quoted, r.clusterName, "${var."+clusterNameVariable+"}")
+	})
+	if s == l.String {
+		return l
+	}
+	return &terraformWriter.Literal{String: s}
+}
+
+// fileContent returns the expression for the contents of the data file with the cluster name replaced,
+// or the empty string if they don't need rewriting.
+func (r *clusterNameRewriter) fileContent(p string) string {
+	if content, found := r.contents[p]; found {
+		return content
+	}
+	// Guard against files recording their own hashes
+	r.contents[p] = ""
+
+	data := string(r.files[p])
+	content := fmt.Sprintf("file(%q)", "${path.module}/"+p)
+	changed := false
+
+	hashes := make([]string, 0, len(r.hashes))
+	for hash := range r.hashes {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		if !strings.Contains(data, hash) {
+			continue
+		}
+		hashed := r.fileContent(r.hashes[hash])
+		if hashed == "" {
+			continue
+		}
+		fn := "base64sha256"
+		if len(hash) == hex.EncodedLen(sha256.Size) {
+			fn = "sha256"
+		}
+		content = fmt.Sprintf("replace(%s, %q, %s(%s))", content, hash, fn, hashed)
+		changed = true
+	}
+	if strings.Contains(data, r.clusterName) {
+		content = fmt.Sprintf("replace(%s, %q, var.%s)", content, r.clusterName, clusterNameVariable)
+		changed = true
+	}
+
+	if !changed {
+		content = ""
+	}
+	r.contents[p] = content
+	return content
+}
+
+// outputs returns the output values with the cluster name replaced.
+func (r *clusterNameRewriter) outputs(outputs map[string]terraformWriter.OutputValue) map[string]terraformWriter.OutputValue {
+	if r == nil {
+		return outputs
+	}
+	rewritten := make(map[string]terraformWriter.OutputValue, len(outputs))
+	for k, v := range outputs {
+		value := terraformWriter.OutputValue{Value: r.literal(v.Value)}
+		for _, l := range v.ValueArray {
+			value.ValueArray = append(value.ValueArray, r.literal(l))
+		}
+		rewritten[k] = value
+	}
+	return rewritten
+}
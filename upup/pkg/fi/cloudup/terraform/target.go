@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -37,6 +38,21 @@ type TerraformTarget struct {
 	outDir string
 	// extra config to add to the provider block
 	clusterSpecTarget *kops.TargetSpec
+
+	// ImportExisting causes import blocks to be generated for resources that already exist,
+	// so that clusters previously managed directly can be adopted by terraform.
+	ImportExisting bool
+
+	// Module causes a reusable terraform module to be written instead of a root configuration.
+	// Provider blocks are left to the caller, and environment-specific values are exposed as variables.
+	Module bool
+}
+
+// Importable is implemented by tasks whose existing cloud resources can be imported into terraform state.
+type Importable interface {
+	// TerraformImports records the imports for the existing resource (as returned by Find)
+	// of the resources rendered for the task.
+	TerraformImports(t *TerraformTarget, actual fi.CloudupTask) error
 }
 
 func NewTerraformTarget(cloud fi.Cloud, project string, outDir string, clusterSpecTarget *kops.TargetSpec) *TerraformTarget {
@@ -67,6 +83,42 @@ func (t *TerraformTarget) DefaultCheckExisting() bool {
 	return false
 }
 
+var _ fi.RecordsExisting[fi.CloudupSubContext] = &TerraformTarget{}
+
+func (t *TerraformTarget) RecordsExisting() bool {
+	return t.ImportExisting
+}
+
+func (t *TerraformTarget) RecordExisting(e, a fi.CloudupTask) error {
+	importable, ok := e.(Importable)
+	if !ok {
+		return nil
+	}
+	return importable.TerraformImports(t, a)
+}
+
+// ModuleVariable returns a reference to the named module variable, declaring it with value as the default,
+// when writing a module. Otherwise value is returned unchanged.
+func (t *TerraformTarget) ModuleVariable(name string, varType string, description string, value *terraformWriter.Literal) (*terraformWriter.Literal, error) {
+	if !t.Module {
+		return value, nil
+	}
+	return t.AddVariable(name, &terraformWriter.Variable{
+		Type:        terraformWriter.LiteralTokens(varType),
+		Description: fi.PtrTo(description),
+		Default:     value,
+	})
+}
+
+// ResourceVariableName returns the name of the module variable for the given property of a resource,
+// dropping the cluster name from the resource name so the variable is the same for every environment.
+func (t *TerraformTarget) ResourceVariableName(resourceName string, property string) string {
+	if t.ClusterName != "" {
+		resourceName = strings.TrimSuffix(resourceName, "."+t.ClusterName)
+	}
+	return resourceName + "_" + property
+}
+
 // tfGetProviderExtraConfig is a helper function to get extra config with safety checks on the pointers.
 func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 	if c != nil &&
@@ -24,6 +24,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -35,23 +36,45 @@ func (t *TerraformTarget) finishHCL2() error {
 	if err != nil {
 		return err
 	}
-	writeLocalsOutputs(buf, outputs)
-
-	t.writeProviders(buf)
-
 	resourcesByType, err := t.GetResourcesByType()
 	if err != nil {
 		return err
 	}
 
-	t.writeResources(buf, resourcesByType)
+	rewriter, err := t.addClusterNameVariable()
+	if err != nil {
+		return err
+	}
+
+	// Resources are written first, as they may declare module variables.
+	resourcesBuf := &bytes.Buffer{}
+	if err := t.writeResources(resourcesBuf, resourcesByType, rewriter); err != nil {
+		return err
+	}
+
+	writeVariables(buf, t.GetVariables())
+
+	writeLocalsOutputs(buf, rewriter.outputs(outputs))
+
+	if !t.Module {
+		t.writeProviders(buf)
+	}
+
+	buf.Write(resourcesBuf.Bytes())
 
 	dataSourcesByType, err := t.GetDataSourcesByType()
 	if err != nil {
 		return err
 	}
 
-	t.writeDataSources(buf, dataSourcesByType)
+	t.writeDataSources(buf, dataSourcesByType, rewriter)
+
+	imports, err := t.GetImports()
+	if err != nil {
+		return err
+	}
+
+	writeImports(buf, imports)
 
 	t.writeTerraform(buf)
 
@@ -165,7 +188,22 @@ func sortedKeysForMap[K ~string, V any](m map[K]V) []K {
 	return keys
 }
 
-func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[string]map[string]interface{}) {
+// writeVariables creates a variable block for each input variable of a module
+// Example:
+//
+//	variable "nodes_max_size" {
+//	  default     = 2
+//	  description = "Maximum size of the nodes autoscaling group"
+//	  type        = number
+//	}
+func writeVariables(buf *bytes.Buffer, variables map[string]*terraformWriter.Variable) {
+	for _, name := range sortedKeysForMap(variables) {
+		toElement(variables[name]).Write(buf, 0, fmt.Sprintf("variable %q", name))
+		buf.WriteString("\n")
+	}
+}
+
+func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[string]map[string]interface{}, rewriter *clusterNameRewriter) error {
 	resourceTypes := make([]string, 0, len(resourcesByType))
 	for resourceType := range resourcesByType {
 		resourceTypes = append(resourceTypes, resourceType)
@@ -179,14 +217,30 @@ func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[
 		}
 		sort.Strings(resourceNames)
 		for _, resourceName := range resourceNames {
-			toElement(resources[resourceName]).
-				Write(buf, 0, fmt.Sprintf("resource %q %q", resourceType, resourceName))
+			e := toElement(resources[resourceName])
+			if t.Module && mergeTags(e, terraformWriter.LiteralTokens("var", "tags")) {
+				if err := t.addTagsVariable(); err != nil {
+					return err
+				}
+			}
+			rewriter.element(e).Write(buf, 0, fmt.Sprintf("resource %q %q", resourceType, resourceName))
 			buf.WriteString("\n")
 		}
 	}
+	return nil
 }
 
-func (t *TerraformTarget) writeDataSources(buf *bytes.Buffer, dataSourcesByType map[string]map[string]interface{}) {
+// addTagsVariable declares the module variable holding the tags added to all resources.
+func (t *TerraformTarget) addTagsVariable() error {
+	_, err := t.AddVariable("tags", &terraformWriter.Variable{
+		Type:        terraformWriter.LiteralTokens("map(string)"),
+		Description: fi.PtrTo("Additional tags to add to all resources"),
+		Default:     terraformWriter.LiteralTokens("{}"),
+	})
+	return err
+}
+
+func (t *TerraformTarget) writeDataSources(buf *bytes.Buffer, dataSourcesByType map[string]map[string]interface{}, rewriter *clusterNameRewriter) {
 	dataSourceTypes := make([]string, 0, len(dataSourcesByType))
 	for dataSourceType := range dataSourcesByType {
 		dataSourceTypes = append(dataSourceTypes, dataSourceType)
@@ -200,13 +254,27 @@ func (t *TerraformTarget) writeDataSources(buf *bytes.Buffer, dataSourcesByType
 		}
 		sort.Strings(dataSourceNames)
 		for _, dataSourceName := range dataSourceNames {
-			toElement(dataSources[dataSourceName]).
+			rewriter.element(toElement(dataSources[dataSourceName])).
 				Write(buf, 0, fmt.Sprintf("data %q %q", dataSourceType, dataSourceName))
 			buf.WriteString("\n")
 		}
 	}
 }
 
+// writeImports creates an import block for each existing resource to adopt
+// Example:
+//
+//	import {
+//	  id = "vpc-12345678"
+//	  to = aws_vpc.example-com
+//	}
+func writeImports(buf *bytes.Buffer, imports []*terraformWriter.Import) {
+	for _, imp := range imports {
+		toElement(imp).Write(buf, 0, "import")
+		buf.WriteString("\n")
+	}
+}
+
 func (t *TerraformTarget) writeTerraform(buf *bytes.Buffer) {
 	buf.WriteString("terraform {\n")
 	buf.WriteString("  required_version = \">= 0.15.0\"\n")
@@ -18,6 +18,8 @@ package terraform
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"strings"
 	"testing"
 
@@ -85,3 +87,188 @@ output "key1" {
 		})
 	}
 }
+
+func TestWriteImports(t *testing.T) {
+	writer := &terraformWriter.TerraformWriter{}
+	writer.InitTerraformWriter()
+	if err := writer.RenderResource("aws_vpc", "example.com", struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.RenderResource("aws_subnet", "us-test-1a.example.com", struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writer.AddImport("aws_vpc", "example.com", "vpc-12345678")
+	writer.AddImport("aws_subnet", "us-test-1a.example.com", "subnet-12345678")
+	// Not rendered, so should not be imported
+	writer.AddImport("aws_internet_gateway", "example.com", "igw-12345678")
+
+	imports, err := writer.GetImports()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	writeImports(buf, imports)
+	actual := strings.TrimSpace(buf.String())
+	expected := strings.TrimSpace(`
+import {
+  id = "subnet-12345678"
+  to = aws_subnet.us-test-1a-example-com
+}
+
+import {
+  id = "vpc-12345678"
+  to = aws_vpc.example-com
+}`)
+	if actual != expected {
+		t.Logf("diff:\n%s\n", diff.FormatDiff(expected, actual))
+		t.Errorf("unexpected output")
+	}
+
+	writer.AddImport("aws_vpc", "example.com", "vpc-87654321")
+	if _, err := writer.GetImports(); err == nil {
+		t.Errorf("expected error for conflicting imports")
+	}
+}
+
+func TestWriteResourcesModuleTags(t *testing.T) {
+	type tagSpecification struct {
+		ResourceType string            `cty:"resource_type"`
+		Tags         map[string]string `cty:"tags"`
+	}
+	type resource struct {
+		Name              string              `cty:"name"`
+		TagSpecifications []*tagSpecification `cty:"tag_specifications"`
+		Tags              map[string]string   `cty:"tags"`
+	}
+
+	target := &TerraformTarget{Module: true}
+	target.InitTerraformWriter()
+	resourcesByType := map[string]map[string]interface{}{
+		"aws_launch_template": {
+			"nodes-example-com": &resource{
+				Name: "nodes.example.com",
+				TagSpecifications: []*tagSpecification{
+					{ResourceType: "instance", Tags: map[string]string{"Name": "nodes.example.com"}},
+				},
+				Tags: map[string]string{"Name": "nodes.example.com"},
+			},
+		},
+		"aws_vpc": {
+			"example-com": &resource{Name: "example.com"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := target.writeResources(buf, resourcesByType, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeVariables(buf, target.GetVariables())
+	actual := strings.TrimSpace(buf.String())
+	expected := strings.TrimSpace(`
+resource "aws_launch_template" "nodes-example-com" {
+  name = "nodes.example.com"
+  tag_specifications {
+    resource_type = "instance"
+    tags = merge(var.tags, {
+      "Name" = "nodes.example.com"
+    })
+  }
+  tags = merge(var.tags, {
+    "Name" = "nodes.example.com"
+  })
+}
+
+resource "aws_vpc" "example-com" {
+  name = "example.com"
+  tags = var.tags
+}
+
+variable "tags" {
+  default     = {}
+  description = "Additional tags to add to all resources"
+  type        = map(string)
+}`)
+	if actual != expected {
+		t.Logf("diff:\n%s\n", diff.FormatDiff(expected, actual))
+		t.Errorf("unexpected output")
+	}
+}
+
+func TestWriteResourcesModuleClusterName(t *testing.T) {
+	type resource struct {
+		Name     string                   `cty:"name"`
+		Content  *terraformWriter.Literal `cty:"content"`
+		UserData *terraformWriter.Literal `cty:"user_data"`
+		Tags     map[string]string        `cty:"tags"`
+	}
+
+	target := &TerraformTarget{Module: true, ClusterName: "example.com"}
+	target.InitTerraformWriter()
+	config := []byte("ClusterName: example.com\n")
+	sum := sha256.Sum256(config)
+	content, err := target.AddFileBytes("aws_s3_object", "nodeupconfig", "content", config, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userData, err := target.AddFileBytes("aws_launch_template", "nodes.example.com", "user_data", []byte("NodeupConfigHash: "+base64.StdEncoding.EncodeToString(sum[:])+"\n"), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resourcesByType := map[string]map[string]interface{}{
+		"aws_launch_template": {
+			"nodes-example-com": &resource{
+				Name:     "nodes.example.com",
+				UserData: userData,
+				Tags:     map[string]string{"kubernetes.io/cluster/example.com": "owned"},
+			},
+		},
+		"aws_s3_object": {
+			"nodeupconfig": &resource{
+				Name:    "example.com/igconfig/nodes/nodeupconfig.yaml",
+				Content: content,
+			},
+		},
+	}
+
+	rewriter, err := target.addClusterNameVariable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := target.writeResources(buf, resourcesByType, rewriter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeVariables(buf, target.GetVariables())
+	actual := strings.TrimSpace(buf.String())
+	expected := strings.TrimSpace(`
+resource "aws_launch_template" "nodes-example-com" {
+  name = "nodes.${var.cluster_name}"
+  tags = merge(var.tags, {
+    "kubernetes.io/cluster/${var.cluster_name}" = "owned"
+  })
+  user_data = base64encode(replace(file("${path.module}/data/aws_launch_template_nodes.example.com_user_data"), "` + base64.StdEncoding.EncodeToString(sum[:]) + `", base64sha256(replace(file("${path.module}/data/aws_s3_object_nodeupconfig_content"), "example.com", var.cluster_name))))
+}
+
+resource "aws_s3_object" "nodeupconfig" {
+  content = replace(file("${path.module}/data/aws_s3_object_nodeupconfig_content"), "example.com", var.cluster_name)
+  name    = "${var.cluster_name}/igconfig/nodes/nodeupconfig.yaml"
+  tags = var.tags
+}
+
+variable "cluster_name" {
+  default     = "example.com"
+  description = "Name of the cluster"
+  type        = string
+}
+
+variable "tags" {
+  default     = {}
+  description = "Additional tags to add to all resources"
+  type        = map(string)
+}`)
+	if actual != expected {
+		t.Logf("diff:\n%s\n", diff.FormatDiff(expected, actual))
+		t.Errorf("unexpected output")
+	}
+}
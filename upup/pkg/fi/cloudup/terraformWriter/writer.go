@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,6 +37,10 @@ type TerraformWriter struct {
 	resources []*terraformResource
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
+	// imports is a list of existing cloud resources that should be imported into TF state
+	imports []*terraformImport
+	// variables is a map of our TF input variables, used when writing a module
+	variables map[string]*Variable
 
 	// Providers is a list of TF Providers we need for writing files
 	Providers map[string]*TerraformProvider
@@ -61,6 +66,25 @@ type terraformResource struct {
 	Item         interface{}
 }
 
+type terraformImport struct {
+	ResourceType string
+	ResourceName string
+	ID           string
+}
+
+// Import is an import block, adopting an existing cloud resource into TF state.
+type Import struct {
+	To *Literal `cty:"to"`
+	ID string   `cty:"id"`
+}
+
+// Variable is a variable block, declaring an input of a TF module.
+type Variable struct {
+	Type        *Literal `cty:"type"`
+	Description *string  `cty:"description"`
+	Default     *Literal `cty:"default"`
+}
+
 type terraformOutputVariable struct {
 	Key        string
 	Value      *Literal
@@ -79,6 +103,7 @@ func sanitizeName(name string) string {
 func (t *TerraformWriter) InitTerraformWriter() {
 	t.Files = make(map[string][]byte)
 	t.outputs = make(map[string]*terraformOutputVariable)
+	t.variables = make(map[string]*Variable)
 }
 
 func (t *TerraformWriter) AddFileBytes(resourceType string, resourceName string, key string, data []byte, base64 bool) (*Literal, error) {
@@ -165,6 +190,46 @@ func (t *TerraformWriter) RenderResource(resourceType string, resourceName strin
 	return nil
 }
 
+// RenderSharedResource is like RenderResource, but for resources that several tasks render identically.
+// The resource is only rendered once; rendering a different resource with the same type and name is an error.
+func (t *TerraformWriter) RenderSharedResource(resourceType string, resourceName string, e interface{}) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, res := range t.resources {
+		if res.ResourceType != resourceType || res.ResourceName != resourceName {
+			continue
+		}
+		if !reflect.DeepEqual(res.Item, e) {
+			return fmt.Errorf("conflicting definitions for shared resource %s.%s", resourceType, sanitizeName(resourceName))
+		}
+		return nil
+	}
+
+	t.resources = append(t.resources, &terraformResource{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Item:         e,
+	})
+
+	return nil
+}
+
+// AddImport records that the resource with the given type and name already exists in the cloud with the given ID,
+// and should be imported into TF state rather than created.
+func (t *TerraformWriter) AddImport(resourceType string, resourceName string, id string) {
+	imp := &terraformImport{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		ID:           id,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.imports = append(t.imports, imp)
+}
+
 func (t *TerraformWriter) AddOutputVariable(key string, literal *Literal) error {
 	v := &terraformOutputVariable{
 		Key:   key,
@@ -182,6 +247,25 @@ func (t *TerraformWriter) AddOutputVariable(key string, literal *Literal) error
 	return nil
 }
 
+// AddVariable declares an input variable of the TF module, returning the reference to it.
+// Declaring the same variable more than once is allowed, as long as the declarations match.
+func (t *TerraformWriter) AddVariable(name string, v *Variable) (*Literal, error) {
+	tfName := sanitizeName(name)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if existing := t.variables[tfName]; existing != nil {
+		if !reflect.DeepEqual(existing, v) {
+			return nil, fmt.Errorf("conflicting declarations for variable %q", tfName)
+		}
+	} else {
+		t.variables[tfName] = v
+	}
+
+	return LiteralTokens("var", tfName), nil
+}
+
 func (t *TerraformWriter) AddOutputVariableArray(key string, literal *Literal) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -263,3 +347,54 @@ func (t *TerraformWriter) GetOutputs() (map[string]OutputValue, error) {
 	}
 	return values, nil
 }
+
+// GetVariables returns the declared input variables, keyed by name.
+func (t *TerraformWriter) GetVariables() map[string]*Variable {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	variables := make(map[string]*Variable, len(t.variables))
+	for k, v := range t.variables {
+		variables[k] = v
+	}
+	return variables
+}
+
+// GetImports returns the import blocks for the recorded imports, sorted by address.
+// Imports of resources that were not rendered are skipped, as TF rejects them.
+func (t *TerraformWriter) GetImports() ([]*Import, error) {
+	resourcesByType, err := t.GetResourcesByType()
+	if err != nil {
+		return nil, err
+	}
+
+	importsByAddress := make(map[string]*Import)
+	for _, imp := range t.imports {
+		tfName := sanitizeName(imp.ResourceName)
+		if _, found := resourcesByType[imp.ResourceType][tfName]; !found {
+			klog.V(2).Infof("not importing %s.%s (%s) as it is not managed by terraform", imp.ResourceType, tfName, imp.ID)
+			continue
+		}
+
+		address := imp.ResourceType + "." + tfName
+		if existing := importsByAddress[address]; existing != nil {
+			if existing.ID != imp.ID {
+				return nil, fmt.Errorf("conflicting imports for %s: %q and %q", address, existing.ID, imp.ID)
+			}
+			continue
+		}
+		importsByAddress[address] = &Import{
+			To: LiteralTokens(imp.ResourceType, tfName),
+			ID: imp.ID,
+		}
+	}
+
+	var imports []*Import
+	for _, imp := range importsByAddress {
+		imports = append(imports, imp)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].To.String < imports[j].To.String
+	})
+	return imports, nil
+}
@@ -78,6 +78,23 @@ func defaultDeltaRunMethod[T SubContext](e Task[T], c *Context[T]) error {
 		}
 	}
 
+	if !checkExisting {
+		if re, ok := c.Target.(RecordsExisting[T]); ok && re.RecordsExisting() {
+			existing, err := invokeFind(e, c)
+			if err != nil {
+				if lifecycle == LifecycleWarnIfInsufficientAccess {
+					c.AddWarning(e, fmt.Sprintf("error checking if task exists; not recording existing resource: %v", err))
+				} else {
+					return err
+				}
+			} else if existing != nil {
+				if err := re.RecordExisting(e, existing); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	if a == nil {
 		// This is kind of subtle.  We want an interface pointer to a struct of the correct type...
 		a = reflect.New(reflect.TypeOf(e)).Elem().Interface().(Task[T])
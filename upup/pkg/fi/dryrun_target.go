@@ -448,6 +448,25 @@ func (t *DryRunTarget[T]) Changes() (map[string]Task[T], map[string]Task[T]) {
 	return creates, updates
 }
 
+// PendingDeletions returns the deletions which are going to be performed
+func (t *DryRunTarget[T]) PendingDeletions() []Deletion[T] {
+	return t.deletions
+}
+
+// Actuals returns the current state of the tasks which are going to be created or updated, keyed by task.
+// The state is nil for tasks which are going to be created.
+func (t *DryRunTarget[T]) Actuals() map[Task[T]]Task[T] {
+	actuals := make(map[Task[T]]Task[T])
+	for _, r := range t.changes {
+		if r.aIsNil {
+			actuals[r.e] = nil
+		} else {
+			actuals[r.e] = r.a
+		}
+	}
+	return actuals
+}
+
 // HasChanges returns true iff any changes would have been made
 func (t *DryRunTarget[T]) HasChanges() bool {
 	return len(t.changes)+len(t.deletions) != 0
@@ -16,7 +16,10 @@ limitations under the License.
 
 package fi
 
-import "k8s.io/apimachinery/pkg/util/sets"
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/pkg/apis/kops"
+)
 
 type Lifecycle string
 
@@ -64,3 +67,66 @@ var LifecycleNameMap = map[string]Lifecycle{
 	"ExistsAndValidates":       LifecycleExistsAndValidates,
 	"ExistsAndWarnIfChanges":   LifecycleExistsAndWarnIfChanges,
 }
+
+// lifecycleStrictness orders the lifecycles by how little they let a task change the cloud resources
+var lifecycleStrictness = map[Lifecycle]int{
+	LifecycleSync:                     0,
+	LifecycleWarnIfInsufficientAccess: 1,
+	LifecycleExistsAndWarnIfChanges:   2,
+	LifecycleExistsAndValidates:       3,
+	LifecycleIgnore:                   4,
+}
+
+// IsAtLeastAsStrictAs returns true if the lifecycle lets a task change no more than the other lifecycle does.
+// Tasks without a lifecycle are synced.
+func (l Lifecycle) IsAtLeastAsStrictAs(other Lifecycle) bool {
+	return lifecycleStrictness[l] >= lifecycleStrictness[other]
+}
+
+// LifecycleOverrideRule overrides the lifecycle of the tasks of a type,
+// optionally only of the task with the given name or cloud ID.
+type LifecycleOverrideRule struct {
+	// TaskType is the type name of the task, such as "SecurityGroup"
+	TaskType string
+	// Name, if set, is the name of the task
+	Name string
+	// ID, if set, is the cloud ID of the task, as returned by CompareWithID
+	ID string
+	// Lifecycle is the lifecycle to set on the matching tasks
+	Lifecycle Lifecycle
+}
+
+// Matches returns true if the rule applies to the task, whose type name is typeName.
+func (r *LifecycleOverrideRule) Matches(typeName string, task any) bool {
+	if r.TaskType != typeName {
+		return false
+	}
+	if r.Name != "" {
+		hasName, ok := task.(HasName)
+		if !ok || ValueOf(hasName.GetName()) != r.Name {
+			return false
+		}
+	}
+	if r.ID != "" {
+		compareWithID, ok := task.(CompareWithID)
+		if !ok || ValueOf(compareWithID.CompareWithID()) != r.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// LifecycleOverrideRulesFromSpec builds the rules for the lifecycle overrides of a cluster or instance group spec.
+// The specs are expected to have been validated.
+func LifecycleOverrideRulesFromSpec(specs []kops.LifecycleOverrideSpec) []LifecycleOverrideRule {
+	var rules []LifecycleOverrideRule
+	for _, spec := range specs {
+		rules = append(rules, LifecycleOverrideRule{
+			TaskType:  spec.Task,
+			Name:      spec.Name,
+			ID:        spec.ID,
+			Lifecycle: LifecycleNameMap[spec.Lifecycle],
+		})
+	}
+	return rules
+}
@@ -18,7 +18,6 @@ package nodeup
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -77,6 +76,9 @@ type NodeUpCommand struct {
 	CacheDir       string
 	ConfigLocation string
 	Target         string
+	// ContainerdRegistriesOnly only applies the containerd registry hosts configuration,
+	// which is what running nodes do periodically to pick up registry changes in place.
+	ContainerdRegistriesOnly bool
 }
 
 // Run is responsible for perform the nodeup process
@@ -137,7 +139,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		if err := utils.YamlUnmarshal([]byte(nodeConfig.NodeupConfig), &nodeupConfig); err != nil {
 			return fmt.Errorf("error parsing BootConfig config response: %v", err)
 		}
-		nodeupConfigHash = sha256.Sum256([]byte(nodeConfig.NodeupConfig))
+		nodeupConfigHash, err = nodeup.HashConfig(&nodeupConfig)
+		if err != nil {
+			return err
+		}
 		nodeupConfig.CAs[fi.CertificateIDCA] = bootConfig.ConfigServer.CACertificates
 	} else if bootConfig.InstanceGroupName != "" {
 		nodeupConfigLocation := configBase.Join("igconfig", bootConfig.InstanceGroupRole.ToLowerString(), bootConfig.InstanceGroupName, "nodeupconfig.yaml")
@@ -150,17 +155,29 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		if err = utils.YamlUnmarshal(b, &nodeupConfig); err != nil {
 			return fmt.Errorf("error parsing NodeupConfig %q: %v", nodeupConfigLocation, err)
 		}
-		nodeupConfigHash = sha256.Sum256(b)
+		nodeupConfigHash, err = nodeup.HashConfig(&nodeupConfig)
+		if err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("no instance group defined in nodeup config")
 	}
 
 	if bootConfig.NodeupConfigHash != "" {
 		if want, got := bootConfig.NodeupConfigHash, base64.StdEncoding.EncodeToString(nodeupConfigHash[:]); got != want {
+			if c.ContainerdRegistriesOnly {
+				// The registries are only applied in place when nothing else changed; otherwise the node has to be updated
+				klog.Warningf("nodeup config has changes other than the containerd registries (hash was %q, expected %q); skipping the in-place refresh of the registries until the node is updated", got, want)
+				return nil
+			}
 			return fmt.Errorf("nodeup config hash mismatch (was %q, expected %q)", got, want)
 		}
 	}
 
+	if c.ContainerdRegistriesOnly {
+		return c.runContainerdRegistriesOnly(ctx, &bootConfig, &nodeupConfig, nodeConfig, out)
+	}
+
 	err = evaluateSpec(&nodeupConfig, bootConfig.CloudProvider)
 	if err != nil {
 		return err
@@ -176,9 +193,23 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("error determining OS distribution: %v", err)
 	}
 
+	assetVerificationKey, err := trustedAssetVerificationKey(&bootConfig)
+	if err != nil {
+		return err
+	}
+	if assetVerificationKey == "" && nodeupConfig.AssetVerification != nil {
+		return fmt.Errorf("asset signatures are required, but there is no public key to verify them with in the boot config or in %s", nodeup.TrustedAssetVerificationKeyPath)
+	}
+
 	configAssets := nodeupConfig.Assets[architecture]
 	assetStore := fi.NewAssetStore(c.CacheDir)
 	for _, asset := range configAssets {
+		// Once there is a key to trust, every asset must be signed, whatever the nodeup config says
+		if assetVerificationKey != "" {
+			if err := verifyAssetSignature(assetVerificationKey, nodeupConfig.AssetVerification, asset); err != nil {
+				return fmt.Errorf("refusing to install asset %q: %w", asset, err)
+			}
+		}
 		err := assetStore.Add(asset)
 		if err != nil {
 			return fmt.Errorf("error adding asset %q: %v", asset, err)
@@ -205,21 +236,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		NodeupConfig: &nodeupConfig,
 	}
 
-	var secretStore fi.SecretStoreReader
 	var keyStore fi.KeystoreReader
-	if nodeConfig != nil {
-		modelContext.SecretStore = configserver.NewSecretStore(nodeConfig.NodeSecrets)
-	} else if nodeupConfig.ConfigStore != nil && nodeupConfig.ConfigStore.Secrets != "" {
-		klog.Infof("Building SecretStore at %q", nodeupConfig.ConfigStore.Secrets)
-		p, err := vfs.Context.BuildVfsPath(nodeupConfig.ConfigStore.Secrets)
-		if err != nil {
-			return fmt.Errorf("error building secret store path: %v", err)
-		}
-
-		secretStore = secrets.NewVFSSecretStoreReader(p)
-		modelContext.SecretStore = secretStore
-	} else {
-		return fmt.Errorf("SecretStore not set")
+	modelContext.SecretStore, err = buildSecretStore(nodeConfig, &nodeupConfig)
+	if err != nil {
+		return err
 	}
 
 	if nodeConfig != nil {
@@ -288,6 +308,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
+	if executable, err := os.Executable(); err != nil {
+		klog.Warningf("unable to determine the nodeup executable: %v", err)
+	} else {
+		modelContext.NodeupCommand = []string{executable, "--conf=" + c.ConfigLocation, "--cache=" + c.CacheDir}
+	}
+
 	loader := &Loader{}
 	loader.Builders = append(loader.Builders, &model.EtcHostsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NTPBuilder{NodeupModelContext: modelContext})
@@ -295,16 +321,19 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.ContainerdRegistriesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SystemdUnitBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NvidiaBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.AcceleratorBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
@@ -325,6 +354,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &networking.AmazonVPCRoutedENIBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
 
+	loader.Builders = append(loader.Builders, &model.OSPackagesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
 	taskMap, err := loader.Build()
 	if err != nil {
@@ -339,6 +369,66 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	}
 	// Protokube load image task is in ProtokubeBuilder
 
+	if err := c.runTasks(ctx, modelContext, cloud, keyStore, taskMap, out); err != nil {
+		return err
+	}
+
+	if nodeupConfig.EnableLifecycleHook {
+		if bootConfig.CloudProvider == api.CloudProviderAWS {
+			err := completeWarmingLifecycleAction(ctx, cloud.(awsup.AWSCloud), modelContext)
+			if err != nil {
+				return fmt.Errorf("failed to complete lifecylce action: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// buildSecretStore returns the secrets of the node, served by the config server or read from the state store
+func buildSecretStore(nodeConfig *nodeup.NodeConfig, nodeupConfig *nodeup.Config) (fi.SecretStoreReader, error) {
+	if nodeConfig != nil {
+		return configserver.NewSecretStore(nodeConfig.NodeSecrets), nil
+	}
+	if nodeupConfig.ConfigStore != nil && nodeupConfig.ConfigStore.Secrets != "" {
+		klog.Infof("Building SecretStore at %q", nodeupConfig.ConfigStore.Secrets)
+		p, err := vfs.Context.BuildVfsPath(nodeupConfig.ConfigStore.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("error building secret store path: %v", err)
+		}
+		return secrets.NewVFSSecretStoreReader(p), nil
+	}
+	return nil, fmt.Errorf("SecretStore not set")
+}
+
+// runContainerdRegistriesOnly applies the containerd registry hosts configuration, leaving the rest of the node untouched.
+// Only the registry hosts files are rendered, from the nodeup config and the dockerconfig secret:
+// assets, cloud and instance metadata are not needed.
+func (c *NodeUpCommand) runContainerdRegistriesOnly(ctx context.Context, bootConfig *nodeup.BootConfig, nodeupConfig *nodeup.Config, nodeConfig *nodeup.NodeConfig, out io.Writer) error {
+	secretStore, err := buildSecretStore(nodeConfig, nodeupConfig)
+	if err != nil {
+		return err
+	}
+
+	modelContext := &model.NodeupModelContext{
+		BootConfig:   bootConfig,
+		NodeupConfig: nodeupConfig,
+		SecretStore:  secretStore,
+	}
+	if err := modelContext.Init(); err != nil {
+		return err
+	}
+
+	loader := &Loader{}
+	loader.Builders = append(loader.Builders, &model.ContainerdRegistriesBuilder{NodeupModelContext: modelContext})
+	taskMap, err := loader.Build()
+	if err != nil {
+		return fmt.Errorf("error building loader: %v", err)
+	}
+
+	return c.runTasks(ctx, modelContext, nil, nil, taskMap, out)
+}
+
+func (c *NodeUpCommand) runTasks(ctx context.Context, modelContext *model.NodeupModelContext, cloud fi.Cloud, keyStore fi.KeystoreReader, taskMap map[string]fi.NodeupTask, out io.Writer) error {
 	var target fi.NodeupTarget
 
 	switch c.Target {
@@ -348,13 +438,13 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			Cloud:    cloud,
 		}
 	case "dryrun":
-		assetBuilder := assets.NewAssetBuilder(vfs.Context, nil, nodeupConfig.KubernetesVersion, false)
+		assetBuilder := assets.NewAssetBuilder(vfs.Context, nil, modelContext.NodeupConfig.KubernetesVersion, false)
 		target = fi.NewNodeupDryRunTarget(assetBuilder, out)
 	default:
 		return fmt.Errorf("unsupported target type %q", c.Target)
 	}
 
-	context, err := fi.NewNodeupContext(ctx, target, keyStore, &bootConfig, &nodeupConfig, taskMap)
+	context, err := fi.NewNodeupContext(ctx, target, keyStore, modelContext.BootConfig, modelContext.NodeupConfig, taskMap)
 	if err != nil {
 		klog.Exitf("error building context: %v", err)
 	}
@@ -372,14 +462,6 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Exitf("error closing target: %v", err)
 	}
 
-	if nodeupConfig.EnableLifecycleHook {
-		if bootConfig.CloudProvider == api.CloudProviderAWS {
-			err := completeWarmingLifecycleAction(ctx, cloud.(awsup.AWSCloud), modelContext)
-			if err != nil {
-				return fmt.Errorf("failed to complete lifecylce action: %w", err)
-			}
-		}
-	}
 	return nil
 }
 
@@ -436,6 +518,43 @@ func completeWarmingLifecycleAction(ctx context.Context, cloud awsup.AWSCloud, m
 	return nil
 }
 
+// trustedAssetVerificationKey returns the public key the signatures of the assets are verified with, if any.
+// The key comes from the image if it provides one, or else from the BootConfig in the user data of the instance;
+// never from the nodeup config, which holds the assets and their signatures.
+func trustedAssetVerificationKey(bootConfig *nodeup.BootConfig) (string, error) {
+	b, err := os.ReadFile(nodeup.TrustedAssetVerificationKeyPath)
+	if err == nil {
+		klog.Infof("Verifying asset signatures with the public key in %s", nodeup.TrustedAssetVerificationKeyPath)
+		return string(b), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("error reading asset verification key: %w", err)
+	}
+	return bootConfig.AssetVerificationPublicKey, nil
+}
+
+// verifyAssetSignature checks the signature of an asset, in the "<hash>@<urls>" format, against its hash.
+// The hash of the downloaded file is verified by the asset store, so this ensures that only signed files are installed.
+func verifyAssetSignature(publicKey string, verification *nodeup.AssetVerificationConfig, asset string) error {
+	i := strings.Index(asset, "@")
+	if i <= 0 {
+		return fmt.Errorf("asset does not have a hash to verify the signature against")
+	}
+	hash := asset[:i]
+
+	var signature string
+	if verification != nil {
+		signature = verification.Signatures[hash]
+	}
+	if signature == "" {
+		return fmt.Errorf("asset is not signed")
+	}
+	if err := assets.VerifyFileSignature(publicKey, hash, signature); err != nil {
+		return fmt.Errorf("invalid asset signature: %w", err)
+	}
+	return nil
+}
+
 func evaluateSpec(nodeupConfig *nodeup.Config, cloudProvider api.CloudProviderID) error {
 	hostnameOverride, err := evaluateHostnameOverride(cloudProvider)
 	if err != nil {
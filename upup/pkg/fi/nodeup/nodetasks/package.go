@@ -60,6 +60,15 @@ const (
 	dockerPackageName           = "docker-ce"
 )
 
+// VersionlockPluginPackage returns the package providing the versionlock plugin of the package manager,
+// used to keep pinned packages at their version on RHEL based distributions.
+func VersionlockPluginPackage(d distributions.Distribution) string {
+	if d == distributions.DistributionAmazonLinux2 {
+		return "yum-plugin-versionlock"
+	}
+	return "python3-dnf-plugin-versionlock"
+}
+
 var _ fi.NodeupHasDependencies = &Package{}
 
 // GetDependencies computes dependencies for the package task
@@ -68,11 +77,21 @@ func (e *Package) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTas
 
 	// UpdatePackages before we install any packages
 	for _, v := range tasks {
-		if _, ok := v.(*UpdatePackages); ok {
+		switch v.(type) {
+		case *UpdatePackages, *YumRepository:
 			deps = append(deps, v)
 		}
 	}
 
+	// Pinned packages are locked with the versionlock plugin, which must be installed first
+	if e.isPinned() {
+		for _, v := range tasks {
+			if vp, ok := v.(*Package); ok && vp != e && isVersionlockPluginPackage(vp.Name) {
+				deps = append(deps, v)
+			}
+		}
+	}
+
 	// If this package is a bare deb, install it after OS managed packages
 	if !e.isOSPackage() {
 		for _, v := range tasks {
@@ -123,6 +142,11 @@ func (p *Package) isOSPackage() bool {
 	return fi.ValueOf(p.Source) == ""
 }
 
+// isPinned returns true if this is an OS provided package that must be installed at an exact version
+func (p *Package) isPinned() bool {
+	return p.isOSPackage() && fi.ValueOf(p.Version) != ""
+}
+
 // String returns a string representation, implementing the Stringer interface
 func (p *Package) String() string {
 	return fmt.Sprintf("Package: %s", p.Name)
@@ -198,7 +222,8 @@ func (e *Package) findDpkg(c *fi.NodeupContext) (*Package, error) {
 		}
 	}
 
-	if c.T.NodeupConfig.UpdatePolicy != kops.UpdatePolicyExternal || !installed {
+	// Pinned packages are only reinstalled if the installed version differs
+	if (c.T.NodeupConfig.UpdatePolicy != kops.UpdatePolicyExternal && !e.isPinned()) || !installed {
 		return nil, nil
 	}
 
@@ -210,7 +235,7 @@ func (e *Package) findDpkg(c *fi.NodeupContext) (*Package, error) {
 }
 
 func (e *Package) findYum(c *fi.NodeupContext) (*Package, error) {
-	args := []string{"/usr/bin/rpm", "-q", e.Name, "--queryformat", "%{NAME} %{VERSION}"}
+	args := []string{"/usr/bin/rpm", "-q", e.Name, "--queryformat", "%{NAME} %{VERSION} %{RELEASE}"}
 	human := strings.Join(args, " ")
 
 	klog.V(2).Infof("Listing installed packages: %s", human)
@@ -232,7 +257,7 @@ func (e *Package) findYum(c *fi.NodeupContext) (*Package, error) {
 		}
 
 		tokens := strings.Split(line, " ")
-		if len(tokens) != 2 {
+		if len(tokens) != 3 {
 			return nil, fmt.Errorf("error parsing rpm line %q", line)
 		}
 
@@ -242,11 +267,15 @@ func (e *Package) findYum(c *fi.NodeupContext) (*Package, error) {
 		}
 		installed = true
 		installedVersion = tokens[1]
+		// Packages can be pinned either to VERSION or to VERSION-RELEASE
+		if fi.ValueOf(e.Version) == tokens[1]+"-"+tokens[2] {
+			installedVersion = tokens[1] + "-" + tokens[2]
+		}
 		// If we implement unhealthy; be sure to implement repair in Render
 		healthy = fi.PtrTo(true)
 	}
 
-	if c.T.NodeupConfig.UpdatePolicy != kops.UpdatePolicyExternal || !installed {
+	if (c.T.NodeupConfig.UpdatePolicy != kops.UpdatePolicyExternal && !e.isPinned()) || !installed {
 		return nil, nil
 	}
 
@@ -317,6 +346,12 @@ func (_ *Package) RenderLocal(t *local.LocalTarget, a, e, changes *Package) erro
 					return err
 				}
 			}
+		} else if e.isPinned() {
+			if d.IsDebianFamily() {
+				pkgs = append(pkgs, e.Name+"="+fi.ValueOf(e.Version))
+			} else {
+				pkgs = append(pkgs, e.Name+"-"+fi.ValueOf(e.Version))
+			}
 		} else {
 			pkgs = append(pkgs, e.Name)
 		}
@@ -325,16 +360,31 @@ func (_ *Package) RenderLocal(t *local.LocalTarget, a, e, changes *Package) erro
 		env := os.Environ()
 		if d.IsDebianFamily() {
 			args = []string{"apt-get", "install", "--yes", "--no-install-recommends"}
+			if e.isPinned() {
+				// Pinned packages may need to be downgraded, and may already be held
+				args = append(args, "--allow-downgrades", "--allow-change-held-packages")
+			}
 			env = append(env, "DEBIAN_FRONTEND=noninteractive")
 		} else if d.IsRHELFamily() {
+			command := "install"
+			if e.isPinned() && a != nil {
+				installed, err := queryInstalledVersion(d, e.Name)
+				if err != nil {
+					return err
+				}
+				// yum doesn't install older versions than the installed one; they must be downgraded
+				if compareRPMVersions(installed, fi.ValueOf(e.Version)) > 0 {
+					command = "downgrade"
+				}
+			}
 
-			if slices.Contains([]distributions.Distribution{
-				distributions.DistributionRhel8, distributions.DistributionRocky8,
-				distributions.DistributionRhel9, distributions.DistributionRocky9,
-			}, d) {
-				args = []string{"/usr/bin/dnf", "install", "-y", "--setopt=install_weak_deps=False"}
-			} else {
-				args = []string{"/usr/bin/yum", "install", "-y"}
+			args = []string{rpmPackageManager(d), command, "-y"}
+			if args[0] == "/usr/bin/dnf" {
+				args = append(args, "--setopt=install_weak_deps=False")
+			}
+			if e.isPinned() {
+				// The lock of a previously pinned version would prevent installing the new one
+				args = append(args, "--disableplugin=versionlock")
 			}
 		} else {
 			return fmt.Errorf("unsupported package system")
@@ -364,6 +414,34 @@ func (_ *Package) RenderLocal(t *local.LocalTarget, a, e, changes *Package) erro
 			}
 			return fmt.Errorf("error installing package %q: %v: %s", e.Name, err, string(output))
 		}
+
+		// Hold pinned packages, so that unattended-upgrades doesn't upgrade them
+		if e.isPinned() && d.IsDebianFamily() {
+			args := []string{"apt-mark", "hold", e.Name}
+			klog.Infof("running command %s", args)
+			cmd := exec.Command(args[0], args[1:]...)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("error holding package %q: %v: %s", e.Name, err, string(output))
+			}
+		}
+
+		// Lock pinned packages, so that yum update doesn't upgrade them
+		if e.isPinned() && d.IsRHELFamily() {
+			// Locks of previously pinned versions are replaced; there is nothing to delete the first time
+			args := []string{rpmPackageManager(d), "versionlock", "delete", e.Name}
+			klog.Infof("running command %s", args)
+			if output, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+				klog.V(2).Infof("no versionlock to delete for %q: %v: %s", e.Name, err, string(output))
+			}
+
+			args = []string{rpmPackageManager(d), "versionlock", "add", e.Name + "-" + fi.ValueOf(e.Version)}
+			klog.Infof("running command %s", args)
+			output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("error locking package %q: %v: %s", e.Name, err, string(output))
+			}
+		}
 	} else {
 		if changes.Healthy != nil {
 			if d.IsDebianFamily() {
@@ -391,3 +469,101 @@ func (_ *Package) RenderLocal(t *local.LocalTarget, a, e, changes *Package) erro
 
 	return nil
 }
+
+// rpmPackageManager returns the package manager to use on RHEL based distributions
+func rpmPackageManager(d distributions.Distribution) string {
+	if slices.Contains([]distributions.Distribution{
+		distributions.DistributionRhel8, distributions.DistributionRocky8,
+		distributions.DistributionRhel9, distributions.DistributionRocky9,
+	}, d) {
+		return "/usr/bin/dnf"
+	}
+	return "/usr/bin/yum"
+}
+
+func isVersionlockPluginPackage(name string) bool {
+	return name == "yum-plugin-versionlock" || name == "python3-dnf-plugin-versionlock"
+}
+
+// compareRPMVersions compares two VERSION or VERSION-RELEASE strings like rpmvercmp does,
+// returning -1, 0 or 1 if a is older than, the same as, or newer than b.
+// If b has no release, only the versions are compared.
+func compareRPMVersions(a, b string) int {
+	if !strings.Contains(b, "-") {
+		a, _, _ = strings.Cut(a, "-")
+	}
+
+	isAlnum := func(c byte) bool {
+		return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	isDigit := func(c byte) bool {
+		return c >= '0' && c <= '9'
+	}
+	segment := func(s string, digits bool) (string, string) {
+		i := 0
+		for i < len(s) && isAlnum(s[i]) && isDigit(s[i]) == digits {
+			i++
+		}
+		return s[:i], s[i:]
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		for len(a) > 0 && !isAlnum(a[0]) && a[0] != '~' {
+			a = a[1:]
+		}
+		for len(b) > 0 && !isAlnum(b[0]) && b[0] != '~' {
+			b = b[1:]
+		}
+
+		// A tilde sorts before everything, even the end of the version
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if len(a) == 0 || len(b) == 0 {
+			break
+		}
+
+		digits := isDigit(a[0])
+		var sa, sb string
+		sa, a = segment(a, digits)
+		sb, b = segment(b, digits)
+		if sb == "" {
+			// Numeric segments are newer than alphabetic ones
+			if digits {
+				return 1
+			}
+			return -1
+		}
+
+		if digits {
+			sa = strings.TrimLeft(sa, "0")
+			sb = strings.TrimLeft(sb, "0")
+			if len(sa) != len(sb) {
+				if len(sa) > len(sb) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(sa, sb); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"testing"
+)
+
+func TestCompareRPMVersions(t *testing.T) {
+	grid := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.7.3", b: "1.7.3", expected: 0},
+		{a: "1.7.3-2.el9", b: "1.7.3-2.el9", expected: 0},
+		{a: "1.7.10", b: "1.7.9", expected: 1},
+		{a: "1.7.9", b: "1.7.10", expected: -1},
+		{a: "1.07", b: "1.7", expected: 0},
+		{a: "1.7.3-10.el9", b: "1.7.3-9.el9", expected: 1},
+		// Pinned without a release, only the version is compared
+		{a: "1.7.3-10.el9", b: "1.7.3", expected: 0},
+		{a: "1.7.4-1.el9", b: "1.7.3", expected: 1},
+		{a: "1.7.3", b: "1.7.3.1", expected: -1},
+		{a: "1.7a", b: "1.7.1", expected: -1},
+		{a: "1.7~rc1", b: "1.7", expected: -1},
+		{a: "1.7~rc1", b: "1.7~rc2", expected: -1},
+		{a: "2.fc39", b: "2.el9", expected: 1},
+	}
+	for _, g := range grid {
+		if actual := compareRPMVersions(g.a, g.b); actual != g.expected {
+			t.Errorf("compareRPMVersions(%q, %q): expected %d, got %d", g.a, g.b, g.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+	"k8s.io/kops/util/pkg/distributions"
+	"sigs.k8s.io/yaml"
+)
+
+// PackageVersionReport checks that the pinned OS packages and the running kernel have the expected versions,
+// and writes the installed versions to a report file.
+// Mismatches are only reported: failing here would keep nodeup from ever starting kubelet on a node that drifted.
+type PackageVersionReport struct {
+	Path string
+
+	// Packages maps the names of pinned packages to their expected versions
+	Packages map[string]string `json:"packages,omitempty"`
+	// KernelVersion is the expected kernel release, as reported by uname -r
+	KernelVersion string `json:"kernelVersion,omitempty"`
+}
+
+// PackageVersionStatus is the content of the report written by PackageVersionReport
+type PackageVersionStatus struct {
+	// KernelVersion is the running kernel release
+	KernelVersion string `json:"kernelVersion"`
+	// Packages maps the names of pinned packages to their installed versions
+	Packages map[string]string `json:"packages,omitempty"`
+	// Mismatches lists the packages, or the kernel, that do not have the expected version
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+var (
+	_ fi.NodeupHasDependencies = &PackageVersionReport{}
+	_ fi.HasName               = &PackageVersionReport{}
+)
+
+// GetDependencies ensures that the report is built after all the packages have been installed
+func (e *PackageVersionReport) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTask {
+	var deps []fi.NodeupTask
+	for _, v := range tasks {
+		if _, ok := v.(*Package); ok {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+func (e *PackageVersionReport) GetName() *string {
+	return &e.Path
+}
+
+func (e *PackageVersionReport) String() string {
+	return fmt.Sprintf("PackageVersionReport: %s", e.Path)
+}
+
+func (e *PackageVersionReport) Find(c *fi.NodeupContext) (*PackageVersionReport, error) {
+	// We always verify the installed versions
+	return nil, nil
+}
+
+func (e *PackageVersionReport) Run(c *fi.NodeupContext) error {
+	return fi.NodeupDefaultDeltaRunMethod(e, c)
+}
+
+func (_ *PackageVersionReport) CheckChanges(a, e, changes *PackageVersionReport) error {
+	return nil
+}
+
+func (_ *PackageVersionReport) RenderLocal(t *local.LocalTarget, a, e, changes *PackageVersionReport) error {
+	d, err := distributions.FindDistribution("/")
+	if err != nil {
+		return fmt.Errorf("unknown or unsupported distro: %v", err)
+	}
+
+	status := &PackageVersionStatus{
+		Packages: make(map[string]string),
+	}
+
+	output, err := exec.Command("uname", "-r").CombinedOutput()
+	if err != nil {
+		klog.Warningf("error getting kernel version: %v: %s", err, string(output))
+	}
+	status.KernelVersion = strings.TrimSpace(string(output))
+	if err == nil && e.KernelVersion != "" && status.KernelVersion != e.KernelVersion {
+		status.Mismatches = append(status.Mismatches, fmt.Sprintf("kernel: expected %q, running %q", e.KernelVersion, status.KernelVersion))
+	}
+
+	var names []string
+	for name := range e.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expected := e.Packages[name]
+		installed, err := queryInstalledVersion(d, name)
+		if err != nil {
+			klog.Warningf("%v", err)
+			continue
+		}
+		status.Packages[name] = installed
+		if !packageVersionMatches(d, installed, expected) {
+			status.Mismatches = append(status.Mismatches, fmt.Sprintf("package %s: expected %q, installed %q", name, expected, installed))
+		}
+	}
+
+	data, err := yaml.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("error building package version report: %v", err)
+	}
+	if err := fi.WriteFile(e.Path, fi.NewBytesResource(data), 0o644, 0o755, "", ""); err != nil {
+		return fmt.Errorf("error writing package version report: %v", err)
+	}
+
+	for name, version := range status.Packages {
+		klog.Infof("Pinned package %q is installed at version %q", name, version)
+	}
+	for _, mismatch := range status.Mismatches {
+		klog.Warningf("installed version does not match the pinned version: %s", mismatch)
+	}
+
+	return nil
+}
+
+// queryInstalledVersion returns the installed version of a package, or an empty string if it is not installed
+func queryInstalledVersion(d distributions.Distribution, name string) (string, error) {
+	var args []string
+	if d.IsDebianFamily() {
+		args = []string{"dpkg-query", "-f", "${Version}", "-W", name}
+	} else if d.IsRHELFamily() {
+		args = []string{"/usr/bin/rpm", "-q", name, "--queryformat", "%{VERSION}-%{RELEASE}"}
+	} else {
+		return "", fmt.Errorf("unsupported package system")
+	}
+
+	klog.V(2).Infof("Querying installed version: %s", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "no packages found") || strings.Contains(string(output), "is not installed") {
+			return "", nil
+		}
+		return "", fmt.Errorf("error querying installed version of %q: %v: %s", name, err, string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// packageVersionMatches returns true if the installed version satisfies the pinned version.
+// On RHEL based distributions, packages can be pinned to either VERSION or VERSION-RELEASE.
+func packageVersionMatches(d distributions.Distribution, installed, expected string) bool {
+	if installed == expected {
+		return true
+	}
+	return d.IsRHELFamily() && strings.HasPrefix(installed, expected+"-")
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// registryHostsOwnerMarker is written to every registry directory managed by kOps.
+// Only directories holding it are read and removed, so that registry configuration managed by the operator is left alone.
+const registryHostsOwnerMarker = ".kops-managed"
+
+// RegistryHosts manages the containerd registry hosts directory (the config_path of the CRI registry plugin).
+// containerd reads the directory on every pull, so there is no need to restart it when the contents change.
+// Registry directories written by kOps that are no longer wanted are removed, so that registries dropped from the spec stop being used.
+type RegistryHosts struct {
+	Path string `json:"path,omitempty"`
+	// Files maps the path of each file, relative to Path, to its contents.
+	// The first element of each path is the directory of the registry.
+	Files map[string]string `json:"files,omitempty"`
+}
+
+var _ fi.NodeupTask = &RegistryHosts{}
+
+func (e *RegistryHosts) GetName() *string {
+	return &e.Path
+}
+
+func (e *RegistryHosts) String() string {
+	return fmt.Sprintf("RegistryHosts: %q", e.Path)
+}
+
+func (e *RegistryHosts) Find(c *fi.NodeupContext) (*RegistryHosts, error) {
+	files, err := readRegistryHosts(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		return nil, nil
+	}
+
+	actual := &RegistryHosts{
+		Path:  e.Path,
+		Files: files,
+	}
+	return actual, nil
+}
+
+// readRegistryHosts returns the contents of the files in the registry directories under dir that are managed by kOps,
+// or nil if dir does not exist
+func readRegistryHosts(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading directory %q: %w", dir, err)
+	}
+
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		registryDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(registryDir, registryHostsOwnerMarker)); err != nil {
+			if os.IsNotExist(err) {
+				klog.V(2).Infof("Ignoring registry hosts directory %q, which is not managed by kOps", registryDir)
+				continue
+			}
+			return nil, fmt.Errorf("error reading directory %q: %w", registryDir, err)
+		}
+
+		err := filepath.WalkDir(registryDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || d.Name() == registryHostsOwnerMarker {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			files[rel] = string(b)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading directory %q: %w", registryDir, err)
+		}
+	}
+	return files, nil
+}
+
+// registryDir returns the directory of the registry a file, relative to Path, belongs to
+func registryDir(name string) string {
+	dir, _, _ := strings.Cut(filepath.ToSlash(name), "/")
+	return dir
+}
+
+func (e *RegistryHosts) Run(c *fi.NodeupContext) error {
+	return fi.NodeupDefaultDeltaRunMethod(e, c)
+}
+
+func (*RegistryHosts) CheckChanges(a, e, changes *RegistryHosts) error {
+	for name := range e.Files {
+		if !filepath.IsLocal(name) || registryDir(name) == name {
+			return fmt.Errorf("registry hosts file %q must be in a registry directory under %q", name, e.Path)
+		}
+	}
+	return nil
+}
+
+func (*RegistryHosts) RenderLocal(t *local.LocalTarget, a, e, changes *RegistryHosts) error {
+	var names []string
+	registries := make(map[string]bool)
+	for name := range e.Files {
+		names = append(names, name)
+		registries[registryDir(name)] = true
+	}
+	sort.Strings(names)
+
+	for registry := range registries {
+		p := filepath.Join(e.Path, registry, registryHostsOwnerMarker)
+		if err := fi.WriteFile(p, fi.NewStringResource("This directory is managed by kOps\n"), 0o644, 0o755, "", ""); err != nil {
+			return fmt.Errorf("error writing registry hosts file %q: %w", p, err)
+		}
+	}
+
+	for _, name := range names {
+		p := filepath.Join(e.Path, name)
+		// The hosts files can hold registry credentials
+		if err := fi.WriteFile(p, fi.NewStringResource(e.Files[name]), 0o600, 0o755, "", ""); err != nil {
+			return fmt.Errorf("error writing registry hosts file %q: %w", p, err)
+		}
+	}
+
+	if a == nil {
+		return nil
+	}
+
+	var errs []error
+	removed := make(map[string]bool)
+	for name := range a.Files {
+		if _, found := e.Files[name]; found {
+			continue
+		}
+
+		// Registries dropped from the spec are removed along with their directory, which only kOps writes to
+		if registry := registryDir(name); !registries[registry] {
+			if removed[registry] {
+				continue
+			}
+			removed[registry] = true
+			p := filepath.Join(e.Path, registry)
+			klog.Infof("Removing registry hosts directory %q", p)
+			if err := os.RemoveAll(p); err != nil {
+				errs = append(errs, fmt.Errorf("error removing registry hosts directory %q: %w", p, err))
+			}
+			continue
+		}
+
+		p := filepath.Join(e.Path, name)
+		klog.Infof("Removing registry hosts file %q", p)
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("error removing registry hosts file %q: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryHostsKeepsUnmanagedDirectories(t *testing.T) {
+	dir := t.TempDir()
+
+	// A mirror configured by the operator, outside of the cluster spec
+	operatorFile := filepath.Join(dir, "ghcr.io", "hosts.toml")
+	if err := os.MkdirAll(filepath.Dir(operatorFile), 0o755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	if err := os.WriteFile(operatorFile, []byte("server = \"https://ghcr.io\"\n"), 0o644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	e := &RegistryHosts{
+		Path: dir,
+		Files: map[string]string{
+			"docker.io/hosts.toml":       "server = \"https://registry-1.docker.io\"\n",
+			"docker.io/ca.crt":           "ca",
+			"registry.k8s.io/hosts.toml": "server = \"https://registry.k8s.io\"\n",
+		},
+	}
+	if err := e.RenderLocal(nil, nil, e, e); err != nil {
+		t.Fatalf("error rendering registry hosts: %v", err)
+	}
+
+	actual, err := readRegistryHosts(dir)
+	if err != nil {
+		t.Fatalf("error reading registry hosts: %v", err)
+	}
+	if !reflect.DeepEqual(actual, e.Files) {
+		t.Errorf("expected the files managed by kOps %v, got %v", e.Files, actual)
+	}
+
+	// Drop a registry, and the CA of another one
+	a := &RegistryHosts{Path: dir, Files: actual}
+	e = &RegistryHosts{
+		Path: dir,
+		Files: map[string]string{
+			"docker.io/hosts.toml": "server = \"https://registry-1.docker.io\"\n",
+		},
+	}
+	if err := e.RenderLocal(nil, a, e, e); err != nil {
+		t.Fatalf("error rendering registry hosts: %v", err)
+	}
+
+	actual, err = readRegistryHosts(dir)
+	if err != nil {
+		t.Fatalf("error reading registry hosts: %v", err)
+	}
+	if !reflect.DeepEqual(actual, e.Files) {
+		t.Errorf("expected the files managed by kOps %v, got %v", e.Files, actual)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "registry.k8s.io")); !os.IsNotExist(err) {
+		t.Errorf("expected the directory of the dropped registry to be removed, got %v", err)
+	}
+	if _, err := os.Stat(operatorFile); err != nil {
+		t.Errorf("expected the registry configured by the operator to be kept, got %v", err)
+	}
+}
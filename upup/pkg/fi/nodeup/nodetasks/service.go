@@ -57,6 +57,9 @@ type Service struct {
 
 	ManageState  *bool `json:"manageState,omitempty"`
 	SmartRestart *bool `json:"smartRestart,omitempty"`
+
+	// BeforeServices are services that must be started after this service
+	BeforeServices []string `json:"beforeServices,omitempty"`
 }
 
 type InstallService struct {
@@ -90,7 +93,13 @@ func (s *Service) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTas
 		switch v := v.(type) {
 		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Chattr, *BindMount, *Archive, *Prefix, *UpdateEtcHostsTask:
 			deps = append(deps, v)
-		case *Service, *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
+		case *Service:
+			for _, b := range v.BeforeServices {
+				if s.Name == b {
+					deps = append(deps, v)
+				}
+			}
+		case *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
 			// ignore
 		case *LoadImageTask:
 			if s.Name == kubeletService {
@@ -217,8 +226,9 @@ func (e *Service) Find(_ *fi.NodeupContext) (*Service, error) {
 		Definition: fi.PtrTo(string(d)),
 
 		// Avoid spurious changes
-		ManageState:  e.ManageState,
-		SmartRestart: e.SmartRestart,
+		ManageState:    e.ManageState,
+		SmartRestart:   e.SmartRestart,
+		BeforeServices: e.BeforeServices,
 	}
 
 	properties, err := getSystemdStatus(e.Name)
@@ -244,7 +254,7 @@ func (e *Service) Find(_ *fi.NodeupContext) (*Service, error) {
 		actual.Enabled = fi.PtrTo(false)
 
 	// TODO: Can probably do better here!
-	case "multi-user.target", "graphical.target multi-user.target":
+	case "multi-user.target", "graphical.target multi-user.target", "timers.target", "local-fs.target", "remote-fs.target":
 		actual.Enabled = fi.PtrTo(true)
 
 	default:
@@ -37,6 +37,21 @@ func TestServiceTask_Deps(t *testing.T) {
 	}
 }
 
+func TestServiceTask_BeforeServices(t *testing.T) {
+	s := &Service{Name: "kubelet.service"}
+
+	tasks := make(map[string]fi.NodeupTask)
+	tasks["Service/containerd.service"] = &Service{Name: "containerd.service"}
+	tasks["Service/foo.service"] = &Service{Name: "foo.service", BeforeServices: []string{"kubelet.service"}}
+	tasks["Service/bar.service"] = &Service{Name: "bar.service", BeforeServices: []string{"containerd.service"}}
+
+	deps := s.GetDependencies(tasks)
+	expected := []fi.NodeupTask{tasks["Service/foo.service"]}
+	if !reflect.DeepEqual(expected, deps) {
+		t.Fatalf("unexpected deps.  expected=%v, actual=%v", expected, deps)
+	}
+}
+
 type FakeTask struct{}
 
 func (t *FakeTask) Run(*fi.NodeupContext) error {
@@ -42,7 +42,8 @@ func NewUpdatePackages() *UpdatePackages {
 func (p *UpdatePackages) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTask {
 	var deps []fi.NodeupTask
 	for _, v := range tasks {
-		if _, ok := v.(*AptSource); ok {
+		switch v.(type) {
+		case *AptSource, *YumRepository:
 			deps = append(deps, v)
 		}
 	}
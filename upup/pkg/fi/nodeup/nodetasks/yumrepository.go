@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// YumRepository configures an additional yum repository
+type YumRepository struct {
+	Name    string
+	Keyring string
+	BaseURL string
+}
+
+func (e *YumRepository) Find(c *fi.NodeupContext) (*YumRepository, error) {
+	return nil, nil
+}
+
+func (f *YumRepository) GetName() *string {
+	return &f.Name
+}
+
+func (f *YumRepository) String() string {
+	return f.Name
+}
+
+func (f *YumRepository) Run(c *fi.NodeupContext) error {
+	return fi.NodeupDefaultDeltaRunMethod(f, c)
+}
+
+func (*YumRepository) CheckChanges(a, e, changes *YumRepository) error {
+	return nil
+}
+
+func (f *YumRepository) RenderLocal(t *local.LocalTarget, a, e, changes *YumRepository) error {
+	lines := []string{
+		"[" + f.Name + "]",
+		"name=" + f.Name,
+		"baseurl=" + f.BaseURL,
+		"enabled=1",
+	}
+	if f.Keyring != "" {
+		lines = append(lines, "gpgcheck=1", "gpgkey="+f.Keyring)
+	} else {
+		lines = append(lines, "gpgcheck=0")
+	}
+
+	repo := strings.Join(lines, "\n") + "\n"
+	if err := fi.WriteFile("/etc/yum.repos.d/"+f.Name+".repo", fi.NewStringResource(repo), 0o644, 0o755, "", ""); err != nil {
+		return fmt.Errorf("error writing yum repository %q: %v", f.Name, err)
+	}
+
+	return nil
+}
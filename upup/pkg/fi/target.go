@@ -25,6 +25,17 @@ type Target[T SubContext] interface {
 	DefaultCheckExisting() bool
 }
 
+// RecordsExisting is implemented by targets that don't check for existing resources by default,
+// but that want to be told about them, for example to adopt them.
+type RecordsExisting[T SubContext] interface {
+	// RecordsExisting returns true if DefaultDeltaRun tasks should invoke Find(),
+	// and report the result to RecordExisting.
+	RecordsExisting() bool
+
+	// RecordExisting is called with the expected task and the existing task returned by Find.
+	RecordExisting(e, a Task[T]) error
+}
+
 type CloudupTarget = Target[CloudupSubContext]
 type InstallTarget = Target[InstallSubContext]
 type NodeupTarget = Target[NodeupSubContext]
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -104,6 +105,10 @@ type ModelBuilderContext[T SubContext] struct {
 
 	Tasks              map[string]Task[T]
 	LifecycleOverrides map[string]Lifecycle
+
+	// LifecycleOverrideRules are the lifecycle overrides from the spec; when several match a task, the last one wins.
+	// They are applied before LifecycleOverrides, so that the command line has the final say.
+	LifecycleOverrideRules []LifecycleOverrideRule
 }
 
 func (c *ModelBuilderContext[T]) WithContext(ctx context.Context) *ModelBuilderContext[T] {
@@ -112,6 +117,14 @@ func (c *ModelBuilderContext[T]) WithContext(ctx context.Context) *ModelBuilderC
 	return &c2
 }
 
+// WithLifecycleOverrideRules returns a context that adds tasks to the same tasks,
+// applying the rules after those of c, for example those of an instance group after those of the cluster.
+func (c *ModelBuilderContext[T]) WithLifecycleOverrideRules(rules []LifecycleOverrideRule) *ModelBuilderContext[T] {
+	c2 := *c
+	c2.LifecycleOverrideRules = append(slices.Clone(c.LifecycleOverrideRules), rules...)
+	return &c2
+}
+
 func (c *ModelBuilderContext[T]) Context() context.Context {
 	ctx := c.ctx
 	if ctx == nil {
@@ -170,9 +183,25 @@ func (c *ModelBuilderContext[T]) setLifecycleOverride(task Task[T]) Task[T] {
 	// certain tasks have not implemented HasLifecycle interface
 	typeName := TypeNameForTask(task)
 
+	var value Lifecycle
+	for i := range c.LifecycleOverrideRules {
+		if c.LifecycleOverrideRules[i].Matches(typeName, task) {
+			value = c.LifecycleOverrideRules[i].Lifecycle
+		}
+	}
+	// The rules from the spec can only make a task do less than its lifecycle for the phase allows,
+	// so that they cannot bring back tasks skipped by --phase, or sync tasks that the phase only validates
+	if hl, ok := task.(HasLifecycle); ok && value != "" && !value.IsAtLeastAsStrictAs(hl.GetLifecycle()) {
+		klog.V(2).Infof("not overriding task %s, lifecycle %s with the less strict lifecycle %s", task, hl.GetLifecycle(), value)
+		value = ""
+	}
+
 	// typeName can be values like "InternetGateway"
-	value, ok := c.LifecycleOverrides[typeName]
-	if ok {
+	if override, ok := c.LifecycleOverrides[typeName]; ok {
+		value = override
+	}
+
+	if value != "" {
 		hl, okHL := task.(HasLifecycle)
 		if !okHL {
 			klog.Warningf("task %T does not implement HasLifecycle", task)
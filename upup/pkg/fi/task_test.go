@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"testing"
+)
+
+type SecurityGroup struct {
+	Name      *string
+	ID        *string
+	Lifecycle Lifecycle
+}
+
+func (t *SecurityGroup) Run(*CloudupContext) error        { return nil }
+func (t *SecurityGroup) GetName() *string                 { return t.Name }
+func (t *SecurityGroup) GetLifecycle() Lifecycle          { return t.Lifecycle }
+func (t *SecurityGroup) SetLifecycle(lifecycle Lifecycle) { t.Lifecycle = lifecycle }
+func (t *SecurityGroup) CompareWithID() *string           { return t.ID }
+
+func TestLifecycleOverrideRules(t *testing.T) {
+	clusterRules := []LifecycleOverrideRule{
+		{TaskType: "SecurityGroup", Lifecycle: LifecycleExistsAndWarnIfChanges},
+		{TaskType: "SecurityGroup", ID: "sg-shared", Lifecycle: LifecycleIgnore},
+		{TaskType: "InternetGateway", Lifecycle: LifecycleIgnore},
+	}
+	igRules := []LifecycleOverrideRule{
+		{TaskType: "SecurityGroup", Name: "nodes", Lifecycle: LifecycleExistsAndValidates},
+	}
+
+	grid := []struct {
+		Name               string
+		Task               *SecurityGroup
+		Rules              []LifecycleOverrideRule
+		LifecycleOverrides map[string]Lifecycle
+		Expected           Lifecycle
+	}{
+		{
+			Name:     "no rules",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleSync},
+			Expected: LifecycleSync,
+		},
+		{
+			Name:     "rule for task type",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleSync},
+			Rules:    clusterRules,
+			Expected: LifecycleExistsAndWarnIfChanges,
+		},
+		{
+			Name:     "rule for ID",
+			Task:     &SecurityGroup{Name: PtrTo("shared"), ID: PtrTo("sg-shared"), Lifecycle: LifecycleSync},
+			Rules:    clusterRules,
+			Expected: LifecycleIgnore,
+		},
+		{
+			Name:     "instance group rule after cluster rules",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleSync},
+			Rules:    append(clusterRules, igRules...),
+			Expected: LifecycleExistsAndValidates,
+		},
+		{
+			Name:               "command line after rules",
+			Task:               &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleSync},
+			Rules:              clusterRules,
+			LifecycleOverrides: map[string]Lifecycle{"SecurityGroup": LifecycleSync},
+			Expected:           LifecycleSync,
+		},
+		{
+			Name:     "rules do not loosen the lifecycle for the phase",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleExistsAndValidates},
+			Rules:    []LifecycleOverrideRule{{TaskType: "SecurityGroup", Lifecycle: LifecycleSync}},
+			Expected: LifecycleExistsAndValidates,
+		},
+		{
+			Name:     "rules tighten the lifecycle for the phase",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleExistsAndWarnIfChanges},
+			Rules:    []LifecycleOverrideRule{{TaskType: "SecurityGroup", Lifecycle: LifecycleExistsAndValidates}},
+			Expected: LifecycleExistsAndValidates,
+		},
+		{
+			Name:     "rules do not apply to ignored tasks",
+			Task:     &SecurityGroup{Name: PtrTo("nodes"), Lifecycle: LifecycleIgnore},
+			Rules:    append(clusterRules, igRules...),
+			Expected: LifecycleIgnore,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			c := &CloudupModelBuilderContext{
+				Tasks:              make(map[string]CloudupTask),
+				LifecycleOverrides: g.LifecycleOverrides,
+			}
+			c.WithLifecycleOverrideRules(g.Rules).AddTask(g.Task)
+			if g.Task.Lifecycle != g.Expected {
+				t.Errorf("expected lifecycle %q, got %q", g.Expected, g.Task.Lifecycle)
+			}
+			if c.Tasks["SecurityGroup/"+ValueOf(g.Task.Name)] != g.Task {
+				t.Errorf("expected task to be added to the shared tasks")
+			}
+		})
+	}
+}